@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/database"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/repository/postgres"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+	"golang.org/x/term"
+)
+
+const usage = `Usage:
+  server                                Start the API server
+  server admin create [-role ROLE]      Create an admin, prompting for email and password`
+
+// runCommand runs a command given on the command line instead of starting the server
+func runCommand(args []string) error {
+	if len(args) >= 2 && args[0] == "admin" && args[1] == "create" {
+		return createAdmin(args[2:])
+	}
+	return fmt.Errorf("unknown command %q\n%s", strings.Join(args, " "), usage)
+}
+
+// createAdmin creates an admin from the terminal, e.g. the first owner of a
+// new installation
+func createAdmin(args []string) error {
+	flags := flag.NewFlagSet("admin create", flag.ContinueOnError)
+	role := flags.String("role", services.RoleOwner, "role of the new admin: OWNER, FINANCE, CHECK_IN or VIEWER")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	*role = strings.ToUpper(*role)
+	if !services.IsValidRole(*role) {
+		return fmt.Errorf("invalid role %q: must be OWNER, FINANCE, CHECK_IN or VIEWER", *role)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := database.Connect(cfg); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+
+	in := bufio.NewReader(os.Stdin)
+
+	email, err := prompt(in, "Email: ", false)
+	if err != nil {
+		return err
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := utils.NewValidator().ValidateEmail(email); err != nil {
+		return err
+	}
+
+	password, err := prompt(in, "Password: ", true)
+	if err != nil {
+		return err
+	}
+	confirmation, err := prompt(in, "Confirm password: ", true)
+	if err != nil {
+		return err
+	}
+	if password != confirmation {
+		return errors.New("passwords do not match")
+	}
+
+	adminService := services.NewAdminService(
+		services.NewAuthService(cfg),
+		postgres.NewAdminRepository(database.DB),
+		postgres.NewSessionRepository(database.DB),
+		postgres.NewTransactor(database.DB),
+	)
+	admin, err := adminService.Create(context.Background(), email, password, *role, "")
+	var weak *services.WeakPasswordError
+	switch {
+	case errors.As(err, &weak):
+		return fmt.Errorf("invalid password: %w", err)
+	case errors.Is(err, repository.ErrDuplicateEmail):
+		return fmt.Errorf("an admin with email %s already exists", email)
+	case err != nil:
+		return fmt.Errorf("failed to create admin: %w", err)
+	}
+
+	fmt.Printf("✅ Admin %s created with role %s\n", admin.Email, admin.Role)
+	return nil
+}
+
+// prompt reads a line from the terminal without its line terminator. Hidden
+// input is not echoed when stdin is a terminal, so passwords don't show on
+// screen.
+func prompt(in *bufio.Reader, label string, hidden bool) (string, error) {
+	fmt.Print(label)
+
+	if fd := int(os.Stdin.Fd()); hidden && term.IsTerminal(fd) {
+		password, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+		return string(password), nil
+	}
+
+	line, err := in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), nil
+}
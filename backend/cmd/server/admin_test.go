@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestPromptKeepsSpaces(t *testing.T) {
+	// go test does not run with a terminal on stdin, so hidden input is read
+	// the same way as when it is piped in
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "line feed", input: "  pass word \n", want: "  pass word "},
+		{name: "carriage return", input: "  pass word \r\n", want: "  pass word "},
+		{name: "no terminator", input: "  pass word ", want: "  pass word "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prompt(bufio.NewReader(strings.NewReader(tt.input)), "", true)
+			if err != nil {
+				t.Fatalf("prompt() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("prompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,22 +1,37 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata" // EVENT_TIMEZONE must resolve in containers without zoneinfo
 
 	"github.com/gin-gonic/gin"
 	"github.com/tau-tau-run/backend/config"
 	"github.com/tau-tau-run/backend/internal/database"
 	"github.com/tau-tau-run/backend/internal/handlers"
+	"github.com/tau-tau-run/backend/internal/mailer"
 	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/payment"
+	"github.com/tau-tau-run/backend/internal/repository/postgres"
 	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/storage"
 	"github.com/tau-tau-run/backend/internal/utils"
 )
 
 func main() {
+	// Run a command like `server admin create` instead of the server
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		return
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -37,11 +52,90 @@ func main() {
 		utils.EmailLogger.Warning("SMTP not fully configured: %v - Email features will be disabled", err)
 	}
 
+	// Initialize repositories
+	tx := postgres.NewTransactor(database.DB)
+	eventRepo := postgres.NewEventRepository(database.DB)
+	raceCategoryRepo := postgres.NewRaceCategoryRepository(database.DB)
+	participantRepo := postgres.NewParticipantRepository(database.DB)
+	adminRepo := postgres.NewAdminRepository(database.DB)
+	sessionRepo := postgres.NewSessionRepository(database.DB)
+	passwordResetRepo := postgres.NewPasswordResetRepository(database.DB)
+	emailLogRepo := postgres.NewEmailLogRepository(database.DB)
+	paymentRepo := postgres.NewPaymentRepository(database.DB)
+	paymentProofRepo := postgres.NewPaymentProofRepository(database.DB)
+	bibReservationRepo := postgres.NewBibReservationRepository(database.DB)
+	emailOutboxRepo := postgres.NewEmailOutboxRepository(database.DB)
+	emailTemplateRepo := postgres.NewEmailTemplateRepository(database.DB)
+	emailScheduleRepo := postgres.NewEmailScheduleRepository(database.DB)
+	emailCampaignRepo := postgres.NewEmailCampaignRepository(database.DB)
+	transferRepo := postgres.NewTransferRepository(database.DB)
+
+	// Initialize file storage for uploads
+	fileStorage, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize file storage: %v", err)
+	}
+
+	// Initialize the email driver
+	emailMailer, err := mailer.New(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize email driver: %v", err)
+	}
+	defer emailMailer.Close()
+
+	// Initialize payment provider (nil when online payments are disabled)
+	paymentProvider, err := payment.NewProvider(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize payment provider: %v", err)
+	}
+	if paymentProvider == nil {
+		utils.ServerLogger.Warning("PAYMENT_PROVIDER not configured - online payments are disabled")
+	} else {
+		utils.ServerLogger.Info("Payment provider: %s", paymentProvider.Name())
+	}
+
 	// Initialize services
 	authService := services.NewAuthService(cfg)
-	emailService := services.NewEmailService(cfg)
-	participantHandler := handlers.NewParticipantHandler()
-	adminHandler := handlers.NewAdminHandler(authService, emailService)
+	adminService := services.NewAdminService(authService, adminRepo, sessionRepo, tx)
+	sessionService := services.NewSessionService(cfg, authService, adminService, sessionRepo, tx)
+	checkInService := services.NewCheckInService(cfg, adminRepo, participantRepo, tx)
+	emailTemplateService := services.NewEmailTemplateService(cfg, checkInService, authService, eventRepo, raceCategoryRepo, participantRepo, emailTemplateRepo, transferRepo, tx)
+	emailService := services.NewEmailService(cfg, emailMailer, emailTemplateService, eventRepo, emailLogRepo, emailCampaignRepo, transferRepo, checkInService)
+	passwordService := services.NewPasswordService(cfg, authService, adminService, sessionService, emailService, adminRepo, passwordResetRepo, tx)
+	emailOutbox := services.NewEmailOutbox(cfg, emailService, participantRepo, emailOutboxRepo, tx)
+	emailScheduleService := services.NewEmailScheduleService(cfg, eventRepo, participantRepo, emailScheduleRepo, emailOutbox, tx)
+	emailLogService := services.NewEmailLogService(emailService, participantRepo, emailLogRepo)
+	emailCampaignService := services.NewEmailCampaignService(cfg, emailTemplateService, eventRepo, raceCategoryRepo, participantRepo, emailCampaignRepo, emailLogRepo, emailOutbox, tx)
+	waitlistService := services.NewWaitlistService(cfg, eventRepo, raceCategoryRepo, participantRepo, emailOutbox, tx)
+	eventService := services.NewEventService(eventRepo, waitlistService)
+	raceCategoryService := services.NewRaceCategoryService(cfg, eventRepo, raceCategoryRepo, waitlistService)
+	registrationService := services.NewRegistrationService(raceCategoryRepo, participantRepo, waitlistService, tx)
+	bibService := services.NewBibService(eventRepo, raceCategoryRepo, participantRepo, bibReservationRepo, tx)
+	paymentService := services.NewPaymentService(cfg, paymentProvider, emailOutbox, bibService, eventRepo, raceCategoryRepo, participantRepo, paymentRepo, tx)
+	cancellationService := services.NewCancellationService(cfg, paymentService, bibService, waitlistService, emailOutbox, eventRepo, participantRepo, tx)
+	transferService := services.NewTransferService(cfg, authService, emailOutbox, eventRepo, raceCategoryRepo, participantRepo, transferRepo, tx)
+	portalService := services.NewPortalService(authService, eventRepo, raceCategoryRepo, participantRepo, transferRepo, emailOutbox, cancellationService, transferService, tx)
+	participantHandler := handlers.NewParticipantHandler(eventService, registrationService)
+	eventHandler := handlers.NewEventHandler(eventService)
+	raceCategoryHandler := handlers.NewRaceCategoryHandler(raceCategoryService)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlistService)
+	bibHandler := handlers.NewBibHandler(bibService)
+	checkInHandler := handlers.NewCheckInHandler(checkInService)
+	emailOutboxHandler := handlers.NewEmailOutboxHandler(emailOutbox)
+	emailTemplateHandler := handlers.NewEmailTemplateHandler(emailTemplateService)
+	emailScheduleHandler := handlers.NewEmailScheduleHandler(emailScheduleService)
+	emailCampaignHandler := handlers.NewEmailCampaignHandler(emailCampaignService)
+	emailLogHandler := handlers.NewEmailLogHandler(emailLogService)
+	portalHandler := handlers.NewPortalHandler(portalService)
+	cancellationHandler := handlers.NewCancellationHandler(cancellationService)
+	transferHandler := handlers.NewTransferHandler(transferService)
+	paymentProofService := services.NewPaymentProofService(fileStorage, paymentProofRepo, participantRepo, paymentService, tx)
+	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	paymentProofHandler := handlers.NewPaymentProofHandler(paymentProofService)
+	adminHandler := handlers.NewAdminHandler(authService, sessionService, paymentService, eventService, registrationService, adminRepo, participantRepo, tx)
+	adminUserHandler := handlers.NewAdminUserHandler(adminService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	passwordHandler := handlers.NewPasswordHandler(passwordService)
 
 	// Setup Gin
 	if cfg.IsProduction() {
@@ -82,8 +176,39 @@ func main() {
 				})
 			})
 			
+			// Events open for registration
+			public.GET("/events", eventHandler.List)
+			public.GET("/events/:id", eventHandler.Get)
+			public.GET("/events/:id/categories", raceCategoryHandler.ListByEvent)
+
 			// Registration endpoint
 			public.POST("/register", participantHandler.Register)
+
+			// Online payment endpoints
+			public.POST("/participants/:id/payment", paymentHandler.CreatePayment)
+			public.POST("/payments/webhook", paymentHandler.Webhook)
+
+			// Proof of payment for bank transfers
+			public.POST("/participants/:id/payment-proof", paymentProofHandler.Upload)
+
+			// Participant portal magic-link login
+			public.POST("/portal/login", portalHandler.RequestLogin)
+			public.POST("/portal/session", portalHandler.Login)
+
+			// Accepting a registration transferred through an emailed link
+			public.POST("/transfers/offer", transferHandler.Offer)
+			public.POST("/transfers/accept", transferHandler.Accept)
+		}
+
+		// Participant portal routes
+		portal := v1.Group("/portal")
+		portal.Use(middleware.ParticipantAuthMiddleware(authService))
+		{
+			portal.GET("/registrations", portalHandler.Registrations)
+			portal.PATCH("/registrations/:id", portalHandler.UpdateRegistration)
+			portal.POST("/registrations/:id/cancel", portalHandler.CancelRegistration)
+			portal.POST("/registrations/:id/transfer", portalHandler.TransferRegistration)
+			portal.DELETE("/registrations/:id/transfer", portalHandler.CancelTransfer)
 		}
 
 		// Admin routes
@@ -91,16 +216,123 @@ func main() {
 		{
 			// POST /login (no auth required)
 			admin.POST("/login", adminHandler.Login)
+
+			// POST /refresh and /logout take a refresh token (no auth required)
+			admin.POST("/refresh", sessionHandler.Refresh)
+			admin.POST("/logout", sessionHandler.Logout)
+
+			// POST /forgot-password and /reset-password (no auth required)
+			admin.POST("/forgot-password", passwordHandler.ForgotPassword)
+			admin.POST("/reset-password", passwordHandler.ResetPassword)
 			
 			// Protected admin routes
 			protected := admin.Group("")
-			protected.Use(middleware.AuthMiddleware(authService))
+			protected.Use(middleware.AuthMiddleware(sessionService))
 			{
+				// Each route requires a permission of the admin's role
+				view := middleware.RequirePermission(services.PermissionView)
+				manageEvents := middleware.RequirePermission(services.PermissionManageEvents)
+				manageParticipants := middleware.RequirePermission(services.PermissionManageParticipants)
+				payments := middleware.RequirePermission(services.PermissionPayments)
+				checkIn := middleware.RequirePermission(services.PermissionCheckIn)
+				manageAdmins := middleware.RequirePermission(services.PermissionManageAdmins)
+
+				// Any admin can log out of all their sessions and change their password
+				protected.POST("/logout-all", sessionHandler.LogoutAll)
+				protected.POST("/change-password", passwordHandler.ChangePassword)
+
+				// Event management
+				protected.GET("/events", view, eventHandler.List)
+				protected.POST("/events", manageEvents, eventHandler.Create)
+				protected.PUT("/events/:id", manageEvents, eventHandler.Update)
+				protected.DELETE("/events/:id", manageEvents, eventHandler.Delete)
+
+				// Race categories of an event
+				protected.GET("/events/:id/categories", view, raceCategoryHandler.ListByEvent)
+				protected.POST("/events/:id/categories", manageEvents, raceCategoryHandler.Create)
+				protected.PUT("/categories/:id", manageEvents, raceCategoryHandler.Update)
+				protected.DELETE("/categories/:id", manageEvents, raceCategoryHandler.Delete)
+
+				// Waitlist management
+				protected.GET("/events/:id/waitlist", view, waitlistHandler.List)
+				protected.PUT("/participants/:id/waitlist-position", manageParticipants, waitlistHandler.Reorder)
+
+				// Bib numbers
+				protected.GET("/events/:id/bib-reservations", view, bibHandler.ListReservations)
+				protected.POST("/events/:id/bib-reservations", manageEvents, bibHandler.CreateReservation)
+				protected.DELETE("/bib-reservations/:id", manageEvents, bibHandler.DeleteReservation)
+				protected.PUT("/participants/:id/bib", manageParticipants, bibHandler.Reassign)
+
+				// QR code race-kit pickup and race-day check-in
+				protected.POST("/check-in/verify", checkIn, checkInHandler.Verify)
+				protected.POST("/check-in/kit", checkIn, checkInHandler.CollectKit)
+				protected.POST("/check-in/race", checkIn, checkInHandler.CheckIn)
+				protected.GET("/participants/:id/check-in-qr", checkIn, checkInHandler.QRCode)
+
 				// GET /participants
-				protected.GET("/participants", adminHandler.GetParticipants)
+				protected.GET("/participants", view, adminHandler.GetParticipants)
+
+				// GET /participants/export
+				protected.GET("/participants/export", view, adminHandler.ExportParticipants)
+
+				// POST /participants/import
+				protected.POST("/participants/import", manageParticipants, adminHandler.ImportParticipants)
 				
 				// PATCH /participants/:id/payment
-				protected.PATCH("/participants/:id/payment", adminHandler.UpdatePaymentStatus)
+				protected.PATCH("/participants/:id/payment", payments, adminHandler.UpdatePaymentStatus)
+
+				// Cancellations and refunds
+				protected.POST("/participants/:id/cancel", manageParticipants, cancellationHandler.Cancel)
+				protected.POST("/participants/:id/refund", payments, cancellationHandler.Refund)
+
+				// Registration transfers
+				protected.POST("/participants/:id/transfer", manageParticipants, transferHandler.Start)
+				protected.DELETE("/participants/:id/transfer", manageParticipants, transferHandler.Cancel)
+				protected.GET("/participants/:id/transfers", view, transferHandler.ListByParticipant)
+				protected.GET("/events/:id/transfers", view, transferHandler.ListByEvent)
+
+				// Payment proof review queue
+				protected.GET("/payment-proofs", payments, paymentProofHandler.List)
+				protected.GET("/payment-proofs/:id/file", payments, paymentProofHandler.Download)
+				protected.POST("/payment-proofs/:id/approve", payments, paymentProofHandler.Approve)
+				protected.POST("/payment-proofs/:id/reject", payments, paymentProofHandler.Reject)
+
+				// Email outbox and dead letters
+				protected.GET("/email-outbox", view, emailOutboxHandler.List)
+				protected.POST("/email-outbox/:id/requeue", manageEvents, emailOutboxHandler.Requeue)
+
+				// Email delivery log and resends
+				protected.GET("/email-logs", view, emailLogHandler.List)
+				protected.GET("/email-logs/:id", view, emailLogHandler.Get)
+				protected.POST("/email-logs/:id/resend", manageParticipants, emailLogHandler.Resend)
+				protected.POST("/participants/:id/emails/:type/resend", manageParticipants, emailLogHandler.ResendType)
+
+				// Email templates per event, with preview and version history
+				protected.GET("/events/:id/email-templates", view, emailTemplateHandler.List)
+				protected.GET("/events/:id/email-templates/:type", view, emailTemplateHandler.Get)
+				protected.PUT("/events/:id/email-templates/:type", manageEvents, emailTemplateHandler.Save)
+				protected.GET("/events/:id/email-templates/:type/versions", view, emailTemplateHandler.Versions)
+				protected.POST("/events/:id/email-templates/:type/preview", manageEvents, emailTemplateHandler.Preview)
+				protected.POST("/events/:id/email-templates/:type/rollback", manageEvents, emailTemplateHandler.Rollback)
+
+				// Scheduled reminder and announcement emails per event
+				protected.GET("/events/:id/email-schedules", view, emailScheduleHandler.List)
+				protected.PUT("/events/:id/email-schedules/:type", manageEvents, emailScheduleHandler.Update)
+
+				// Email campaigns to segments of an event's participants
+				protected.GET("/events/:id/campaigns", view, emailCampaignHandler.List)
+				protected.POST("/events/:id/campaigns", manageEvents, emailCampaignHandler.Create)
+				protected.POST("/events/:id/campaigns/preview", manageEvents, emailCampaignHandler.Preview)
+				protected.GET("/campaigns/:id", view, emailCampaignHandler.Get)
+				protected.GET("/campaigns/:id/logs", view, emailCampaignHandler.Logs)
+
+				// Admin accounts and their roles
+				protected.GET("/admins", manageAdmins, adminUserHandler.List)
+				protected.POST("/admins", manageAdmins, adminUserHandler.Invite)
+				protected.PUT("/admins/:id/role", manageAdmins, adminUserHandler.UpdateRole)
+				protected.POST("/admins/:id/disable", manageAdmins, adminUserHandler.Disable)
+				protected.POST("/admins/:id/enable", manageAdmins, adminUserHandler.Enable)
+				protected.DELETE("/admins/:id", manageAdmins, adminUserHandler.Delete)
 			}
 		}
 	}
@@ -111,6 +343,28 @@ func main() {
 	utils.ServerLogger.Info("Public API: http://localhost:%s/api/v1/public", port)
 	utils.ServerLogger.Info("Admin API: http://localhost:%s/api/v1/admin", port)
 
+	// Expire unpaid waitlist offers and pass their spots on
+	background, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go waitlistService.Run(background, time.Minute)
+
+	// Queue payment reminders, race-kit pickup and race-day emails as they fall due
+	go emailScheduleService.Run(background, time.Minute)
+
+	// Delete expired admin refresh tokens and revoked access tokens
+	go sessionService.Run(background, time.Hour)
+
+	// Delete expired admin password reset links
+	go passwordService.Run(background, time.Hour)
+
+	// Deliver queued emails, then wait for password reset links still being sent
+	emailsDone := make(chan struct{})
+	go func() {
+		emailOutbox.Run(background, 5*time.Second)
+		passwordService.Wait()
+		close(emailsDone)
+	}()
+
 	// Graceful shutdown
 	go func() {
 		if err := router.Run(":" + port); err != nil {
@@ -124,5 +378,13 @@ func main() {
 	<-quit
 
 	utils.ServerLogger.Info("Shutting down server...")
+
+	// Let emails that are being sent finish; the rest stay queued for the next start
+	stopBackground()
+	select {
+	case <-emailsDone:
+	case <-time.After(30 * time.Second):
+		utils.EmailLogger.Warning("Timed out waiting for in-flight emails; they will be retried after restart")
+	}
 	fmt.Println("\n✅ Server shutdown complete")
 }
@@ -5,17 +5,26 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	SMTP     SMTPConfig
-	Event    EventConfig
-	CORS     CORSConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	JWT           JWTConfig
+	SMTP          SMTPConfig
+	Outbox        OutboxConfig
+	CORS          CORSConfig
+	Payment       PaymentConfig
+	Storage       StorageConfig
+	Waitlist      WaitlistConfig
+	CheckIn       CheckInConfig
+	Events        EventsConfig
+	Portal        PortalConfig
+	Transfer      TransferConfig
+	PasswordReset PasswordResetConfig
 }
 
 type ServerConfig struct {
@@ -35,30 +44,84 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret          string
-	ExpirationHours int
+	Secret             string
+	AccessTokenMinutes int // Lifetime of admin access tokens
+	RefreshTokenDays   int // Lifetime of a login session, renewed by each refresh
 }
 
 type SMTPConfig struct {
+	Driver   string // "smtp", "api", "file" or "memory"
 	Host     string
 	Port     string
 	Username string
 	Password string
+	TLS      string // "starttls", "tls" (implicit, usually port 465) or "none"
 	FromEmail string
 	FromName  string
+
+	APIURL string // Endpoint of the "api" driver
+	APIKey string
+	FileDir string // Directory the "file" driver writes .eml files to
+
+	// Addresses ("runner@example.com") and domains ("@example.com") that may
+	// receive real email outside production
+	AllowedRecipients []string
 }
 
-type EventConfig struct {
-	Name        string
-	Date        string
-	Location    string
-	Description string
+type OutboxConfig struct {
+	Workers      int // Emails delivered concurrently
+	MaxAttempts  int // Attempts before an email is dead-lettered
+	RetrySeconds int // Delay before the first retry, doubled after every further failure
+	CampaignRate int // Campaign emails sent per minute, so large campaigns don't trip provider limits
 }
 
 type CORSConfig struct {
 	AllowedOrigins []string
 }
 
+type StorageConfig struct {
+	Driver   string // Only "local" is supported for now
+	LocalDir string
+}
+
+type WaitlistConfig struct {
+	OfferHours int // How long a participant promoted from the waitlist has to pay
+}
+
+type CheckInConfig struct {
+	TokenSecret string // Signs the QR codes scanned at race-kit pickup and check-in
+}
+
+type EventsConfig struct {
+	Timezone string // IANA time zone of event dates and email schedules
+}
+
+type PortalConfig struct {
+	URL          string // Participant portal page that magic links open, with ?token= appended
+	LinkMinutes  int    // How long a magic link can be used to log in
+	SessionHours int    // How long a participant stays logged in
+}
+
+type PasswordResetConfig struct {
+	URL         string // Admin page that password reset links open, with ?token= appended
+	LinkMinutes int    // How long a password reset link can be used
+}
+
+type TransferConfig struct {
+	URL       string // Page where recipients accept a registration transfer, with ?token= appended
+	LinkHours int    // How long the recipient has to accept a transfer
+}
+
+type PaymentConfig struct {
+	Provider      string // "fake", "gateway", or empty to disable online payments
+	BaseURL       string
+	ServerKey     string
+	WebhookSecret string
+	Amount        int64 // In the smallest currency unit
+	Currency      string
+	SuccessURL    string
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	// Load .env file if it exists (ignore error if file doesn't exist)
@@ -80,26 +143,69 @@ func Load() (*Config, error) {
 			MaxIdleConns:   getEnvAsInt("DB_MAX_IDLE_CONNECTIONS", 5),
 		},
 		JWT: JWTConfig{
-			Secret:          getEnv("JWT_SECRET", ""),
-			ExpirationHours: getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
+			Secret:             getEnv("JWT_SECRET", ""),
+			AccessTokenMinutes: getEnvAsInt("JWT_ACCESS_TOKEN_MINUTES", 15),
+			RefreshTokenDays:   getEnvAsInt("JWT_REFRESH_TOKEN_DAYS", 30),
 		},
 		SMTP: SMTPConfig{
+			Driver:   getEnv("SMTP_DRIVER", defaultSMTPDriver(getEnv("ENV", "development"))),
 			Host:     getEnv("SMTP_HOST", ""),
 			Port:     getEnv("SMTP_PORT", "587"),
 			Username: getEnv("SMTP_USERNAME", ""),
 			Password: getEnv("SMTP_PASSWORD", ""),
+			TLS:      getEnv("SMTP_TLS", defaultSMTPTLS(getEnv("SMTP_PORT", "587"))),
 			FromEmail: getEnv("SMTP_FROM_EMAIL", "noreply@tautaurun.com"),
 			FromName:  getEnv("SMTP_FROM_NAME", "Tau-Tau Run Team"),
+			APIURL:    getEnv("SMTP_API_URL", ""),
+			APIKey:    getEnv("SMTP_API_KEY", ""),
+			FileDir:   getEnv("SMTP_FILE_DIR", "./mail"),
+			AllowedRecipients: getEnvAsList("SMTP_ALLOWED_RECIPIENTS"),
 		},
-		Event: EventConfig{
-			Name:        getEnv("EVENT_NAME", "Tau-Tau Run Fun Run 5K"),
-			Date:        getEnv("EVENT_DATE", "TBD"),
-			Location:    getEnv("EVENT_LOCATION", "TBD"),
-			Description: getEnv("EVENT_DESCRIPTION", "Join us for an exciting 5K fun run event!"),
+		Outbox: OutboxConfig{
+			Workers:      getEnvAsInt("EMAIL_OUTBOX_WORKERS", 4),
+			MaxAttempts:  getEnvAsInt("EMAIL_OUTBOX_MAX_ATTEMPTS", 8),
+			RetrySeconds: getEnvAsInt("EMAIL_OUTBOX_RETRY_SECONDS", 30),
+			CampaignRate: getEnvAsInt("EMAIL_CAMPAIGN_RATE_PER_MINUTE", 60),
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: strings.Split(getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000"), ","),
 		},
+		Payment: PaymentConfig{
+			Provider:      getEnv("PAYMENT_PROVIDER", ""),
+			BaseURL:       getEnv("PAYMENT_BASE_URL", ""),
+			ServerKey:     getEnv("PAYMENT_SERVER_KEY", ""),
+			WebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+			Amount:        int64(getEnvAsInt("PAYMENT_AMOUNT", 150000)),
+			Currency:      getEnv("PAYMENT_CURRENCY", "IDR"),
+			SuccessURL:    getEnv("PAYMENT_SUCCESS_URL", "http://localhost:3000"),
+		},
+		Storage: StorageConfig{
+			Driver:   getEnv("STORAGE_DRIVER", "local"),
+			LocalDir: getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+		},
+		Waitlist: WaitlistConfig{
+			OfferHours: getEnvAsInt("WAITLIST_OFFER_HOURS", 48),
+		},
+		CheckIn: CheckInConfig{
+			// Falls back to the JWT secret so existing deployments keep working
+			TokenSecret: getEnv("CHECKIN_TOKEN_SECRET", getEnv("JWT_SECRET", "")),
+		},
+		Events: EventsConfig{
+			Timezone: getEnv("EVENT_TIMEZONE", "Asia/Jakarta"),
+		},
+		Portal: PortalConfig{
+			URL:          getEnv("PORTAL_URL", "http://localhost:3000/portal"),
+			LinkMinutes:  getEnvAsInt("PORTAL_LINK_MINUTES", 15),
+			SessionHours: getEnvAsInt("PORTAL_SESSION_HOURS", 24),
+		},
+		Transfer: TransferConfig{
+			URL:       getEnv("TRANSFER_URL", "http://localhost:3000/transfer"),
+			LinkHours: getEnvAsInt("TRANSFER_LINK_HOURS", 72),
+		},
+		PasswordReset: PasswordResetConfig{
+			URL:         getEnv("PASSWORD_RESET_URL", "http://localhost:3000/admin/reset-password"),
+			LinkMinutes: getEnvAsInt("PASSWORD_RESET_LINK_MINUTES", 60),
+		},
 	}
 
 	// Validate required fields
@@ -124,8 +230,90 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("JWT_SECRET must be at least 32 characters long")
 	}
 
+	if c.JWT.AccessTokenMinutes < 1 || c.JWT.RefreshTokenDays < 1 {
+		return fmt.Errorf("JWT_ACCESS_TOKEN_MINUTES and JWT_REFRESH_TOKEN_DAYS must be at least 1")
+	}
+
+	switch c.Payment.Provider {
+	case "":
+		// Online payments disabled; payment status is only changed by admins
+	case "fake":
+		if c.IsProduction() {
+			return fmt.Errorf("PAYMENT_PROVIDER=fake is not allowed in production")
+		}
+	case "gateway":
+		if c.Payment.BaseURL == "" || c.Payment.ServerKey == "" {
+			return fmt.Errorf("PAYMENT_BASE_URL and PAYMENT_SERVER_KEY are required for the gateway payment provider")
+		}
+		if c.Payment.WebhookSecret == "" {
+			return fmt.Errorf("PAYMENT_WEBHOOK_SECRET is required for the gateway payment provider")
+		}
+	default:
+		return fmt.Errorf("PAYMENT_PROVIDER must be fake, gateway or empty")
+	}
+
+	if c.Waitlist.OfferHours < 1 {
+		return fmt.Errorf("WAITLIST_OFFER_HOURS must be at least 1")
+	}
+
+	if c.Outbox.Workers < 1 || c.Outbox.MaxAttempts < 1 || c.Outbox.RetrySeconds < 1 {
+		return fmt.Errorf("EMAIL_OUTBOX_WORKERS, EMAIL_OUTBOX_MAX_ATTEMPTS and EMAIL_OUTBOX_RETRY_SECONDS must be at least 1")
+	}
+
+	if c.Outbox.CampaignRate < 1 {
+		return fmt.Errorf("EMAIL_CAMPAIGN_RATE_PER_MINUTE must be at least 1")
+	}
+
+	if c.Portal.LinkMinutes < 1 || c.Portal.SessionHours < 1 {
+		return fmt.Errorf("PORTAL_LINK_MINUTES and PORTAL_SESSION_HOURS must be at least 1")
+	}
+
+	if c.Transfer.LinkHours < 1 {
+		return fmt.Errorf("TRANSFER_LINK_HOURS must be at least 1")
+	}
+
+	if c.PasswordReset.LinkMinutes < 1 {
+		return fmt.Errorf("PASSWORD_RESET_LINK_MINUTES must be at least 1")
+	}
+
+	if len(c.CheckIn.TokenSecret) < 32 {
+		return fmt.Errorf("CHECKIN_TOKEN_SECRET must be at least 32 characters long")
+	}
+
+	if _, err := time.LoadLocation(c.Events.Timezone); err != nil {
+		return fmt.Errorf("EVENT_TIMEZONE must be an IANA time zone such as Asia/Jakarta: %v", err)
+	}
+
+	switch c.SMTP.Driver {
+	case "smtp", "api":
+		// Outside production only allowlisted recipients may get real email
+		if !c.IsProduction() && len(c.SMTP.AllowedRecipients) == 0 {
+			return fmt.Errorf("SMTP_ALLOWED_RECIPIENTS is required for SMTP_DRIVER=%s outside production; use SMTP_DRIVER=file to keep emails local", c.SMTP.Driver)
+		}
+	case "file":
+		if c.SMTP.FileDir == "" {
+			return fmt.Errorf("SMTP_FILE_DIR is required for SMTP_DRIVER=file")
+		}
+	case "memory":
+		if c.IsProduction() {
+			return fmt.Errorf("SMTP_DRIVER=memory is not allowed in production")
+		}
+	default:
+		return fmt.Errorf("SMTP_DRIVER must be smtp, api, file or memory")
+	}
+
+	switch c.SMTP.TLS {
+	case "starttls", "tls", "none":
+	default:
+		return fmt.Errorf("SMTP_TLS must be starttls, tls or none")
+	}
+
+	if c.SMTP.Driver == "api" && c.SMTP.APIURL == "" {
+		return fmt.Errorf("SMTP_API_URL is required for SMTP_DRIVER=api")
+	}
+
 	// SMTP validation is optional (emails won't work but app will run)
-	if c.SMTP.Host == "" || c.SMTP.Username == "" || c.SMTP.Password == "" {
+	if c.SMTP.Driver == "smtp" && (c.SMTP.Host == "" || c.SMTP.Username == "" || c.SMTP.Password == "") {
 		fmt.Println("⚠️  WARNING: SMTP credentials not configured. Email sending will be disabled.")
 	}
 
@@ -142,6 +330,16 @@ func (c *Config) IsProduction() bool {
 	return c.Server.Env == "production"
 }
 
+// EventLocation returns the time zone of event dates and email schedules
+func (c *Config) EventLocation() *time.Location {
+	loc, err := time.LoadLocation(c.Events.Timezone)
+	if err != nil {
+		// Validate rejects unknown time zones, so this only happens to hand-built configs
+		return time.UTC
+	}
+	return loc
+}
+
 // DatabaseDSN returns the PostgreSQL connection string
 func (c *Config) DatabaseDSN() string {
 	return fmt.Sprintf(
@@ -163,6 +361,34 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvAsList gets a comma-separated environment variable as a list, skipping blank entries
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// defaultSMTPDriver sends real email only in production, so development and
+// staging keep emails on disk unless told otherwise
+func defaultSMTPDriver(env string) string {
+	if env == "production" {
+		return "smtp"
+	}
+	return "file"
+}
+
+// defaultSMTPTLS uses implicit TLS on the SMTPS port and STARTTLS elsewhere
+func defaultSMTPTLS(port string) string {
+	if port == "465" {
+		return "tls"
+	}
+	return "starttls"
+}
+
 // getEnvAsInt gets environment variable as integer with fallback
 func getEnvAsInt(key string, defaultValue int) int {
 	valueStr := getEnv(key, "")
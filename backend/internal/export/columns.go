@@ -0,0 +1,177 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// Column describes one exportable participant field
+type Column struct {
+	Key    string
+	Header string
+	Value  func(p *models.Participant) string
+}
+
+// ParticipantColumns lists every exportable column in output order
+var ParticipantColumns = []Column{
+	{Key: "id", Header: "ID", Value: func(p *models.Participant) string { return p.ID }},
+	{Key: "event_id", Header: "Event ID", Value: func(p *models.Participant) string { return p.EventID }},
+	{Key: "category_id", Header: "Category ID", Value: func(p *models.Participant) string {
+		if p.CategoryID == nil {
+			return ""
+		}
+		return *p.CategoryID
+	}},
+	{Key: "name", Header: "Name", Value: func(p *models.Participant) string { return p.Name }},
+	{Key: "email", Header: "Email", Value: func(p *models.Participant) string { return p.Email }},
+	{Key: "phone", Header: "Phone", Value: func(p *models.Participant) string { return p.Phone }},
+	{Key: "instagram_handle", Header: "Instagram", Value: func(p *models.Participant) string {
+		if p.InstagramHandle == nil {
+			return ""
+		}
+		return *p.InstagramHandle
+	}},
+	{Key: "address", Header: "Address", Value: func(p *models.Participant) string { return p.Address }},
+	{Key: "date_of_birth", Header: "Date of Birth", Value: func(p *models.Participant) string {
+		if p.DateOfBirth == nil {
+			return ""
+		}
+		return *p.DateOfBirth
+	}},
+	{Key: "registration_status", Header: "Registration Status", Value: func(p *models.Participant) string { return p.RegistrationStatus }},
+	{Key: "payment_status", Header: "Payment Status", Value: func(p *models.Participant) string { return p.PaymentStatus }},
+	{Key: "bib_number", Header: "Bib Number", Value: func(p *models.Participant) string {
+		if p.BibNumber == nil {
+			return ""
+		}
+		return strconv.Itoa(*p.BibNumber)
+	}},
+	{Key: "waitlist_position", Header: "Waitlist Position", Value: func(p *models.Participant) string {
+		if p.WaitlistPosition == nil {
+			return ""
+		}
+		return strconv.Itoa(*p.WaitlistPosition)
+	}},
+	{Key: "offer_expires_at", Header: "Offer Expires At", Value: func(p *models.Participant) string {
+		if p.OfferExpiresAt == nil {
+			return ""
+		}
+		return p.OfferExpiresAt.Format(time.RFC3339)
+	}},
+	{Key: "kit_collected_at", Header: "Race Kit Collected At", Value: func(p *models.Participant) string {
+		if p.KitCollectedAt == nil {
+			return ""
+		}
+		return p.KitCollectedAt.Format(time.RFC3339)
+	}},
+	{Key: "checked_in_at", Header: "Checked In At", Value: func(p *models.Participant) string {
+		if p.CheckedInAt == nil {
+			return ""
+		}
+		return p.CheckedInAt.Format(time.RFC3339)
+	}},
+	{Key: "cancelled_at", Header: "Cancelled At", Value: func(p *models.Participant) string {
+		if p.CancelledAt == nil {
+			return ""
+		}
+		return p.CancelledAt.Format(time.RFC3339)
+	}},
+	{Key: "cancellation_reason", Header: "Cancellation Reason", Value: func(p *models.Participant) string {
+		if p.CancellationReason == nil {
+			return ""
+		}
+		return *p.CancellationReason
+	}},
+	{Key: "refund_amount", Header: "Refund Amount", Value: func(p *models.Participant) string {
+		if p.RefundAmount == nil || p.RefundCurrency == nil {
+			return ""
+		}
+		return strconv.FormatInt(*p.RefundAmount, 10) + " " + *p.RefundCurrency
+	}},
+	{Key: "refunded_at", Header: "Refunded At", Value: func(p *models.Participant) string {
+		if p.RefundedAt == nil {
+			return ""
+		}
+		return p.RefundedAt.Format(time.RFC3339)
+	}},
+	{Key: "created_at", Header: "Registered At", Value: func(p *models.Participant) string { return p.CreatedAt.Format(time.RFC3339) }},
+	{Key: "updated_at", Header: "Updated At", Value: func(p *models.Participant) string { return p.UpdatedAt.Format(time.RFC3339) }},
+}
+
+// ColumnKeys returns the keys of all exportable columns
+func ColumnKeys() []string {
+	keys := make([]string, len(ParticipantColumns))
+	for i, col := range ParticipantColumns {
+		keys[i] = col.Key
+	}
+	return keys
+}
+
+// SelectColumns returns the requested columns in the canonical order of
+// ParticipantColumns, regardless of the order they were requested in.
+// An empty selection returns every column.
+func SelectColumns(keys []string) ([]Column, error) {
+	if len(keys) == 0 {
+		return ParticipantColumns, nil
+	}
+
+	requested := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		requested[key] = true
+	}
+
+	var selected []Column
+	for _, col := range ParticipantColumns {
+		if requested[col.Key] {
+			selected = append(selected, col)
+			delete(requested, col.Key)
+		}
+	}
+
+	for key := range requested {
+		return nil, fmt.Errorf("unknown column %q", key)
+	}
+
+	if len(selected) == 0 {
+		return ParticipantColumns, nil
+	}
+
+	return selected, nil
+}
+
+// Headers returns the header row for columns
+func Headers(columns []Column) []string {
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.Header
+	}
+	return headers
+}
+
+// Row returns the values of columns for p. Values are supplied by the public
+// registration form, so any that a spreadsheet would run as a formula are
+// made plain text.
+func Row(columns []Column, p *models.Participant) []string {
+	row := make([]string, len(columns))
+	for i, col := range columns {
+		row[i] = plainText(col.Value(p))
+	}
+	return row
+}
+
+// plainText prefixes value with an apostrophe if it starts with a character
+// that makes spreadsheet applications read it as a formula
+func plainText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
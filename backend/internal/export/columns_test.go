@@ -0,0 +1,117 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+func TestSelectColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		wantKeys []string
+		wantErr  bool
+	}{
+		{name: "no selection", keys: nil, wantKeys: ColumnKeys()},
+		{name: "blank keys only", keys: []string{" ", ""}, wantKeys: ColumnKeys()},
+		{name: "canonical order", keys: []string{"email", "name"}, wantKeys: []string{"name", "email"}},
+		{name: "case and spaces", keys: []string{" Payment_Status ", "ID"}, wantKeys: []string{"id", "payment_status"}},
+		{name: "duplicates", keys: []string{"name", "name"}, wantKeys: []string{"name"}},
+		{name: "unknown column", keys: []string{"name", "password"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := SelectColumns(tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectColumns() error = %v, want error %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var keys []string
+			for _, col := range columns {
+				keys = append(keys, col.Key)
+			}
+			if strings.Join(keys, ",") != strings.Join(tt.wantKeys, ",") {
+				t.Errorf("SelectColumns() = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestRow(t *testing.T) {
+	registered := time.Date(2026, 10, 17, 8, 30, 0, 0, time.UTC)
+	instagram := "budi_runs"
+
+	columns, err := SelectColumns([]string{"name", "instagram_handle", "payment_status", "created_at"})
+	if err != nil {
+		t.Fatalf("SelectColumns() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		p    *models.Participant
+		want []string
+	}{
+		{
+			name: "all values",
+			p:    &models.Participant{Name: "Budi", InstagramHandle: &instagram, PaymentStatus: "PAID", CreatedAt: registered},
+			want: []string{"Budi", "budi_runs", "PAID", "2026-10-17T08:30:00Z"},
+		},
+		{
+			name: "no instagram handle",
+			p:    &models.Participant{Name: "Citra", PaymentStatus: "UNPAID", CreatedAt: registered},
+			want: []string{"Citra", "", "UNPAID", "2026-10-17T08:30:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Row(columns, tt.p)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Row() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := Headers(columns); strings.Join(got, "|") != "Name|Instagram|Payment Status|Registered At" {
+		t.Errorf("Headers() = %q", got)
+	}
+}
+
+func TestRowFormulas(t *testing.T) {
+	columns, err := SelectColumns([]string{"name", "phone", "address"})
+	if err != nil {
+		t.Fatalf("SelectColumns() error = %v", err)
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: `=HYPERLINK("https://example.com","Budi")`, want: `'=HYPERLINK("https://example.com","Budi")`},
+		{value: "+62812345678", want: "'+62812345678"},
+		{value: "-1+2", want: "'-1+2"},
+		{value: "@SUM(A1:A2)", want: "'@SUM(A1:A2)"},
+		{value: "\t=1+2", want: "'\t=1+2"},
+		{value: "\r=1+2", want: "'\r=1+2"},
+		{value: "Jl. Sudirman = 1", want: "Jl. Sudirman = 1"},
+		{value: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := Row(columns, &models.Participant{Name: tt.value, Phone: tt.value, Address: tt.value})
+			for i, value := range got {
+				if value != tt.want {
+					t.Errorf("Row()[%d] = %q, want %q", i, value, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+var testRecords = [][]string{
+	{"Name", "Address"},
+	{"Budi", `Jalan "Merdeka", No. 1`},
+	{"Citra & <Dewi>", "Line one\nLine two"},
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writeRecords(t, FormatCSV, &buf)
+
+	got, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	assertRecords(t, got)
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writeRecords(t, FormatXLSX, &buf)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to open workbook: %v", err)
+	}
+
+	var sheet io.ReadCloser
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			if sheet, err = f.Open(); err != nil {
+				t.Fatalf("failed to open worksheet: %v", err)
+			}
+		}
+	}
+	if sheet == nil {
+		t.Fatal("workbook has no worksheet")
+	}
+	defer sheet.Close()
+
+	var doc struct {
+		Rows []struct {
+			Ref   string `xml:"r,attr"`
+			Cells []struct {
+				Ref  string `xml:"r,attr"`
+				Text string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.NewDecoder(sheet).Decode(&doc); err != nil {
+		t.Fatalf("failed to parse worksheet: %v", err)
+	}
+
+	var got [][]string
+	for i, row := range doc.Rows {
+		var record []string
+		for j, cell := range row.Cells {
+			if want := columnName(j) + row.Ref; cell.Ref != want {
+				t.Errorf("row %d cell %d reference = %s, want %s", i, j, cell.Ref, want)
+			}
+			record = append(record, cell.Text)
+		}
+		got = append(got, record)
+	}
+	assertRecords(t, got)
+}
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 1: "B", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for i, want := range tests {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestNewWriterUnsupportedFormat(t *testing.T) {
+	if _, err := NewWriter("pdf", io.Discard); err == nil {
+		t.Error("NewWriter(pdf) error = nil, want an error")
+	}
+}
+
+// writeRecords writes testRecords to w in format
+func writeRecords(t *testing.T, format string, w io.Writer) {
+	t.Helper()
+
+	writer, err := NewWriter(format, w)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for _, record := range testRecords {
+		if err := writer.Write(record); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+// assertRecords checks that got reads back as testRecords
+func assertRecords(t *testing.T, got [][]string) {
+	t.Helper()
+
+	if len(got) != len(testRecords) {
+		t.Fatalf("got %d rows, want %d", len(got), len(testRecords))
+	}
+	for i := range testRecords {
+		if strings.Join(got[i], "|") != strings.Join(testRecords[i], "|") {
+			t.Errorf("row %d = %q, want %q", i, got[i], testRecords[i])
+		}
+	}
+}
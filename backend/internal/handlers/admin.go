@@ -1,27 +1,52 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/tau-tau-run/backend/internal/middleware"
 	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
 	"github.com/tau-tau-run/backend/internal/services"
 	"github.com/tau-tau-run/backend/internal/utils"
 )
 
 // AdminHandler handles admin-related requests
 type AdminHandler struct {
-	authService  *services.AuthService
-	emailService *services.EmailService
+	validator           *utils.Validator
+	authService         *services.AuthService
+	sessionService      *services.SessionService
+	paymentService      *services.PaymentService
+	eventService        *services.EventService
+	registrationService *services.RegistrationService
+	admins              repository.AdminRepository
+	participants        repository.ParticipantRepository
+	tx                  repository.Transactor
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(authService *services.AuthService, emailService *services.EmailService) *AdminHandler {
+func NewAdminHandler(
+	authService *services.AuthService,
+	sessionService *services.SessionService,
+	paymentService *services.PaymentService,
+	eventService *services.EventService,
+	registrationService *services.RegistrationService,
+	admins repository.AdminRepository,
+	participants repository.ParticipantRepository,
+	tx repository.Transactor,
+) *AdminHandler {
 	return &AdminHandler{
-		authService:  authService,
-		emailService: emailService,
+		validator:           utils.NewValidator(),
+		authService:         authService,
+		sessionService:      sessionService,
+		paymentService:      paymentService,
+		eventService:        eventService,
+		registrationService: registrationService,
+		admins:              admins,
+		participants:        participants,
+		tx:                  tx,
 	}
 }
 
@@ -39,7 +64,7 @@ func (h *AdminHandler) Login(c *gin.Context) {
 	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
 
 	// Find admin by email
-	admin, err := models.FindAdminByEmail(req.Email)
+	admin, err := h.admins.FindByEmail(c.Request.Context(), req.Email)
 	if err != nil {
 		utils.AuthLogger.Error("Failed to find admin: %v", err)
 		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
@@ -60,8 +85,15 @@ func (h *AdminHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, expiresAt, err := h.authService.GenerateToken(admin.ID, admin.Email)
+	// Disabled admins keep their password but can no longer log in
+	if admin.DisabledAt != nil {
+		utils.AuthLogger.Warning("Login attempt by disabled admin: %s", req.Email)
+		middleware.RespondWithError(c, http.StatusForbidden, "ACCOUNT_DISABLED", "This admin account has been disabled", nil)
+		return
+	}
+
+	// Start a session with an access and refresh token
+	session, err := h.sessionService.Start(c.Request.Context(), admin)
 	if err != nil {
 		utils.AuthLogger.Error("Failed to generate token for admin %s: %v", admin.Email, err)
 		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to generate authentication token", nil)
@@ -69,39 +101,40 @@ func (h *AdminHandler) Login(c *gin.Context) {
 	}
 
 	// Log successful login
-	utils.AuthLogger.Info("Admin logged in: %s", admin.Email)
+	utils.AuthLogger.Info("Admin logged in: %s (%s)", admin.Email, admin.Role)
 
 	// Return success response
-	middleware.RespondWithSuccess(c, http.StatusOK, "Login successful", models.LoginResponse{
-		Token: token,
-		Admin: models.AdminInfo{
-			ID:    admin.ID,
-			Email: admin.Email,
-		},
-		ExpiresAt: expiresAt,
-	})
+	middleware.RespondWithSuccess(c, http.StatusOK, "Login successful", session)
 }
 
-// GetParticipants returns all participants (protected route)
+// GetParticipants returns a filtered, sorted page of participants (protected route)
 func (h *AdminHandler) GetParticipants(c *gin.Context) {
 	// Get admin info from context (set by auth middleware)
 	adminEmail := middleware.GetAdminEmail(c)
 	utils.AuthLogger.Info("Admin %s requested participant list", adminEmail)
 
-	// Get all participants
-	participants, err := models.GetAllParticipants()
+	query, validationErrors := parseParticipantQuery(c)
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid query parameters", validationErrors)
+		return
+	}
+
+	participants, total, err := h.participants.List(c.Request.Context(), query)
 	if err != nil {
 		utils.DBLogger.Error("Failed to get participants: %v", err)
 		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to retrieve participants", nil)
 		return
 	}
 
+	totalPages := (total + query.Limit - 1) / query.Limit
+
 	// Return success response
 	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
 		"participants": participants,
-		"total":        len(participants),
-		"page":         1,
-		"limit":        len(participants),
+		"total":        total,
+		"page":         query.Page,
+		"limit":        query.Limit,
+		"total_pages":  totalPages,
 	})
 }
 
@@ -127,52 +160,46 @@ func (h *AdminHandler) UpdatePaymentStatus(c *gin.Context) {
 		return
 	}
 
-	// Find participant
-	participant, err := models.FindParticipantByID(participantID)
-	if err != nil {
-		utils.DBLogger.Error("Failed to find participant: %v", err)
-		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
-		return
+	err := services.ErrParticipantNotFound
+	var change *services.PaymentStatusChange
+	if isValidID(participantID) {
+		change, err = h.paymentService.UpdateStatus(c.Request.Context(), participantID, req.PaymentStatus)
 	}
-
-	if participant == nil {
+	if errors.Is(err, services.ErrParticipantNotFound) {
 		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "Participant with the specified ID does not exist", gin.H{
 			"id": participantID,
 		})
 		return
 	}
-
-	// Store old status for email trigger logic
-	oldStatus := participant.PaymentStatus
-
-	// Update payment status
-	if err := participant.UpdatePaymentStatus(req.PaymentStatus); err != nil {
+	if errors.Is(err, services.ErrCancelled) {
+		middleware.RespondWithError(c, http.StatusConflict, "REGISTRATION_CANCELLED", "Registration has been cancelled. Use the refund endpoint instead.", nil)
+		return
+	}
+	if errors.Is(err, services.ErrWaitlisted) {
+		middleware.RespondWithError(c, http.StatusConflict, "WAITLISTED", "Registration is on the waitlist and has no spot to pay for yet", nil)
+		return
+	}
+	if errors.Is(err, services.ErrOfferExpired) {
+		middleware.RespondWithError(c, http.StatusConflict, "OFFER_EXPIRED", "The spot offered to this registration has expired", nil)
+		return
+	}
+	if err != nil {
 		utils.DBLogger.Error("Failed to update payment status: %v", err)
 		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to update payment status", nil)
 		return
 	}
 
+	participant := change.Participant
+
 	// Log the update
 	utils.AuthLogger.Info("Admin %s updated participant %s payment status: %s → %s",
-		adminEmail, participant.Email, oldStatus, req.PaymentStatus)
-
-	// Trigger email if UNPAID → PAID (idempotency check)
-	emailSent := false
-	if oldStatus == "UNPAID" && req.PaymentStatus == "PAID" {
-		utils.EmailLogger.Info("Payment status changed to PAID for %s - triggering confirmation email", participant.Email)
-		
-		// Send email asynchronously (non-blocking)
-		h.emailService.SendConfirmationEmailAsync(participant)
-		emailSent = true
-	} else if oldStatus == "PAID" && req.PaymentStatus == "PAID" {
-		utils.EmailLogger.Info("Payment status already PAID for %s - skipping duplicate email", participant.Email)
-	}
+		adminEmail, participant.Email, change.OldStatus, req.PaymentStatus)
 
 	// Return success response
 	middleware.RespondWithSuccess(c, http.StatusOK, "Payment status updated successfully", gin.H{
 		"id":             participant.ID,
 		"payment_status": participant.PaymentStatus,
 		"updated_at":     participant.UpdatedAt,
-		"email_sent":     emailSent,
+		"email_sent":     change.EmailSent,
 	})
 }
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminHandlerUpdatePaymentStatusMalformedID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The ID is refused before it reaches the payment service or the database
+	router := gin.New()
+	router.PATCH("/participants/:id/payment", NewAdminHandler(nil, nil, nil, nil, nil, nil, nil, nil).UpdatePaymentStatus)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/participants/1%20OR%201=1/payment", strings.NewReader(`{"payment_status":"PAID"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusNotFound, w.Body)
+	}
+	if !strings.Contains(w.Body.String(), `"code":"PARTICIPANT_NOT_FOUND"`) {
+		t.Errorf("body = %s, want error code PARTICIPANT_NOT_FOUND", w.Body)
+	}
+}
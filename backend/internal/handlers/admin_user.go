@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// AdminUserHandler handles requests to manage admin accounts
+type AdminUserHandler struct {
+	validator    *utils.Validator
+	adminService *services.AdminService
+}
+
+// NewAdminUserHandler creates a new admin account handler
+func NewAdminUserHandler(adminService *services.AdminService) *AdminUserHandler {
+	return &AdminUserHandler{
+		validator:    utils.NewValidator(),
+		adminService: adminService,
+	}
+}
+
+// List returns every admin with their role (protected route)
+func (h *AdminUserHandler) List(c *gin.Context) {
+	admins, err := h.adminService.List(c.Request.Context())
+	if respondAdminError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Admins retrieved successfully", admins)
+}
+
+// Invite adds an admin with an initial password (protected route)
+func (h *AdminUserHandler) Invite(c *gin.Context) {
+	var req models.InviteAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
+	req.Role = strings.ToUpper(strings.TrimSpace(req.Role))
+
+	if err := h.validator.ValidateEmail(req.Email); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "email", Message: err.Error()},
+		})
+		return
+	}
+
+	admin, err := h.adminService.Create(c.Request.Context(), req.Email, req.Password, req.Role, middleware.GetAdminID(c))
+	if respondAdminError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s invited %s as %s", middleware.GetAdminEmail(c), admin.Email, admin.Role)
+
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Admin invited successfully", admin)
+}
+
+// UpdateRole gives an admin another role (protected route)
+func (h *AdminUserHandler) UpdateRole(c *gin.Context) {
+	var req models.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	adminID := c.Param("id")
+	role := strings.ToUpper(strings.TrimSpace(req.Role))
+
+	err := services.ErrAdminNotFound
+	var admin *models.Admin
+	if isValidID(adminID) {
+		admin, err = h.adminService.UpdateRole(c.Request.Context(), adminID, role)
+	}
+	if respondAdminError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s set the role of %s to %s", middleware.GetAdminEmail(c), admin.Email, admin.Role)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Role updated successfully", admin)
+}
+
+// Disable stops an admin from logging in and using the API (protected route)
+func (h *AdminUserHandler) Disable(c *gin.Context) {
+	adminID := c.Param("id")
+
+	err := services.ErrAdminNotFound
+	var admin *models.Admin
+	if isValidID(adminID) {
+		admin, err = h.adminService.Disable(c.Request.Context(), adminID, middleware.GetAdminID(c))
+	}
+	if respondAdminError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s disabled admin %s", middleware.GetAdminEmail(c), admin.Email)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Admin disabled successfully", admin)
+}
+
+// Enable lets a disabled admin log in again (protected route)
+func (h *AdminUserHandler) Enable(c *gin.Context) {
+	adminID := c.Param("id")
+
+	err := services.ErrAdminNotFound
+	var admin *models.Admin
+	if isValidID(adminID) {
+		admin, err = h.adminService.Enable(c.Request.Context(), adminID)
+	}
+	if respondAdminError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s enabled admin %s", middleware.GetAdminEmail(c), admin.Email)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Admin enabled successfully", admin)
+}
+
+// Delete removes an admin for good (protected route)
+func (h *AdminUserHandler) Delete(c *gin.Context) {
+	adminID := c.Param("id")
+
+	err := services.ErrAdminNotFound
+	var admin *models.Admin
+	if isValidID(adminID) {
+		admin, err = h.adminService.Delete(c.Request.Context(), adminID, middleware.GetAdminID(c))
+	}
+	if respondAdminError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s deleted admin %s", middleware.GetAdminEmail(c), admin.Email)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Admin deleted successfully", gin.H{
+		"id": admin.ID,
+	})
+}
+
+// respondAdminError writes the response for an admin account error, reporting whether there was one
+func respondAdminError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrInvalidRole):
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "role", Message: "role must be OWNER, FINANCE, CHECK_IN or VIEWER"},
+		})
+	case errors.As(err, new(*services.WeakPasswordError)):
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "password", Message: err.Error()},
+		})
+	case errors.Is(err, services.ErrAdminNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "ADMIN_NOT_FOUND", "Admin does not exist", nil)
+	case errors.Is(err, services.ErrLastOwner):
+		middleware.RespondWithError(c, http.StatusConflict, "LAST_OWNER", "The last active owner cannot be given another role, disabled or deleted. Make another admin an owner first.", nil)
+	case errors.Is(err, services.ErrOwnAccount):
+		middleware.RespondWithError(c, http.StatusConflict, "OWN_ACCOUNT", "You cannot disable or delete your own account", nil)
+	case errors.Is(err, repository.ErrDuplicateEmail):
+		middleware.RespondWithError(c, http.StatusConflict, "DUPLICATE_EMAIL", "Email address already belongs to an admin", nil)
+	default:
+		utils.DBLogger.Error("Admin request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// maxBibNumber bounds bib numbers and reservations to what fits on a race bib
+const maxBibNumber = 99999
+
+// BibHandler handles bib number requests
+type BibHandler struct {
+	validator  *utils.Validator
+	bibService *services.BibService
+}
+
+// NewBibHandler creates a new bib handler
+func NewBibHandler(bibService *services.BibService) *BibHandler {
+	return &BibHandler{
+		validator:  utils.NewValidator(),
+		bibService: bibService,
+	}
+}
+
+// ListReservations returns the bib reservations of an event (protected route)
+func (h *BibHandler) ListReservations(c *gin.Context) {
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	var reservations []models.BibReservation
+	if isValidID(eventID) {
+		reservations, err = h.bibService.ListReservations(c.Request.Context(), eventID)
+	}
+	if respondBibError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"reservations": reservations,
+	})
+}
+
+// CreateReservation holds a range of bib numbers back from automatic assignment (protected route)
+func (h *BibHandler) CreateReservation(c *gin.Context) {
+	var req models.BibReservationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	req.Label = h.validator.SanitizeString(req.Label)
+	if req.CategoryID != nil {
+		trimmed := strings.TrimSpace(*req.CategoryID)
+		req.CategoryID = &trimmed
+		if trimmed == "" {
+			req.CategoryID = nil
+		}
+	}
+
+	var validationErrors []utils.ValidationError
+	if req.Label == "" || len(req.Label) > 100 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "label", Message: "label is required and must be at most 100 characters"})
+	}
+	if req.RangeStart < 1 || req.RangeStart > maxBibNumber {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "range_start", Message: "range_start must be between 1 and 99999"})
+	}
+	if req.RangeEnd < req.RangeStart || req.RangeEnd > maxBibNumber {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "range_end", Message: "range_end must be between range_start and 99999"})
+	}
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
+		return
+	}
+
+	reservation := &models.BibReservation{
+		EventID:    c.Param("id"),
+		CategoryID: req.CategoryID,
+		RangeStart: req.RangeStart,
+		RangeEnd:   req.RangeEnd,
+		Label:      req.Label,
+	}
+
+	err := services.ErrEventNotFound
+	if isValidID(reservation.EventID) {
+		err = services.ErrCategoryNotFound
+		if reservation.CategoryID == nil || isValidID(*reservation.CategoryID) {
+			err = h.bibService.Reserve(c.Request.Context(), reservation)
+		}
+	}
+	if respondBibError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s reserved bib numbers %d-%d (%s) for event %s",
+		middleware.GetAdminEmail(c), reservation.RangeStart, reservation.RangeEnd, reservation.Label, reservation.EventID)
+
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Bib numbers reserved successfully", reservation)
+}
+
+// DeleteReservation returns a reserved range to automatic assignment (protected route)
+func (h *BibHandler) DeleteReservation(c *gin.Context) {
+	reservationID := c.Param("id")
+
+	err := services.ErrBibReservationNotFound
+	if isValidID(reservationID) {
+		err = h.bibService.DeleteReservation(c.Request.Context(), reservationID)
+	}
+	if respondBibError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s deleted bib reservation %s", middleware.GetAdminEmail(c), reservationID)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Bib reservation deleted successfully", gin.H{
+		"id": reservationID,
+	})
+}
+
+// Reassign sets or clears a participant's bib number by hand (protected route)
+func (h *BibHandler) Reassign(c *gin.Context) {
+	participantID := c.Param("id")
+
+	var req struct {
+		BibNumber *int `json:"bib_number"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+	if req.BibNumber != nil && (*req.BibNumber < 1 || *req.BibNumber > maxBibNumber) {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "bib_number", Message: "bib_number must be between 1 and 99999, or null to remove it"},
+		})
+		return
+	}
+
+	err := services.ErrParticipantNotFound
+	var participant *models.Participant
+	if isValidID(participantID) {
+		participant, err = h.bibService.Reassign(c.Request.Context(), participantID, req.BibNumber)
+	}
+	if respondBibError(c, err) {
+		return
+	}
+
+	if participant.BibNumber == nil {
+		utils.AuthLogger.Info("Admin %s removed the bib number of %s", middleware.GetAdminEmail(c), participant.Email)
+	} else {
+		utils.AuthLogger.Info("Admin %s assigned bib number %d to %s", middleware.GetAdminEmail(c), *participant.BibNumber, participant.Email)
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Bib number updated successfully", participant)
+}
+
+// respondBibError writes the error response for a failed bib request and reports whether it did
+func respondBibError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrEventNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "EVENT_NOT_FOUND", "Event with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrCategoryNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "CATEGORY_NOT_FOUND", "Race category does not exist for this event", nil)
+	case errors.Is(err, services.ErrParticipantNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "Participant with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrBibReservationNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "BIB_RESERVATION_NOT_FOUND", "Bib reservation with the specified ID does not exist", nil)
+	case errors.Is(err, repository.ErrDuplicateBibNumber):
+		middleware.RespondWithError(c, http.StatusConflict, "BIB_NUMBER_TAKEN", "Another participant in this race category already has this bib number", nil)
+	default:
+		utils.DBLogger.Error("Bib request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// CancellationHandler handles admin cancellation and refund requests
+type CancellationHandler struct {
+	validator           *utils.Validator
+	cancellationService *services.CancellationService
+}
+
+// NewCancellationHandler creates a new cancellation handler
+func NewCancellationHandler(cancellationService *services.CancellationService) *CancellationHandler {
+	return &CancellationHandler{
+		validator:           utils.NewValidator(),
+		cancellationService: cancellationService,
+	}
+}
+
+// Cancel cancels a registration, optionally overriding the refund policy (protected route)
+func (h *CancellationHandler) Cancel(c *gin.Context) {
+	req, ok := bindCancelRequest(c, h.validator, true)
+	if !ok {
+		return
+	}
+
+	participantID := c.Param("id")
+
+	err := services.ErrParticipantNotFound
+	var participant *models.Participant
+	if isValidID(participantID) {
+		participant, err = h.cancellationService.Cancel(c.Request.Context(), participantID, req.Reason, middleware.GetAdminID(c), req.RefundPercent)
+	}
+	if respondCancellationError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s cancelled the registration of %s (%s)", middleware.GetAdminEmail(c), participant.Email, participant.ID)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Registration cancelled successfully", participant)
+}
+
+// Refund records that the refund owed for a cancelled registration was sent (protected route)
+func (h *CancellationHandler) Refund(c *gin.Context) {
+	participantID := c.Param("id")
+
+	err := services.ErrParticipantNotFound
+	var participant *models.Participant
+	if isValidID(participantID) {
+		participant, err = h.cancellationService.Refund(c.Request.Context(), participantID, middleware.GetAdminID(c))
+	}
+	if respondCancellationError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s recorded the refund of %s (%s)", middleware.GetAdminEmail(c), participant.Email, participant.ID)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Refund recorded successfully", participant)
+}
+
+// bindCancelRequest reads and validates a cancellation, writing the error
+// response if it is invalid. refund_percent is rejected unless allowRefundPercent.
+func bindCancelRequest(c *gin.Context, v *utils.Validator, allowRefundPercent bool) (models.CancelRequest, bool) {
+	var req models.CancelRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return req, false
+	}
+
+	req.Reason = v.SanitizeString(req.Reason)
+
+	var validationErrors []utils.ValidationError
+	if req.Reason == "" || len(req.Reason) > 500 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "reason", Message: "reason is required and must be at most 500 characters"})
+	}
+	if req.RefundPercent != nil {
+		switch {
+		case !allowRefundPercent:
+			validationErrors = append(validationErrors, utils.ValidationError{Field: "refund_percent", Message: "refund_percent can only be set by admins"})
+		case *req.RefundPercent < 0 || *req.RefundPercent > 100:
+			validationErrors = append(validationErrors, utils.ValidationError{Field: "refund_percent", Message: "refund_percent must be between 0 and 100"})
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
+		return req, false
+	}
+
+	return req, true
+}
+
+// respondCancellationError writes the response for a cancellation error, reporting whether there was one
+func respondCancellationError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrParticipantNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "Registration does not exist", nil)
+	case errors.Is(err, services.ErrCancelled):
+		middleware.RespondWithError(c, http.StatusConflict, "REGISTRATION_CANCELLED", "Registration is already cancelled", nil)
+	case errors.Is(err, services.ErrAlreadyCheckedIn):
+		middleware.RespondWithError(c, http.StatusConflict, "ALREADY_CHECKED_IN", "Participant has already checked in and cannot cancel", nil)
+	case errors.Is(err, services.ErrCancellationClosed):
+		middleware.RespondWithError(c, http.StatusConflict, "CANCELLATION_CLOSED", "Registrations can no longer be cancelled online. Please contact the organizers.", nil)
+	case errors.Is(err, services.ErrNoRefundPending):
+		middleware.RespondWithError(c, http.StatusConflict, "NO_REFUND_PENDING", "No refund is pending for this registration", nil)
+	case errors.Is(err, services.ErrCategoryNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "CATEGORY_NOT_FOUND", "Race category of this registration no longer exists", nil)
+	default:
+		utils.DBLogger.Error("Cancellation request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
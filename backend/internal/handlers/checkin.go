@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// CheckInHandler handles QR code scans at race-kit pickup and race-day check-in
+type CheckInHandler struct {
+	checkInService *services.CheckInService
+}
+
+// NewCheckInHandler creates a new check-in handler
+func NewCheckInHandler(checkInService *services.CheckInService) *CheckInHandler {
+	return &CheckInHandler{checkInService: checkInService}
+}
+
+// scanRequest is the body of a QR code scan. EventID, when set, rejects
+// participants of other events.
+type scanRequest struct {
+	Token   string `json:"token"`
+	EventID string `json:"event_id"`
+}
+
+// Verify shows the participant a scanned QR code belongs to without recording anything (protected route)
+func (h *CheckInHandler) Verify(c *gin.Context) {
+	req, ok := bindScanRequest(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.checkInService.Verify(c.Request.Context(), req.Token, req.EventID)
+	if respondCheckInError(c, status, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", status)
+}
+
+// CollectKit records a race-kit pickup (protected route)
+func (h *CheckInHandler) CollectKit(c *gin.Context) {
+	req, ok := bindScanRequest(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.checkInService.CollectKit(c.Request.Context(), req.Token, req.EventID, middleware.GetAdminID(c))
+	if respondCheckInError(c, status, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s handed out the race kit of %s", middleware.GetAdminEmail(c), status.Participant.Email)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Race kit collected", status)
+}
+
+// CheckIn records a race-day check-in (protected route)
+func (h *CheckInHandler) CheckIn(c *gin.Context) {
+	req, ok := bindScanRequest(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.checkInService.CheckIn(c.Request.Context(), req.Token, req.EventID, middleware.GetAdminID(c))
+	if respondCheckInError(c, status, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s checked in %s", middleware.GetAdminEmail(c), status.Participant.Email)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Participant checked in", status)
+}
+
+// QRCode returns the check-in QR code of a paid participant as a PNG image (protected route)
+func (h *CheckInHandler) QRCode(c *gin.Context) {
+	participantID := c.Param("id")
+
+	err := services.ErrParticipantNotFound
+	var png []byte
+	if isValidID(participantID) {
+		png, err = h.checkInService.ParticipantQRCode(c.Request.Context(), participantID)
+	}
+	if respondCheckInError(c, nil, err) {
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// bindScanRequest parses and validates a scan request, writing the error
+// response and returning false if it is invalid
+func bindScanRequest(c *gin.Context) (scanRequest, bool) {
+	var req scanRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return req, false
+	}
+
+	req.Token = strings.TrimSpace(req.Token)
+	req.EventID = strings.TrimSpace(req.EventID)
+
+	var validationErrors []utils.ValidationError
+	if req.Token == "" {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "token", Message: "token is required"})
+	}
+	if req.EventID != "" && !isValidID(req.EventID) {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "event_id", Message: "event_id must be a valid ID"})
+	}
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
+		return req, false
+	}
+
+	return req, true
+}
+
+// respondCheckInError writes the error response for a failed check-in request
+// and reports whether it did. Double scans include the status recorded by the
+// first scan.
+func respondCheckInError(c *gin.Context, status *services.CheckInStatus, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrInvalidCheckInToken):
+		middleware.RespondWithError(c, http.StatusBadRequest, "INVALID_QR_CODE", "QR code is not a valid check-in code", nil)
+	case errors.Is(err, services.ErrParticipantNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "Participant with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrWrongEvent):
+		middleware.RespondWithError(c, http.StatusConflict, "WRONG_EVENT", "Participant is registered for another event", nil)
+	case errors.Is(err, services.ErrCancelled):
+		middleware.RespondWithError(c, http.StatusConflict, "REGISTRATION_CANCELLED", "Participant's registration has been cancelled", nil)
+	case errors.Is(err, services.ErrNotPaid):
+		middleware.RespondWithError(c, http.StatusConflict, "NOT_PAID", "Participant has not paid", nil)
+	case errors.Is(err, services.ErrKitAlreadyCollected):
+		middleware.RespondWithError(c, http.StatusConflict, "KIT_ALREADY_COLLECTED", "Race kit was already collected", status)
+	case errors.Is(err, services.ErrAlreadyCheckedIn):
+		middleware.RespondWithError(c, http.StatusConflict, "ALREADY_CHECKED_IN", "Participant has already checked in", status)
+	default:
+		utils.DBLogger.Error("Check-in request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// EmailCampaignHandler handles email campaign requests
+type EmailCampaignHandler struct {
+	campaignService *services.EmailCampaignService
+}
+
+// NewEmailCampaignHandler creates a new email campaign handler
+func NewEmailCampaignHandler(campaignService *services.EmailCampaignService) *EmailCampaignHandler {
+	return &EmailCampaignHandler{campaignService: campaignService}
+}
+
+// List returns the campaigns of an event, newest first (protected route)
+func (h *EmailCampaignHandler) List(c *gin.Context) {
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	var campaigns []models.EmailCampaign
+	if isValidID(eventID) {
+		campaigns, err = h.campaignService.List(c.Request.Context(), eventID)
+	}
+	if respondEmailCampaignError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"campaigns": campaigns,
+	})
+}
+
+// Preview counts the recipients of a campaign and renders it for one of them (protected route)
+func (h *EmailCampaignHandler) Preview(c *gin.Context) {
+	req, ok := bindEmailCampaignRequest(c)
+	if !ok {
+		return
+	}
+
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	var preview *models.CampaignPreview
+	if isValidID(eventID) {
+		preview, err = h.campaignService.Preview(c.Request.Context(), eventID, req)
+	}
+	if respondEmailCampaignError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", preview)
+}
+
+// Create sends a campaign to a segment of an event's participants (protected route)
+func (h *EmailCampaignHandler) Create(c *gin.Context) {
+	req, ok := bindEmailCampaignRequest(c)
+	if !ok {
+		return
+	}
+
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	var campaign *models.EmailCampaign
+	if isValidID(eventID) {
+		campaign, err = h.campaignService.Create(c.Request.Context(), eventID, req, middleware.GetAdminID(c))
+	}
+	if respondEmailCampaignError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s sent campaign %s %q to %d participants of event %s",
+		middleware.GetAdminEmail(c), campaign.ID, campaign.Subject, campaign.Recipients, eventID)
+
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Email campaign queued successfully", campaign)
+}
+
+// Get returns a campaign with its delivery progress (protected route)
+func (h *EmailCampaignHandler) Get(c *gin.Context) {
+	id := c.Param("id")
+
+	err := services.ErrCampaignNotFound
+	var campaign *models.EmailCampaign
+	if isValidID(id) {
+		campaign, err = h.campaignService.Get(c.Request.Context(), id)
+	}
+	if respondEmailCampaignError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", campaign)
+}
+
+// Logs returns the delivery attempts of a campaign, one per recipient and
+// retry (protected route)
+func (h *EmailCampaignHandler) Logs(c *gin.Context) {
+	var validationErrors []utils.ValidationError
+
+	status := strings.ToUpper(strings.TrimSpace(c.Query("status")))
+	switch status {
+	case "", "SUCCESS", "FAILED":
+	default:
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "status", Message: "status must be either SUCCESS or FAILED"})
+	}
+
+	page, limit, pageErrors := parsePagination(c)
+	validationErrors = append(validationErrors, pageErrors...)
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid query parameters", validationErrors)
+		return
+	}
+
+	id := c.Param("id")
+
+	err := services.ErrCampaignNotFound
+	var logs []models.EmailLog
+	var total int
+	if isValidID(id) {
+		logs, total, err = h.campaignService.Logs(c.Request.Context(), id, status, page, limit)
+	}
+	if respondEmailCampaignError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"logs":        logs,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": (total + limit - 1) / limit,
+	})
+}
+
+// bindEmailCampaignRequest reads and validates a campaign, writing the error
+// response if it is invalid
+func bindEmailCampaignRequest(c *gin.Context) (models.EmailCampaignRequest, bool) {
+	var req models.EmailCampaignRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return req, false
+	}
+
+	validationErrors := validateTemplateFields(req.Subject, req.HTMLBody, req.TextBody)
+
+	segment := &req.Segment
+	segment.PaymentStatus = strings.ToUpper(strings.TrimSpace(segment.PaymentStatus))
+	segment.RegistrationStatus = strings.ToUpper(strings.TrimSpace(segment.RegistrationStatus))
+	segment.CategoryID = strings.TrimSpace(segment.CategoryID)
+
+	switch segment.PaymentStatus {
+	case "", "PAID", "UNPAID", "REFUND_PENDING", "REFUNDED":
+	default:
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "segment.payment_status", Message: "payment_status must be one of: PAID, UNPAID, REFUND_PENDING, REFUNDED"})
+	}
+	switch segment.RegistrationStatus {
+	case "", "PENDING", "CONFIRMED", "WAITLISTED", "EXPIRED", "CANCELLED":
+	default:
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "segment.registration_status", Message: "registration_status must be one of: PENDING, CONFIRMED, WAITLISTED, EXPIRED, CANCELLED"})
+	}
+	if segment.CategoryID != "" && !isValidID(segment.CategoryID) {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "segment.category_id", Message: "category_id must be a valid race category ID"})
+	}
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
+		return req, false
+	}
+
+	return req, true
+}
+
+// respondEmailCampaignError writes the response for an email campaign error, reporting whether there was one
+func respondEmailCampaignError(c *gin.Context, err error) bool {
+	var templateErr *services.EmailTemplateError
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &templateErr):
+		middleware.RespondWithError(c, http.StatusBadRequest, "INVALID_TEMPLATE", "Email campaign could not be rendered", []utils.ValidationError{
+			{Field: templateErr.Field, Message: templateErr.Err.Error()},
+		})
+	case errors.Is(err, services.ErrEventNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "EVENT_NOT_FOUND", "Event with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrCategoryNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "CATEGORY_NOT_FOUND", "Race category does not exist for this event", nil)
+	case errors.Is(err, services.ErrCampaignNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "CAMPAIGN_NOT_FOUND", "Email campaign with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrNoRecipients):
+		middleware.RespondWithError(c, http.StatusConflict, "NO_RECIPIENTS", "No participants match the campaign segment", nil)
+	default:
+		utils.DBLogger.Error("Email campaign request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
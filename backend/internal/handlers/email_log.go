@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// EmailLogHandler handles email log and resend requests
+type EmailLogHandler struct {
+	logService *services.EmailLogService
+}
+
+// NewEmailLogHandler creates a new email log handler
+func NewEmailLogHandler(logService *services.EmailLogService) *EmailLogHandler {
+	return &EmailLogHandler{logService: logService}
+}
+
+// List returns email sending attempts, newest first (protected route)
+func (h *EmailLogHandler) List(c *gin.Context) {
+	filter, validationErrors := parseEmailLogFilter(c)
+
+	page, limit, pageErrors := parsePagination(c)
+	validationErrors = append(validationErrors, pageErrors...)
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid query parameters", validationErrors)
+		return
+	}
+
+	logs, total, err := h.logService.List(c.Request.Context(), filter, page, limit)
+	if err != nil {
+		utils.DBLogger.Error("Failed to get email logs: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to retrieve email logs", nil)
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"logs":        logs,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": (total + limit - 1) / limit,
+	})
+}
+
+// Get returns a single email sending attempt (protected route)
+func (h *EmailLogHandler) Get(c *gin.Context) {
+	id := c.Param("id")
+
+	err := services.ErrEmailLogNotFound
+	var log *models.EmailLog
+	if isValidID(id) {
+		log, err = h.logService.Get(c.Request.Context(), id)
+	}
+	if respondEmailLogError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", log)
+}
+
+// Resend sends the email of a log entry to its participant again (protected route)
+func (h *EmailLogHandler) Resend(c *gin.Context) {
+	id := c.Param("id")
+
+	err := services.ErrEmailLogNotFound
+	var log *models.EmailLog
+	if isValidID(id) {
+		log, err = h.logService.Resend(c.Request.Context(), id, middleware.GetAdminID(c))
+	}
+	if respondEmailLogError(c, err) {
+		return
+	}
+
+	respondResent(c, log)
+}
+
+// ResendType sends a participant an email of any type again (protected route)
+func (h *EmailLogHandler) ResendType(c *gin.Context) {
+	participantID := c.Param("id")
+
+	err := services.ErrParticipantNotFound
+	var log *models.EmailLog
+	if isValidID(participantID) {
+		log, err = h.logService.ResendType(c.Request.Context(), participantID, emailTypeParam(c), middleware.GetAdminID(c))
+	}
+	if respondEmailLogError(c, err) {
+		return
+	}
+
+	respondResent(c, log)
+}
+
+// respondResent writes the response for a resent email, which is logged whether or not it was sent
+func respondResent(c *gin.Context, log *models.EmailLog) {
+	utils.AuthLogger.Info("Admin %s resent the %s email to %s: %s",
+		middleware.GetAdminEmail(c), log.EmailType, log.RecipientEmail, log.Status)
+
+	if log.Status != "SUCCESS" {
+		middleware.RespondWithError(c, http.StatusBadGateway, "EMAIL_SEND_FAILED", "Email could not be sent", log)
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Email sent successfully", log)
+}
+
+// parseEmailLogFilter reads email log filters from query parameters
+func parseEmailLogFilter(c *gin.Context) (repository.EmailLogFilter, []utils.ValidationError) {
+	var filter repository.EmailLogFilter
+	var errors []utils.ValidationError
+
+	if participantID := strings.TrimSpace(c.Query("participant_id")); participantID != "" {
+		if !isValidID(participantID) {
+			errors = append(errors, utils.ValidationError{Field: "participant_id", Message: "participant_id must be a valid participant ID"})
+		}
+		filter.ParticipantID = participantID
+	}
+
+	if eventID := strings.TrimSpace(c.Query("event_id")); eventID != "" {
+		if !isValidID(eventID) {
+			errors = append(errors, utils.ValidationError{Field: "event_id", Message: "event_id must be a valid event ID"})
+		}
+		filter.EventID = eventID
+	}
+
+	filter.EmailType = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(c.Query("email_type")), "-", "_"))
+
+	if status := strings.ToUpper(strings.TrimSpace(c.Query("status"))); status != "" {
+		if status != "SUCCESS" && status != "FAILED" {
+			errors = append(errors, utils.ValidationError{Field: "status", Message: "status must be either SUCCESS or FAILED"})
+		}
+		filter.Status = status
+	}
+
+	if from := c.Query("sent_from"); from != "" {
+		t, _, err := parseDateParam(from)
+		if err != nil {
+			errors = append(errors, utils.ValidationError{Field: "sent_from", Message: "sent_from must be a date (YYYY-MM-DD) or RFC3339 timestamp"})
+		} else {
+			filter.SentFrom = &t
+		}
+	}
+
+	if to := c.Query("sent_to"); to != "" {
+		t, dateOnly, err := parseDateParam(to)
+		if err != nil {
+			errors = append(errors, utils.ValidationError{Field: "sent_to", Message: "sent_to must be a date (YYYY-MM-DD) or RFC3339 timestamp"})
+		} else {
+			// A plain date includes the whole day
+			if dateOnly {
+				t = t.AddDate(0, 0, 1)
+			} else {
+				t = t.Add(time.Nanosecond)
+			}
+			filter.SentBefore = &t
+		}
+	}
+
+	return filter, errors
+}
+
+// respondEmailLogError writes the response for an email log error, reporting whether there was one
+func respondEmailLogError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrEmailLogNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "EMAIL_LOG_NOT_FOUND", "Email log with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrParticipantNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "Participant with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrUnknownEmailType):
+		middleware.RespondWithError(c, http.StatusNotFound, "EMAIL_TYPE_NOT_FOUND", "Email type does not exist", nil)
+	case errors.Is(err, services.ErrCampaignNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "CAMPAIGN_NOT_FOUND", "Email campaign with the specified ID does not exist", nil)
+	default:
+		utils.DBLogger.Error("Email log request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+func TestParseEmailLogFilter(t *testing.T) {
+	day := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	participantID := "6f1c2a4e-8d3b-4c5a-9e7f-0a1b2c3d4e5f"
+
+	tests := []struct {
+		name        string
+		query       string
+		want        repository.EmailLogFilter
+		wantInvalid []string
+	}{
+		{name: "no filters", query: ""},
+		{
+			name:  "filters are normalized",
+			query: "participant_id=" + participantID + "&email_type=payment-confirmation&status=failed",
+			want:  repository.EmailLogFilter{ParticipantID: participantID, EmailType: "PAYMENT_CONFIRMATION", Status: "FAILED"},
+		},
+		{
+			name:  "date range includes the whole last day",
+			query: "sent_from=2026-10-17&sent_to=2026-10-17",
+			want:  repository.EmailLogFilter{SentFrom: &day, SentBefore: ptrTime(day.AddDate(0, 0, 1))},
+		},
+		{
+			name:        "invalid values",
+			query:       "participant_id=42&event_id=abc&status=SENT&sent_from=yesterday&sent_to=17-10-2026",
+			want:        repository.EmailLogFilter{ParticipantID: "42", EventID: "abc", Status: "SENT"},
+			wantInvalid: []string{"participant_id", "event_id", "status", "sent_from", "sent_to"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := parseEmailLogFilter(queryContext(tt.query))
+
+			var invalid []string
+			for _, e := range errs {
+				invalid = append(invalid, e.Field)
+			}
+			if len(invalid) != len(tt.wantInvalid) {
+				t.Fatalf("invalid fields = %v, want %v", invalid, tt.wantInvalid)
+			}
+			for i := range invalid {
+				if invalid[i] != tt.wantInvalid[i] {
+					t.Fatalf("invalid fields = %v, want %v", invalid, tt.wantInvalid)
+				}
+			}
+
+			if got.ParticipantID != tt.want.ParticipantID || got.EventID != tt.want.EventID ||
+				got.EmailType != tt.want.EmailType || got.Status != tt.want.Status {
+				t.Errorf("filter = %+v, want %+v", got, tt.want)
+			}
+			if !equalTime(got.SentFrom, tt.want.SentFrom) || !equalTime(got.SentBefore, tt.want.SentBefore) {
+				t.Errorf("sent range = %v to %v, want %v to %v", got.SentFrom, got.SentBefore, tt.want.SentFrom, tt.want.SentBefore)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// EmailOutboxHandler handles inspection of the email outbox
+type EmailOutboxHandler struct {
+	outbox *services.EmailOutbox
+}
+
+// NewEmailOutboxHandler creates a new email outbox handler
+func NewEmailOutboxHandler(outbox *services.EmailOutbox) *EmailOutboxHandler {
+	return &EmailOutboxHandler{outbox: outbox}
+}
+
+// List returns outbox emails with a status, dead-lettered ones by default (protected route)
+func (h *EmailOutboxHandler) List(c *gin.Context) {
+	var validationErrors []utils.ValidationError
+
+	status := strings.ToUpper(strings.TrimSpace(c.DefaultQuery("status", services.OutboxStatusDead)))
+	switch status {
+	case services.OutboxStatusPending, services.OutboxStatusSending, services.OutboxStatusSent, services.OutboxStatusSkipped, services.OutboxStatusDead:
+	default:
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "status", Message: "status must be one of: PENDING, SENDING, SENT, SKIPPED, DEAD"})
+	}
+
+	page, limit, pageErrors := parsePagination(c)
+	validationErrors = append(validationErrors, pageErrors...)
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid query parameters", validationErrors)
+		return
+	}
+
+	emails, total, err := h.outbox.List(c.Request.Context(), status, page, limit)
+	if err != nil {
+		utils.DBLogger.Error("Failed to get outbox emails: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to retrieve outbox emails", nil)
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"emails":      emails,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": (total + limit - 1) / limit,
+	})
+}
+
+// Requeue retries a dead-lettered email (protected route)
+func (h *EmailOutboxHandler) Requeue(c *gin.Context) {
+	id := c.Param("id")
+
+	err := services.ErrOutboxEmailNotFound
+	var email *models.OutboxEmail
+	if isValidID(id) {
+		email, err = h.outbox.Requeue(c.Request.Context(), id)
+	}
+	switch {
+	case errors.Is(err, services.ErrOutboxEmailNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "OUTBOX_EMAIL_NOT_FOUND", "Outbox email with the specified ID does not exist", nil)
+		return
+	case errors.Is(err, services.ErrNotDeadLetter):
+		middleware.RespondWithError(c, http.StatusConflict, "NOT_DEAD_LETTER", "Only dead-lettered emails can be requeued", nil)
+		return
+	case err != nil:
+		utils.DBLogger.Error("Failed to requeue outbox email %s: %v", id, err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to requeue email", nil)
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s requeued outbox email %s", middleware.GetAdminEmail(c), id)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Email requeued", email)
+}
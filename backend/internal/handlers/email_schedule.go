@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// maxScheduleDays bounds how far from registration or the event an email is scheduled
+const maxScheduleDays = 365
+
+// EmailScheduleHandler handles scheduled email requests
+type EmailScheduleHandler struct {
+	scheduleService *services.EmailScheduleService
+}
+
+// NewEmailScheduleHandler creates a new email schedule handler
+func NewEmailScheduleHandler(scheduleService *services.EmailScheduleService) *EmailScheduleHandler {
+	return &EmailScheduleHandler{scheduleService: scheduleService}
+}
+
+// List returns when an event sends each scheduled email (protected route)
+func (h *EmailScheduleHandler) List(c *gin.Context) {
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	var schedules []models.EmailSchedule
+	if isValidID(eventID) {
+		schedules, err = h.scheduleService.List(c.Request.Context(), eventID)
+	}
+	if respondEmailScheduleError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"schedules": schedules,
+	})
+}
+
+// Update changes when an event sends a scheduled email (protected route)
+func (h *EmailScheduleHandler) Update(c *gin.Context) {
+	var req models.EmailScheduleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	emailType := emailTypeParam(c)
+	req.SendTime = strings.TrimSpace(req.SendTime)
+
+	var validationErrors []utils.ValidationError
+	if req.Days != nil {
+		if emailType == services.EmailTypeRaceDayBriefing {
+			if *req.Days != 0 {
+				validationErrors = append(validationErrors, utils.ValidationError{Field: "days", Message: "the race-day briefing is always sent on the event date"})
+			}
+		} else if *req.Days < 1 || *req.Days > maxScheduleDays {
+			validationErrors = append(validationErrors, utils.ValidationError{Field: "days", Message: "days must be between 1 and 365"})
+		}
+	}
+	if req.SendTime != "" {
+		if t, err := time.Parse("15:04", req.SendTime); err != nil || t.Format("15:04") != req.SendTime {
+			validationErrors = append(validationErrors, utils.ValidationError{Field: "send_time", Message: "send_time must be a time of day in HH:MM format"})
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
+		return
+	}
+
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	var schedule *models.EmailSchedule
+	if isValidID(eventID) {
+		schedule, err = h.scheduleService.Update(c.Request.Context(), eventID, emailType, req, middleware.GetAdminID(c))
+	}
+	if respondEmailScheduleError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s set the %s email of event %s to enabled=%t, days=%d, send_time=%s",
+		middleware.GetAdminEmail(c), emailType, eventID, schedule.Enabled, schedule.Days, schedule.SendTime)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Email schedule updated successfully", schedule)
+}
+
+// respondEmailScheduleError writes the response for an email schedule error, reporting whether there was one
+func respondEmailScheduleError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrEventNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "EVENT_NOT_FOUND", "Event with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrUnknownEmailType), errors.Is(err, services.ErrNotScheduledEmailType):
+		middleware.RespondWithError(c, http.StatusNotFound, "EMAIL_TYPE_NOT_FOUND", "Email type does not exist or is not sent on a schedule", nil)
+	default:
+		utils.DBLogger.Error("Email schedule request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// maxTemplateBodyLength bounds the HTML and plain text bodies of a template
+const maxTemplateBodyLength = 200000
+
+// EmailTemplateHandler handles email template requests
+type EmailTemplateHandler struct {
+	templateService *services.EmailTemplateService
+}
+
+// NewEmailTemplateHandler creates a new email template handler
+func NewEmailTemplateHandler(templateService *services.EmailTemplateService) *EmailTemplateHandler {
+	return &EmailTemplateHandler{templateService: templateService}
+}
+
+// List returns the current template of every email type of an event (protected route)
+func (h *EmailTemplateHandler) List(c *gin.Context) {
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	var templates []models.EmailTemplate
+	if isValidID(eventID) {
+		templates, err = h.templateService.List(c.Request.Context(), eventID)
+	}
+	if respondEmailTemplateError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"templates": templates,
+	})
+}
+
+// Get returns the current template of an email type (protected route)
+func (h *EmailTemplateHandler) Get(c *gin.Context) {
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	var template *models.EmailTemplate
+	if isValidID(eventID) {
+		template, err = h.templateService.Get(c.Request.Context(), eventID, emailTypeParam(c))
+	}
+	if respondEmailTemplateError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", template)
+}
+
+// Versions returns the saved versions of a template, newest first (protected route)
+func (h *EmailTemplateHandler) Versions(c *gin.Context) {
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	var versions []models.EmailTemplate
+	if isValidID(eventID) {
+		versions, err = h.templateService.Versions(c.Request.Context(), eventID, emailTypeParam(c))
+	}
+	if respondEmailTemplateError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"versions": versions,
+	})
+}
+
+// Save stores a new version of a template (protected route)
+func (h *EmailTemplateHandler) Save(c *gin.Context) {
+	var req models.EmailTemplateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	validationErrors := validateTemplateFields(req.Subject, req.HTMLBody, req.TextBody)
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
+		return
+	}
+
+	eventID := c.Param("id")
+	emailType := emailTypeParam(c)
+
+	err := services.ErrEventNotFound
+	var template *models.EmailTemplate
+	if isValidID(eventID) {
+		template, err = h.templateService.Save(c.Request.Context(), eventID, emailType, req, middleware.GetAdminID(c))
+	}
+	if respondEmailTemplateError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s saved version %d of the %s email template of event %s",
+		middleware.GetAdminEmail(c), template.Version, emailType, eventID)
+
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Email template saved successfully", template)
+}
+
+// Rollback makes an earlier version of a template current again (protected route)
+func (h *EmailTemplateHandler) Rollback(c *gin.Context) {
+	var req struct {
+		Version *int `json:"version"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	if req.Version == nil || *req.Version < 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "version", Message: "version is required; use 0 for the built-in default"},
+		})
+		return
+	}
+
+	eventID := c.Param("id")
+	emailType := emailTypeParam(c)
+
+	err := services.ErrEventNotFound
+	var template *models.EmailTemplate
+	if isValidID(eventID) {
+		template, err = h.templateService.Rollback(c.Request.Context(), eventID, emailType, *req.Version, middleware.GetAdminID(c))
+	}
+	if respondEmailTemplateError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s rolled the %s email template of event %s back to version %d (saved as version %d)",
+		middleware.GetAdminEmail(c), emailType, eventID, *req.Version, template.Version)
+
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Email template rolled back successfully", template)
+}
+
+// Preview renders a template, optionally with unsaved changes, for a real or
+// sample participant (protected route)
+func (h *EmailTemplateHandler) Preview(c *gin.Context) {
+	var req models.EmailTemplatePreviewRequest
+
+	// An empty body previews the current template
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+			return
+		}
+	}
+	req.ParticipantID = strings.TrimSpace(req.ParticipantID)
+
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	var rendered *models.RenderedEmail
+	if isValidID(eventID) {
+		err = services.ErrParticipantNotFound
+		if req.ParticipantID == "" || isValidID(req.ParticipantID) {
+			rendered, err = h.templateService.Preview(c.Request.Context(), eventID, emailTypeParam(c), req)
+		}
+	}
+	if respondEmailTemplateError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", rendered)
+}
+
+// validateTemplateFields checks the subject and bodies of a template
+func validateTemplateFields(subject, htmlBody, textBody string) []utils.ValidationError {
+	var validationErrors []utils.ValidationError
+	if strings.TrimSpace(subject) == "" || utf8.RuneCountInString(subject) > 255 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "subject", Message: "subject is required and must be at most 255 characters"})
+	}
+	if strings.TrimSpace(htmlBody) == "" || len(htmlBody) > maxTemplateBodyLength {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "html_body", Message: "html_body is required and must be at most 200000 bytes"})
+	}
+	if strings.TrimSpace(textBody) == "" || len(textBody) > maxTemplateBodyLength {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "text_body", Message: "text_body is required and must be at most 200000 bytes"})
+	}
+	return validationErrors
+}
+
+// emailTypeParam returns the email type in the URL, which may be written in
+// lowercase with dashes, e.g. payment-confirmation
+func emailTypeParam(c *gin.Context) string {
+	return strings.ToUpper(strings.ReplaceAll(c.Param("type"), "-", "_"))
+}
+
+// respondEmailTemplateError writes the response for an email template error, reporting whether there was one
+func respondEmailTemplateError(c *gin.Context, err error) bool {
+	var templateErr *services.EmailTemplateError
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &templateErr):
+		middleware.RespondWithError(c, http.StatusBadRequest, "INVALID_TEMPLATE", "Email template could not be rendered", []utils.ValidationError{
+			{Field: templateErr.Field, Message: templateErr.Err.Error()},
+		})
+	case errors.Is(err, services.ErrEventNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "EVENT_NOT_FOUND", "Event with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrUnknownEmailType):
+		middleware.RespondWithError(c, http.StatusNotFound, "EMAIL_TYPE_NOT_FOUND", "Email type does not exist", nil)
+	case errors.Is(err, services.ErrEmailTemplateVersionNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "TEMPLATE_VERSION_NOT_FOUND", "Email template version does not exist", nil)
+	case errors.Is(err, services.ErrParticipantNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "Participant with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrWrongEvent):
+		middleware.RespondWithError(c, http.StatusConflict, "WRONG_EVENT", "Participant is registered for another event", nil)
+	default:
+		utils.DBLogger.Error("Email template request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// EventHandler handles event requests
+type EventHandler struct {
+	validator    *utils.Validator
+	eventService *services.EventService
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(eventService *services.EventService) *EventHandler {
+	return &EventHandler{
+		validator:    utils.NewValidator(),
+		eventService: eventService,
+	}
+}
+
+// List returns all events
+func (h *EventHandler) List(c *gin.Context) {
+	events, err := h.eventService.List(c.Request.Context())
+	if err != nil {
+		utils.DBLogger.Error("Failed to get events: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to retrieve events", nil)
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"events": events,
+	})
+}
+
+// Get returns a single event
+func (h *EventHandler) Get(c *gin.Context) {
+	event, err := h.getEvent(c, c.Param("id"))
+	if respondEventError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", event)
+}
+
+// Create adds a new event (protected route)
+func (h *EventHandler) Create(c *gin.Context) {
+	event, ok := h.bindEvent(c)
+	if !ok {
+		return
+	}
+
+	if err := h.eventService.Create(c.Request.Context(), event); err != nil {
+		utils.DBLogger.Error("Failed to create event: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to create event", nil)
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s created event %s (%s)", middleware.GetAdminEmail(c), event.Name, event.ID)
+
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Event created successfully", event)
+}
+
+// Update replaces the details of an event (protected route)
+func (h *EventHandler) Update(c *gin.Context) {
+	event, ok := h.bindEvent(c)
+	if !ok {
+		return
+	}
+	event.ID = c.Param("id")
+
+	err := services.ErrEventNotFound
+	if isValidID(event.ID) {
+		err = h.eventService.Update(c.Request.Context(), event)
+	}
+	if respondEventError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s updated event %s (%s)", middleware.GetAdminEmail(c), event.Name, event.ID)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Event updated successfully", event)
+}
+
+// Delete removes an event nobody has registered for (protected route)
+func (h *EventHandler) Delete(c *gin.Context) {
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	if isValidID(eventID) {
+		err = h.eventService.Delete(c.Request.Context(), eventID)
+	}
+	if errors.Is(err, repository.ErrEventInUse) {
+		middleware.RespondWithError(c, http.StatusConflict, "EVENT_IN_USE", "Event has registered participants and cannot be deleted. Close registration instead.", nil)
+		return
+	}
+	if respondEventError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s deleted event %s", middleware.GetAdminEmail(c), eventID)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Event deleted successfully", gin.H{
+		"id": eventID,
+	})
+}
+
+// getEvent finds an event, treating malformed IDs as not found
+func (h *EventHandler) getEvent(c *gin.Context, id string) (*models.Event, error) {
+	if !isValidID(id) {
+		return nil, services.ErrEventNotFound
+	}
+	return h.eventService.Get(c.Request.Context(), id)
+}
+
+// bindEvent binds and validates an event request, writing the error response if it is invalid
+func (h *EventHandler) bindEvent(c *gin.Context) (*models.Event, bool) {
+	var req models.EventRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return nil, false
+	}
+
+	req.Name = h.validator.SanitizeString(req.Name)
+	req.EventDate = h.validator.SanitizeString(req.EventDate)
+	req.Location = h.validator.SanitizeString(req.Location)
+	req.Description = h.validator.SanitizeString(req.Description)
+
+	var validationErrors []utils.ValidationError
+	if req.Name == "" || len(req.Name) > 255 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "name", Message: "name is required and must be at most 255 characters"})
+	}
+	if _, err := time.Parse("2006-01-02", req.EventDate); err != nil {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "event_date", Message: "event_date must be a date (YYYY-MM-DD)"})
+	}
+	if req.Location == "" || len(req.Location) > 255 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "location", Message: "location is required and must be at most 255 characters"})
+	}
+	if len(req.Description) > 2000 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "description", Message: "description must be at most 2000 characters"})
+	}
+	if req.Capacity != nil && *req.Capacity <= 0 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "capacity", Message: "capacity must be a positive integer"})
+	}
+	if req.RefundFullUntil != nil {
+		if _, err := time.Parse("2006-01-02", *req.RefundFullUntil); err != nil {
+			validationErrors = append(validationErrors, utils.ValidationError{Field: "refund_full_until", Message: "refund_full_until must be a date (YYYY-MM-DD)"})
+		}
+	}
+	if req.RefundPartialPercent < 0 || req.RefundPartialPercent > 100 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "refund_partial_percent", Message: "refund_partial_percent must be between 0 and 100"})
+	}
+	if req.RefundCutoffDays < 0 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "refund_cutoff_days", Message: "refund_cutoff_days must not be negative"})
+	}
+	if req.TransferDeadline != nil {
+		if _, err := time.Parse("2006-01-02", *req.TransferDeadline); err != nil {
+			validationErrors = append(validationErrors, utils.ValidationError{Field: "transfer_deadline", Message: "transfer_deadline must be a date (YYYY-MM-DD)"})
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
+		return nil, false
+	}
+
+	event := &models.Event{
+		Name:             req.Name,
+		EventDate:        req.EventDate,
+		Location:         req.Location,
+		Description:      req.Description,
+		RegistrationOpen: true,
+		Capacity:         req.Capacity,
+
+		RefundFullUntil:      req.RefundFullUntil,
+		RefundPartialPercent: req.RefundPartialPercent,
+		RefundCutoffDays:     req.RefundCutoffDays,
+
+		TransferDeadline: req.TransferDeadline,
+	}
+	if req.RegistrationOpen != nil {
+		event.RegistrationOpen = *req.RegistrationOpen
+	}
+
+	return event, true
+}
+
+// respondEventError writes the error response for a failed event lookup and reports whether it did
+func respondEventError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrEventNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "EVENT_NOT_FOUND", "Event with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrEventRequired):
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "event_id", Message: "event_id is required; more than one event is open for registration"},
+		})
+	case errors.Is(err, services.ErrRegistrationClosed):
+		middleware.RespondWithError(c, http.StatusForbidden, "REGISTRATION_CLOSED", "Registration for this event is closed", nil)
+	default:
+		utils.DBLogger.Error("Event request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
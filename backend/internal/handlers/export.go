@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/export"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// ExportParticipants streams participants matching the list filters as CSV or XLSX (protected route)
+func (h *AdminHandler) ExportParticipants(c *gin.Context) {
+	adminEmail := middleware.GetAdminEmail(c)
+
+	filter, validationErrors := parseParticipantFilter(c)
+	sortBy, sortOrder, sortErrors := parseParticipantSort(c)
+	validationErrors = append(validationErrors, sortErrors...)
+
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", export.FormatCSV)))
+	if !export.IsValidFormat(format) {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "format", Message: "format must be either csv or xlsx"})
+	}
+
+	var columnKeys []string
+	if columns := c.Query("columns"); columns != "" {
+		columnKeys = strings.Split(columns, ",")
+	}
+	columns, err := export.SelectColumns(columnKeys)
+	if err != nil {
+		validationErrors = append(validationErrors, utils.ValidationError{
+			Field:   "columns",
+			Message: "columns must be a comma-separated list of: " + strings.Join(export.ColumnKeys(), ", "),
+		})
+	}
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid query parameters", validationErrors)
+		return
+	}
+
+	query := repository.ParticipantQuery{
+		Filter:    filter,
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
+	}
+
+	filename := fmt.Sprintf("participants-%s.%s", time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	writer, err := export.NewWriter(format, c.Writer)
+	if err == nil {
+		err = writer.Write(export.Headers(columns))
+	}
+
+	rows := 0
+	if err == nil {
+		err = h.participants.Stream(c.Request.Context(), query, func(p *models.Participant) error {
+			rows++
+			return writer.Write(export.Row(columns, p))
+		})
+	}
+
+	if err == nil {
+		err = writer.Close()
+	}
+
+	if err != nil {
+		utils.DBLogger.Error("Failed to export participants after %d rows: %v", rows, err)
+
+		// Headers can only be replaced if nothing has reached the client yet
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to export participants", nil)
+			return
+		}
+
+		// The response is already streaming, so the client gets a truncated file
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s exported %d participants as %s", adminEmail, rows, format)
+}
@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+const (
+	maxImportFileSize = 5 << 20 // 5 MB
+	maxImportRows     = 5000
+)
+
+// Import row statuses
+const (
+	importRowValid   = "VALID"
+	importRowInvalid = "INVALID"
+	importRowCreated = "CREATED"
+)
+
+// errImportDryRun rolls back a dry run once every row has been registered
+var errImportDryRun = errors.New("import dry run")
+
+// importColumnAliases maps normalized CSV headers to registration fields.
+// Export headers are accepted so an exported file can be re-imported.
+var importColumnAliases = map[string]string{
+	"name":             "name",
+	"email":            "email",
+	"phone":            "phone",
+	"instagram_handle": "instagram_handle",
+	"instagram":        "instagram_handle",
+	"address":          "address",
+	"category_id":      "category_id",
+	"category":         "category_id",
+	"date_of_birth":    "date_of_birth",
+}
+
+var importRequiredColumns = []string{"name", "email", "phone", "address"}
+
+// importRow is one parsed CSV data row
+type importRow struct {
+	number int // Spreadsheet row number; the header is row 1
+	req    models.CreateParticipantRequest
+}
+
+// ImportRowResult reports the outcome for a single CSV row. RegistrationStatus
+// and WaitlistPosition tell whether the row got a spot or joined the waitlist.
+type ImportRowResult struct {
+	Row                int                     `json:"row"`
+	Email              string                  `json:"email"`
+	Status             string                  `json:"status"`
+	ID                 string                  `json:"id,omitempty"`
+	CategoryID         *string                 `json:"category_id,omitempty"`
+	RegistrationStatus string                  `json:"registration_status,omitempty"`
+	WaitlistPosition   *int                    `json:"waitlist_position,omitempty"`
+	Errors             []utils.ValidationError `json:"errors,omitempty"`
+}
+
+// ImportParticipants registers participants from an uploaded CSV file (protected route).
+// Rows are registered like public registrations, so they must choose a race
+// category when the event has any and join the waitlist once it is full.
+// With dry_run=true every row is registered and then rolled back.
+func (h *AdminHandler) ImportParticipants(c *gin.Context) {
+	adminEmail := middleware.GetAdminEmail(c)
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid query parameters", []utils.ValidationError{
+				{Field: "dry_run", Message: "dry_run must be true or false"},
+			})
+			return
+		}
+		dryRun = parsed
+	}
+
+	// Rows are registered for the event_id query parameter, or the only open event
+	event, err := resolveEvent(c, h.eventService, c.Query("event_id"), true)
+	if respondEventError(c, err) {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "INVALID_FILE", "A CSV file up to 5 MB must be uploaded in the \"file\" field", nil)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ServerLogger.Error("Failed to open uploaded import file: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to read uploaded file", nil)
+		return
+	}
+	defer file.Close()
+
+	rows, err := parseImportCSV(file)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "INVALID_FILE", err.Error(), nil)
+		return
+	}
+
+	results := make([]ImportRowResult, len(rows))
+	created, waitlisted := 0, 0
+
+	validate := func(ctx context.Context) error {
+		seen := make(map[string]int, len(rows))
+		for i := range rows {
+			row := &rows[i]
+			result := &results[i]
+			result.Row = row.number
+			result.Errors = prepareRegistration(h.validator, &row.req)
+			result.Email = row.req.Email
+
+			row.req.CategoryID = strings.TrimSpace(row.req.CategoryID)
+			if row.req.CategoryID != "" && !isValidID(row.req.CategoryID) {
+				result.Errors = append(result.Errors, utils.ValidationError{
+					Field:   "category_id",
+					Message: "Race category does not exist for this event",
+				})
+			}
+
+			if row.req.Email != "" {
+				if first, ok := seen[row.req.Email]; ok {
+					result.Errors = append(result.Errors, utils.ValidationError{
+						Field:   "email",
+						Message: fmt.Sprintf("email is duplicated in this file (first seen on row %d)", first),
+					})
+				} else {
+					seen[row.req.Email] = row.number
+
+					existing, err := h.participants.FindByEventAndEmail(ctx, event.ID, row.req.Email)
+					if err != nil {
+						return err
+					}
+					if existing != nil {
+						result.Errors = append(result.Errors, utils.ValidationError{
+							Field:   "email",
+							Message: "Email address is already registered for this event",
+						})
+					}
+				}
+			}
+
+			result.Status = importRowValid
+			if len(result.Errors) > 0 {
+				result.Status = importRowInvalid
+			}
+		}
+		return nil
+	}
+
+	// Validate and register in one transaction so either every valid row is
+	// registered or none are. A dry run rolls it back, still reporting which
+	// rows would have been waitlisted.
+	var duplicateRow int
+	err = h.tx.WithinTx(c.Request.Context(), func(ctx context.Context) error {
+		if err := validate(ctx); err != nil {
+			return err
+		}
+
+		for i := range rows {
+			result := &results[i]
+			if result.Status != importRowValid {
+				continue
+			}
+
+			participant, err := h.registrationService.Register(ctx, event, &rows[i].req)
+			if rowErrors := importRegistrationErrors(err); rowErrors != nil {
+				result.Status = importRowInvalid
+				result.Errors = rowErrors
+				continue
+			}
+			if err != nil {
+				if errors.Is(err, repository.ErrDuplicateEmail) {
+					duplicateRow = rows[i].number
+				}
+				return err
+			}
+
+			result.CategoryID = participant.CategoryID
+			result.RegistrationStatus = participant.RegistrationStatus
+			result.WaitlistPosition = participant.WaitlistPosition
+			if participant.RegistrationStatus == "WAITLISTED" {
+				waitlisted++
+			}
+			if !dryRun {
+				result.Status = importRowCreated
+				result.ID = participant.ID
+				created++
+			}
+		}
+
+		if dryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errImportDryRun) {
+		err = nil
+	}
+
+	if err != nil {
+		// Registered concurrently by someone else after the duplicate check
+		if errors.Is(err, repository.ErrDuplicateEmail) {
+			middleware.RespondWithError(c, http.StatusConflict, "DUPLICATE_EMAIL", "An email address was registered while importing; no rows were imported", gin.H{
+				"row": duplicateRow,
+			})
+			return
+		}
+
+		utils.DBLogger.Error("Failed to import participants: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to import participants", nil)
+		return
+	}
+
+	invalid := 0
+	for _, result := range results {
+		if result.Status == importRowInvalid {
+			invalid++
+		}
+	}
+
+	if dryRun {
+		utils.AuthLogger.Info("Admin %s validated import of %d rows for %s (%d invalid, %d waitlisted)", adminEmail, len(rows), event.Name, invalid, waitlisted)
+	} else {
+		utils.AuthLogger.Info("Admin %s imported %d participants for %s (%d waitlisted, %d invalid rows skipped)", adminEmail, created, event.Name, waitlisted, invalid)
+	}
+
+	message := "Import completed"
+	if dryRun {
+		message = "Dry run completed; no participants were registered"
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, message, gin.H{
+		"dry_run":      dryRun,
+		"event_id":     event.ID,
+		"total_rows":   len(rows),
+		"valid_rows":   len(rows) - invalid,
+		"invalid_rows": invalid,
+		"created":      created,
+		"waitlisted":   waitlisted,
+		"rows":         results,
+	})
+}
+
+// parseImportCSV reads registration rows from a CSV file with a header row
+func parseImportCSV(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %v", err)
+	}
+
+	// Map each known column to its index, ignoring unknown columns
+	columns := make(map[string]int)
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // UTF-8 BOM written by Excel
+		}
+		key := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+		if field, ok := importColumnAliases[key]; ok {
+			columns[field] = i
+		}
+	}
+
+	var missing []string
+	for _, field := range importRequiredColumns {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("CSV header is missing required columns: %s", strings.Join(missing, ", "))
+	}
+
+	value := func(record []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	var rows []importRow
+	for number := 2; ; number++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV on row %d: %v", number, err)
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, fmt.Errorf("CSV file must not contain more than %d rows", maxImportRows)
+		}
+
+		row := importRow{
+			number: number,
+			req: models.CreateParticipantRequest{
+				Name:    value(record, "name"),
+				Email:   value(record, "email"),
+				Phone:   value(record, "phone"),
+				Address: value(record, "address"),
+			},
+		}
+		if handle := value(record, "instagram_handle"); strings.TrimSpace(handle) != "" {
+			row.req.InstagramHandle = &handle
+		}
+		if dateOfBirth := value(record, "date_of_birth"); strings.TrimSpace(dateOfBirth) != "" {
+			row.req.DateOfBirth = &dateOfBirth
+		}
+		row.req.CategoryID = value(record, "category_id")
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("CSV file has no data rows")
+	}
+
+	return rows, nil
+}
+
+// importRegistrationErrors returns the row errors for a registration error that
+// only concerns its row, or nil for other errors
+func importRegistrationErrors(err error) []utils.ValidationError {
+	var field, message string
+	switch {
+	case errors.Is(err, services.ErrCategoryRequired):
+		field, message = "category_id", "category_id is required for this event"
+	case errors.Is(err, services.ErrCategoryNotFound):
+		field, message = "category_id", "Race category does not exist for this event"
+	case errors.Is(err, services.ErrDateOfBirthRequired):
+		field, message = "date_of_birth", "date_of_birth is required for this race category"
+	case errors.Is(err, services.ErrAgeNotEligible):
+		field, message = "date_of_birth", "Participant age on the event date is outside the limits of this race category"
+	default:
+		return nil
+	}
+	return []utils.ValidationError{{Field: field, Message: message}}
+}
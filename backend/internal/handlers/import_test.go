@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+	"github.com/tau-tau-run/backend/internal/services"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		csv       string
+		wantRows  []models.CreateParticipantRequest
+		wantLines []int
+		wantErr   string
+	}{
+		{
+			name: "export headers in another order",
+			csv: "\ufeffEmail,Name,Instagram,Address,Phone,Registration Status\n" +
+				"budi@example.com,Budi,@budi,Jalan Merdeka 1,+6281111111111,PENDING\n" +
+				"citra@example.com,Citra,,Jalan Sudirman 2,+6282222222222,PENDING\n",
+			wantRows: []models.CreateParticipantRequest{
+				{Name: "Budi", Email: "budi@example.com", Phone: "+6281111111111", Address: "Jalan Merdeka 1", InstagramHandle: ptrString("@budi")},
+				{Name: "Citra", Email: "citra@example.com", Phone: "+6282222222222", Address: "Jalan Sudirman 2"},
+			},
+			wantLines: []int{2, 3},
+		},
+		{
+			name:      "short row",
+			csv:       "name,email,phone,address\nBudi,budi@example.com\n",
+			wantRows:  []models.CreateParticipantRequest{{Name: "Budi", Email: "budi@example.com"}},
+			wantLines: []int{2},
+		},
+		{
+			name:    "empty file",
+			csv:     "",
+			wantErr: "CSV file is empty",
+		},
+		{
+			name:    "header only",
+			csv:     "name,email,phone,address\n",
+			wantErr: "CSV file has no data rows",
+		},
+		{
+			name:    "missing columns",
+			csv:     "name,email\nBudi,budi@example.com\n",
+			wantErr: "CSV header is missing required columns: phone, address",
+		},
+		{
+			name:    "malformed quotes",
+			csv:     "name,email,phone,address\n\"Budi,budi@example.com,+6281111111111,Jalan\n",
+			wantErr: "invalid CSV on row 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseImportCSV(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseImportCSV() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseImportCSV() error = %v", err)
+			}
+
+			if len(rows) != len(tt.wantRows) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.wantRows))
+			}
+			for i, row := range rows {
+				want := tt.wantRows[i]
+				if row.number != tt.wantLines[i] {
+					t.Errorf("row %d number = %d, want %d", i, row.number, tt.wantLines[i])
+				}
+				if row.req.Name != want.Name || row.req.Email != want.Email || row.req.Phone != want.Phone || row.req.Address != want.Address {
+					t.Errorf("row %d = %+v, want %+v", i, row.req, want)
+				}
+				if (row.req.InstagramHandle == nil) != (want.InstagramHandle == nil) ||
+					(want.InstagramHandle != nil && *row.req.InstagramHandle != *want.InstagramHandle) {
+					t.Errorf("row %d instagram handle = %v, want %v", i, row.req.InstagramHandle, want.InstagramHandle)
+				}
+			}
+		})
+	}
+}
+
+func TestAdminHandlerImportParticipants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	file := "name,email,phone,address\n" +
+		"Budi,budi@example.com,+6281111111111,Jalan Merdeka 1\n" +
+		"Registered,registered@example.com,+6282222222222,Jalan Sudirman 2\n" +
+		"Budi Again,BUDI@example.com,+6283333333333,Jalan Thamrin 3\n" +
+		"X,not-an-email,123,\n" +
+		"Citra,citra@example.com,+6284444444444,Jalan Gatot Subroto 4\n"
+
+	tests := []struct {
+		name        string
+		dryRun      bool
+		wantCreated int
+	}{
+		{name: "import", wantCreated: 2},
+		{name: "dry run", dryRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := memory.NewDB()
+			events := memory.NewEventRepository(db)
+			participants := memory.NewParticipantRepository(db)
+			ctx := context.Background()
+
+			// The registered runner holds one of two spots, so the last row
+			// is waitlisted
+			capacity := 2
+			event := &models.Event{Name: "City Run", EventDate: "2026-12-06", RegistrationOpen: true, Capacity: &capacity}
+			if err := events.Create(ctx, event); err != nil {
+				t.Fatalf("failed to create event: %v", err)
+			}
+			if err := participants.Create(ctx, &models.Participant{EventID: event.ID, Name: "Registered", Email: "registered@example.com"}); err != nil {
+				t.Fatalf("failed to create participant: %v", err)
+			}
+
+			cfg := &config.Config{Waitlist: config.WaitlistConfig{OfferHours: 48}}
+			categories := memory.NewRaceCategoryRepository(db)
+			tx := memory.NewTransactor(db)
+			emailService := services.NewEmailService(cfg, mailer.NewMemoryMailer(), nil, events, memory.NewEmailLogRepository(db), nil, nil, nil)
+			outbox := services.NewEmailOutbox(cfg, emailService, participants, memory.NewEmailOutboxRepository(db), tx)
+			waitlist := services.NewWaitlistService(cfg, events, categories, participants, outbox, tx)
+			registration := services.NewRegistrationService(categories, participants, waitlist, tx)
+
+			h := NewAdminHandler(nil, nil, nil, services.NewEventService(events, waitlist), registration, memory.NewAdminRepository(db), participants, tx)
+			router := gin.New()
+			router.POST("/import", h.ImportParticipants)
+
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			part, err := mw.CreateFormFile("file", "participants.csv")
+			if err != nil {
+				t.Fatalf("failed to create form file: %v", err)
+			}
+			part.Write([]byte(file))
+			mw.Close()
+
+			url := "/import"
+			if tt.dryRun {
+				url += "?dry_run=true"
+			}
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, url, &body)
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+			}
+
+			var resp struct {
+				Data struct {
+					DryRun      bool              `json:"dry_run"`
+					ValidRows   int               `json:"valid_rows"`
+					InvalidRows int               `json:"invalid_rows"`
+					Created     int               `json:"created"`
+					Waitlisted  int               `json:"waitlisted"`
+					Rows        []ImportRowResult `json:"rows"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			wantStatus := importRowCreated
+			if tt.dryRun {
+				wantStatus = importRowValid
+			}
+			wantStatuses := []string{wantStatus, importRowInvalid, importRowInvalid, importRowInvalid, wantStatus}
+			if len(resp.Data.Rows) != len(wantStatuses) {
+				t.Fatalf("got %d row results, want %d", len(resp.Data.Rows), len(wantStatuses))
+			}
+			for i, row := range resp.Data.Rows {
+				if row.Status != wantStatuses[i] || row.Row != i+2 {
+					t.Errorf("row result %d = row %d %s %v, want row %d %s", i, row.Row, row.Status, row.Errors, i+2, wantStatuses[i])
+				}
+			}
+			if resp.Data.DryRun != tt.dryRun || resp.Data.ValidRows != 2 || resp.Data.InvalidRows != 3 || resp.Data.Created != tt.wantCreated || resp.Data.Waitlisted != 1 {
+				t.Errorf("summary = %+v", resp.Data)
+			}
+			if last := resp.Data.Rows[4]; last.RegistrationStatus != "WAITLISTED" || last.WaitlistPosition == nil || *last.WaitlistPosition != 1 {
+				t.Errorf("last row = %s at %v, want WAITLISTED at 1", last.RegistrationStatus, last.WaitlistPosition)
+			}
+
+			imported, err := participants.FindByEventAndEmail(ctx, event.ID, "budi@example.com")
+			if err != nil {
+				t.Fatalf("FindByEventAndEmail() error = %v", err)
+			}
+			if stored := imported != nil; stored != !tt.dryRun {
+				t.Errorf("imported participant stored = %t, want %t", stored, !tt.dryRun)
+			}
+		})
+	}
+}
+
+func ptrString(s string) *string {
+	return &s
+}
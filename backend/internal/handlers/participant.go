@@ -1,25 +1,32 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
 	"github.com/tau-tau-run/backend/internal/middleware"
 	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/services"
 	"github.com/tau-tau-run/backend/internal/utils"
 )
 
 // ParticipantHandler handles participant-related requests
 type ParticipantHandler struct {
-	validator *utils.Validator
+	validator           *utils.Validator
+	eventService        *services.EventService
+	registrationService *services.RegistrationService
 }
 
 // NewParticipantHandler creates a new participant handler
-func NewParticipantHandler() *ParticipantHandler {
+func NewParticipantHandler(eventService *services.EventService, registrationService *services.RegistrationService) *ParticipantHandler {
 	return &ParticipantHandler{
-		validator: utils.NewValidator(),
+		validator:           utils.NewValidator(),
+		eventService:        eventService,
+		registrationService: registrationService,
 	}
 }
 
@@ -33,74 +40,125 @@ func (h *ParticipantHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Sanitize inputs
-	req.Name = h.validator.SanitizeString(req.Name)
-	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
-	req.Phone = h.validator.SanitizeString(req.Phone)
-	req.Address = h.validator.SanitizeString(req.Address)
-	if req.InstagramHandle != nil {
-		sanitized := h.validator.SanitizeString(*req.InstagramHandle)
-		req.InstagramHandle = &sanitized
-	}
-
-	// Validate all fields
-	validationErrors := h.validator.ValidateRegistrationData(
-		req.Name,
-		req.Email,
-		req.Phone,
-		req.InstagramHandle,
-		req.Address,
-	)
-
+	// Sanitize and validate all fields
+	validationErrors := prepareRegistration(h.validator, &req)
 	if len(validationErrors) > 0 {
 		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
 		return
 	}
 
-	// Check for duplicate email
-	existing, err := models.FindParticipantByEmail(req.Email)
-	if err != nil {
-		utils.DBLogger.Error("Failed to check duplicate email: %v", err)
-		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	// Find the event being registered for
+	event, err := resolveEvent(c, h.eventService, req.EventID, false)
+	if respondEventError(c, err) {
+		return
+	}
+
+	// Create participant, taking a spot in the chosen race category
+	req.CategoryID = strings.TrimSpace(req.CategoryID)
+	if req.CategoryID != "" && !isValidID(req.CategoryID) {
+		middleware.RespondWithError(c, http.StatusNotFound, "CATEGORY_NOT_FOUND", "Race category does not exist for this event", nil)
 		return
 	}
 
-	if existing != nil {
-		middleware.RespondWithError(c, http.StatusConflict, "DUPLICATE_EMAIL", "Email address is already registered", gin.H{
-			"email": req.Email,
+	participant, err := h.registrationService.Register(c.Request.Context(), event, &req)
+	switch {
+	case errors.Is(err, repository.ErrDuplicateEmail):
+		middleware.RespondWithError(c, http.StatusConflict, "DUPLICATE_EMAIL", "Email address is already registered for this event", gin.H{
+			"email":    req.Email,
+			"event_id": event.ID,
 		})
 		return
+	case errors.Is(err, services.ErrCategoryRequired):
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "category_id", Message: "category_id is required for this event"},
+		})
+		return
+	case errors.Is(err, services.ErrCategoryNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "CATEGORY_NOT_FOUND", "Race category does not exist for this event", nil)
+		return
+	case errors.Is(err, services.ErrDateOfBirthRequired):
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "date_of_birth", Message: "date_of_birth is required for this race category"},
+		})
+		return
+	case errors.Is(err, services.ErrAgeNotEligible):
+		middleware.RespondWithError(c, http.StatusBadRequest, "AGE_NOT_ELIGIBLE", "Participant age on the event date is outside the limits of this race category", nil)
+		return
+	case err != nil:
+		utils.DBLogger.Error("Failed to create participant: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to register participant", nil)
+		return
 	}
 
-	// Create participant
-	participant := &models.Participant{
-		Name:            req.Name,
-		Email:           req.Email,
-		Phone:           req.Phone,
-		InstagramHandle: req.InstagramHandle,
-		Address:         req.Address,
+	data := gin.H{
+		"id":                  participant.ID,
+		"event_id":            participant.EventID,
+		"category_id":         participant.CategoryID,
+		"email":               participant.Email,
+		"registration_status": participant.RegistrationStatus,
+		"payment_status":      participant.PaymentStatus,
 	}
 
-	if err := participant.Create(); err != nil {
-		// Check for unique constraint violation (just in case of race condition)
-		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-			middleware.RespondWithError(c, http.StatusConflict, "DUPLICATE_EMAIL", "Email address is already registered", nil)
-			return
-		}
+	if participant.RegistrationStatus == "WAITLISTED" {
+		utils.ServerLogger.Info("New participant waitlisted for %s at position %d: %s (%s)", event.Name, *participant.WaitlistPosition, participant.Name, participant.Email)
 
-		utils.DBLogger.Error("Failed to create participant: %v", err)
-		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to register participant", nil)
+		data["waitlist_position"] = participant.WaitlistPosition
+		middleware.RespondWithSuccess(c, http.StatusCreated, "This race is full, so you have been added to the waitlist. We will email you if a spot opens up.", data)
 		return
 	}
 
 	// Log successful registration
-	utils.ServerLogger.Info("New participant registered: %s (%s)", participant.Name, participant.Email)
+	utils.ServerLogger.Info("New participant registered for %s: %s (%s)", event.Name, participant.Name, participant.Email)
 
 	// Return success response
-	middleware.RespondWithSuccess(c, http.StatusCreated, "Registration successful! Your payment status is pending.", gin.H{
-		"id":                  participant.ID,
-		"email":               participant.Email,
-		"registration_status": participant.RegistrationStatus,
-		"payment_status":      participant.PaymentStatus,
-	})
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Registration successful! Your payment status is pending.", data)
+}
+
+// resolveEvent finds the event a registration is for. An empty eventID selects
+// the only open event. Admins may also register participants for closed events.
+func resolveEvent(c *gin.Context, eventService *services.EventService, eventID string, allowClosed bool) (*models.Event, error) {
+	eventID = strings.TrimSpace(eventID)
+	if eventID != "" && !isValidID(eventID) {
+		return nil, services.ErrEventNotFound
+	}
+	if eventID != "" && allowClosed {
+		return eventService.Get(c.Request.Context(), eventID)
+	}
+	return eventService.ResolveForRegistration(c.Request.Context(), eventID)
+}
+
+// prepareRegistration sanitizes registration input in place and validates it
+func prepareRegistration(v *utils.Validator, req *models.CreateParticipantRequest) []utils.ValidationError {
+	req.Name = v.SanitizeString(req.Name)
+	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
+	req.Phone = v.SanitizeString(req.Phone)
+	req.Address = v.SanitizeString(req.Address)
+	if req.InstagramHandle != nil {
+		sanitized := v.SanitizeString(*req.InstagramHandle)
+		req.InstagramHandle = &sanitized
+	}
+	if req.DateOfBirth != nil {
+		sanitized := strings.TrimSpace(*req.DateOfBirth)
+		req.DateOfBirth = &sanitized
+		if sanitized == "" {
+			req.DateOfBirth = nil
+		}
+	}
+
+	errors := v.ValidateRegistrationData(
+		req.Name,
+		req.Email,
+		req.Phone,
+		req.InstagramHandle,
+		req.Address,
+	)
+
+	if req.DateOfBirth != nil {
+		born, err := time.Parse("2006-01-02", *req.DateOfBirth)
+		if err != nil || born.Year() < 1900 || born.After(time.Now()) {
+			errors = append(errors, utils.ValidationError{Field: "date_of_birth", Message: "date_of_birth must be a past date (YYYY-MM-DD)"})
+		}
+	}
+
+	return errors
 }
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+	"github.com/tau-tau-run/backend/internal/services"
+)
+
+func TestParticipantHandlerRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		body       string // {{open}} and {{closed}} are replaced by event IDs
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "only open event",
+			body:       `{"name":"New Runner","email":"New@Example.com","phone":"+6281234567890","address":"Jalan Merdeka 1"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "named event",
+			body:       `{"event_id":"{{open}}","name":"New Runner","email":"new@example.com","phone":"+6281234567890","address":"Jalan Merdeka 1"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "registered email",
+			body:       `{"name":"Other Runner","email":"runner@example.com","phone":"+6281234567890","address":"Jalan Merdeka 1"}`,
+			wantStatus: http.StatusConflict,
+			wantCode:   "DUPLICATE_EMAIL",
+		},
+		{
+			name:       "closed event",
+			body:       `{"event_id":"{{closed}}","name":"New Runner","email":"new@example.com","phone":"+6281234567890","address":"Jalan Merdeka 1"}`,
+			wantStatus: http.StatusForbidden,
+			wantCode:   "REGISTRATION_CLOSED",
+		},
+		{
+			name:       "unknown event",
+			body:       `{"event_id":"00000000-0000-4000-8000-000000000000","name":"New Runner","email":"new@example.com","phone":"+6281234567890","address":"Jalan Merdeka 1"}`,
+			wantStatus: http.StatusNotFound,
+			wantCode:   "EVENT_NOT_FOUND",
+		},
+		{
+			name:       "malformed event ID",
+			body:       `{"event_id":"1 OR 1=1","name":"New Runner","email":"new@example.com","phone":"+6281234567890","address":"Jalan Merdeka 1"}`,
+			wantStatus: http.StatusNotFound,
+			wantCode:   "EVENT_NOT_FOUND",
+		},
+		{
+			name:       "invalid email",
+			body:       `{"name":"New Runner","email":"not-an-email","phone":"+6281234567890","address":"Jalan Merdeka 1"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "VALIDATION_ERROR",
+		},
+		{
+			name:       "malformed JSON",
+			body:       `{"name":`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "VALIDATION_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := memory.NewDB()
+			events := memory.NewEventRepository(db)
+			participants := memory.NewParticipantRepository(db)
+			ctx := context.Background()
+
+			open := &models.Event{Name: "City Run", EventDate: "2026-12-06", RegistrationOpen: true}
+			closed := &models.Event{Name: "Trail Run", EventDate: "2026-11-01"}
+			for _, event := range []*models.Event{open, closed} {
+				if err := events.Create(ctx, event); err != nil {
+					t.Fatalf("failed to create event: %v", err)
+				}
+			}
+			existing := &models.Participant{EventID: open.ID, Name: "Runner", Email: "runner@example.com"}
+			if err := participants.Create(ctx, existing); err != nil {
+				t.Fatalf("failed to create participant: %v", err)
+			}
+
+			cfg := &config.Config{Waitlist: config.WaitlistConfig{OfferHours: 48}}
+			categories := memory.NewRaceCategoryRepository(db)
+			tx := memory.NewTransactor(db)
+			emailService := services.NewEmailService(cfg, mailer.NewMemoryMailer(), nil, events, memory.NewEmailLogRepository(db), nil, nil, nil)
+			outbox := services.NewEmailOutbox(cfg, emailService, participants, memory.NewEmailOutboxRepository(db), tx)
+			waitlist := services.NewWaitlistService(cfg, events, categories, participants, outbox, tx)
+			registration := services.NewRegistrationService(categories, participants, waitlist, tx)
+
+			router := gin.New()
+			router.POST("/register", NewParticipantHandler(services.NewEventService(events, waitlist), registration).Register)
+
+			body := strings.NewReplacer("{{open}}", open.ID, "{{closed}}", closed.ID).Replace(tt.body)
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantCode != "" && !strings.Contains(w.Body.String(), `"code":"`+tt.wantCode+`"`) {
+				t.Errorf("body = %s, want error code %s", w.Body, tt.wantCode)
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+
+			created, err := participants.FindByEventAndEmail(ctx, open.ID, "new@example.com")
+			if err != nil {
+				t.Fatalf("FindByEventAndEmail() error = %v", err)
+			}
+			if created == nil || created.Name != "New Runner" {
+				t.Errorf("stored participant = %+v, want New Runner with a lowercased email", created)
+			}
+		})
+	}
+}
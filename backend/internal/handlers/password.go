@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// PasswordHandler handles requests to change and reset admin passwords
+type PasswordHandler struct {
+	validator       *utils.Validator
+	passwordService *services.PasswordService
+}
+
+// NewPasswordHandler creates a new admin password handler
+func NewPasswordHandler(passwordService *services.PasswordService) *PasswordHandler {
+	return &PasswordHandler{
+		validator:       utils.NewValidator(),
+		passwordService: passwordService,
+	}
+}
+
+// ChangePassword changes the password of the logged-in admin (protected route)
+func (h *PasswordHandler) ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	session, err := h.passwordService.ChangePassword(c.Request.Context(), middleware.GetAdminID(c), req.CurrentPassword, req.NewPassword)
+	if errors.Is(err, services.ErrWrongPassword) {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "current_password", Message: err.Error()},
+		})
+		return
+	}
+	if respondPasswordError(c, err, "new_password") {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Password changed successfully. Your other sessions have been logged out.", session)
+}
+
+// ForgotPassword emails a password reset link to an admin (public route)
+func (h *PasswordHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	email := strings.TrimSpace(strings.ToLower(req.Email))
+	if err := h.validator.ValidateEmail(email); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "email", Message: err.Error()},
+		})
+		return
+	}
+
+	if err := h.passwordService.RequestReset(c.Request.Context(), email); err != nil {
+		utils.DBLogger.Error("Failed to send password reset link: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+		return
+	}
+
+	// The same answer whether or not the email belongs to an admin, so the
+	// form can't be used to find admin accounts
+	middleware.RespondWithSuccess(c, http.StatusAccepted, "If that email belongs to an admin, we have sent it a password reset link.", nil)
+}
+
+// ResetPassword sets a new password with the token of a reset link (public route)
+func (h *PasswordHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	_, err := h.passwordService.Reset(c.Request.Context(), strings.TrimSpace(req.Token), req.Password)
+	if respondPasswordError(c, err, "password") {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Password reset successfully. Please log in with your new password.", nil)
+}
+
+// respondPasswordError writes the response for a password error, reporting
+// whether there was one. field is the request field of the new password.
+func respondPasswordError(c *gin.Context, err error, field string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, new(*services.WeakPasswordError)):
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: field, Message: err.Error()},
+		})
+	case errors.Is(err, services.ErrInvalidResetToken):
+		middleware.RespondWithError(c, http.StatusUnauthorized, "INVALID_RESET_LINK", "This password reset link is invalid, has expired or was already used. Please request a new one.", nil)
+	case errors.Is(err, services.ErrAdminDisabled):
+		middleware.RespondWithError(c, http.StatusForbidden, "ACCOUNT_DISABLED", "This admin account has been disabled", nil)
+	case errors.Is(err, services.ErrAdminNotFound):
+		middleware.RespondWithError(c, http.StatusUnauthorized, "UNAUTHORIZED", "This admin account no longer exists", nil)
+	default:
+		utils.AuthLogger.Error("Password request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/payment"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+const maxWebhookBodySize = 1 << 20 // 1 MB
+
+// PaymentHandler handles online payment requests
+type PaymentHandler struct {
+	paymentService *services.PaymentService
+}
+
+// NewPaymentHandler creates a new payment handler
+func NewPaymentHandler(paymentService *services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+// CreatePayment starts an online payment for a participant and returns its checkout URL
+func (h *PaymentHandler) CreatePayment(c *gin.Context) {
+	participantID := c.Param("id")
+
+	err := services.ErrParticipantNotFound
+	var p *models.Payment
+	if isValidID(participantID) {
+		p, err = h.paymentService.CreateCharge(c.Request.Context(), participantID)
+	}
+	switch {
+	case errors.Is(err, services.ErrPaymentsDisabled):
+		middleware.RespondWithError(c, http.StatusServiceUnavailable, "PAYMENTS_DISABLED", "Online payments are not available. Please contact the organizers.", nil)
+		return
+	case errors.Is(err, services.ErrParticipantNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "Participant with the specified ID does not exist", gin.H{
+			"id": participantID,
+		})
+		return
+	case errors.Is(err, services.ErrCancelled):
+		middleware.RespondWithError(c, http.StatusConflict, "REGISTRATION_CANCELLED", "This registration has been cancelled", nil)
+		return
+	case errors.Is(err, services.ErrAlreadyPaid):
+		middleware.RespondWithError(c, http.StatusConflict, "ALREADY_PAID", "This registration has already been paid", nil)
+		return
+	case errors.Is(err, services.ErrWaitlisted):
+		middleware.RespondWithError(c, http.StatusConflict, "WAITLISTED", "This registration is on the waitlist. We will email you if a spot opens up.", nil)
+		return
+	case errors.Is(err, services.ErrOfferExpired):
+		middleware.RespondWithError(c, http.StatusConflict, "OFFER_EXPIRED", "The spot offered to this registration has expired", nil)
+		return
+	case err != nil:
+		utils.ServerLogger.Error("Failed to create payment for participant %s: %v", participantID, err)
+		middleware.RespondWithError(c, http.StatusBadGateway, "PAYMENT_PROVIDER_ERROR", "Failed to start payment. Please try again later.", nil)
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Payment created", gin.H{
+		"reference":    p.Reference,
+		"amount":       p.Amount,
+		"currency":     p.Currency,
+		"status":       p.Status,
+		"checkout_url": p.CheckoutURL,
+		"expires_at":   p.ExpiresAt,
+	})
+}
+
+// Webhook receives signed payment notifications from the payment provider
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body", nil)
+		return
+	}
+
+	change, err := h.paymentService.HandleWebhook(c.Request.Context(), payload, c.Request.Header)
+	switch {
+	case errors.Is(err, services.ErrPaymentsDisabled):
+		middleware.RespondWithError(c, http.StatusServiceUnavailable, "PAYMENTS_DISABLED", "Online payments are not enabled", nil)
+		return
+	case errors.Is(err, payment.ErrInvalidSignature):
+		utils.ServerLogger.Warning("Rejected payment webhook with invalid signature from %s", c.ClientIP())
+		middleware.RespondWithError(c, http.StatusUnauthorized, "INVALID_SIGNATURE", "Webhook signature verification failed", nil)
+		return
+	case errors.Is(err, services.ErrPaymentNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PAYMENT_NOT_FOUND", "Payment with the specified reference does not exist", nil)
+		return
+	case errors.Is(err, services.ErrAmountMismatch):
+		utils.ServerLogger.Warning("Rejected payment webhook with mismatched amount: %s", string(payload))
+		middleware.RespondWithError(c, http.StatusBadRequest, "AMOUNT_MISMATCH", "Paid amount does not match the payment", nil)
+		return
+	case err != nil:
+		utils.ServerLogger.Error("Failed to process payment webhook: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to process webhook", nil)
+		return
+	}
+
+	emailSent := false
+	if change != nil {
+		utils.ServerLogger.Info("Payment gateway updated participant %s payment status: %s → %s",
+			change.Participant.Email, change.OldStatus, change.Participant.PaymentStatus)
+		emailSent = change.EmailSent
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Webhook processed", gin.H{
+		"email_sent": emailSent,
+	})
+}
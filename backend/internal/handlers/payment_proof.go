@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// PaymentProofHandler handles proof-of-payment uploads and their review
+type PaymentProofHandler struct {
+	proofService *services.PaymentProofService
+}
+
+// NewPaymentProofHandler creates a new payment proof handler
+func NewPaymentProofHandler(proofService *services.PaymentProofService) *PaymentProofHandler {
+	return &PaymentProofHandler{proofService: proofService}
+}
+
+// Upload stores a bank transfer receipt for a participant
+func (h *PaymentProofHandler) Upload(c *gin.Context) {
+	participantID := c.Param("id")
+
+	// Leave room for the other multipart fields on top of the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxPaymentProofSize+64<<10)
+
+	email := c.PostForm("email")
+	fileHeader, err := c.FormFile("file")
+	if err != nil || email == "" {
+		middleware.RespondWithError(c, http.StatusBadRequest, "INVALID_FILE", "An image or PDF up to 5 MB must be uploaded in the \"file\" field together with the registered \"email\"", nil)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ServerLogger.Error("Failed to open uploaded payment proof: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to read uploaded file", nil)
+		return
+	}
+	defer file.Close()
+
+	err = services.ErrParticipantNotFound
+	var proof *models.PaymentProof
+	if isValidID(participantID) {
+		proof, err = h.proofService.Upload(c.Request.Context(), participantID, email, fileHeader.Filename, file)
+	}
+	switch {
+	case errors.Is(err, services.ErrParticipantNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "No registration matches this ID and email", nil)
+		return
+	case errors.Is(err, services.ErrCancelled):
+		middleware.RespondWithError(c, http.StatusConflict, "REGISTRATION_CANCELLED", "This registration has been cancelled", nil)
+		return
+	case errors.Is(err, services.ErrAlreadyPaid):
+		middleware.RespondWithError(c, http.StatusConflict, "ALREADY_PAID", "This registration has already been paid", nil)
+		return
+	case errors.Is(err, services.ErrWaitlisted):
+		middleware.RespondWithError(c, http.StatusConflict, "WAITLISTED", "This registration is on the waitlist. We will email you if a spot opens up.", nil)
+		return
+	case errors.Is(err, services.ErrOfferExpired):
+		middleware.RespondWithError(c, http.StatusConflict, "OFFER_EXPIRED", "The spot offered to this registration has expired", nil)
+		return
+	case errors.Is(err, services.ErrProofTooLarge), errors.Is(err, services.ErrProofEmpty):
+		middleware.RespondWithError(c, http.StatusBadRequest, "INVALID_FILE", "File must not be empty or larger than 5 MB", nil)
+		return
+	case errors.Is(err, services.ErrProofUnsupportedType):
+		middleware.RespondWithError(c, http.StatusUnsupportedMediaType, "UNSUPPORTED_FILE_TYPE", "File must be a JPEG, PNG or WebP image, or a PDF", nil)
+		return
+	case err != nil:
+		utils.ServerLogger.Error("Failed to store payment proof for participant %s: %v", participantID, err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to upload payment proof", nil)
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Payment proof uploaded. We will review it shortly.", gin.H{
+		"id":         proof.ID,
+		"status":     proof.Status,
+		"created_at": proof.CreatedAt,
+	})
+}
+
+// List returns the review queue of payment proofs (protected route)
+func (h *PaymentProofHandler) List(c *gin.Context) {
+	var validationErrors []utils.ValidationError
+
+	status := strings.ToUpper(strings.TrimSpace(c.DefaultQuery("status", services.ProofStatusPending)))
+	if status != services.ProofStatusPending && status != services.ProofStatusApproved && status != services.ProofStatusRejected {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "status", Message: "status must be one of: PENDING, APPROVED, REJECTED"})
+	}
+
+	page, limit, pageErrors := parsePagination(c)
+	validationErrors = append(validationErrors, pageErrors...)
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid query parameters", validationErrors)
+		return
+	}
+
+	proofs, total, err := h.proofService.List(c.Request.Context(), status, page, limit)
+	if err != nil {
+		utils.DBLogger.Error("Failed to get payment proofs: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to retrieve payment proofs", nil)
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"proofs":      proofs,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": (total + limit - 1) / limit,
+	})
+}
+
+// Download streams the uploaded proof file (protected route)
+func (h *PaymentProofHandler) Download(c *gin.Context) {
+	proofID := c.Param("id")
+
+	err := services.ErrProofNotFound
+	var proof *models.PaymentProof
+	var file io.ReadCloser
+	if isValidID(proofID) {
+		proof, file, err = h.proofService.Open(c.Request.Context(), proofID)
+	}
+	if errors.Is(err, services.ErrProofNotFound) {
+		middleware.RespondWithError(c, http.StatusNotFound, "PROOF_NOT_FOUND", "Payment proof with the specified ID does not exist", nil)
+		return
+	}
+	if err != nil {
+		utils.ServerLogger.Error("Failed to open payment proof %s: %v", proofID, err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to retrieve payment proof", nil)
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", proof.ContentType)
+	c.Header("Content-Length", strconv.FormatInt(proof.Size, 10))
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, proof.Filename))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, file); err != nil {
+		utils.ServerLogger.Error("Failed to stream payment proof %s: %v", proof.ID, err)
+	}
+}
+
+// Approve accepts a proof and marks the participant PAID (protected route)
+func (h *PaymentProofHandler) Approve(c *gin.Context) {
+	adminEmail := middleware.GetAdminEmail(c)
+
+	proofID := c.Param("id")
+
+	err := services.ErrProofNotFound
+	var proof *models.PaymentProof
+	var change *services.PaymentStatusChange
+	if isValidID(proofID) {
+		proof, change, err = h.proofService.Approve(c.Request.Context(), proofID, middleware.GetAdminID(c))
+	}
+	if h.respondReviewError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s approved payment proof %s for %s: %s → %s",
+		adminEmail, proof.ID, change.Participant.Email, change.OldStatus, change.Participant.PaymentStatus)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Payment proof approved", gin.H{
+		"proof":          proof,
+		"participant_id": change.Participant.ID,
+		"payment_status": change.Participant.PaymentStatus,
+		"email_sent":     change.EmailSent,
+	})
+}
+
+// Reject declines a proof with an optional reason (protected route)
+func (h *PaymentProofHandler) Reject(c *gin.Context) {
+	adminEmail := middleware.GetAdminEmail(c)
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+			return
+		}
+	}
+
+	proofID := c.Param("id")
+
+	err := services.ErrProofNotFound
+	var proof *models.PaymentProof
+	if isValidID(proofID) {
+		proof, err = h.proofService.Reject(c.Request.Context(), proofID, middleware.GetAdminID(c), req.Reason)
+	}
+	if h.respondReviewError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s rejected payment proof %s", adminEmail, proof.ID)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Payment proof rejected", gin.H{
+		"proof": proof,
+	})
+}
+
+// respondReviewError writes the error response for a failed review and reports whether it did
+func (h *PaymentProofHandler) respondReviewError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrProofNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PROOF_NOT_FOUND", "Payment proof with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrProofAlreadyReviewed):
+		middleware.RespondWithError(c, http.StatusConflict, "PROOF_ALREADY_REVIEWED", "Payment proof has already been reviewed", nil)
+	case errors.Is(err, services.ErrParticipantNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "Participant for this payment proof no longer exists", nil)
+	case errors.Is(err, services.ErrCancelled):
+		middleware.RespondWithError(c, http.StatusConflict, "REGISTRATION_CANCELLED", "Registration for this payment proof has been cancelled", nil)
+	case errors.Is(err, services.ErrWaitlisted):
+		middleware.RespondWithError(c, http.StatusConflict, "WAITLISTED", "Registration for this payment proof is on the waitlist and has no spot to pay for yet", nil)
+	case errors.Is(err, services.ErrOfferExpired):
+		middleware.RespondWithError(c, http.StatusConflict, "OFFER_EXPIRED", "The spot offered to the registration for this payment proof has expired", nil)
+	default:
+		utils.DBLogger.Error("Failed to review payment proof %s: %v", c.Param("id"), err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to review payment proof", nil)
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPaymentProofHandlerMalformedID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The ID is refused before it reaches the proof service or the database
+	h := NewPaymentProofHandler(nil)
+	router := gin.New()
+	router.GET("/payment-proofs/:id/file", h.Download)
+	router.POST("/payment-proofs/:id/approve", h.Approve)
+	router.POST("/payment-proofs/:id/reject", h.Reject)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+	}{
+		{name: "download", method: http.MethodGet, path: "/payment-proofs/1%20OR%201=1/file"},
+		{name: "approve", method: http.MethodPost, path: "/payment-proofs/1%20OR%201=1/approve"},
+		{name: "reject", method: http.MethodPost, path: "/payment-proofs/1%20OR%201=1/reject"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusNotFound, w.Body)
+			}
+			if !strings.Contains(w.Body.String(), `"code":"PROOF_NOT_FOUND"`) {
+				t.Errorf("body = %s, want error code PROOF_NOT_FOUND", w.Body)
+			}
+		})
+	}
+}
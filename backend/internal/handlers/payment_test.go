@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPaymentHandlerCreatePaymentMalformedID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The ID is refused before it reaches the payment service or the database
+	router := gin.New()
+	router.POST("/participants/:id/payments", NewPaymentHandler(nil).CreatePayment)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/participants/1%20OR%201=1/payments", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusNotFound, w.Body)
+	}
+	if !strings.Contains(w.Body.String(), `"code":"PARTICIPANT_NOT_FOUND"`) {
+		t.Errorf("body = %s, want error code PARTICIPANT_NOT_FOUND", w.Body)
+	}
+}
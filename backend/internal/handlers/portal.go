@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// PortalHandler handles participant portal requests
+type PortalHandler struct {
+	validator     *utils.Validator
+	portalService *services.PortalService
+}
+
+// NewPortalHandler creates a new participant portal handler
+func NewPortalHandler(portalService *services.PortalService) *PortalHandler {
+	return &PortalHandler{
+		validator:     utils.NewValidator(),
+		portalService: portalService,
+	}
+}
+
+// RequestLogin emails a magic link to a registered participant (public route)
+func (h *PortalHandler) RequestLogin(c *gin.Context) {
+	var req models.PortalLoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	email := strings.TrimSpace(strings.ToLower(req.Email))
+	if err := h.validator.ValidateEmail(email); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "email", Message: err.Error()},
+		})
+		return
+	}
+
+	if err := h.portalService.RequestLogin(c.Request.Context(), email); err != nil {
+		utils.DBLogger.Error("Failed to send portal login link: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+		return
+	}
+
+	// The same answer whether or not the email is registered, so the form
+	// can't be used to find out who is running
+	middleware.RespondWithSuccess(c, http.StatusAccepted, "If that email is registered, we have sent it a login link.", nil)
+}
+
+// Login exchanges a magic link for a participant session (public route)
+func (h *PortalHandler) Login(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	token, expiresAt, err := h.portalService.Login(c.Request.Context(), strings.TrimSpace(req.Token))
+	switch {
+	case errors.Is(err, services.ErrInvalidLoginLink):
+		middleware.RespondWithError(c, http.StatusUnauthorized, "INVALID_LOGIN_LINK", "This login link is invalid or has expired. Please request a new one.", nil)
+		return
+	case err != nil:
+		utils.DBLogger.Error("Failed to log participant in: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Login successful", gin.H{
+		"token":      token,
+		"expires_at": expiresAt,
+	})
+}
+
+// Registrations returns the registrations of the logged-in participant (participant route)
+func (h *PortalHandler) Registrations(c *gin.Context) {
+	registrations, err := h.portalService.Registrations(c.Request.Context(), middleware.GetParticipantEmail(c))
+	if err != nil {
+		utils.DBLogger.Error("Failed to get portal registrations: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to retrieve registrations", nil)
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"registrations": registrations,
+	})
+}
+
+// UpdateRegistration changes the contact details of a registration of the
+// logged-in participant (participant route)
+func (h *PortalHandler) UpdateRegistration(c *gin.Context) {
+	var req models.PortalUpdateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	if validationErrors := h.preparePortalUpdate(&req); len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
+		return
+	}
+
+	participantID := c.Param("id")
+	email := middleware.GetParticipantEmail(c)
+
+	err := services.ErrParticipantNotFound
+	var registration *models.PortalRegistration
+	if isValidID(participantID) {
+		registration, err = h.portalService.UpdateRegistration(c.Request.Context(), email, participantID, req)
+	}
+	switch {
+	case errors.Is(err, services.ErrParticipantNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "Registration does not exist", nil)
+		return
+	case err != nil:
+		utils.DBLogger.Error("Failed to update registration %s: %v", participantID, err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to update registration", nil)
+		return
+	}
+
+	utils.ServerLogger.Info("Participant %s updated the contact details of registration %s", email, participantID)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Registration updated successfully", registration)
+}
+
+// CancelRegistration cancels a registration of the logged-in participant (participant route)
+func (h *PortalHandler) CancelRegistration(c *gin.Context) {
+	req, ok := bindCancelRequest(c, h.validator, false)
+	if !ok {
+		return
+	}
+
+	participantID := c.Param("id")
+	email := middleware.GetParticipantEmail(c)
+
+	err := services.ErrParticipantNotFound
+	var registration *models.PortalRegistration
+	if isValidID(participantID) {
+		registration, err = h.portalService.CancelRegistration(c.Request.Context(), email, participantID, req.Reason)
+	}
+	if respondCancellationError(c, err) {
+		return
+	}
+
+	utils.ServerLogger.Info("Participant %s cancelled registration %s", email, participantID)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Registration cancelled successfully", registration)
+}
+
+// TransferRegistration starts a transfer of a registration of the logged-in
+// participant to another runner (participant route)
+func (h *PortalHandler) TransferRegistration(c *gin.Context) {
+	req, ok := bindTransferRequest(c, h.validator)
+	if !ok {
+		return
+	}
+
+	participantID := c.Param("id")
+	email := middleware.GetParticipantEmail(c)
+
+	err := services.ErrParticipantNotFound
+	var registration *models.PortalRegistration
+	if isValidID(participantID) {
+		registration, err = h.portalService.TransferRegistration(c.Request.Context(), email, participantID, req.Name, req.Email)
+	}
+	if respondTransferError(c, err) {
+		return
+	}
+
+	utils.ServerLogger.Info("Participant %s started the transfer of registration %s to %s", email, participantID, req.Email)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Transfer started successfully", registration)
+}
+
+// CancelTransfer withdraws the pending transfer of a registration of the
+// logged-in participant (participant route)
+func (h *PortalHandler) CancelTransfer(c *gin.Context) {
+	participantID := c.Param("id")
+	email := middleware.GetParticipantEmail(c)
+
+	err := services.ErrParticipantNotFound
+	var registration *models.PortalRegistration
+	if isValidID(participantID) {
+		registration, err = h.portalService.CancelTransfer(c.Request.Context(), email, participantID)
+	}
+	if respondTransferError(c, err) {
+		return
+	}
+
+	utils.ServerLogger.Info("Participant %s cancelled the transfer of registration %s", email, participantID)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Transfer cancelled successfully", registration)
+}
+
+// preparePortalUpdate sanitizes the fields of req in place and validates them
+// with the same rules as registration
+func (h *PortalHandler) preparePortalUpdate(req *models.PortalUpdateRequest) []utils.ValidationError {
+	var validationErrors []utils.ValidationError
+
+	if req.Phone != nil {
+		phone := h.validator.SanitizeString(*req.Phone)
+		req.Phone = &phone
+		if err := h.validator.ValidatePhone(phone); err != nil {
+			validationErrors = append(validationErrors, utils.ValidationError{Field: "phone", Message: err.Error()})
+		}
+	}
+
+	if req.Address != nil {
+		address := h.validator.SanitizeString(*req.Address)
+		req.Address = &address
+		if err := h.validator.ValidateAddress(address); err != nil {
+			validationErrors = append(validationErrors, utils.ValidationError{Field: "address", Message: err.Error()})
+		}
+	}
+
+	if req.InstagramHandle != nil {
+		handle := h.validator.SanitizeString(*req.InstagramHandle)
+		req.InstagramHandle = &handle
+		if err := h.validator.ValidateInstagramHandle(req.InstagramHandle); err != nil {
+			validationErrors = append(validationErrors, utils.ValidationError{Field: "instagram_handle", Message: err.Error()})
+		}
+	}
+
+	if req.Phone == nil && req.Address == nil && req.InstagramHandle == nil {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "phone", Message: "at least one of phone, address or instagram_handle is required"})
+	}
+
+	return validationErrors
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// uuidPattern matches the IDs generated by the database
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// isValidID reports whether id is shaped like a database ID
+func isValidID(id string) bool {
+	return uuidPattern.MatchString(id)
+}
+
+// parseParticipantFilter reads participant filters from query parameters
+func parseParticipantFilter(c *gin.Context) (repository.ParticipantFilter, []utils.ValidationError) {
+	var filter repository.ParticipantFilter
+	var errors []utils.ValidationError
+
+	if eventID := strings.TrimSpace(c.Query("event_id")); eventID != "" {
+		if !isValidID(eventID) {
+			errors = append(errors, utils.ValidationError{Field: "event_id", Message: "event_id must be a valid event ID"})
+		}
+		filter.EventID = eventID
+	}
+
+	if status := strings.ToUpper(strings.TrimSpace(c.Query("payment_status"))); status != "" {
+		switch status {
+		case "PAID", "UNPAID", "REFUND_PENDING", "REFUNDED":
+		default:
+			errors = append(errors, utils.ValidationError{Field: "payment_status", Message: "payment_status must be one of: PAID, UNPAID, REFUND_PENDING, REFUNDED"})
+		}
+		filter.PaymentStatus = status
+	}
+
+	if status := strings.ToUpper(strings.TrimSpace(c.Query("registration_status"))); status != "" {
+		switch status {
+		case "PENDING", "CONFIRMED", "WAITLISTED", "EXPIRED", "CANCELLED":
+		default:
+			errors = append(errors, utils.ValidationError{Field: "registration_status", Message: "registration_status must be one of: PENDING, CONFIRMED, WAITLISTED, EXPIRED, CANCELLED"})
+		}
+		filter.RegistrationStatus = status
+	}
+
+	if categoryID := strings.TrimSpace(c.Query("category_id")); categoryID != "" {
+		if !isValidID(categoryID) {
+			errors = append(errors, utils.ValidationError{Field: "category_id", Message: "category_id must be a valid race category ID"})
+		}
+		filter.CategoryID = categoryID
+	}
+
+	if value := strings.TrimSpace(c.Query("checked_in")); value != "" {
+		checkedIn, err := strconv.ParseBool(value)
+		if err != nil {
+			errors = append(errors, utils.ValidationError{Field: "checked_in", Message: "checked_in must be either true or false"})
+		}
+		filter.CheckedIn = &checkedIn
+	}
+
+	if from := c.Query("created_from"); from != "" {
+		t, _, err := parseDateParam(from)
+		if err != nil {
+			errors = append(errors, utils.ValidationError{Field: "created_from", Message: "created_from must be a date (YYYY-MM-DD) or RFC3339 timestamp"})
+		} else {
+			filter.CreatedFrom = &t
+		}
+	}
+
+	if to := c.Query("created_to"); to != "" {
+		t, dateOnly, err := parseDateParam(to)
+		if err != nil {
+			errors = append(errors, utils.ValidationError{Field: "created_to", Message: "created_to must be a date (YYYY-MM-DD) or RFC3339 timestamp"})
+		} else {
+			// A plain date includes the whole day
+			if dateOnly {
+				t = t.AddDate(0, 0, 1)
+			} else {
+				t = t.Add(time.Nanosecond)
+			}
+			filter.CreatedBefore = &t
+		}
+	}
+
+	filter.Search = strings.TrimSpace(c.Query("search"))
+
+	return filter, errors
+}
+
+// parseParticipantQuery reads filters, sorting and pagination from query parameters
+func parseParticipantQuery(c *gin.Context) (repository.ParticipantQuery, []utils.ValidationError) {
+	filter, errors := parseParticipantFilter(c)
+
+	sortBy, sortOrder, sortErrors := parseParticipantSort(c)
+	errors = append(errors, sortErrors...)
+
+	page, limit, pageErrors := parsePagination(c)
+	errors = append(errors, pageErrors...)
+
+	q := repository.ParticipantQuery{
+		Filter:    filter,
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
+		Page:      page,
+		Limit:     limit,
+	}
+
+	return q, errors
+}
+
+// parsePagination reads page and limit, defaulting to the first page
+func parsePagination(c *gin.Context) (int, int, []utils.ValidationError) {
+	var errors []utils.ValidationError
+	page, limit := 1, defaultPageLimit
+
+	if value := c.Query("page"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			errors = append(errors, utils.ValidationError{Field: "page", Message: "page must be a positive integer"})
+		}
+		page = n
+	}
+
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxPageLimit {
+			errors = append(errors, utils.ValidationError{Field: "limit", Message: "limit must be between 1 and " + strconv.Itoa(maxPageLimit)})
+		}
+		limit = n
+	}
+
+	return page, limit, errors
+}
+
+// parseParticipantSort reads sort_by and sort_order, defaulting to newest first
+func parseParticipantSort(c *gin.Context) (string, string, []utils.ValidationError) {
+	var errors []utils.ValidationError
+	sortBy, sortOrder := "created_at", repository.SortDesc
+
+	if field := strings.ToLower(strings.TrimSpace(c.Query("sort_by"))); field != "" {
+		if !repository.IsValidParticipantSortField(field) {
+			errors = append(errors, utils.ValidationError{
+				Field:   "sort_by",
+				Message: "sort_by must be one of: " + strings.Join(repository.ParticipantSortFields, ", "),
+			})
+		}
+		sortBy = field
+	}
+
+	if order := strings.ToLower(strings.TrimSpace(c.Query("sort_order"))); order != "" {
+		if order != repository.SortAsc && order != repository.SortDesc {
+			errors = append(errors, utils.ValidationError{Field: "sort_order", Message: "sort_order must be either asc or desc"})
+		}
+		sortOrder = order
+	}
+
+	return sortBy, sortOrder, errors
+}
+
+// parseDateParam accepts YYYY-MM-DD or RFC3339 and reports whether it was a plain date
+func parseDateParam(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+// queryContext returns a gin context for a GET request with query string q
+func queryContext(q string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/?"+q, nil)
+	return c
+}
+
+func TestParseParticipantQuery(t *testing.T) {
+	day := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		query       string
+		want        repository.ParticipantQuery
+		wantInvalid []string // Fields reported as invalid
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			want:  repository.ParticipantQuery{SortBy: "created_at", SortOrder: repository.SortDesc, Page: 1, Limit: defaultPageLimit},
+		},
+		{
+			name:  "filters and sorting",
+			query: "payment_status=paid&registration_status=Confirmed&search=+budi+&sort_by=Name&sort_order=ASC&page=3&limit=50",
+			want: repository.ParticipantQuery{
+				Filter:    repository.ParticipantFilter{PaymentStatus: "PAID", RegistrationStatus: "CONFIRMED", Search: "budi"},
+				SortBy:    "name",
+				SortOrder: repository.SortAsc,
+				Page:      3,
+				Limit:     50,
+			},
+		},
+		{
+			name:  "date range includes the whole last day",
+			query: "created_from=2026-10-17&created_to=2026-10-17",
+			want: repository.ParticipantQuery{
+				Filter:    repository.ParticipantFilter{CreatedFrom: &day, CreatedBefore: ptrTime(day.AddDate(0, 0, 1))},
+				SortBy:    "created_at",
+				SortOrder: repository.SortDesc,
+				Page:      1,
+				Limit:     defaultPageLimit,
+			},
+		},
+		{
+			name:  "timestamp range includes the last instant",
+			query: "created_to=2026-10-17T00:00:00Z",
+			want: repository.ParticipantQuery{
+				Filter:    repository.ParticipantFilter{CreatedBefore: ptrTime(day.Add(time.Nanosecond))},
+				SortBy:    "created_at",
+				SortOrder: repository.SortDesc,
+				Page:      1,
+				Limit:     defaultPageLimit,
+			},
+		},
+		{
+			name:        "invalid values",
+			query:       "payment_status=OVERDUE&registration_status=DONE&created_from=yesterday&created_to=17-10-2026&sort_by=password&sort_order=up&page=0&limit=101",
+			wantInvalid: []string{"payment_status", "registration_status", "created_from", "created_to", "sort_by", "sort_order", "page", "limit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := parseParticipantQuery(queryContext(tt.query))
+
+			var invalid []string
+			for _, e := range errs {
+				invalid = append(invalid, e.Field)
+			}
+			if len(invalid) != len(tt.wantInvalid) {
+				t.Fatalf("invalid fields = %v, want %v", invalid, tt.wantInvalid)
+			}
+			for i := range invalid {
+				if invalid[i] != tt.wantInvalid[i] {
+					t.Fatalf("invalid fields = %v, want %v", invalid, tt.wantInvalid)
+				}
+			}
+			if tt.wantInvalid != nil {
+				return
+			}
+
+			if got.SortBy != tt.want.SortBy || got.SortOrder != tt.want.SortOrder || got.Page != tt.want.Page || got.Limit != tt.want.Limit {
+				t.Errorf("sorting and paging = %s %s page %d limit %d, want %s %s page %d limit %d",
+					got.SortBy, got.SortOrder, got.Page, got.Limit,
+					tt.want.SortBy, tt.want.SortOrder, tt.want.Page, tt.want.Limit)
+			}
+			f, want := got.Filter, tt.want.Filter
+			if f.PaymentStatus != want.PaymentStatus || f.RegistrationStatus != want.RegistrationStatus || f.Search != want.Search {
+				t.Errorf("filter = %+v, want %+v", f, want)
+			}
+			if !equalTime(f.CreatedFrom, want.CreatedFrom) || !equalTime(f.CreatedBefore, want.CreatedBefore) {
+				t.Errorf("created range = %v to %v, want %v to %v", f.CreatedFrom, f.CreatedBefore, want.CreatedFrom, want.CreatedBefore)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// currencyPattern matches ISO 4217 currency codes
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// RaceCategoryHandler handles race category requests
+type RaceCategoryHandler struct {
+	validator       *utils.Validator
+	categoryService *services.RaceCategoryService
+}
+
+// NewRaceCategoryHandler creates a new race category handler
+func NewRaceCategoryHandler(categoryService *services.RaceCategoryService) *RaceCategoryHandler {
+	return &RaceCategoryHandler{
+		validator:       utils.NewValidator(),
+		categoryService: categoryService,
+	}
+}
+
+// ListByEvent returns the categories of an event with the number of registered participants
+func (h *RaceCategoryHandler) ListByEvent(c *gin.Context) {
+	eventID := c.Param("id")
+
+	err := services.ErrEventNotFound
+	var categories []models.RaceCategory
+	if isValidID(eventID) {
+		categories, err = h.categoryService.ListByEvent(c.Request.Context(), eventID)
+	}
+	if respondCategoryError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"categories": categories,
+	})
+}
+
+// Create adds a race category to an event (protected route)
+func (h *RaceCategoryHandler) Create(c *gin.Context) {
+	category, ok := h.bindCategory(c)
+	if !ok {
+		return
+	}
+	category.EventID = c.Param("id")
+
+	err := services.ErrEventNotFound
+	if isValidID(category.EventID) {
+		err = h.categoryService.Create(c.Request.Context(), category)
+	}
+	if respondCategoryError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s created race category %s (%s) for event %s",
+		middleware.GetAdminEmail(c), category.Name, category.ID, category.EventID)
+
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Race category created successfully", category)
+}
+
+// Update replaces the details of a race category (protected route)
+func (h *RaceCategoryHandler) Update(c *gin.Context) {
+	category, ok := h.bindCategory(c)
+	if !ok {
+		return
+	}
+	category.ID = c.Param("id")
+
+	err := services.ErrCategoryNotFound
+	if isValidID(category.ID) {
+		err = h.categoryService.Update(c.Request.Context(), category)
+	}
+	if respondCategoryError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s updated race category %s (%s)", middleware.GetAdminEmail(c), category.Name, category.ID)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Race category updated successfully", category)
+}
+
+// Delete removes a race category nobody has registered for (protected route)
+func (h *RaceCategoryHandler) Delete(c *gin.Context) {
+	categoryID := c.Param("id")
+
+	err := services.ErrCategoryNotFound
+	if isValidID(categoryID) {
+		err = h.categoryService.Delete(c.Request.Context(), categoryID)
+	}
+	if respondCategoryError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s deleted race category %s", middleware.GetAdminEmail(c), categoryID)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Race category deleted successfully", gin.H{
+		"id": categoryID,
+	})
+}
+
+// bindCategory binds and validates a race category request, writing the error response if it is invalid
+func (h *RaceCategoryHandler) bindCategory(c *gin.Context) (*models.RaceCategory, bool) {
+	var req models.RaceCategoryRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return nil, false
+	}
+
+	req.Name = h.validator.SanitizeString(req.Name)
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+
+	var validationErrors []utils.ValidationError
+	if req.Name == "" || len(req.Name) > 100 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "name", Message: "name is required and must be at most 100 characters"})
+	}
+	if req.DistanceKM <= 0 || req.DistanceKM > 1000 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "distance_km", Message: "distance_km must be greater than 0 and at most 1000"})
+	}
+	if req.Price <= 0 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "price", Message: "price must be a positive amount in the smallest currency unit"})
+	}
+	if req.Currency != "" && !currencyPattern.MatchString(req.Currency) {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "currency", Message: "currency must be a 3-letter ISO 4217 code"})
+	}
+	if req.Capacity <= 0 {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "capacity", Message: "capacity must be a positive integer"})
+	}
+	if req.MinAge != nil && (*req.MinAge < 0 || *req.MinAge > 120) {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "min_age", Message: "min_age must be between 0 and 120"})
+	}
+	if req.MaxAge != nil && (*req.MaxAge < 0 || *req.MaxAge > 120) {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "max_age", Message: "max_age must be between 0 and 120"})
+	}
+	if req.MinAge != nil && req.MaxAge != nil && *req.MinAge > *req.MaxAge {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "max_age", Message: "max_age must not be less than min_age"})
+	}
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
+		return nil, false
+	}
+
+	return &models.RaceCategory{
+		Name:       req.Name,
+		DistanceKM: req.DistanceKM,
+		Price:      req.Price,
+		Currency:   req.Currency,
+		Capacity:   req.Capacity,
+		MinAge:     req.MinAge,
+		MaxAge:     req.MaxAge,
+	}, true
+}
+
+// respondCategoryError writes the error response for a failed race category request and reports whether it did
+func respondCategoryError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrEventNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "EVENT_NOT_FOUND", "Event with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrCategoryNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "CATEGORY_NOT_FOUND", "Race category with the specified ID does not exist", nil)
+	case errors.Is(err, repository.ErrDuplicateCategory):
+		middleware.RespondWithError(c, http.StatusConflict, "DUPLICATE_CATEGORY", "This event already has a race category with that name", nil)
+	case errors.Is(err, repository.ErrCategoryInUse):
+		middleware.RespondWithError(c, http.StatusConflict, "CATEGORY_IN_USE", "Race category has registered participants and cannot be deleted", nil)
+	default:
+		utils.DBLogger.Error("Race category request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
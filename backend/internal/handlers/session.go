@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// SessionHandler handles refreshing and logging out admin sessions
+type SessionHandler struct {
+	sessionService *services.SessionService
+}
+
+// NewSessionHandler creates a new admin session handler
+func NewSessionHandler(sessionService *services.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token (public route)
+func (h *SessionHandler) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	session, err := h.sessionService.Refresh(c.Request.Context(), req.RefreshToken)
+	switch {
+	case errors.Is(err, services.ErrRefreshTokenReused):
+		middleware.RespondWithError(c, http.StatusUnauthorized, "REFRESH_TOKEN_REUSED", "This refresh token was already used, so the session has been logged out. Please log in again.", nil)
+		return
+	case errors.Is(err, services.ErrInvalidRefreshToken):
+		middleware.RespondWithError(c, http.StatusUnauthorized, "INVALID_REFRESH_TOKEN", "Refresh token is invalid, expired or logged out. Please log in again.", nil)
+		return
+	case err != nil:
+		utils.AuthLogger.Error("Failed to refresh admin session: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Session refreshed successfully", session)
+}
+
+// Logout revokes the session of a refresh token (public route, so sessions
+// can be logged out after their access token has expired)
+func (h *SessionHandler) Logout(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	if err := h.sessionService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		utils.AuthLogger.Error("Failed to log out admin session: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Logged out successfully", nil)
+}
+
+// LogoutAll revokes every session of the logged-in admin (protected route)
+func (h *SessionHandler) LogoutAll(c *gin.Context) {
+	if err := h.sessionService.LogoutAll(c.Request.Context(), middleware.GetAdminID(c)); err != nil {
+		utils.AuthLogger.Error("Failed to log out sessions of admin %s: %v", middleware.GetAdminEmail(c), err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s logged out of all sessions", middleware.GetAdminEmail(c))
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Logged out of all sessions successfully", nil)
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// transferStatuses are the statuses transfers can be filtered by
+var transferStatuses = map[string]bool{
+	"PENDING":   true,
+	"COMPLETED": true,
+	"CANCELLED": true,
+	"EXPIRED":   true,
+}
+
+// TransferHandler handles registration transfer requests
+type TransferHandler struct {
+	validator       *utils.Validator
+	transferService *services.TransferService
+}
+
+// NewTransferHandler creates a new registration transfer handler
+func NewTransferHandler(transferService *services.TransferService) *TransferHandler {
+	return &TransferHandler{
+		validator:       utils.NewValidator(),
+		transferService: transferService,
+	}
+}
+
+// Start starts a transfer of a registration to another runner (protected route)
+func (h *TransferHandler) Start(c *gin.Context) {
+	req, ok := bindTransferRequest(c, h.validator)
+	if !ok {
+		return
+	}
+
+	participantID := c.Param("id")
+
+	err := services.ErrParticipantNotFound
+	var transfer *models.RegistrationTransfer
+	if isValidID(participantID) {
+		transfer, err = h.transferService.Start(c.Request.Context(), participantID, req.Name, req.Email, middleware.GetAdminID(c))
+	}
+	if respondTransferError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s started the transfer of registration %s from %s to %s", middleware.GetAdminEmail(c), participantID, transfer.FromEmail, transfer.ToEmail)
+
+	middleware.RespondWithSuccess(c, http.StatusCreated, "Transfer started successfully", transfer)
+}
+
+// Cancel withdraws the pending transfer of a registration (protected route)
+func (h *TransferHandler) Cancel(c *gin.Context) {
+	participantID := c.Param("id")
+
+	err := services.ErrParticipantNotFound
+	var transfer *models.RegistrationTransfer
+	if isValidID(participantID) {
+		transfer, err = h.transferService.Cancel(c.Request.Context(), participantID, middleware.GetAdminID(c))
+	}
+	if respondTransferError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s cancelled the transfer of registration %s to %s", middleware.GetAdminEmail(c), participantID, transfer.ToEmail)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Transfer cancelled successfully", transfer)
+}
+
+// ListByParticipant returns the transfer history of a registration (protected route)
+func (h *TransferHandler) ListByParticipant(c *gin.Context) {
+	participantID := c.Param("id")
+
+	err := services.ErrParticipantNotFound
+	var transfers []models.RegistrationTransfer
+	if isValidID(participantID) {
+		transfers, err = h.transferService.ListByParticipant(c.Request.Context(), participantID)
+	}
+	if respondTransferError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Transfers retrieved successfully", transfers)
+}
+
+// ListByEvent returns the transfers of an event, optionally filtered by status (protected route)
+func (h *TransferHandler) ListByEvent(c *gin.Context) {
+	eventID := c.Param("id")
+
+	status := strings.ToUpper(strings.TrimSpace(c.Query("status")))
+	if status != "" && !transferStatuses[status] {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "status", Message: "status must be PENDING, COMPLETED, CANCELLED or EXPIRED"},
+		})
+		return
+	}
+
+	err := services.ErrEventNotFound
+	var transfers []models.RegistrationTransfer
+	if isValidID(eventID) {
+		transfers, err = h.transferService.ListByEvent(c.Request.Context(), eventID, status)
+	}
+	if respondTransferError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Transfers retrieved successfully", transfers)
+}
+
+// Offer returns the registration a transfer link offers, for the recipient to
+// review before accepting it (public route)
+func (h *TransferHandler) Offer(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	offer, err := h.transferService.Offer(c.Request.Context(), req.Token)
+	if respondTransferError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Transfer retrieved successfully", offer)
+}
+
+// Accept completes a transfer with the details of its recipient (public route)
+func (h *TransferHandler) Accept(c *gin.Context) {
+	var req models.TransferAcceptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+
+	offer, err := h.transferService.Offer(c.Request.Context(), req.Token)
+	if respondTransferError(c, err) {
+		return
+	}
+
+	// The recipient's details are checked like those of a new registration,
+	// with the email address the transfer was sent to
+	details := models.CreateParticipantRequest{
+		Name:            req.Name,
+		Email:           offer.ToEmail,
+		Phone:           req.Phone,
+		InstagramHandle: req.InstagramHandle,
+		Address:         req.Address,
+		DateOfBirth:     req.DateOfBirth,
+	}
+	if validationErrors := prepareRegistration(h.validator, &details); len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
+		return
+	}
+	req.Name = details.Name
+	req.Phone = details.Phone
+	req.InstagramHandle = details.InstagramHandle
+	req.Address = details.Address
+	req.DateOfBirth = details.DateOfBirth
+
+	participant, err := h.transferService.Accept(c.Request.Context(), req)
+	if respondTransferError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Registration transferred successfully", gin.H{
+		"id":                  participant.ID,
+		"event_id":            participant.EventID,
+		"category_id":         participant.CategoryID,
+		"name":                participant.Name,
+		"email":               participant.Email,
+		"registration_status": participant.RegistrationStatus,
+		"payment_status":      participant.PaymentStatus,
+		"bib_number":          participant.BibNumber,
+	})
+}
+
+// bindTransferRequest reads and validates the recipient of a transfer,
+// writing the error response if it is invalid
+func bindTransferRequest(c *gin.Context, v *utils.Validator) (models.TransferRequest, bool) {
+	var req models.TransferRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return req, false
+	}
+
+	req.Name = v.SanitizeString(req.Name)
+	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
+
+	var validationErrors []utils.ValidationError
+	if err := v.ValidateName(req.Name); err != nil {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "name", Message: err.Error()})
+	}
+	if err := v.ValidateEmail(req.Email); err != nil {
+		validationErrors = append(validationErrors, utils.ValidationError{Field: "email", Message: err.Error()})
+	}
+
+	if len(validationErrors) > 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", validationErrors)
+		return req, false
+	}
+
+	return req, true
+}
+
+// respondTransferError writes the response for a transfer error, reporting whether there was one
+func respondTransferError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrParticipantNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "Registration does not exist", nil)
+	case errors.Is(err, services.ErrEventNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "EVENT_NOT_FOUND", "Event does not exist", nil)
+	case errors.Is(err, services.ErrInvalidTransferLink):
+		middleware.RespondWithError(c, http.StatusUnauthorized, "INVALID_TRANSFER_LINK", "Transfer link is invalid, expired or already used", nil)
+	case errors.Is(err, services.ErrTransferClosed):
+		middleware.RespondWithError(c, http.StatusConflict, "TRANSFER_CLOSED", "Registrations of this event can no longer be transferred", nil)
+	case errors.Is(err, services.ErrTransferPending):
+		middleware.RespondWithError(c, http.StatusConflict, "TRANSFER_PENDING", "Registration already has a pending transfer. Cancel it first.", nil)
+	case errors.Is(err, services.ErrNoTransferPending):
+		middleware.RespondWithError(c, http.StatusConflict, "NO_TRANSFER_PENDING", "Registration has no pending transfer", nil)
+	case errors.Is(err, repository.ErrDuplicateEmail):
+		middleware.RespondWithError(c, http.StatusConflict, "DUPLICATE_EMAIL", "Email address is already registered for this event", nil)
+	case errors.Is(err, services.ErrCancelled):
+		middleware.RespondWithError(c, http.StatusConflict, "REGISTRATION_CANCELLED", "Registration is cancelled", nil)
+	case errors.Is(err, services.ErrNotPaid):
+		middleware.RespondWithError(c, http.StatusConflict, "NOT_PAID", "Only paid registrations can be transferred", nil)
+	case errors.Is(err, services.ErrAlreadyCheckedIn):
+		middleware.RespondWithError(c, http.StatusConflict, "ALREADY_CHECKED_IN", "Participant has already checked in", nil)
+	case errors.Is(err, services.ErrKitAlreadyCollected):
+		middleware.RespondWithError(c, http.StatusConflict, "KIT_ALREADY_COLLECTED", "Race kit was already collected", nil)
+	case errors.Is(err, services.ErrDateOfBirthRequired):
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "date_of_birth", Message: "date_of_birth is required for this race category"},
+		})
+	case errors.Is(err, services.ErrAgeNotEligible):
+		middleware.RespondWithError(c, http.StatusBadRequest, "AGE_NOT_ELIGIBLE", "Participant age on the event date is outside the limits of this race category", nil)
+	default:
+		utils.DBLogger.Error("Transfer request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
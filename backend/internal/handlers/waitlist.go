@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/internal/middleware"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// WaitlistHandler handles waitlist requests
+type WaitlistHandler struct {
+	waitlistService *services.WaitlistService
+}
+
+// NewWaitlistHandler creates a new waitlist handler
+func NewWaitlistHandler(waitlistService *services.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{waitlistService: waitlistService}
+}
+
+// List returns the waitlist of an event in queue order, optionally for one race category (protected route)
+func (h *WaitlistHandler) List(c *gin.Context) {
+	eventID := c.Param("id")
+
+	categoryID := strings.TrimSpace(c.Query("category_id"))
+	if categoryID != "" && !isValidID(categoryID) {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid query parameters", []utils.ValidationError{
+			{Field: "category_id", Message: "category_id must be a valid ID"},
+		})
+		return
+	}
+
+	err := services.ErrEventNotFound
+	var waitlist []models.Participant
+	if isValidID(eventID) {
+		waitlist, err = h.waitlistService.List(c.Request.Context(), eventID, categoryID)
+	}
+	if respondWaitlistError(c, err) {
+		return
+	}
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "", gin.H{
+		"waitlist": waitlist,
+		"total":    len(waitlist),
+	})
+}
+
+// Reorder moves a participant to another position in their waitlist (protected route)
+func (h *WaitlistHandler) Reorder(c *gin.Context) {
+	participantID := c.Param("id")
+
+	var req struct {
+		Position int `json:"position"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", nil)
+		return
+	}
+	if req.Position < 1 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid input data", []utils.ValidationError{
+			{Field: "position", Message: "position must be a positive integer"},
+		})
+		return
+	}
+
+	err := services.ErrParticipantNotFound
+	var participant *models.Participant
+	if isValidID(participantID) {
+		participant, err = h.waitlistService.Reorder(c.Request.Context(), participantID, req.Position)
+	}
+	if respondWaitlistError(c, err) {
+		return
+	}
+
+	utils.AuthLogger.Info("Admin %s moved %s to waitlist position %d",
+		middleware.GetAdminEmail(c), participant.Email, *participant.WaitlistPosition)
+
+	middleware.RespondWithSuccess(c, http.StatusOK, "Waitlist position updated successfully", participant)
+}
+
+// respondWaitlistError writes the error response for a failed waitlist request and reports whether it did
+func respondWaitlistError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrEventNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "EVENT_NOT_FOUND", "Event with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrParticipantNotFound):
+		middleware.RespondWithError(c, http.StatusNotFound, "PARTICIPANT_NOT_FOUND", "Participant with the specified ID does not exist", nil)
+	case errors.Is(err, services.ErrNotWaitlisted):
+		middleware.RespondWithError(c, http.StatusConflict, "NOT_WAITLISTED", "Participant is not on the waitlist", nil)
+	default:
+		utils.DBLogger.Error("Waitlist request failed: %v", err)
+		middleware.RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIMailer sends email through a transactional email provider's HTTP API.
+//
+// Each message is POSTed as JSON to the configured URL with the API key as a
+// bearer token. Attachments are base64 encoded, and inline ones carry the
+// content ID the HTML refers to. Most providers accept this shape directly or
+// through a small relay.
+type APIMailer struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// NewAPIMailer creates a new HTTP API mailer
+func NewAPIMailer(url, apiKey string) *APIMailer {
+	return &APIMailer{
+		url:    url,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// apiAddress is an email address in the API request
+type apiAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+// apiAttachment is an attachment in the API request
+type apiAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"` // Base64 encoded by encoding/json
+	ContentID   string `json:"content_id,omitempty"`
+}
+
+// apiMessage is the body of the API request
+type apiMessage struct {
+	MessageID   string          `json:"message_id"`
+	From        apiAddress      `json:"from"`
+	To          []apiAddress    `json:"to"`
+	Subject     string          `json:"subject"`
+	Text        string          `json:"text"`
+	HTML        string          `json:"html,omitempty"`
+	Attachments []apiAttachment `json:"attachments,omitempty"`
+}
+
+// Name returns the driver name
+func (m *APIMailer) Name() string {
+	return "api"
+}
+
+// Send posts msg to the provider
+func (m *APIMailer) Send(ctx context.Context, msg *Message) error {
+	if msg.MessageID == "" {
+		id, err := newMessageID(msg.From.Address)
+		if err != nil {
+			return err
+		}
+		msg.MessageID = id
+	}
+
+	body := apiMessage{
+		MessageID: msg.MessageID,
+		From:      apiAddress{Email: msg.From.Address, Name: msg.From.Name},
+		Subject:   msg.Subject,
+		Text:      msg.Text,
+		HTML:      msg.HTML,
+	}
+	for _, to := range msg.To {
+		body.To = append(body.To, apiAddress{Email: to.Address, Name: to.Name})
+	}
+	for _, a := range msg.Attachments {
+		body.Attachments = append(body.Attachments, apiAttachment{
+			Filename:    a.Filename,
+			ContentType: a.ContentType,
+			Content:     a.Data,
+			ContentID:   a.ContentID,
+		})
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode email: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		reply, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err := fmt.Errorf("email API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(reply)))
+		// Apart from credential, timeout and rate-limit failures, a 4xx means the
+		// message itself was refused, so retrying won't help
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+			resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden &&
+			resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			return fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Close does nothing; the HTTP client manages its own connections
+func (m *APIMailer) Close() error {
+	return nil
+}
@@ -0,0 +1,84 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIMailerSend(t *testing.T) {
+	var got apiMessage
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	msg := testMessage("runner@example.com")
+	msg.Attachments = []Attachment{{Filename: "qr.png", ContentType: "image/png", Data: []byte("\x89PNG\x00"), ContentID: "checkin-qr"}}
+
+	if err := NewAPIMailer(server.URL, "test-api-key").Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if auth != "Bearer test-api-key" {
+		t.Errorf("Authorization = %q, want the API key as a bearer token", auth)
+	}
+	if got.MessageID == "" || got.MessageID != msg.MessageID {
+		t.Errorf("message ID = %q, want %q", got.MessageID, msg.MessageID)
+	}
+	if got.From.Email != "noreply@tautaurun.id" || got.From.Name != "Tau-Tau Run" {
+		t.Errorf("from = %+v", got.From)
+	}
+	if len(got.To) != 1 || got.To[0].Email != "runner@example.com" {
+		t.Errorf("to = %+v, want runner@example.com", got.To)
+	}
+	if got.Subject != msg.Subject || got.Text != msg.Text || got.HTML != msg.HTML {
+		t.Errorf("content = %q %q %q", got.Subject, got.Text, got.HTML)
+	}
+	if len(got.Attachments) != 1 || got.Attachments[0].ContentID != "checkin-qr" || !bytes.Equal(got.Attachments[0].Content, []byte("\x89PNG\x00")) {
+		t.Errorf("attachments = %+v, want the inline QR code", got.Attachments)
+	}
+}
+
+func TestAPIMailerSendErrors(t *testing.T) {
+	tests := []struct {
+		status       int
+		wantRejected bool
+	}{
+		{status: http.StatusBadRequest, wantRejected: true},
+		{status: http.StatusUnprocessableEntity, wantRejected: true},
+		{status: http.StatusUnauthorized},
+		{status: http.StatusForbidden},
+		{status: http.StatusRequestTimeout},
+		{status: http.StatusTooManyRequests},
+		{status: http.StatusInternalServerError},
+		{status: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, `{"error":"nope"}`, tt.status)
+			}))
+			defer server.Close()
+
+			err := NewAPIMailer(server.URL, "test-api-key").Send(context.Background(), testMessage("runner@example.com"))
+			if err == nil {
+				t.Fatal("Send() error = nil")
+			}
+			if rejected := errors.Is(err, ErrRejected); rejected != tt.wantRejected {
+				t.Errorf("Send() error = %v, want rejected %t", err, tt.wantRejected)
+			}
+		})
+	}
+}
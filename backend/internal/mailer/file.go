@@ -0,0 +1,77 @@
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer writes each message as an .eml file to a directory instead of
+// sending it. The files open in any mail client, which makes it the safe
+// choice for development and staging.
+type FileMailer struct {
+	dir string
+}
+
+// NewFileMailer creates a file mailer writing to dir, creating it if needed
+func NewFileMailer(dir string) (*FileMailer, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email directory: %w", err)
+	}
+
+	if err := os.MkdirAll(abs, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create email directory: %w", err)
+	}
+
+	return &FileMailer{dir: abs}, nil
+}
+
+// Name returns the driver name
+func (m *FileMailer) Name() string {
+	return "file"
+}
+
+// Send writes msg to a new file named after the time it was sent
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("failed to name email file: %w", err)
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000Z"), hex.EncodeToString(suffix))
+
+	// Write to a temporary file first so readers never see a partial message
+	tmp, err := os.CreateTemp(m.dir, ".eml-*")
+	if err != nil {
+		return fmt.Errorf("failed to create email file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write email file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write email file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(m.dir, name)); err != nil {
+		return fmt.Errorf("failed to store email file: %w", err)
+	}
+
+	return nil
+}
+
+// Close does nothing
+func (m *FileMailer) Close() error {
+	return nil
+}
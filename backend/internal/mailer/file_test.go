@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "emails")
+	m, err := NewFileMailer(dir)
+	if err != nil {
+		t.Fatalf("NewFileMailer() error = %v", err)
+	}
+
+	ctx := context.Background()
+	for _, to := range []string{"first@example.com", "second@example.com"} {
+		if err := m.Send(ctx, testMessage(to)); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("directory has %d files, want 2 .eml files", len(entries))
+	}
+
+	recipients := map[string]bool{}
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".eml") {
+			t.Errorf("file %s is not an .eml file", entry.Name())
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		msg, err := mail.ReadMessage(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("file %s is not an email: %v", entry.Name(), err)
+		}
+		recipients[msg.Header.Get("To")] = true
+	}
+	if !recipients["<first@example.com>"] || !recipients["<second@example.com>"] {
+		t.Errorf("recipients = %v, want both messages", recipients)
+	}
+}
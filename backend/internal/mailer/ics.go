@@ -0,0 +1,90 @@
+package mailer
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CalendarEvent is an all-day event to attach to an email as an iCalendar
+// (.ics) file
+type CalendarEvent struct {
+	UID         string // Stable across emails so calendars update rather than duplicate the event
+	Summary     string
+	Location    string
+	Description string
+	Date        time.Time // Only the date is used
+}
+
+// Attachment returns the event as an RFC 5545 iCalendar attachment that mail
+// clients offer to add to the recipient's calendar
+func (e CalendarEvent) Attachment(filename string) Attachment {
+	return Attachment{
+		Filename:    filename,
+		ContentType: "text/calendar; charset=UTF-8; method=PUBLISH",
+		Data:        e.ICS(),
+	}
+}
+
+// ICS encodes the event in iCalendar format
+func (e CalendarEvent) ICS() []byte {
+	var buf bytes.Buffer
+	line := func(s string) {
+		buf.WriteString(foldLine(s))
+		buf.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Tau-Tau Run//Registration//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("BEGIN:VEVENT")
+	line("UID:" + escapeText(e.UID))
+	line("DTSTAMP:" + time.Now().UTC().Format("20060102T150405Z"))
+	line("DTSTART;VALUE=DATE:" + e.Date.Format("20060102"))
+	line("DTEND;VALUE=DATE:" + e.Date.AddDate(0, 0, 1).Format("20060102"))
+	line("SUMMARY:" + escapeText(e.Summary))
+	if e.Location != "" {
+		line("LOCATION:" + escapeText(e.Location))
+	}
+	if e.Description != "" {
+		line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	line("TRANSP:TRANSPARENT")
+	line("END:VEVENT")
+	line("END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+// escapeText escapes an iCalendar TEXT value
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// foldLine splits a content line into lines of at most 75 octets, continued
+// with a leading space, without splitting UTF-8 characters
+func foldLine(s string) string {
+	const limit = 75
+
+	var buf strings.Builder
+	width := 0
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if width+size > limit {
+			buf.WriteString("\r\n ")
+			width = 1
+		}
+		buf.WriteRune(r)
+		width += size
+	}
+	return buf.String()
+}
@@ -0,0 +1,92 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarEventICS(t *testing.T) {
+	e := CalendarEvent{
+		UID:         "event-id@tautaurun.id",
+		Summary:     "City Run; 10K, Jakarta",
+		Location:    "Monas\nJakarta",
+		Description: strings.Repeat("Lari pagi bersama – ", 10),
+		Date:        time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	ics := string(e.ICS())
+	if !strings.HasSuffix(ics, "\r\n") {
+		t.Error("ICS() does not end with CRLF")
+	}
+
+	var unfolded []string
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line of %d octets, want at most 75: %q", len(line), line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+	}
+
+	properties := map[string]string{}
+	for _, line := range unfolded {
+		name, value, _ := strings.Cut(line, ":")
+		properties[name] = value
+	}
+
+	want := map[string]string{
+		"UID":                "event-id@tautaurun.id",
+		"SUMMARY":            `City Run\; 10K\, Jakarta`,
+		"LOCATION":           `Monas\nJakarta`,
+		"DESCRIPTION":        strings.Repeat("Lari pagi bersama – ", 10),
+		"DTSTART;VALUE=DATE": "20261231",
+		"DTEND;VALUE=DATE":   "20270101",
+		"METHOD":             "PUBLISH",
+		"TRANSP":             "TRANSPARENT",
+		"CALSCALE":           "GREGORIAN",
+		"PRODID":             "-//Tau-Tau Run//Registration//EN",
+		"VERSION":            "2.0",
+	}
+	for name, value := range want {
+		if properties[name] != value {
+			t.Errorf("%s = %q, want %q", name, properties[name], value)
+		}
+	}
+}
+
+func TestCalendarEventICSOmitsEmptyFields(t *testing.T) {
+	ics := string(CalendarEvent{UID: "id", Summary: "Run", Date: time.Date(2026, 12, 6, 0, 0, 0, 0, time.UTC)}.ICS())
+
+	for _, name := range []string{"LOCATION", "DESCRIPTION"} {
+		if strings.Contains(ics, name+":") {
+			t.Errorf("ICS() contains an empty %s", name)
+		}
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	tests := []string{
+		"",
+		"SUMMARY:short",
+		strings.Repeat("a", 75),
+		strings.Repeat("a", 76),
+		strings.Repeat("é", 60), // Two octets each, so folds must not split one
+	}
+
+	for _, s := range tests {
+		folded := foldLine(s)
+		lines := strings.Split(folded, "\r\n ")
+		for _, line := range lines {
+			if len(line) > 75 {
+				t.Errorf("foldLine(%q) has a line of %d octets", s, len(line))
+			}
+		}
+		if strings.Join(lines, "") != s {
+			t.Errorf("foldLine(%q) unfolds to %q", s, strings.Join(lines, ""))
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Package mailer builds MIME email messages and delivers them.
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tau-tau-run/backend/config"
+)
+
+// Errors returned by mailers
+var (
+	// ErrRejected is returned when the mail server or API refuses a message
+	// outright, so sending it again will fail the same way
+	ErrRejected = errors.New("message rejected")
+
+	// ErrRecipientNotAllowed is returned for recipients outside
+	// SMTP_ALLOWED_RECIPIENTS when running outside production
+	ErrRecipientNotAllowed = errors.New("recipient not allowed")
+)
+
+// Mailer delivers email messages
+type Mailer interface {
+	Name() string
+	Send(ctx context.Context, msg *Message) error
+	// Close releases connections held open between sends
+	Close() error
+}
+
+// New creates the mailer selected by cfg. Outside production, mailers that
+// deliver real email only send to the allowed recipients.
+func New(cfg *config.Config) (Mailer, error) {
+	var m Mailer
+	switch cfg.SMTP.Driver {
+	case "smtp":
+		m = NewSMTPMailer(SMTPOptions{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			TLS:      cfg.SMTP.TLS,
+			// One connection per outbox worker
+			MaxIdle: cfg.Outbox.Workers,
+		})
+	case "api":
+		m = NewAPIMailer(cfg.SMTP.APIURL, cfg.SMTP.APIKey)
+	case "file":
+		fileMailer, err := NewFileMailer(cfg.SMTP.FileDir)
+		if err != nil {
+			return nil, err
+		}
+		return fileMailer, nil
+	case "memory":
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("unsupported email driver %q", cfg.SMTP.Driver)
+	}
+
+	if !cfg.IsProduction() {
+		m = RestrictRecipients(m, cfg.SMTP.AllowedRecipients)
+	}
+	return m, nil
+}
+
+// restrictedMailer refuses messages to recipients outside an allowlist
+type restrictedMailer struct {
+	Mailer
+	allowed []string
+}
+
+// RestrictRecipients wraps m so that it only sends to the given addresses and
+// domains. Domains are written "@example.com". A message with any recipient
+// outside the list is not sent at all.
+func RestrictRecipients(m Mailer, allowed []string) Mailer {
+	normalized := make([]string, len(allowed))
+	for i, a := range allowed {
+		normalized[i] = strings.ToLower(strings.TrimSpace(a))
+	}
+	return &restrictedMailer{Mailer: m, allowed: normalized}
+}
+
+// Send sends msg if all its recipients are allowed
+func (r *restrictedMailer) Send(ctx context.Context, msg *Message) error {
+	for _, recipient := range msg.Recipients() {
+		if !r.isAllowed(recipient) {
+			return fmt.Errorf("%w: %s", ErrRecipientNotAllowed, recipient)
+		}
+	}
+	return r.Mailer.Send(ctx, msg)
+}
+
+// isAllowed reports whether an address matches the allowlist
+func (r *restrictedMailer) isAllowed(address string) bool {
+	address = strings.ToLower(address)
+	for _, a := range r.allowed {
+		if strings.HasPrefix(a, "@") {
+			if strings.HasSuffix(address, a) {
+				return true
+			}
+		} else if address == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"testing"
+
+	"github.com/tau-tau-run/backend/config"
+)
+
+// testMessage returns a message from the race organizers to the given addresses
+func testMessage(to ...string) *Message {
+	m := &Message{
+		From:    mail.Address{Name: "Tau-Tau Run", Address: "noreply@tautaurun.id"},
+		Subject: "Payment Confirmed",
+		Text:    "See you on race day",
+		HTML:    "<p>See you on race day</p>",
+	}
+	for _, address := range to {
+		m.To = append(m.To, mail.Address{Address: address})
+	}
+	return m
+}
+
+func TestRestrictRecipients(t *testing.T) {
+	tests := []struct {
+		name    string
+		to      []string
+		wantErr error
+	}{
+		{name: "allowed address", to: []string{"tester@example.com"}},
+		{name: "allowed address in another case", to: []string{"Tester@Example.com"}},
+		{name: "allowed domain", to: []string{"anyone@tautaurun.id"}},
+		{name: "other address", to: []string{"runner@example.com"}, wantErr: ErrRecipientNotAllowed},
+		{name: "subdomain of allowed domain", to: []string{"anyone@mail.tautaurun.id"}, wantErr: ErrRecipientNotAllowed},
+		{name: "domain as a suffix of another", to: []string{"anyone@nottautaurun.id"}, wantErr: ErrRecipientNotAllowed},
+		{name: "one of several not allowed", to: []string{"tester@example.com", "runner@example.com"}, wantErr: ErrRecipientNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := NewMemoryMailer()
+			m := RestrictRecipients(sent, []string{" tester@example.com ", "@TauTauRun.id"})
+
+			err := m.Send(context.Background(), testMessage(tt.to...))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Send() error = %v, want %v", err, tt.wantErr)
+			}
+			wantSent := 1
+			if tt.wantErr != nil {
+				wantSent = 0
+			}
+			if got := len(sent.Sent()); got != wantSent {
+				t.Errorf("sent %d messages, want %d", got, wantSent)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		driver         string
+		env            string
+		wantName       string
+		wantRestricted bool
+		wantErr        bool
+	}{
+		{driver: "smtp", env: "production", wantName: "smtp"},
+		{driver: "smtp", env: "staging", wantName: "smtp", wantRestricted: true},
+		{driver: "api", env: "development", wantName: "api", wantRestricted: true},
+		{driver: "file", env: "development", wantName: "file"},
+		{driver: "memory", env: "development", wantName: "memory"},
+		{driver: "carrier-pigeon", env: "development", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver+" in "+tt.env, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Server.Env = tt.env
+			cfg.SMTP.Driver = tt.driver
+			cfg.SMTP.FileDir = t.TempDir()
+
+			m, err := New(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, want error %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if m.Name() != tt.wantName {
+				t.Errorf("New() = %s mailer, want %s", m.Name(), tt.wantName)
+			}
+			if _, restricted := m.(*restrictedMailer); restricted != tt.wantRestricted {
+				t.Errorf("New() restricted = %t, want %t", restricted, tt.wantRestricted)
+			}
+		})
+	}
+}
+
+func TestMemoryMailer(t *testing.T) {
+	m := NewMemoryMailer()
+
+	if err := m.Send(context.Background(), testMessage("runner@example.com")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	sent := m.Sent()
+	if len(sent) != 1 || sent[0].Message.Subject != "Payment Confirmed" || len(sent[0].Raw) == 0 {
+		t.Fatalf("Sent() = %+v, want the message with its raw bytes", sent)
+	}
+
+	m.Reset()
+	if got := len(m.Sent()); got != 0 {
+		t.Errorf("Sent() after Reset() = %d messages, want none", got)
+	}
+}
@@ -0,0 +1,62 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// SentMessage is a message captured by a MemoryMailer
+type SentMessage struct {
+	Message *Message
+	Raw     []byte // The message as it would have been sent
+}
+
+// MemoryMailer keeps sent messages in memory instead of sending them, for
+// tests and local tooling
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []SentMessage
+}
+
+// NewMemoryMailer creates a new in-memory mailer
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Name returns the driver name
+func (m *MemoryMailer) Name() string {
+	return "memory"
+}
+
+// Send records msg
+func (m *MemoryMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, SentMessage{Message: msg, Raw: data})
+	return nil
+}
+
+// Sent returns the messages sent so far, oldest first
+func (m *MemoryMailer) Sent() []SentMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SentMessage(nil), m.sent...)
+}
+
+// Reset forgets the messages sent so far
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = nil
+}
+
+// Close does nothing
+func (m *MemoryMailer) Close() error {
+	return nil
+}
@@ -0,0 +1,240 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Message is an email with a plain text body, an optional HTML alternative
+// and attachments
+type Message struct {
+	From    mail.Address
+	To      []mail.Address
+	Subject string
+	Text    string
+	HTML    string
+
+	// Attachments with a ContentID are shown inline in the HTML body,
+	// referenced as cid:<ContentID>. The others are regular attachments.
+	Attachments []Attachment
+
+	// Date and MessageID are set when the message is built if left empty
+	Date      time.Time
+	MessageID string
+}
+
+// Attachment is a file attached to a message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+	ContentID   string
+}
+
+// part is one body part of a multipart message
+type part struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// Recipients returns the addresses of all recipients, for the SMTP envelope
+func (m *Message) Recipients() []string {
+	recipients := make([]string, len(m.To))
+	for i, to := range m.To {
+		recipients[i] = to.Address
+	}
+	return recipients
+}
+
+// Bytes builds the message in RFC 5322 format with CRLF line endings.
+//
+// The body is multipart/alternative with the plain text and HTML versions,
+// wrapped in multipart/related when there are inline attachments and in
+// multipart/mixed when there are regular ones. Header values are RFC 2047
+// encoded where they contain non-ASCII characters.
+func (m *Message) Bytes() ([]byte, error) {
+	if m.Date.IsZero() {
+		m.Date = time.Now()
+	}
+	if m.MessageID == "" {
+		id, err := newMessageID(m.From.Address)
+		if err != nil {
+			return nil, err
+		}
+		m.MessageID = id
+	}
+
+	body, err := m.body()
+	if err != nil {
+		return nil, err
+	}
+
+	to := make([]string, len(m.To))
+	for i := range m.To {
+		to[i] = m.To[i].String()
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf, "Date", m.Date.Format(time.RFC1123Z))
+	writeHeader(&buf, "From", m.From.String())
+	writeHeader(&buf, "To", strings.Join(to, ", "))
+	writeHeader(&buf, "Message-ID", m.MessageID)
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("UTF-8", m.Subject))
+	writeHeader(&buf, "MIME-Version", "1.0")
+	for _, key := range []string{"Content-Type", "Content-Transfer-Encoding"} {
+		if value := body.header.Get(key); value != "" {
+			writeHeader(&buf, key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body.body)
+
+	return buf.Bytes(), nil
+}
+
+// body builds the top-level body part
+func (m *Message) body() (part, error) {
+	body := textPart("text/plain", m.Text)
+
+	if m.HTML != "" {
+		alternative, err := multipartOf("alternative", []part{body, textPart("text/html", m.HTML)})
+		if err != nil {
+			return part{}, err
+		}
+		body = alternative
+	}
+
+	var inline, attached []part
+	for _, a := range m.Attachments {
+		if a.ContentID != "" {
+			inline = append(inline, a.part())
+		} else {
+			attached = append(attached, a.part())
+		}
+	}
+
+	if len(inline) > 0 {
+		related, err := multipartOf("related", append([]part{body}, inline...))
+		if err != nil {
+			return part{}, err
+		}
+		body = related
+	}
+
+	if len(attached) > 0 {
+		mixed, err := multipartOf("mixed", append([]part{body}, attached...))
+		if err != nil {
+			return part{}, err
+		}
+		body = mixed
+	}
+
+	return body, nil
+}
+
+// part encodes an attachment as a base64 body part
+func (a Attachment) part() part {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType(a.ContentType, map[string]string{"name": a.Filename}))
+	header.Set("Content-Transfer-Encoding", "base64")
+
+	disposition := "attachment"
+	if a.ContentID != "" {
+		disposition = "inline"
+		header.Set("Content-ID", "<"+a.ContentID+">")
+	}
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename}))
+
+	return part{header: header, body: base64Lines(a.Data)}
+}
+
+// textPart encodes text as a quoted-printable UTF-8 body part
+func textPart(contentType, text string) part {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType+"; charset=UTF-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	var buf bytes.Buffer
+	w := quotedprintable.NewWriter(&buf)
+	// Writes to a bytes.Buffer cannot fail
+	_, _ = w.Write([]byte(normalizeNewlines(text)))
+	_ = w.Close()
+
+	return part{header: header, body: buf.Bytes()}
+}
+
+// multipartOf combines parts into a multipart body part of the given subtype
+func multipartOf(subtype string, parts []part) (part, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	for _, p := range parts {
+		pw, err := w.CreatePart(p.header)
+		if err != nil {
+			return part{}, fmt.Errorf("failed to build %s part: %w", subtype, err)
+		}
+		if _, err := pw.Write(p.body); err != nil {
+			return part{}, fmt.Errorf("failed to build %s part: %w", subtype, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return part{}, fmt.Errorf("failed to build %s part: %w", subtype, err)
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": w.Boundary()}))
+	return part{header: header, body: buf.Bytes()}, nil
+}
+
+// writeHeader writes one header line
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	buf.WriteString(": ")
+	buf.WriteString(value)
+	buf.WriteString("\r\n")
+}
+
+// base64Lines encodes data as base64 in lines of 76 characters
+func base64Lines(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var buf bytes.Buffer
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	return buf.Bytes()
+}
+
+// normalizeNewlines converts line endings to CRLF
+func normalizeNewlines(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", "\r\n")
+}
+
+// newMessageID generates a unique Message-ID in the domain of the sender
+func newMessageID(from string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate message ID: %w", err)
+	}
+
+	domain := "localhost"
+	if _, d, ok := strings.Cut(from, "@"); ok && d != "" {
+		domain = d
+	}
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain), nil
+}
@@ -0,0 +1,223 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parsedPart is a decoded body part of a built message
+type parsedPart struct {
+	contentType string
+	params      map[string]string
+	header      map[string][]string
+	body        []byte
+	parts       []parsedPart // Of a multipart part
+}
+
+// parse reads a built message back with the standard library
+func parse(t *testing.T, raw []byte) (*mail.Message, parsedPart) {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return msg, parsePart(t, msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Header, body)
+}
+
+func parsePart(t *testing.T, contentType, encoding string, header map[string][]string, body []byte) parsedPart {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("ParseMediaType(%q) error = %v", contentType, err)
+	}
+	p := parsedPart{contentType: mediaType, params: params, header: header}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		r := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			child, err := r.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("failed to read %s part: %v", mediaType, err)
+			}
+			data, err := io.ReadAll(child)
+			if err != nil {
+				t.Fatalf("failed to read %s part: %v", mediaType, err)
+			}
+			p.parts = append(p.parts, parsePart(t, child.Header.Get("Content-Type"), child.Header.Get("Content-Transfer-Encoding"), child.Header, data))
+		}
+		return p
+	}
+
+	switch encoding {
+	case "quoted-printable":
+		p.body, err = io.ReadAll(quotedprintable.NewReader(bytes.NewReader(body)))
+	case "base64":
+		p.body, err = base64.StdEncoding.DecodeString(strings.ReplaceAll(string(body), "\r\n", ""))
+	default:
+		p.body = body
+	}
+	if err != nil {
+		t.Fatalf("failed to decode %s part: %v", encoding, err)
+	}
+	return p
+}
+
+// contentTypes lists the media types of a part tree, nested in brackets
+func (p parsedPart) contentTypes() string {
+	if len(p.parts) == 0 {
+		return p.contentType
+	}
+	children := make([]string, len(p.parts))
+	for i, child := range p.parts {
+		children[i] = child.contentTypes()
+	}
+	return p.contentType + "[" + strings.Join(children, " ") + "]"
+}
+
+func TestMessageBytesStructure(t *testing.T) {
+	qr := Attachment{Filename: "qr.png", ContentType: "image/png", Data: []byte("\x89PNG"), ContentID: "qr@tautaurun"}
+	ics := Attachment{Filename: "race.ics", ContentType: "text/calendar", Data: []byte("BEGIN:VCALENDAR")}
+
+	tests := []struct {
+		name        string
+		html        string
+		attachments []Attachment
+		want        string
+	}{
+		{name: "plain text", want: "text/plain"},
+		{name: "HTML", html: "<p>Hi</p>", want: "multipart/alternative[text/plain text/html]"},
+		{
+			name:        "inline image",
+			html:        `<img src="cid:qr@tautaurun">`,
+			attachments: []Attachment{qr},
+			want:        "multipart/related[multipart/alternative[text/plain text/html] image/png]",
+		},
+		{
+			name:        "inline image and attachment",
+			html:        `<img src="cid:qr@tautaurun">`,
+			attachments: []Attachment{qr, ics},
+			want:        "multipart/mixed[multipart/related[multipart/alternative[text/plain text/html] image/png] text/calendar]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Message{
+				From:        mail.Address{Name: "Tau-Tau Run", Address: "noreply@tautaurun.id"},
+				To:          []mail.Address{{Address: "runner@example.com"}},
+				Subject:     "Payment Confirmed",
+				Text:        "Hi",
+				HTML:        tt.html,
+				Attachments: tt.attachments,
+			}
+			raw, err := m.Bytes()
+			if err != nil {
+				t.Fatalf("Bytes() error = %v", err)
+			}
+
+			_, body := parse(t, raw)
+			if got := body.contentTypes(); got != tt.want {
+				t.Errorf("structure = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageBytesContent(t *testing.T) {
+	png := bytes.Repeat([]byte("\x89PNG\x00\xff"), 40)
+	m := &Message{
+		From:    mail.Address{Name: "Tau-Tau Run", Address: "noreply@tautaurun.id"},
+		To:      []mail.Address{{Name: "Dewi Ayu", Address: "dewi@example.com"}, {Address: "budi@example.com"}},
+		Subject: "Pembayaran diterima – Lari Kota 🏃",
+		Text:    "Halo Dewi,\nPembayaran Rp 150.000 diterima.\n" + strings.Repeat("panjang ", 20),
+		HTML:    `<p>Halo Dewi,</p><img src="cid:qr@tautaurun">`,
+		Attachments: []Attachment{
+			{Filename: "qr.png", ContentType: "image/png", Data: png, ContentID: "qr@tautaurun"},
+		},
+		Date: time.Date(2026, 12, 6, 5, 30, 0, 0, time.UTC),
+	}
+
+	raw, err := m.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+
+	for i, line := range strings.Split(string(raw), "\r\n") {
+		if len(line) > 998 || strings.Contains(line, "\n") {
+			t.Fatalf("line %d is not a valid RFC 5322 line: %q", i+1, line)
+		}
+	}
+
+	msg, body := parse(t, raw)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != m.Subject {
+		t.Errorf("Subject = %q, %v, want %q", subject, err, m.Subject)
+	}
+	to, err := msg.Header.AddressList("To")
+	if err != nil || len(to) != 2 || to[0].Name != "Dewi Ayu" || to[1].Address != "budi@example.com" {
+		t.Errorf("To = %v, %v, want both recipients", to, err)
+	}
+	if date, err := msg.Header.Date(); err != nil || !date.Equal(m.Date) {
+		t.Errorf("Date = %v, %v, want %v", date, err, m.Date)
+	}
+	if id := msg.Header.Get("Message-ID"); !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, "@tautaurun.id>") {
+		t.Errorf("Message-ID = %q, want one in the sender's domain", id)
+	}
+	if got := strings.Join(m.Recipients(), ","); got != "dewi@example.com,budi@example.com" {
+		t.Errorf("Recipients() = %s", got)
+	}
+
+	alternative, image := body.parts[0], body.parts[1]
+	text, html := alternative.parts[0], alternative.parts[1]
+	if want := strings.ReplaceAll(m.Text, "\n", "\r\n"); string(text.body) != want {
+		t.Errorf("text body = %q, want %q", text.body, want)
+	}
+	if text.params["charset"] != "UTF-8" {
+		t.Errorf("text charset = %q, want UTF-8", text.params["charset"])
+	}
+	if string(html.body) != m.HTML {
+		t.Errorf("HTML body = %q, want %q", html.body, m.HTML)
+	}
+	if !bytes.Equal(image.body, png) {
+		t.Errorf("inline image does not decode to the attached data")
+	}
+	if cid := image.header["Content-Id"]; len(cid) != 1 || cid[0] != "<qr@tautaurun>" {
+		t.Errorf("Content-ID = %v, want <qr@tautaurun>", cid)
+	}
+	if disposition := image.header["Content-Disposition"]; len(disposition) != 1 || !strings.HasPrefix(disposition[0], "inline") {
+		t.Errorf("Content-Disposition = %v, want inline", disposition)
+	}
+}
+
+func TestBase64Lines(t *testing.T) {
+	data := bytes.Repeat([]byte{0xff, 0x00, 0x7f}, 100)
+
+	lines := strings.Split(string(base64Lines(data)), "\r\n")
+	for i, line := range lines {
+		if len(line) > 76 {
+			t.Errorf("line %d has %d characters, want at most 76", i+1, len(line))
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(lines, ""))
+	if err != nil || !bytes.Equal(decoded, data) {
+		t.Errorf("base64Lines() does not decode to its input: %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"sync"
+	"time"
+)
+
+const (
+	// smtpTimeout bounds connecting and each send on a connection
+	smtpTimeout = 30 * time.Second
+
+	// smtpIdleTimeout is how long an unused connection is kept. Servers
+	// usually hang up on idle clients after a few minutes.
+	smtpIdleTimeout = time.Minute
+)
+
+// SMTPOptions configures an SMTPMailer
+type SMTPOptions struct {
+	Host     string
+	Port     string
+	Username string // Authentication is skipped when empty
+	Password string
+	TLS      string // "starttls", "tls" (implicit TLS) or "none"
+	MaxIdle  int    // Connections kept open for reuse between sends
+}
+
+// SMTPMailer sends email through an SMTP server, reusing connections between
+// sends. With STARTTLS the server must offer it; the mailer never falls back
+// to plain text.
+type SMTPMailer struct {
+	opts SMTPOptions
+
+	mu     sync.Mutex
+	idle   []*smtpConn
+	closed bool
+}
+
+// smtpConn is an authenticated connection to the SMTP server
+type smtpConn struct {
+	conn     net.Conn
+	client   *smtp.Client
+	lastUsed time.Time
+}
+
+// NewSMTPMailer creates a new SMTP mailer
+func NewSMTPMailer(opts SMTPOptions) *SMTPMailer {
+	return &SMTPMailer{opts: opts}
+}
+
+// Name returns the driver name
+func (m *SMTPMailer) Name() string {
+	return "smtp"
+}
+
+// Send delivers msg over a pooled connection
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	if m.opts.Host == "" {
+		return fmt.Errorf("SMTP not configured")
+	}
+
+	data, err := msg.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	c, err := m.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.send(msg.From.Address, msg.Recipients(), data); err != nil {
+		// The state of the session is unknown, so the connection is not reused
+		c.client.Close()
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	m.release(c)
+	return nil
+}
+
+// Close closes the idle connections. Connections in use are closed when their
+// send finishes.
+func (m *SMTPMailer) Close() error {
+	m.mu.Lock()
+	idle := m.idle
+	m.idle = nil
+	m.closed = true
+	m.mu.Unlock()
+
+	for _, c := range idle {
+		c.quit()
+	}
+	return nil
+}
+
+// acquire returns a live idle connection, or a new one
+func (m *SMTPMailer) acquire(ctx context.Context) (*smtpConn, error) {
+	for {
+		m.mu.Lock()
+		if len(m.idle) == 0 {
+			m.mu.Unlock()
+			return m.dial(ctx)
+		}
+		c := m.idle[len(m.idle)-1]
+		m.idle = m.idle[:len(m.idle)-1]
+		m.mu.Unlock()
+
+		if time.Since(c.lastUsed) < smtpIdleTimeout && c.alive() {
+			return c, nil
+		}
+		c.client.Close()
+	}
+}
+
+// release returns a connection to the pool, or closes it if the pool is full
+func (m *SMTPMailer) release(c *smtpConn) {
+	c.lastUsed = time.Now()
+
+	m.mu.Lock()
+	if !m.closed && len(m.idle) < m.opts.MaxIdle {
+		m.idle = append(m.idle, c)
+		m.mu.Unlock()
+		return
+	}
+	m.mu.Unlock()
+
+	c.quit()
+}
+
+// dial connects, secures and authenticates a new connection
+func (m *SMTPMailer) dial(ctx context.Context) (*smtpConn, error) {
+	addr := net.JoinHostPort(m.opts.Host, m.opts.Port)
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	tlsConfig := &tls.Config{ServerName: m.opts.Host}
+
+	var conn net.Conn
+	var err error
+	if m.opts.TLS == "tls" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, m.opts.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	if err := m.handshake(client, tlsConfig); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &smtpConn{conn: conn, client: client}, nil
+}
+
+// handshake upgrades the connection to TLS when configured and authenticates
+func (m *SMTPMailer) handshake(client *smtp.Client, tlsConfig *tls.Config) error {
+	if m.opts.TLS == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if m.opts.Username == "" {
+		return nil
+	}
+	if ok, _ := client.Extension("AUTH"); !ok {
+		return fmt.Errorf("SMTP server does not support authentication")
+	}
+	// PlainAuth refuses to send credentials without TLS, except to localhost
+	if err := client.Auth(smtp.PlainAuth("", m.opts.Username, m.opts.Password, m.opts.Host)); err != nil {
+		return fmt.Errorf("SMTP authentication failed: %w", err)
+	}
+	return nil
+}
+
+// send runs one mail transaction on the connection
+func (c *smtpConn) send(from string, to []string, data []byte) error {
+	_ = c.conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	if err := c.client.Mail(from); err != nil {
+		return rejected(err)
+	}
+	for _, recipient := range to {
+		if err := c.client.Rcpt(recipient); err != nil {
+			return rejected(err)
+		}
+	}
+
+	w, err := c.client.Data()
+	if err != nil {
+		return rejected(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return rejected(w.Close())
+}
+
+// alive checks that the server has not dropped an idle connection
+func (c *smtpConn) alive() bool {
+	_ = c.conn.SetDeadline(time.Now().Add(smtpTimeout))
+	return c.client.Noop() == nil
+}
+
+// quit ends the session politely
+func (c *smtpConn) quit() {
+	_ = c.conn.SetDeadline(time.Now().Add(smtpTimeout))
+	if err := c.client.Quit(); err != nil {
+		c.client.Close()
+	}
+}
+
+// rejected marks permanent (5xx) SMTP replies as ErrRejected
+func rejected(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		return fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	return err
+}
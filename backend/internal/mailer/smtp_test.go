@@ -0,0 +1,183 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSMTPServer accepts SMTP sessions without TLS or authentication and
+// records what it receives. Recipients in reject get a permanent 550 reply.
+type fakeSMTPServer struct {
+	listener net.Listener
+	reject   map[string]bool
+
+	mu          sync.Mutex
+	connections int
+	messages    []string
+}
+
+func newFakeSMTPServer(t *testing.T, reject ...string) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &fakeSMTPServer{listener: listener, reject: map[string]bool{}}
+	for _, address := range reject {
+		s.reject["<"+address+">"] = true
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.connections++
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0])
+
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL", "NOOP", "RSET":
+			reply("250 OK")
+		case "RCPT":
+			if s.reject[command[strings.Index(command, ":")+1:]] {
+				reply("550 No such user")
+			} else {
+				reply("250 OK")
+			}
+		case "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 Queued")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) mailer(maxIdle int) *SMTPMailer {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return NewSMTPMailer(SMTPOptions{Host: host, Port: port, TLS: "none", MaxIdle: maxIdle})
+}
+
+func (s *fakeSMTPServer) stats() (connections int, messages []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections, append([]string(nil), s.messages...)
+}
+
+func TestSMTPMailerReusesConnections(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	m := server.mailer(1)
+	defer m.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := m.Send(ctx, testMessage("runner@example.com")); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	connections, messages := server.stats()
+	if len(messages) != 3 {
+		t.Fatalf("server received %d messages, want 3", len(messages))
+	}
+	if !strings.Contains(messages[0], "Subject: Payment Confirmed") {
+		t.Errorf("message = %q, want the built email", messages[0])
+	}
+	if connections != 1 {
+		t.Errorf("mailer opened %d connections, want 1 reused connection", connections)
+	}
+}
+
+func TestSMTPMailerWithoutPool(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	m := server.mailer(0)
+	defer m.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := m.Send(ctx, testMessage("runner@example.com")); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	if connections, _ := server.stats(); connections != 2 {
+		t.Errorf("mailer opened %d connections, want one per send", connections)
+	}
+}
+
+func TestSMTPMailerRejectedRecipient(t *testing.T) {
+	server := newFakeSMTPServer(t, "gone@example.com")
+	m := server.mailer(1)
+	defer m.Close()
+
+	ctx := context.Background()
+	err := m.Send(ctx, testMessage("gone@example.com"))
+	if !errors.Is(err, ErrRejected) {
+		t.Fatalf("Send() error = %v, want ErrRejected", err)
+	}
+
+	// The failed session is dropped rather than reused
+	if err := m.Send(ctx, testMessage("runner@example.com")); err != nil {
+		t.Fatalf("Send() after rejection error = %v", err)
+	}
+	connections, messages := server.stats()
+	if len(messages) != 1 || connections != 2 {
+		t.Errorf("server got %d messages over %d connections, want 1 over 2", len(messages), connections)
+	}
+}
+
+func TestSMTPMailerNotConfigured(t *testing.T) {
+	m := NewSMTPMailer(SMTPOptions{})
+
+	err := m.Send(context.Background(), testMessage("runner@example.com"))
+	if err == nil || errors.Is(err, ErrRejected) {
+		t.Errorf("Send() error = %v, want a transient configuration error", err)
+	}
+}
@@ -1,15 +1,18 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/tau-tau-run/backend/internal/services"
+	"github.com/tau-tau-run/backend/internal/utils"
 )
 
-// AuthMiddleware validates JWT tokens for protected routes
-func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
+// AuthMiddleware validates JWT access tokens for protected routes, refusing
+// revoked tokens and tokens of admins that have since been disabled or deleted
+func AuthMiddleware(sessionService *services.SessionService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -29,22 +32,82 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		// Validate token
-		claims, err := authService.ValidateToken(tokenString)
+		// Validate token and check the admin can still use the API
+		admin, err := sessionService.Authenticate(c.Request.Context(), tokenString)
 		if err != nil {
-			RespondWithError(c, http.StatusForbidden, "TOKEN_EXPIRED", "Your session has expired. Please log in again.", nil)
+			switch {
+			case errors.Is(err, services.ErrInvalidToken):
+				RespondWithError(c, http.StatusForbidden, "TOKEN_EXPIRED", "Your session has expired. Refresh it or log in again.", nil)
+			case errors.Is(err, services.ErrTokenRevoked):
+				RespondWithError(c, http.StatusUnauthorized, "TOKEN_REVOKED", "You have been logged out. Please log in again.", nil)
+			case errors.Is(err, services.ErrAdminDisabled):
+				RespondWithError(c, http.StatusForbidden, "ACCOUNT_DISABLED", "This admin account has been disabled", nil)
+			case errors.Is(err, services.ErrAdminNotFound):
+				RespondWithError(c, http.StatusUnauthorized, "UNAUTHORIZED", "This admin account no longer exists", nil)
+			default:
+				utils.AuthLogger.Error("Failed to authenticate admin: %v", err)
+				RespondWithError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil)
+			}
 			c.Abort()
 			return
 		}
 
-		// Attach admin info to context
-		c.Set("admin_id", claims.AdminID)
-		c.Set("admin_email", claims.Email)
+		// Attach admin info to context. The role is the stored one, not the
+		// one in the token.
+		c.Set("admin_id", admin.ID)
+		c.Set("admin_email", admin.Email)
+		c.Set("admin_role", admin.Role)
 
 		c.Next()
 	}
 }
 
+// RequirePermission only lets admins whose role has permission through. It
+// runs after AuthMiddleware.
+func RequirePermission(permission services.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !services.RoleHasPermission(GetAdminRole(c), permission) {
+			utils.AuthLogger.Warning("Admin %s (%s) denied %s %s", GetAdminEmail(c), GetAdminRole(c), c.Request.Method, c.FullPath())
+			RespondWithError(c, http.StatusForbidden, "FORBIDDEN", "Your role does not allow this action", nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ParticipantAuthMiddleware validates participant session tokens for participant portal routes
+func ParticipantAuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			RespondWithError(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required. Please log in with the link we emailed you.", nil)
+			c.Abort()
+			return
+		}
+
+		claims, err := authService.ValidateParticipantToken(parts[1])
+		if err != nil {
+			RespondWithError(c, http.StatusForbidden, "TOKEN_EXPIRED", "Your session has expired. Please request a new login link.", nil)
+			c.Abort()
+			return
+		}
+
+		c.Set("participant_email", claims.Email)
+
+		c.Next()
+	}
+}
+
+// GetParticipantEmail retrieves the email of the logged-in participant from context
+func GetParticipantEmail(c *gin.Context) string {
+	if email, exists := c.Get("participant_email"); exists {
+		return email.(string)
+	}
+	return ""
+}
+
 // GetAdminID retrieves admin ID from context
 func GetAdminID(c *gin.Context) string {
 	if adminID, exists := c.Get("admin_id"); exists {
@@ -53,6 +116,14 @@ func GetAdminID(c *gin.Context) string {
 	return ""
 }
 
+// GetAdminRole retrieves admin role from context
+func GetAdminRole(c *gin.Context) string {
+	if role, exists := c.Get("admin_role"); exists {
+		return role.(string)
+	}
+	return ""
+}
+
 // GetAdminEmail retrieves admin email from context
 func GetAdminEmail(c *gin.Context) string {
 	if email, exists := c.Get("admin_email"); exists {
@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+	"github.com/tau-tau-run/backend/internal/services"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		role       string // Stored role of the admin, or empty for no token
+		permission services.Permission
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "owner",
+			role:       services.RoleOwner,
+			permission: services.PermissionManageAdmins,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "finance handles payments",
+			role:       services.RoleFinance,
+			permission: services.PermissionPayments,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "finance cannot manage events",
+			role:       services.RoleFinance,
+			permission: services.PermissionManageEvents,
+			wantStatus: http.StatusForbidden,
+			wantCode:   "FORBIDDEN",
+		},
+		{
+			name:       "check-in volunteer cannot view participants",
+			role:       services.RoleCheckIn,
+			permission: services.PermissionView,
+			wantStatus: http.StatusForbidden,
+			wantCode:   "FORBIDDEN",
+		},
+		{
+			name:       "no token",
+			permission: services.PermissionView,
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "UNAUTHORIZED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture()
+			token := ""
+			if tt.role != "" {
+				token = f.token(t, f.add(tt.role))
+			}
+
+			w := f.get(token, tt.permission)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			if code := errorCode(w); code != tt.wantCode {
+				t.Errorf("error code = %q, want %s", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareStoredRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	f := newAuthFixture()
+	owner := f.add(services.RoleOwner)
+	f.add(services.RoleOwner)
+	token := f.token(t, owner)
+
+	if w := f.get(token, services.PermissionManageEvents); w.Code != http.StatusOK {
+		t.Fatalf("status as owner = %d, want 200", w.Code)
+	}
+
+	// The token still says OWNER, but the demotion applies at once
+	if _, err := f.service.UpdateRole(ctx, owner.ID, services.RoleViewer); err != nil {
+		t.Fatalf("UpdateRole() error = %v", err)
+	}
+	if w := f.get(token, services.PermissionManageEvents); w.Code != http.StatusForbidden {
+		t.Errorf("status after demotion = %d, want 403", w.Code)
+	}
+	if w := f.get(token, services.PermissionView); w.Code != http.StatusOK {
+		t.Errorf("status of viewing after demotion = %d, want 200", w.Code)
+	}
+
+	gone, _, err := f.auth.GenerateToken("00000000-0000-4000-8000-000000000000", "gone@tautaurun.id", services.RoleOwner, "gone-jti")
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	if w := f.get(gone, services.PermissionView); w.Code != http.StatusUnauthorized || errorCode(w) != "UNAUTHORIZED" {
+		t.Errorf("unknown admin = %d %s, want 401 UNAUTHORIZED", w.Code, errorCode(w))
+	}
+}
+
+func TestAuthMiddlewareDisabledAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	f := newAuthFixture()
+	owner := f.add(services.RoleOwner)
+	viewer := f.add(services.RoleViewer)
+	token := f.token(t, viewer)
+
+	if _, err := f.service.Disable(ctx, viewer.ID, owner.ID); err != nil {
+		t.Fatalf("Disable() error = %v", err)
+	}
+	// Disabling logs the admin out everywhere
+	if w := f.get(token, services.PermissionView); w.Code != http.StatusUnauthorized || errorCode(w) != "TOKEN_REVOKED" {
+		t.Errorf("token of a disabled admin = %d %s, want 401 TOKEN_REVOKED", w.Code, errorCode(w))
+	}
+
+	// A token not tied to a revoked session is refused too
+	unrevoked, _, err := f.auth.GenerateToken(viewer.ID, viewer.Email, viewer.Role, "unrevoked-jti")
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	if w := f.get(unrevoked, services.PermissionView); w.Code != http.StatusForbidden || errorCode(w) != "ACCOUNT_DISABLED" {
+		t.Errorf("disabled admin = %d %s, want 403 ACCOUNT_DISABLED", w.Code, errorCode(w))
+	}
+
+	if _, err := f.service.Enable(ctx, viewer.ID); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	if w := f.get(f.token(t, viewer), services.PermissionView); w.Code != http.StatusOK {
+		t.Errorf("status of a new session after enabling = %d, want 200", w.Code)
+	}
+}
+
+// authFixture is the admin auth middleware on an in-memory database
+type authFixture struct {
+	auth     *services.AuthService
+	admins   *memory.AdminRepository
+	service  *services.AdminService
+	sessions *services.SessionService
+}
+
+func newAuthFixture() *authFixture {
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-jwt-secret-at-least-32-bytes-long", AccessTokenMinutes: 15, RefreshTokenDays: 30}}
+	db := memory.NewDB()
+	tx := memory.NewTransactor(db)
+	sessions := memory.NewSessionRepository(db)
+
+	f := &authFixture{
+		auth:   services.NewAuthService(cfg),
+		admins: memory.NewAdminRepository(db),
+	}
+	f.service = services.NewAdminService(f.auth, f.admins, sessions, tx)
+	f.sessions = services.NewSessionService(cfg, f.auth, f.service, sessions, tx)
+	return f
+}
+
+// add stores an admin with role
+func (f *authFixture) add(role string) models.Admin {
+	return f.admins.Add(models.Admin{Email: strings.ToLower(role) + "@tautaurun.id", Role: role})
+}
+
+// token returns the access token of a new session of admin
+func (f *authFixture) token(t *testing.T, admin models.Admin) string {
+	t.Helper()
+
+	session, err := f.sessions.Start(context.Background(), &admin)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	return session.Token
+}
+
+// get calls an admin route that needs permission with token, if not empty
+func (f *authFixture) get(token string, permission services.Permission) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.GET("/admin", AuthMiddleware(f.sessions), RequirePermission(permission), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// errorCode returns the error code of a response
+func errorCode(w *httptest.ResponseRecorder) string {
+	var body ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		return ""
+	}
+	return body.Error.Code
+}
@@ -1,19 +1,16 @@
 package models
 
-import (
-	"database/sql"
-	"fmt"
-	"time"
-
-	"github.com/tau-tau-run/backend/internal/database"
-)
+import "time"
 
 // Admin represents an authenticated administrator
 type Admin struct {
-	ID           string    `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"` // Never expose in JSON
-	CreatedAt    time.Time `json:"created_at"`
+	ID           string     `json:"id"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"`          // Never expose in JSON
+	Role         string     `json:"role"`       // OWNER, FINANCE, CHECK_IN or VIEWER
+	CreatedBy    *string    `json:"created_by"` // Admin who invited them, nil if created from the command line
+	DisabledAt   *time.Time `json:"disabled_at"`
+	CreatedAt    time.Time  `json:"created_at"`
 }
 
 // LoginRequest represents admin login request
@@ -22,71 +19,31 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// LoginResponse represents admin login response
+// LoginResponse represents admin login response, also returned when a
+// session is refreshed
 type LoginResponse struct {
-	Token     string    `json:"token"`
-	Admin     AdminInfo `json:"admin"`
-	ExpiresAt time.Time `json:"expires_at"`
+	Token            string    `json:"token"`
+	Admin            AdminInfo `json:"admin"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
 }
 
 // AdminInfo represents public admin information
 type AdminInfo struct {
 	ID    string `json:"id"`
 	Email string `json:"email"`
+	Role  string `json:"role"`
 }
 
-// FindAdminByEmail finds an admin by email
-func FindAdminByEmail(email string) (*Admin, error) {
-	query := `
-		SELECT id, email, password_hash, created_at
-		FROM admins
-		WHERE email = $1
-	`
-
-	admin := &Admin{}
-
-	// Use database.DB instead of sql.DB
-	err := database.DB.QueryRow(query, email).Scan(
-		&admin.ID,
-		&admin.Email,
-		&admin.PasswordHash,
-		&admin.CreatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, nil // Not found
-	}
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to find admin: %w", err)
-	}
-
-	return admin, nil
+// UpdateRoleRequest represents a request to change the role of an admin
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required"`
 }
 
-// FindAdminByID finds an admin by ID
-func FindAdminByID(id string) (*Admin, error) {
-	query := `
-		SELECT id, email, password_hash, created_at
-		FROM admins
-		WHERE id = $1
-	`
-
-	admin := &Admin{}
-	err := database.DB.QueryRow(query, id).Scan(
-		&admin.ID,
-		&admin.Email,
-		&admin.PasswordHash,
-		&admin.CreatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, nil // Not found
-	}
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to find admin: %w", err)
-	}
-
-	return admin, nil
+// InviteAdminRequest represents a request to add an admin with an initial password
+type InviteAdminRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role" binding:"required"`
 }
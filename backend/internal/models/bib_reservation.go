@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// BibReservation holds a range of bib numbers back from automatic assignment,
+// e.g. for elite runners and guests. Without a category it applies to every
+// race category of the event.
+type BibReservation struct {
+	ID         string    `json:"id"`
+	EventID    string    `json:"event_id"`
+	CategoryID *string   `json:"category_id"`
+	RangeStart int       `json:"range_start"` // Inclusive
+	RangeEnd   int       `json:"range_end"`   // Inclusive
+	Label      string    `json:"label"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Contains reports whether bib number n is in the reserved range
+func (r *BibReservation) Contains(n int) bool {
+	return n >= r.RangeStart && n <= r.RangeEnd
+}
+
+// BibReservationRequest represents create bib reservation request data
+type BibReservationRequest struct {
+	CategoryID *string `json:"category_id"`
+	RangeStart int     `json:"range_start"`
+	RangeEnd   int     `json:"range_end"`
+	Label      string  `json:"label"`
+}
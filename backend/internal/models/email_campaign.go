@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// EmailCampaign is an email an admin sent to a segment of an event's participants
+type EmailCampaign struct {
+	ID             string          `json:"id"`
+	EventID        string          `json:"event_id"`
+	Subject        string          `json:"subject"`
+	HTMLBody       string          `json:"html_body"`
+	TextBody       string          `json:"text_body"`
+	Segment        CampaignSegment `json:"segment"`
+	Recipients     int             `json:"recipients"`
+	CreatedBy      *string         `json:"created_by"`
+	CreatedByEmail *string         `json:"created_by_email"`
+	CreatedAt      time.Time       `json:"created_at"`
+
+	// Delivery progress, populated when getting a single campaign
+	Delivery *CampaignDelivery `json:"delivery,omitempty"`
+}
+
+// Template returns the subject and bodies of c, which are rendered like an email template
+func (c *EmailCampaign) Template() *EmailTemplate {
+	return &EmailTemplate{
+		EventID:   c.EventID,
+		EmailType: "CAMPAIGN",
+		Subject:   c.Subject,
+		HTMLBody:  c.HTMLBody,
+		TextBody:  c.TextBody,
+	}
+}
+
+// CampaignSegment selects the participants of an event a campaign is sent to.
+// Empty fields match everyone.
+type CampaignSegment struct {
+	PaymentStatus      string `json:"payment_status,omitempty"`
+	RegistrationStatus string `json:"registration_status,omitempty"`
+	CategoryID         string `json:"category_id,omitempty"`
+	CheckedIn          *bool  `json:"checked_in,omitempty"`
+}
+
+// CampaignDelivery counts the emails of a campaign by outbox status
+type CampaignDelivery struct {
+	Pending int `json:"pending"` // Queued, being sent or waiting for a retry
+	Sent    int `json:"sent"`
+	Skipped int `json:"skipped"` // No longer applied to the recipient
+	Failed  int `json:"failed"`  // Dead-lettered
+}
+
+// EmailCampaignRequest represents campaign create and preview request data.
+// The subject and bodies are templates, like email templates.
+type EmailCampaignRequest struct {
+	Subject  string          `json:"subject"`
+	HTMLBody string          `json:"html_body"`
+	TextBody string          `json:"text_body"`
+	Segment  CampaignSegment `json:"segment"`
+}
+
+// CampaignPreview is the number of participants a campaign would go to and
+// how it looks for one of them
+type CampaignPreview struct {
+	Recipients int            `json:"recipients"`
+	Email      *RenderedEmail `json:"email"`
+}
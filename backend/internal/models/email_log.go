@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// EmailLog represents a single email sending attempt
+type EmailLog struct {
+	ID             string    `json:"id"`
+	ParticipantID  string    `json:"participant_id"`
+	RecipientEmail string    `json:"recipient_email"`
+	EmailType      string    `json:"email_type"`
+	CampaignID     *string   `json:"campaign_id"` // Set for CAMPAIGN emails
+	Status         string    `json:"status"`
+	ErrorMessage   *string   `json:"error_message"`
+	ResendOf       *string   `json:"resend_of"` // Log entry of the email an admin resent
+	ResentBy       *string   `json:"resent_by"` // Admin who resent it
+	SentAt         time.Time `json:"sent_at"`
+
+	// Participant details, populated when listing email logs
+	ParticipantName string `json:"participant_name,omitempty"`
+	EventID         string `json:"event_id,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// OutboxEmail is an email waiting in the outbox to be delivered, or the record
+// of one that was delivered or gave up
+type OutboxEmail struct {
+	ID            string     `json:"id"`
+	ParticipantID string     `json:"participant_id"`
+	EmailType     string     `json:"email_type"`
+	CampaignID    *string    `json:"campaign_id"` // Set for CAMPAIGN emails
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     *string    `json:"last_error"`
+	SentAt        *time.Time `json:"sent_at"`
+	SkipReason    *string    `json:"skip_reason"` // Why a SKIPPED email was not sent
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Participant details, populated when listing the outbox
+	ParticipantName  string `json:"participant_name,omitempty"`
+	ParticipantEmail string `json:"participant_email,omitempty"`
+}
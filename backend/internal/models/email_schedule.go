@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// EmailSchedule configures when an event sends a scheduled email type.
+// Times are in the event time zone.
+type EmailSchedule struct {
+	EventID   string `json:"event_id"`
+	EmailType string `json:"email_type"`
+	Enabled   bool   `json:"enabled"`
+	// Days after registering for PAYMENT_REMINDER, days before the event for
+	// KIT_PICKUP; RACE_DAY_BRIEFING is sent on the event date and ignores it
+	Days      int        `json:"days"`
+	SendTime  string     `json:"send_time"`  // HH:MM
+	UpdatedBy *string    `json:"updated_by"` // Admin who last changed the schedule
+	UpdatedAt *time.Time `json:"updated_at"` // nil until the schedule is first saved
+	IsDefault bool       `json:"is_default"` // The event uses the built-in schedule
+}
+
+// EmailScheduleRequest represents update email schedule request data. Days
+// and SendTime keep their current values when omitted.
+type EmailScheduleRequest struct {
+	Enabled  *bool  `json:"enabled" binding:"required"`
+	Days     *int   `json:"days"`
+	SendTime string `json:"send_time"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// EmailTemplate is one version of the subject, HTML body and plain text body
+// of an email type for an event
+type EmailTemplate struct {
+	ID             string     `json:"id,omitempty"`
+	EventID        string     `json:"event_id"`
+	EmailType      string     `json:"email_type"`
+	Version        int        `json:"version"` // 0 for the built-in default
+	Subject        string     `json:"subject"`
+	HTMLBody       string     `json:"html_body"`
+	TextBody       string     `json:"text_body"`
+	RestoredFrom   *int       `json:"restored_from"` // Version copied by a rollback
+	CreatedBy      *string    `json:"created_by"`
+	CreatedByEmail *string    `json:"created_by_email"`
+	CreatedAt      *time.Time `json:"created_at"`
+	IsDefault      bool       `json:"is_default"` // Not stored; the event has no template of its own
+}
+
+// EmailTemplateRequest represents save email template request data
+type EmailTemplateRequest struct {
+	Subject  string `json:"subject"`
+	HTMLBody string `json:"html_body"`
+	TextBody string `json:"text_body"`
+}
+
+// EmailTemplatePreviewRequest represents email template preview request data.
+// Template fields that are left out are taken from the current template, and
+// without a participant a sample one is used.
+type EmailTemplatePreviewRequest struct {
+	Subject       *string `json:"subject"`
+	HTMLBody      *string `json:"html_body"`
+	TextBody      *string `json:"text_body"`
+	ParticipantID string  `json:"participant_id"`
+}
+
+// RenderedEmail is an email rendered from a template
+type RenderedEmail struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
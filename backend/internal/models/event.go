@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Event represents a race participants can register for
+type Event struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	EventDate        string    `json:"event_date"` // YYYY-MM-DD
+	Location         string    `json:"location"`
+	Description      string    `json:"description"`
+	RegistrationOpen bool      `json:"registration_open"`
+	Capacity         *int      `json:"capacity"`   // Only used without race categories; nil means unlimited
+	Registered       int       `json:"registered"` // Participants holding a spot
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	// Refund policy for paid registrations that are cancelled: a full refund
+	// up to and including RefundFullUntil (YYYY-MM-DD), RefundPartialPercent
+	// after it, and none in the last RefundCutoffDays days before the event
+	RefundFullUntil      *string `json:"refund_full_until"`
+	RefundPartialPercent int     `json:"refund_partial_percent"`
+	RefundCutoffDays     int     `json:"refund_cutoff_days"`
+
+	// Last day (YYYY-MM-DD) registrations can be transferred to another
+	// runner; nil means until the day before the event
+	TransferDeadline *string `json:"transfer_deadline"`
+}
+
+// EventRequest represents create and update event request data
+type EventRequest struct {
+	Name             string `json:"name" binding:"required"`
+	EventDate        string `json:"event_date" binding:"required"`
+	Location         string `json:"location" binding:"required"`
+	Description      string `json:"description"`
+	RegistrationOpen *bool  `json:"registration_open"`
+	Capacity         *int   `json:"capacity"`
+
+	RefundFullUntil      *string `json:"refund_full_until"`
+	RefundPartialPercent int     `json:"refund_partial_percent"`
+	RefundCutoffDays     int     `json:"refund_cutoff_days"`
+
+	TransferDeadline *string `json:"transfer_deadline"`
+}
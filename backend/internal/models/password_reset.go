@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// PasswordReset is a single-use token that lets an admin who forgot their
+// password choose a new one
+type PasswordReset struct {
+	ID        string
+	AdminID   string
+	TokenHash string // SHA-256 of the token; the token itself is never stored
+	ExpiresAt time.Time
+	UsedAt    *time.Time // Set when used, or when a newer link or a reset replaced it
+	CreatedAt time.Time
+}
+
+// ForgotPasswordRequest represents a request to email an admin a password reset link
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// ResetPasswordRequest represents a request to set a new password with a reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// ChangePasswordRequest represents a request of a logged-in admin to change their password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Payment represents an online payment attempt through a payment provider
+type Payment struct {
+	ID                string     `json:"id"`
+	ParticipantID     string     `json:"participant_id"`
+	Provider          string     `json:"provider"`
+	Reference         string     `json:"reference"`
+	ProviderReference string     `json:"provider_reference"`
+	Amount            int64      `json:"amount"`
+	Currency          string     `json:"currency"`
+	Status            string     `json:"status"`
+	CheckoutURL       string     `json:"checkout_url"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+	PaidAt            *time.Time `json:"paid_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
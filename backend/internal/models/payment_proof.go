@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// PaymentProof is a proof of payment (transfer receipt) uploaded by a participant
+type PaymentProof struct {
+	ID            string     `json:"id"`
+	ParticipantID string     `json:"participant_id"`
+	StorageKey    string     `json:"-"`
+	Filename      string     `json:"filename"`
+	ContentType   string     `json:"content_type"`
+	Size          int64      `json:"size"`
+	Status        string     `json:"status"`
+	ReviewNote    *string    `json:"review_note"`
+	ReviewedBy    *string    `json:"reviewed_by"`
+	ReviewedAt    *time.Time `json:"reviewed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	// Participant details, populated when listing proofs for review
+	ParticipantName  string `json:"participant_name,omitempty"`
+	ParticipantEmail string `json:"participant_email,omitempty"`
+}
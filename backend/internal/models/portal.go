@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// PortalRegistration is what participants see of a registration of theirs in
+// the participant portal
+type PortalRegistration struct {
+	ID                 string     `json:"id"`
+	EventID            string     `json:"event_id"`
+	EventName          string     `json:"event_name"`
+	EventDate          string     `json:"event_date"`
+	EventLocation      string     `json:"event_location"`
+	CategoryID         *string    `json:"category_id"`
+	CategoryName       *string    `json:"category_name"`
+	Name               string     `json:"name"`
+	Email              string     `json:"email"`
+	Phone              string     `json:"phone"`
+	InstagramHandle    *string    `json:"instagram_handle"`
+	Address            string     `json:"address"`
+	RegistrationStatus string     `json:"registration_status"`
+	PaymentStatus      string     `json:"payment_status"`
+	WaitlistPosition   *int       `json:"waitlist_position"`
+	OfferExpiresAt     *time.Time `json:"offer_expires_at"`
+	BibNumber          *int       `json:"bib_number"`
+	KitCollected       bool       `json:"kit_collected"`
+	CheckedIn          bool       `json:"checked_in"`
+	CancelledAt        *time.Time `json:"cancelled_at"`
+	CancellationReason *string    `json:"cancellation_reason"`
+	RefundAmount       *int64     `json:"refund_amount"`
+	RefundCurrency     *string    `json:"refund_currency"`
+	RefundedAt         *time.Time `json:"refunded_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+
+	PendingTransfer *RegistrationTransfer `json:"pending_transfer"` // Transfer waiting for its recipient, if any
+}
+
+// PortalLoginRequest asks for a magic link to the participant portal
+type PortalLoginRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// PortalUpdateRequest represents the fields participants may change
+// themselves. Omitted fields are left unchanged; an empty instagram_handle
+// removes it.
+type PortalUpdateRequest struct {
+	Phone           *string `json:"phone"`
+	Address         *string `json:"address"`
+	InstagramHandle *string `json:"instagram_handle"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// RaceCategory represents a distance of an event with its own price and capacity
+type RaceCategory struct {
+	ID         string    `json:"id"`
+	EventID    string    `json:"event_id"`
+	Name       string    `json:"name"`
+	DistanceKM float64   `json:"distance_km"`
+	Price      int64     `json:"price"` // In the smallest currency unit
+	Currency   string    `json:"currency"`
+	Capacity   int       `json:"capacity"`
+	MinAge     *int      `json:"min_age"` // On the event date, inclusive
+	MaxAge     *int      `json:"max_age"`
+	Registered int       `json:"registered"` // Participants holding a spot, excluding the waitlist
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// RaceCategoryRequest represents create and update race category request data
+type RaceCategoryRequest struct {
+	Name       string  `json:"name" binding:"required"`
+	DistanceKM float64 `json:"distance_km"`
+	Price      int64   `json:"price"`
+	Currency   string  `json:"currency"`
+	Capacity   int     `json:"capacity"`
+	MinAge     *int    `json:"min_age"`
+	MaxAge     *int    `json:"max_age"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// RefreshToken is one token of an admin session. Each login starts a family
+// of refresh tokens; refreshing uses up the current token and adds the next
+// one to the family, together with a new access token.
+type RefreshToken struct {
+	ID              string
+	AdminID         string
+	FamilyID        string
+	TokenHash       string // SHA-256 of the token; the token itself is never stored
+	AccessJTI       string // jti of the access token issued with this refresh token
+	AccessExpiresAt time.Time
+	ExpiresAt       time.Time
+	UsedAt          *time.Time // Set when exchanged for the next token
+	RevokedAt       *time.Time // Set when the session was logged out or its token reused
+	CreatedAt       time.Time
+}
+
+// RefreshRequest represents a request to refresh an admin session or log it out
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
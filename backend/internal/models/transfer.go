@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// RegistrationTransfer is a registration given by its holder to another
+// runner. It records who held the registration before and after, and is
+// PENDING until the recipient accepts it through the emailed link.
+type RegistrationTransfer struct {
+	ID            string     `json:"id"`
+	ParticipantID string     `json:"participant_id"`
+	EventID       string     `json:"event_id"`
+	Status        string     `json:"status"` // PENDING, COMPLETED, CANCELLED or EXPIRED
+	FromName      string     `json:"from_name"`
+	FromEmail     string     `json:"from_email"`
+	FromPhone     string     `json:"from_phone"`
+	ToName        string     `json:"to_name"`
+	ToEmail       string     `json:"to_email"`
+	ToPhone       *string    `json:"to_phone"`     // Set when accepted
+	InitiatedBy   *string    `json:"initiated_by"` // Admin who started it; nil if the participant did
+	ExpiresAt     time.Time  `json:"expires_at"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	CancelledAt   *time.Time `json:"cancelled_at"`
+	CancelledBy   *string    `json:"cancelled_by"` // Admin who cancelled it; nil if the participant did
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TransferRequest represents a request to transfer a registration to another runner
+type TransferRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required"`
+}
+
+// TransferOffer is what the recipient of a transfer sees before accepting it
+type TransferOffer struct {
+	EventName     string    `json:"event_name"`
+	EventDate     string    `json:"event_date"`
+	EventLocation string    `json:"event_location"`
+	CategoryName  *string   `json:"category_name"`
+	FromName      string    `json:"from_name"`
+	ToName        string    `json:"to_name"`
+	ToEmail       string    `json:"to_email"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// TransferAcceptRequest represents the details the recipient of a transfer
+// registers with. The email address is the one the transfer was sent to.
+type TransferAcceptRequest struct {
+	Token           string  `json:"token" binding:"required"`
+	Name            string  `json:"name" binding:"required"`
+	Phone           string  `json:"phone" binding:"required"`
+	InstagramHandle *string `json:"instagram_handle"`
+	Address         string  `json:"address" binding:"required"`
+	DateOfBirth     *string `json:"date_of_birth"`
+}
@@ -0,0 +1,99 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultFakeWebhookSecret signs fake webhooks when no secret is configured
+const DefaultFakeWebhookSecret = "fake-webhook-secret"
+
+// FakeProvider is a local payment provider for development. Charges stay
+// PENDING until a webhook signed with the configured secret reports otherwise.
+type FakeProvider struct {
+	webhookSecret string
+
+	mu       sync.Mutex
+	statuses map[string]string
+}
+
+// NewFakeProvider creates a new fake payment provider
+func NewFakeProvider(webhookSecret string) *FakeProvider {
+	if webhookSecret == "" {
+		webhookSecret = DefaultFakeWebhookSecret
+	}
+	return &FakeProvider{
+		webhookSecret: webhookSecret,
+		statuses:      make(map[string]string),
+	}
+}
+
+// fakeWebhookPayload is the body accepted by the fake webhook
+type fakeWebhookPayload struct {
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+	Amount    int64  `json:"amount"`
+}
+
+// Name returns the provider name stored with each payment
+func (f *FakeProvider) Name() string {
+	return "fake"
+}
+
+// CreateCharge records a pending charge without contacting any gateway
+func (f *FakeProvider) CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	providerRef := "fake_" + req.Reference
+	f.statuses[providerRef] = StatusPending
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	return &Charge{
+		ProviderReference: providerRef,
+		CheckoutURL:       fmt.Sprintf("https://fake-gateway.local/pay/%s", req.Reference),
+		Status:            StatusPending,
+		ExpiresAt:         &expiresAt,
+	}, nil
+}
+
+// ParseWebhook verifies the signature and decodes a fake callback
+func (f *FakeProvider) ParseWebhook(payload []byte, headers http.Header) (*WebhookEvent, error) {
+	if err := verifySignature(f.webhookSecret, payload, headers.Get(SignatureHeader)); err != nil {
+		return nil, err
+	}
+
+	var body fakeWebhookPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook payload: %w", err)
+	}
+
+	event := &WebhookEvent{
+		Reference:         body.Reference,
+		ProviderReference: "fake_" + body.Reference,
+		Status:            normalizeStatus(body.Status),
+		Amount:            body.Amount,
+	}
+
+	f.mu.Lock()
+	f.statuses[event.ProviderReference] = event.Status
+	f.mu.Unlock()
+
+	return event, nil
+}
+
+// QueryStatus returns the last status seen for a fake charge
+func (f *FakeProvider) QueryStatus(ctx context.Context, providerReference string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	status, ok := f.statuses[providerReference]
+	if !ok {
+		return "", fmt.Errorf("failed to query payment status: unknown charge %s", providerReference)
+	}
+	return status, nil
+}
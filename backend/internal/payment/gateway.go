@@ -0,0 +1,138 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GatewayProvider talks to an invoice-based payment gateway (Xendit/Midtrans style).
+// Invoices are created with HTTP Basic auth using the server key, and the gateway
+// signs callbacks with an HMAC-SHA256 of the body.
+type GatewayProvider struct {
+	baseURL       string
+	serverKey     string
+	webhookSecret string
+	successURL    string
+	client        *http.Client
+}
+
+// NewGatewayProvider creates a new gateway payment provider
+func NewGatewayProvider(baseURL, serverKey, webhookSecret, successURL string) *GatewayProvider {
+	return &GatewayProvider{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		serverKey:     serverKey,
+		webhookSecret: webhookSecret,
+		successURL:    successURL,
+		client:        &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// gatewayInvoice is the invoice representation used by the gateway API
+type gatewayInvoice struct {
+	ID         string     `json:"id"`
+	ExternalID string     `json:"external_id"`
+	Status     string     `json:"status"`
+	Amount     int64      `json:"amount"`
+	InvoiceURL string     `json:"invoice_url"`
+	ExpiryDate *time.Time `json:"expiry_date"`
+}
+
+// Name returns the provider name stored with each payment
+func (g *GatewayProvider) Name() string {
+	return "gateway"
+}
+
+// CreateCharge creates an invoice and returns its hosted checkout URL
+func (g *GatewayProvider) CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"external_id":          req.Reference,
+		"amount":               req.Amount,
+		"currency":             req.Currency,
+		"description":          req.Description,
+		"payer_email":          req.CustomerEmail,
+		"customer":             map[string]string{"given_names": req.CustomerName, "email": req.CustomerEmail},
+		"success_redirect_url": g.successURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode charge request: %w", err)
+	}
+
+	var invoice gatewayInvoice
+	if err := g.do(ctx, http.MethodPost, "/v2/invoices", body, &invoice); err != nil {
+		return nil, fmt.Errorf("failed to create charge: %w", err)
+	}
+
+	return &Charge{
+		ProviderReference: invoice.ID,
+		CheckoutURL:       invoice.InvoiceURL,
+		Status:            normalizeStatus(invoice.Status),
+		ExpiresAt:         invoice.ExpiryDate,
+	}, nil
+}
+
+// ParseWebhook verifies the callback signature and decodes the invoice it describes
+func (g *GatewayProvider) ParseWebhook(payload []byte, headers http.Header) (*WebhookEvent, error) {
+	if err := verifySignature(g.webhookSecret, payload, headers.Get(SignatureHeader)); err != nil {
+		return nil, err
+	}
+
+	var invoice gatewayInvoice
+	if err := json.Unmarshal(payload, &invoice); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook payload: %w", err)
+	}
+
+	return &WebhookEvent{
+		Reference:         invoice.ExternalID,
+		ProviderReference: invoice.ID,
+		Status:            normalizeStatus(invoice.Status),
+		Amount:            invoice.Amount,
+	}, nil
+}
+
+// QueryStatus fetches the current status of an invoice
+func (g *GatewayProvider) QueryStatus(ctx context.Context, providerReference string) (string, error) {
+	var invoice gatewayInvoice
+	if err := g.do(ctx, http.MethodGet, "/v2/invoices/"+url.PathEscape(providerReference), nil, &invoice); err != nil {
+		return "", fmt.Errorf("failed to query payment status: %w", err)
+	}
+
+	return normalizeStatus(invoice.Status), nil
+}
+
+// do sends an authenticated JSON request and decodes the response into out
+func (g *GatewayProvider) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(g.serverKey, "")
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("gateway returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,114 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+)
+
+// Payment statuses reported by providers
+const (
+	StatusPending = "PENDING"
+	StatusPaid    = "PAID"
+	StatusFailed  = "FAILED"
+	StatusExpired = "EXPIRED"
+)
+
+// SignatureHeader carries the hex-encoded HMAC-SHA256 of a webhook body
+const SignatureHeader = "X-Callback-Signature"
+
+// ErrInvalidSignature is returned when a webhook signature does not match
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ChargeRequest describes a payment to collect from a participant
+type ChargeRequest struct {
+	Reference     string // Our unique reference, echoed back in webhooks
+	Amount        int64
+	Currency      string
+	Description   string
+	CustomerName  string
+	CustomerEmail string
+}
+
+// Charge is a payment created at the provider
+type Charge struct {
+	ProviderReference string
+	CheckoutURL       string
+	Status            string
+	ExpiresAt         *time.Time
+}
+
+// WebhookEvent is a verified payment notification from a provider
+type WebhookEvent struct {
+	Reference         string
+	ProviderReference string
+	Status            string
+	Amount            int64
+}
+
+// Provider creates charges at a payment gateway and verifies its callbacks
+type Provider interface {
+	Name() string
+	CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error)
+	// ParseWebhook verifies the signature in headers and decodes the payload
+	ParseWebhook(payload []byte, headers http.Header) (*WebhookEvent, error)
+	QueryStatus(ctx context.Context, providerReference string) (string, error)
+}
+
+// NewProvider creates the provider selected by cfg, or nil if online payments are disabled
+func NewProvider(cfg *config.Config) (Provider, error) {
+	switch cfg.Payment.Provider {
+	case "":
+		return nil, nil
+	case "fake":
+		return NewFakeProvider(cfg.Payment.WebhookSecret), nil
+	case "gateway":
+		return NewGatewayProvider(cfg.Payment.BaseURL, cfg.Payment.ServerKey, cfg.Payment.WebhookSecret, cfg.Payment.SuccessURL), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", cfg.Payment.Provider)
+	}
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of payload
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifySignature checks signature against payload in constant time
+func verifySignature(secret string, payload []byte, signature string) error {
+	expected, err := hex.DecodeString(Sign(secret, payload))
+	if err != nil {
+		return err
+	}
+
+	given, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil || !hmac.Equal(expected, given) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// normalizeStatus maps gateway-specific statuses onto ours
+func normalizeStatus(status string) string {
+	switch strings.ToUpper(status) {
+	case "PAID", "SETTLED", "SETTLEMENT", "CAPTURE", "SUCCEEDED":
+		return StatusPaid
+	case "EXPIRED", "EXPIRE":
+		return StatusExpired
+	case "FAILED", "FAILURE", "DENY", "CANCEL", "CANCELLED":
+		return StatusFailed
+	default:
+		return StatusPending
+	}
+}
@@ -0,0 +1,75 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFakeProviderParseWebhook(t *testing.T) {
+	const secret = "webhook-secret"
+	payload := []byte(`{"reference":"TTR-TEST","status":"settlement","amount":150000}`)
+
+	tests := []struct {
+		name      string
+		signature string
+		payload   []byte
+		wantErr   error
+	}{
+		{name: "valid signature", signature: Sign(secret, payload), payload: payload},
+		{name: "missing signature", signature: "", payload: payload, wantErr: ErrInvalidSignature},
+		{name: "not hex", signature: "not-a-signature", payload: payload, wantErr: ErrInvalidSignature},
+		{name: "other secret", signature: Sign("other-secret", payload), payload: payload, wantErr: ErrInvalidSignature},
+		{
+			name:      "tampered payload",
+			signature: Sign(secret, payload),
+			payload:   []byte(`{"reference":"TTR-TEST","status":"settlement","amount":1}`),
+			wantErr:   ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewFakeProvider(secret)
+			headers := http.Header{}
+			headers.Set(SignatureHeader, tt.signature)
+
+			event, err := provider.ParseWebhook(tt.payload, headers)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if event.Reference != "TTR-TEST" || event.Status != StatusPaid || event.Amount != 150000 {
+				t.Errorf("ParseWebhook() = %+v, want a PAID event for TTR-TEST of 150000", event)
+			}
+
+			status, err := provider.QueryStatus(context.Background(), event.ProviderReference)
+			if err != nil || status != StatusPaid {
+				t.Errorf("QueryStatus() = %s, %v, want %s", status, err, StatusPaid)
+			}
+		})
+	}
+}
+
+func TestNormalizeStatus(t *testing.T) {
+	tests := map[string]string{
+		"PAID":       StatusPaid,
+		"settlement": StatusPaid,
+		"Capture":    StatusPaid,
+		"EXPIRE":     StatusExpired,
+		"deny":       StatusFailed,
+		"CANCELLED":  StatusFailed,
+		"pending":    StatusPending,
+		"":           StatusPending,
+		"authorize":  StatusPending,
+	}
+	for status, want := range tests {
+		if got := normalizeStatus(status); got != want {
+			t.Errorf("normalizeStatus(%q) = %s, want %s", status, got, want)
+		}
+	}
+}
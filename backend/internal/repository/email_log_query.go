@@ -0,0 +1,14 @@
+package repository
+
+import "time"
+
+// EmailLogFilter narrows down which email logs are returned.
+// Zero values mean "no filter".
+type EmailLogFilter struct {
+	ParticipantID string
+	EventID       string
+	EmailType     string
+	Status        string
+	SentFrom      *time.Time // inclusive
+	SentBefore    *time.Time // exclusive
+}
@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+// AdminRepository stores admins in memory
+type AdminRepository struct {
+	db *DB
+}
+
+// NewAdminRepository creates a new in-memory admin repository
+func NewAdminRepository(db *DB) *AdminRepository {
+	return &AdminRepository{db: db}
+}
+
+// Add stores an admin directly, for seeding tests and development data
+func (r *AdminRepository) Add(admin models.Admin) models.Admin {
+	defer r.db.lock(context.Background())()
+
+	if admin.ID == "" {
+		admin.ID = newID()
+	}
+	if admin.Role == "" {
+		admin.Role = "OWNER"
+	}
+	if admin.CreatedAt.IsZero() {
+		admin.CreatedAt = time.Now()
+	}
+
+	r.db.admins[admin.ID] = admin
+	return admin
+}
+
+// Create stores a new admin
+func (r *AdminRepository) Create(ctx context.Context, admin *models.Admin) error {
+	defer r.db.lock(ctx)()
+
+	for _, existing := range r.db.admins {
+		if existing.Email == admin.Email {
+			return repository.ErrDuplicateEmail
+		}
+	}
+
+	admin.ID = newID()
+	admin.CreatedAt = time.Now()
+	r.db.admins[admin.ID] = *admin
+	return nil
+}
+
+// FindByID finds an admin by ID
+func (r *AdminRepository) FindByID(ctx context.Context, id string) (*models.Admin, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	admin, ok := r.db.admins[id]
+	if !ok {
+		return nil, nil // Not found
+	}
+	return &admin, nil
+}
+
+// FindByIDForUpdate finds an admin by ID. Transactions are already
+// serialized in memory, so no row lock is needed.
+func (r *AdminRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.Admin, error) {
+	return r.FindByID(ctx, id)
+}
+
+// FindByEmail finds an admin by email
+func (r *AdminRepository) FindByEmail(ctx context.Context, email string) (*models.Admin, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, admin := range r.db.admins {
+		if admin.Email == email {
+			return &admin, nil
+		}
+	}
+	return nil, nil // Not found
+}
+
+// List retrieves every admin, ordered by email
+func (r *AdminRepository) List(ctx context.Context) ([]models.Admin, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	admins := make([]models.Admin, 0, len(r.db.admins))
+	for _, admin := range r.db.admins {
+		admins = append(admins, admin)
+	}
+	sort.Slice(admins, func(i, j int) bool {
+		return admins[i].Email < admins[j].Email
+	})
+
+	return admins, nil
+}
+
+// CountActiveByRoleForUpdate returns how many admins that are not disabled
+// have a role. Transactions are already serialized in memory, so no row lock
+// is needed.
+func (r *AdminRepository) CountActiveByRoleForUpdate(ctx context.Context, role string) (int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	count := 0
+	for _, admin := range r.db.admins {
+		if admin.Role == role && admin.DisabledAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// UpdateRole changes the role of an admin
+func (r *AdminRepository) UpdateRole(ctx context.Context, id, role string) error {
+	defer r.db.lock(ctx)()
+
+	admin, ok := r.db.admins[id]
+	if !ok {
+		return fmt.Errorf("failed to update admin role: admin %s not found", id)
+	}
+
+	admin.Role = role
+	r.db.admins[id] = admin
+	return nil
+}
+
+// UpdatePassword sets the password hash of an admin
+func (r *AdminRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	defer r.db.lock(ctx)()
+
+	admin, ok := r.db.admins[id]
+	if !ok {
+		return fmt.Errorf("failed to update admin password: admin %s not found", id)
+	}
+
+	admin.PasswordHash = passwordHash
+	r.db.admins[id] = admin
+	return nil
+}
+
+// SetDisabled disables an admin at disabledAt, or enables them if it is nil
+func (r *AdminRepository) SetDisabled(ctx context.Context, id string, disabledAt *time.Time) error {
+	defer r.db.lock(ctx)()
+
+	admin, ok := r.db.admins[id]
+	if !ok {
+		return fmt.Errorf("failed to update admin: admin %s not found", id)
+	}
+
+	admin.DisabledAt = disabledAt
+	r.db.admins[id] = admin
+	return nil
+}
+
+// Delete removes an admin
+func (r *AdminRepository) Delete(ctx context.Context, id string) error {
+	defer r.db.lock(ctx)()
+
+	if _, ok := r.db.admins[id]; !ok {
+		return fmt.Errorf("failed to delete admin: admin %s not found", id)
+	}
+
+	delete(r.db.admins, id)
+	for tokenID, t := range r.db.refreshTokens {
+		if t.AdminID == id {
+			delete(r.db.refreshTokens, tokenID)
+		}
+	}
+	for resetID, reset := range r.db.passwordResets {
+		if reset.AdminID == id {
+			delete(r.db.passwordResets, resetID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// BibReservationRepository stores bib number reservations in memory
+type BibReservationRepository struct {
+	db *DB
+}
+
+// NewBibReservationRepository creates a new in-memory bib reservation repository
+func NewBibReservationRepository(db *DB) *BibReservationRepository {
+	return &BibReservationRepository{db: db}
+}
+
+// Create stores a new bib reservation
+func (r *BibReservationRepository) Create(ctx context.Context, res *models.BibReservation) error {
+	defer r.db.lock(ctx)()
+
+	res.ID = newID()
+	res.CreatedAt = time.Now()
+
+	r.db.bibReservations[res.ID] = *res
+	return nil
+}
+
+// FindByID finds a bib reservation by ID
+func (r *BibReservationRepository) FindByID(ctx context.Context, id string) (*models.BibReservation, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	res, ok := r.db.bibReservations[id]
+	if !ok {
+		return nil, nil // Not found
+	}
+	return &res, nil
+}
+
+// ListByEvent retrieves the reservations of an event, lowest range first
+func (r *BibReservationRepository) ListByEvent(ctx context.Context, eventID string) ([]models.BibReservation, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	reservations := []models.BibReservation{}
+	for _, res := range r.db.bibReservations {
+		if res.EventID == eventID {
+			reservations = append(reservations, res)
+		}
+	}
+
+	sort.Slice(reservations, func(i, j int) bool {
+		if reservations[i].RangeStart == reservations[j].RangeStart {
+			return reservations[i].RangeEnd < reservations[j].RangeEnd
+		}
+		return reservations[i].RangeStart < reservations[j].RangeStart
+	})
+
+	return reservations, nil
+}
+
+// Delete removes a bib reservation
+func (r *BibReservationRepository) Delete(ctx context.Context, id string) error {
+	defer r.db.lock(ctx)()
+
+	delete(r.db.bibReservations, id)
+	return nil
+}
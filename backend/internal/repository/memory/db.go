@@ -0,0 +1,214 @@
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+// DB is an in-memory data store shared by the memory repositories.
+// It is intended for tests and local development without PostgreSQL.
+type DB struct {
+	mu              sync.RWMutex
+	events          map[string]models.Event
+	participants    map[string]models.Participant
+	admins          map[string]models.Admin
+	emailLogs       []models.EmailLog
+	payments        map[string]models.Payment
+	paymentProofs   map[string]models.PaymentProof
+	raceCategories  map[string]models.RaceCategory
+	bibReservations map[string]models.BibReservation
+	emailOutbox     map[string]models.OutboxEmail
+	emailTemplates  map[string]models.EmailTemplate
+	emailSchedules  map[string]models.EmailSchedule // Keyed by event ID and email type
+	emailCampaigns  map[string]models.EmailCampaign
+	transfers       map[string]models.RegistrationTransfer
+	refreshTokens   map[string]models.RefreshToken
+	revokedTokens   map[string]time.Time // Expiry of revoked access tokens, keyed by jti
+	passwordResets  map[string]models.PasswordReset
+
+	// txMu is held by a running transaction and by every write outside one,
+	// so restoring a snapshot cannot undo changes made by anyone else
+	txMu sync.Mutex
+}
+
+// NewDB creates an empty in-memory data store
+func NewDB() *DB {
+	return &DB{
+		events:          make(map[string]models.Event),
+		participants:    make(map[string]models.Participant),
+		admins:          make(map[string]models.Admin),
+		payments:        make(map[string]models.Payment),
+		paymentProofs:   make(map[string]models.PaymentProof),
+		raceCategories:  make(map[string]models.RaceCategory),
+		bibReservations: make(map[string]models.BibReservation),
+		emailOutbox:     make(map[string]models.OutboxEmail),
+		emailTemplates:  make(map[string]models.EmailTemplate),
+		emailSchedules:  make(map[string]models.EmailSchedule),
+		emailCampaigns:  make(map[string]models.EmailCampaign),
+		transfers:       make(map[string]models.RegistrationTransfer),
+		refreshTokens:   make(map[string]models.RefreshToken),
+		revokedTokens:   make(map[string]time.Time),
+		passwordResets:  make(map[string]models.PasswordReset),
+	}
+}
+
+// Transactor runs functions against the in-memory store, restoring the
+// previous state if fn returns an error
+type Transactor struct {
+	db *DB
+}
+
+// NewTransactor creates a new in-memory transactor
+func NewTransactor(db *DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+type txKey struct{}
+
+// WithinTx runs fn and rolls back all changes made by it on error.
+// Nested calls join the outer transaction.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	t.db.txMu.Lock()
+	defer t.db.txMu.Unlock()
+
+	snapshot := t.db.snapshot()
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		t.db.restore(snapshot)
+		return err
+	}
+
+	return nil
+}
+
+// lock locks the store for a write and returns the function that unlocks it.
+// A write outside a transaction first waits for any running transaction.
+func (db *DB) lock(ctx context.Context) func() {
+	if ctx.Value(txKey{}) != nil {
+		db.mu.Lock()
+		return db.mu.Unlock
+	}
+
+	db.txMu.Lock()
+	db.mu.Lock()
+	return func() {
+		db.mu.Unlock()
+		db.txMu.Unlock()
+	}
+}
+
+// snapshot returns a copy of the current state
+func (db *DB) snapshot() *DB {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	copied := NewDB()
+	for k, v := range db.events {
+		copied.events[k] = v
+	}
+	for k, v := range db.participants {
+		copied.participants[k] = v
+	}
+	for k, v := range db.admins {
+		copied.admins[k] = v
+	}
+	copied.emailLogs = append(copied.emailLogs, db.emailLogs...)
+	for k, v := range db.payments {
+		copied.payments[k] = v
+	}
+	for k, v := range db.paymentProofs {
+		copied.paymentProofs[k] = v
+	}
+	for k, v := range db.raceCategories {
+		copied.raceCategories[k] = v
+	}
+	for k, v := range db.bibReservations {
+		copied.bibReservations[k] = v
+	}
+	for k, v := range db.emailOutbox {
+		copied.emailOutbox[k] = v
+	}
+	for k, v := range db.emailTemplates {
+		copied.emailTemplates[k] = v
+	}
+	for k, v := range db.emailSchedules {
+		copied.emailSchedules[k] = v
+	}
+	for k, v := range db.emailCampaigns {
+		copied.emailCampaigns[k] = v
+	}
+	for k, v := range db.transfers {
+		copied.transfers[k] = v
+	}
+	for k, v := range db.refreshTokens {
+		copied.refreshTokens[k] = v
+	}
+	for k, v := range db.revokedTokens {
+		copied.revokedTokens[k] = v
+	}
+	for k, v := range db.passwordResets {
+		copied.passwordResets[k] = v
+	}
+	return copied
+}
+
+// restore replaces the current state with a snapshot
+func (db *DB) restore(s *DB) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.events = s.events
+	db.participants = s.participants
+	db.admins = s.admins
+	db.emailLogs = s.emailLogs
+	db.payments = s.payments
+	db.paymentProofs = s.paymentProofs
+	db.raceCategories = s.raceCategories
+	db.bibReservations = s.bibReservations
+	db.emailOutbox = s.emailOutbox
+	db.emailTemplates = s.emailTemplates
+	db.emailSchedules = s.emailSchedules
+	db.emailCampaigns = s.emailCampaigns
+	db.transfers = s.transfers
+	db.refreshTokens = s.refreshTokens
+	db.revokedTokens = s.revokedTokens
+	db.passwordResets = s.passwordResets
+}
+
+// newID generates a random UUID v4
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// Compile-time checks that the repositories satisfy their interfaces
+var (
+	_ repository.Transactor               = (*Transactor)(nil)
+	_ repository.EventRepository          = (*EventRepository)(nil)
+	_ repository.ParticipantRepository    = (*ParticipantRepository)(nil)
+	_ repository.AdminRepository          = (*AdminRepository)(nil)
+	_ repository.EmailLogRepository       = (*EmailLogRepository)(nil)
+	_ repository.PaymentRepository        = (*PaymentRepository)(nil)
+	_ repository.PaymentProofRepository   = (*PaymentProofRepository)(nil)
+	_ repository.RaceCategoryRepository   = (*RaceCategoryRepository)(nil)
+	_ repository.BibReservationRepository = (*BibReservationRepository)(nil)
+	_ repository.EmailOutboxRepository    = (*EmailOutboxRepository)(nil)
+	_ repository.EmailTemplateRepository  = (*EmailTemplateRepository)(nil)
+	_ repository.EmailScheduleRepository  = (*EmailScheduleRepository)(nil)
+	_ repository.EmailCampaignRepository  = (*EmailCampaignRepository)(nil)
+	_ repository.TransferRepository       = (*TransferRepository)(nil)
+	_ repository.SessionRepository        = (*SessionRepository)(nil)
+	_ repository.PasswordResetRepository  = (*PasswordResetRepository)(nil)
+)
@@ -0,0 +1,188 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+func TestTransactorWithinTx(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name      string
+		fnErr     error
+		wantKept  bool
+		wantError error
+	}{
+		{name: "commit", wantKept: true},
+		{name: "rollback", fnErr: errFailed, wantError: errFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewDB()
+			tx := NewTransactor(db)
+			participants := NewParticipantRepository(db)
+			ctx := context.Background()
+
+			existing := &models.Participant{EventID: "event-id", Name: "Existing", Email: "existing@example.com"}
+			if err := participants.Create(ctx, existing); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			var created *models.Participant
+			err := tx.WithinTx(ctx, func(ctx context.Context) error {
+				created = &models.Participant{EventID: "event-id", Name: "Runner", Email: "runner@example.com"}
+				if err := participants.Create(ctx, created); err != nil {
+					return err
+				}
+				if err := participants.UpdatePaymentStatus(ctx, existing, "PAID"); err != nil {
+					return err
+				}
+				return tt.fnErr
+			})
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("WithinTx() error = %v, want %v", err, tt.wantError)
+			}
+
+			got, err := participants.FindByID(ctx, created.ID)
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			if kept := got != nil; kept != tt.wantKept {
+				t.Errorf("participant created in the transaction kept = %t, want %t", kept, tt.wantKept)
+			}
+
+			stored, err := participants.FindByID(ctx, existing.ID)
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			if paid := stored.PaymentStatus == "PAID"; paid != tt.wantKept {
+				t.Errorf("payment status = %s, update kept = %t, want %t", stored.PaymentStatus, paid, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestTransactorWithinTxNested(t *testing.T) {
+	db := NewDB()
+	tx := NewTransactor(db)
+	participants := NewParticipantRepository(db)
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+
+	err := tx.WithinTx(ctx, func(ctx context.Context) error {
+		// The inner call joins the outer transaction instead of waiting for it
+		if err := tx.WithinTx(ctx, func(ctx context.Context) error {
+			return participants.Create(ctx, &models.Participant{EventID: "event-id", Name: "Runner", Email: "runner@example.com"})
+		}); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("WithinTx() error = %v, want %v", err, errFailed)
+	}
+
+	got, err := participants.FindByEventAndEmail(ctx, "event-id", "runner@example.com")
+	if err != nil {
+		t.Fatalf("FindByEventAndEmail() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("participant created in the inner call kept after the outer rollback: %+v", got)
+	}
+}
+
+func TestTransactorRollbackKeepsOtherWrites(t *testing.T) {
+	db := NewDB()
+	tx := NewTransactor(db)
+	participants := NewParticipantRepository(db)
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+
+	done := make(chan error, 1)
+	err := tx.WithinTx(ctx, func(txCtx context.Context) error {
+		if err := participants.Create(txCtx, &models.Participant{EventID: "event-id", Name: "Runner", Email: "runner@example.com"}); err != nil {
+			return err
+		}
+
+		// A write outside the transaction waits until it has been rolled back
+		go func() {
+			done <- participants.Create(ctx, &models.Participant{EventID: "event-id", Name: "Other", Email: "other@example.com"})
+		}()
+		select {
+		case <-done:
+			t.Error("write outside the transaction did not wait for it")
+		case <-time.After(20 * time.Millisecond):
+		}
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("WithinTx() error = %v, want %v", err, errFailed)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Create() outside the transaction error = %v", err)
+	}
+
+	got, err := participants.FindByEventAndEmail(ctx, "event-id", "other@example.com")
+	if err != nil {
+		t.Fatalf("FindByEventAndEmail() error = %v", err)
+	}
+	if got == nil {
+		t.Error("participant created outside the transaction was lost in its rollback")
+	}
+	rolledBack, err := participants.FindByEventAndEmail(ctx, "event-id", "runner@example.com")
+	if err != nil {
+		t.Fatalf("FindByEventAndEmail() error = %v", err)
+	}
+	if rolledBack != nil {
+		t.Errorf("participant created in the transaction kept after its rollback: %+v", rolledBack)
+	}
+}
+
+func TestParticipantRepositoryCreateDuplicateEmail(t *testing.T) {
+	participants := NewParticipantRepository(NewDB())
+	ctx := context.Background()
+
+	if err := participants.Create(ctx, &models.Participant{EventID: "event-id", Name: "Runner", Email: "runner@example.com"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	err := participants.Create(ctx, &models.Participant{EventID: "event-id", Name: "Other", Email: "runner@example.com"})
+	if !errors.Is(err, repository.ErrDuplicateEmail) {
+		t.Errorf("Create() of a duplicate email error = %v, want %v", err, repository.ErrDuplicateEmail)
+	}
+
+	// The same runner may register for another event
+	if err := participants.Create(ctx, &models.Participant{EventID: "other-event-id", Name: "Runner", Email: "runner@example.com"}); err != nil {
+		t.Errorf("Create() for another event error = %v", err)
+	}
+}
+
+func TestParticipantRepositoryCreateDefaults(t *testing.T) {
+	participants := NewParticipantRepository(NewDB())
+	ctx := context.Background()
+
+	p := &models.Participant{EventID: "event-id", Name: "Runner", Email: "runner@example.com"}
+	if err := participants.Create(ctx, p); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if p.ID == "" || p.CreatedAt.IsZero() {
+		t.Errorf("Create() did not set the ID and creation time: %+v", p)
+	}
+
+	got, err := participants.FindByEventAndEmail(ctx, "event-id", "runner@example.com")
+	if err != nil {
+		t.Fatalf("FindByEventAndEmail() error = %v", err)
+	}
+	if got == nil || got.ID != p.ID {
+		t.Fatalf("FindByEventAndEmail() = %+v, want participant %s", got, p.ID)
+	}
+	if got.RegistrationStatus != "PENDING" || got.PaymentStatus != "UNPAID" {
+		t.Errorf("statuses = %s/%s, want PENDING/UNPAID", got.RegistrationStatus, got.PaymentStatus)
+	}
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// EmailCampaignRepository stores email campaigns in memory
+type EmailCampaignRepository struct {
+	db *DB
+}
+
+// NewEmailCampaignRepository creates a new in-memory email campaign repository
+func NewEmailCampaignRepository(db *DB) *EmailCampaignRepository {
+	return &EmailCampaignRepository{db: db}
+}
+
+// Create stores a new campaign
+func (r *EmailCampaignRepository) Create(ctx context.Context, c *models.EmailCampaign) error {
+	defer r.db.lock(ctx)()
+
+	c.ID = newID()
+	c.CreatedAt = time.Now()
+	c.Delivery = nil
+
+	r.db.emailCampaigns[c.ID] = *c
+	c.CreatedByEmail = r.adminEmail(c.CreatedBy)
+	return nil
+}
+
+// FindByID finds a campaign by ID
+func (r *EmailCampaignRepository) FindByID(ctx context.Context, id string) (*models.EmailCampaign, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	c, ok := r.db.emailCampaigns[id]
+	if !ok {
+		return nil, nil // Not found
+	}
+	c.CreatedByEmail = r.adminEmail(c.CreatedBy)
+	return &c, nil
+}
+
+// ListByEvent retrieves the campaigns of an event, newest first
+func (r *EmailCampaignRepository) ListByEvent(ctx context.Context, eventID string) ([]models.EmailCampaign, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	campaigns := []models.EmailCampaign{}
+	for _, c := range r.db.emailCampaigns {
+		if c.EventID == eventID {
+			c.CreatedByEmail = r.adminEmail(c.CreatedBy)
+			campaigns = append(campaigns, c)
+		}
+	}
+
+	sort.Slice(campaigns, func(i, j int) bool {
+		if campaigns[i].CreatedAt.Equal(campaigns[j].CreatedAt) {
+			return campaigns[i].ID > campaigns[j].ID
+		}
+		return campaigns[i].CreatedAt.After(campaigns[j].CreatedAt)
+	})
+
+	return campaigns, nil
+}
+
+// adminEmail returns the email of an admin, or nil if there is none.
+// The caller must hold the lock.
+func (r *EmailCampaignRepository) adminEmail(adminID *string) *string {
+	if adminID == nil {
+		return nil
+	}
+	admin, ok := r.db.admins[*adminID]
+	if !ok {
+		return nil
+	}
+	return &admin.Email
+}
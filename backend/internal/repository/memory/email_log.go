@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+// EmailLogRepository stores email logs in memory
+type EmailLogRepository struct {
+	db *DB
+}
+
+// NewEmailLogRepository creates a new in-memory email log repository
+func NewEmailLogRepository(db *DB) *EmailLogRepository {
+	return &EmailLogRepository{db: db}
+}
+
+// Create stores an email log entry
+func (r *EmailLogRepository) Create(ctx context.Context, log *models.EmailLog) error {
+	defer r.db.lock(ctx)()
+
+	log.ID = newID()
+	log.SentAt = time.Now()
+	r.db.emailLogs = append(r.db.emailLogs, *log)
+	return nil
+}
+
+// All returns every stored email log, oldest first
+func (r *EmailLogRepository) All() []models.EmailLog {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	return append([]models.EmailLog(nil), r.db.emailLogs...)
+}
+
+// HasSucceeded reports whether an email of emailType was ever sent to a participant
+func (r *EmailLogRepository) HasSucceeded(ctx context.Context, participantID, emailType string) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, log := range r.db.emailLogs {
+		if log.ParticipantID == participantID && log.EmailType == emailType && log.Status == "SUCCESS" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListByCampaign retrieves one page of the attempts to send a campaign, oldest first
+func (r *EmailLogRepository) ListByCampaign(ctx context.Context, campaignID, status string, page, limit int) ([]models.EmailLog, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	// Logs are appended in the order they were written
+	logs := []models.EmailLog{}
+	for _, log := range r.db.emailLogs {
+		if log.CampaignID != nil && *log.CampaignID == campaignID && (status == "" || log.Status == status) {
+			logs = append(logs, log)
+		}
+	}
+
+	total := len(logs)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return logs[start:end], total, nil
+}
+
+// FindByID finds an email log entry by ID
+func (r *EmailLogRepository) FindByID(ctx context.Context, id string) (*models.EmailLog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, log := range r.db.emailLogs {
+		if log.ID == id {
+			return &log, nil
+		}
+	}
+	return nil, nil // Not found
+}
+
+// FindLatest finds the most recent attempt to send a participant an email of emailType
+func (r *EmailLogRepository) FindLatest(ctx context.Context, participantID, emailType string) (*models.EmailLog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for i := len(r.db.emailLogs) - 1; i >= 0; i-- {
+		log := r.db.emailLogs[i]
+		if log.ParticipantID == participantID && log.EmailType == emailType {
+			return &log, nil
+		}
+	}
+	return nil, nil // Not found
+}
+
+// List retrieves one page of the email logs matching f, newest first
+func (r *EmailLogRepository) List(ctx context.Context, f repository.EmailLogFilter, page, limit int) ([]models.EmailLog, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	logs := []models.EmailLog{}
+	for i := len(r.db.emailLogs) - 1; i >= 0; i-- {
+		log := r.db.emailLogs[i]
+		participant := r.db.participants[log.ParticipantID]
+		if !matchesEmailLogFilter(log, participant, f) {
+			continue
+		}
+		log.ParticipantName = participant.Name
+		log.EventID = participant.EventID
+		logs = append(logs, log)
+	}
+
+	total := len(logs)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return logs[start:end], total, nil
+}
+
+// matchesEmailLogFilter reports whether an email log to participant matches f
+func matchesEmailLogFilter(log models.EmailLog, participant models.Participant, f repository.EmailLogFilter) bool {
+	if f.ParticipantID != "" && log.ParticipantID != f.ParticipantID {
+		return false
+	}
+	if f.EventID != "" && participant.EventID != f.EventID {
+		return false
+	}
+	if f.EmailType != "" && log.EmailType != f.EmailType {
+		return false
+	}
+	if f.Status != "" && log.Status != f.Status {
+		return false
+	}
+	if f.SentFrom != nil && log.SentAt.Before(*f.SentFrom) {
+		return false
+	}
+	if f.SentBefore != nil && !log.SentAt.Before(*f.SentBefore) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// EmailOutboxRepository stores the email outbox in memory
+type EmailOutboxRepository struct {
+	db *DB
+}
+
+// NewEmailOutboxRepository creates a new in-memory email outbox repository
+func NewEmailOutboxRepository(db *DB) *EmailOutboxRepository {
+	return &EmailOutboxRepository{db: db}
+}
+
+// Create queues a new PENDING email
+func (r *EmailOutboxRepository) Create(ctx context.Context, e *models.OutboxEmail) error {
+	defer r.db.lock(ctx)()
+
+	now := time.Now()
+	e.ID = newID()
+	e.Status = "PENDING"
+	e.Attempts = 0
+	if e.NextAttemptAt.IsZero() {
+		e.NextAttemptAt = now
+	}
+	e.CreatedAt = now
+	e.UpdatedAt = now
+
+	r.db.emailOutbox[e.ID] = *e
+	return nil
+}
+
+// FindByID finds an outbox email by ID
+func (r *EmailOutboxRepository) FindByID(ctx context.Context, id string) (*models.OutboxEmail, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	e, ok := r.db.emailOutbox[id]
+	if !ok {
+		return nil, nil // Not found
+	}
+	return &e, nil
+}
+
+// FindByIDForUpdate finds an outbox email by ID. Transactions are already
+// serialized in memory, so no row lock is needed.
+func (r *EmailOutboxRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.OutboxEmail, error) {
+	return r.FindByID(ctx, id)
+}
+
+// Claim marks up to limit due emails SENDING for one worker
+func (r *EmailOutboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEmail, error) {
+	defer r.db.lock(ctx)()
+
+	due := []models.OutboxEmail{}
+	for _, e := range r.db.emailOutbox {
+		if (e.Status == "PENDING" || e.Status == "SENDING") && !e.NextAttemptAt.After(now) {
+			due = append(due, e)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for i := range due {
+		due[i].Status = "SENDING"
+		due[i].Attempts++
+		due[i].NextAttemptAt = now.Add(lease)
+		due[i].UpdatedAt = time.Now()
+		r.db.emailOutbox[due[i].ID] = due[i]
+	}
+
+	return due, nil
+}
+
+// Update saves the delivery state of an outbox email
+func (r *EmailOutboxRepository) Update(ctx context.Context, e *models.OutboxEmail) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.emailOutbox[e.ID]
+	if !ok {
+		return fmt.Errorf("failed to update outbox email: email %s not found", e.ID)
+	}
+
+	stored.Status = e.Status
+	stored.Attempts = e.Attempts
+	stored.NextAttemptAt = e.NextAttemptAt
+	stored.LastError = e.LastError
+	stored.SentAt = e.SentAt
+	stored.SkipReason = e.SkipReason
+	stored.UpdatedAt = time.Now()
+	r.db.emailOutbox[e.ID] = stored
+
+	e.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// List retrieves one page of outbox emails with the given status, oldest first
+func (r *EmailOutboxRepository) List(ctx context.Context, status string, page, limit int) ([]models.OutboxEmail, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	emails := []models.OutboxEmail{}
+	for _, e := range r.db.emailOutbox {
+		if e.Status != status {
+			continue
+		}
+		if participant, ok := r.db.participants[e.ParticipantID]; ok {
+			e.ParticipantName = participant.Name
+			e.ParticipantEmail = participant.Email
+		}
+		emails = append(emails, e)
+	}
+
+	sort.Slice(emails, func(i, j int) bool {
+		if emails[i].CreatedAt.Equal(emails[j].CreatedAt) {
+			return emails[i].ID < emails[j].ID
+		}
+		return emails[i].CreatedAt.Before(emails[j].CreatedAt)
+	})
+
+	total := len(emails)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return emails[start:end], total, nil
+}
+
+// CountByCampaign counts the emails of a campaign by delivery status
+func (r *EmailOutboxRepository) CountByCampaign(ctx context.Context, campaignID string) (*models.CampaignDelivery, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	delivery := &models.CampaignDelivery{}
+	for _, e := range r.db.emailOutbox {
+		if e.CampaignID == nil || *e.CampaignID != campaignID {
+			continue
+		}
+		switch e.Status {
+		case "SENT":
+			delivery.Sent++
+		case "SKIPPED":
+			delivery.Skipped++
+		case "DEAD":
+			delivery.Failed++
+		default:
+			delivery.Pending++
+		}
+	}
+
+	return delivery, nil
+}
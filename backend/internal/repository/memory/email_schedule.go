@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// EmailScheduleRepository stores email schedules in memory
+type EmailScheduleRepository struct {
+	db *DB
+}
+
+// NewEmailScheduleRepository creates a new in-memory email schedule repository
+func NewEmailScheduleRepository(db *DB) *EmailScheduleRepository {
+	return &EmailScheduleRepository{db: db}
+}
+
+// ListByEvent retrieves the saved schedules of an event
+func (r *EmailScheduleRepository) ListByEvent(ctx context.Context, eventID string) ([]models.EmailSchedule, error) {
+	return r.list(func(s models.EmailSchedule) bool { return s.EventID == eventID }), nil
+}
+
+// ListEnabled retrieves the enabled schedules of every event
+func (r *EmailScheduleRepository) ListEnabled(ctx context.Context) ([]models.EmailSchedule, error) {
+	return r.list(func(s models.EmailSchedule) bool { return s.Enabled }), nil
+}
+
+// Save creates or replaces a schedule
+func (r *EmailScheduleRepository) Save(ctx context.Context, s *models.EmailSchedule) error {
+	defer r.db.lock(ctx)()
+
+	now := time.Now()
+	s.UpdatedAt = &now
+	s.IsDefault = false
+
+	r.db.emailSchedules[s.EventID+"/"+s.EmailType] = *s
+	return nil
+}
+
+// list returns the schedules matching keep, by event and email type
+func (r *EmailScheduleRepository) list(keep func(s models.EmailSchedule) bool) []models.EmailSchedule {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	schedules := []models.EmailSchedule{}
+	for _, s := range r.db.emailSchedules {
+		if keep(s) {
+			schedules = append(schedules, s)
+		}
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		if schedules[i].EventID == schedules[j].EventID {
+			return schedules[i].EmailType < schedules[j].EmailType
+		}
+		return schedules[i].EventID < schedules[j].EventID
+	})
+
+	return schedules
+}
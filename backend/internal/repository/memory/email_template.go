@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// EmailTemplateRepository stores email template versions in memory
+type EmailTemplateRepository struct {
+	db *DB
+}
+
+// NewEmailTemplateRepository creates a new in-memory email template repository
+func NewEmailTemplateRepository(db *DB) *EmailTemplateRepository {
+	return &EmailTemplateRepository{db: db}
+}
+
+// Create stores t as the next version of its event and email type
+func (r *EmailTemplateRepository) Create(ctx context.Context, t *models.EmailTemplate) error {
+	defer r.db.lock(ctx)()
+
+	latest := 0
+	for _, existing := range r.db.emailTemplates {
+		if existing.EventID == t.EventID && existing.EmailType == t.EmailType && existing.Version > latest {
+			latest = existing.Version
+		}
+	}
+
+	now := time.Now()
+	t.ID = newID()
+	t.Version = latest + 1
+	t.CreatedAt = &now
+	t.IsDefault = false
+
+	r.db.emailTemplates[t.ID] = *t
+	t.CreatedByEmail = r.adminEmail(t.CreatedBy)
+	return nil
+}
+
+// FindLatest finds the highest version of a template
+func (r *EmailTemplateRepository) FindLatest(ctx context.Context, eventID, emailType string) (*models.EmailTemplate, error) {
+	versions, _ := r.ListVersions(ctx, eventID, emailType)
+	if len(versions) == 0 {
+		return nil, nil // Not found
+	}
+	return &versions[0], nil
+}
+
+// FindVersion finds one version of a template
+func (r *EmailTemplateRepository) FindVersion(ctx context.Context, eventID, emailType string, version int) (*models.EmailTemplate, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, t := range r.db.emailTemplates {
+		if t.EventID == eventID && t.EmailType == emailType && t.Version == version {
+			t.CreatedByEmail = r.adminEmail(t.CreatedBy)
+			return &t, nil
+		}
+	}
+	return nil, nil // Not found
+}
+
+// ListVersions retrieves every version of a template, newest first
+func (r *EmailTemplateRepository) ListVersions(ctx context.Context, eventID, emailType string) ([]models.EmailTemplate, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	versions := []models.EmailTemplate{}
+	for _, t := range r.db.emailTemplates {
+		if t.EventID == eventID && t.EmailType == emailType {
+			t.CreatedByEmail = r.adminEmail(t.CreatedBy)
+			versions = append(versions, t)
+		}
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+
+	return versions, nil
+}
+
+// adminEmail returns the email of an admin, or nil if there is none.
+// The caller must hold the lock.
+func (r *EmailTemplateRepository) adminEmail(adminID *string) *string {
+	if adminID == nil {
+		return nil
+	}
+	admin, ok := r.db.admins[*adminID]
+	if !ok {
+		return nil
+	}
+	return &admin.Email
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+// EventRepository stores events in memory
+type EventRepository struct {
+	db *DB
+}
+
+// NewEventRepository creates a new in-memory event repository
+func NewEventRepository(db *DB) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+// Create stores a new event
+func (r *EventRepository) Create(ctx context.Context, e *models.Event) error {
+	defer r.db.lock(ctx)()
+
+	now := time.Now()
+	e.ID = newID()
+	e.Registered = 0
+	e.CreatedAt = now
+	e.UpdatedAt = now
+
+	r.db.events[e.ID] = *e
+	return nil
+}
+
+// FindByID finds an event by ID
+func (r *EventRepository) FindByID(ctx context.Context, id string) (*models.Event, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	e, ok := r.db.events[id]
+	if !ok {
+		return nil, nil // Not found
+	}
+	e.Registered = r.registered(id)
+	return &e, nil
+}
+
+// FindByIDForUpdate finds an event by ID. Transactions are already
+// serialized in memory, so no row lock is needed.
+func (r *EventRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.Event, error) {
+	return r.FindByID(ctx, id)
+}
+
+// List retrieves all events, soonest event date first
+func (r *EventRepository) List(ctx context.Context) ([]models.Event, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	events := make([]models.Event, 0, len(r.db.events))
+	for _, e := range r.db.events {
+		e.Registered = r.registered(e.ID)
+		events = append(events, e)
+	}
+
+	// YYYY-MM-DD sorts chronologically as a string
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].EventDate == events[j].EventDate {
+			return events[i].CreatedAt.Before(events[j].CreatedAt)
+		}
+		return events[i].EventDate < events[j].EventDate
+	})
+
+	return events, nil
+}
+
+// Update saves the editable fields of an event
+func (r *EventRepository) Update(ctx context.Context, e *models.Event) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.events[e.ID]
+	if !ok {
+		return fmt.Errorf("failed to update event: event %s not found", e.ID)
+	}
+
+	stored.Name = e.Name
+	stored.EventDate = e.EventDate
+	stored.Location = e.Location
+	stored.Description = e.Description
+	stored.RegistrationOpen = e.RegistrationOpen
+	stored.Capacity = e.Capacity
+	stored.RefundFullUntil = e.RefundFullUntil
+	stored.RefundPartialPercent = e.RefundPartialPercent
+	stored.RefundCutoffDays = e.RefundCutoffDays
+	stored.TransferDeadline = e.TransferDeadline
+	stored.UpdatedAt = time.Now()
+	r.db.events[e.ID] = stored
+
+	*e = stored
+	e.Registered = r.registered(e.ID)
+	return nil
+}
+
+// Delete removes an event without participants
+func (r *EventRepository) Delete(ctx context.Context, id string) error {
+	defer r.db.lock(ctx)()
+
+	for _, p := range r.db.participants {
+		if p.EventID == id {
+			return repository.ErrEventInUse
+		}
+	}
+
+	delete(r.db.events, id)
+	return nil
+}
+
+// registered counts the participants holding a spot in an event. The caller must hold db.mu.
+func (r *EventRepository) registered(eventID string) int {
+	count := 0
+	for _, p := range r.db.participants {
+		if p.EventID == eventID && holdsSpot(p) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,499 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+// ParticipantRepository stores participants in memory
+type ParticipantRepository struct {
+	db *DB
+}
+
+// NewParticipantRepository creates a new in-memory participant repository
+func NewParticipantRepository(db *DB) *ParticipantRepository {
+	return &ParticipantRepository{db: db}
+}
+
+// Create stores a new participant. The registration status defaults to PENDING.
+func (r *ParticipantRepository) Create(ctx context.Context, p *models.Participant) error {
+	defer r.db.lock(ctx)()
+
+	for _, existing := range r.db.participants {
+		if existing.EventID == p.EventID && existing.Email == p.Email {
+			return repository.ErrDuplicateEmail
+		}
+	}
+
+	now := time.Now()
+	p.ID = newID()
+	if p.RegistrationStatus == "" {
+		p.RegistrationStatus = "PENDING"
+	}
+	p.PaymentStatus = "UNPAID"
+	p.CheckInVersion = 1
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	r.db.participants[p.ID] = *p
+	return nil
+}
+
+// FindByID finds a participant by ID
+func (r *ParticipantRepository) FindByID(ctx context.Context, id string) (*models.Participant, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	p, ok := r.db.participants[id]
+	if !ok {
+		return nil, nil // Not found
+	}
+	return &p, nil
+}
+
+// FindByIDForUpdate finds a participant by ID. Transactions are already
+// serialized in memory, so no row lock is needed.
+func (r *ParticipantRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.Participant, error) {
+	return r.FindByID(ctx, id)
+}
+
+// FindByEventAndEmail finds a participant by event and email
+func (r *ParticipantRepository) FindByEventAndEmail(ctx context.Context, eventID, email string) (*models.Participant, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, p := range r.db.participants {
+		if p.EventID == eventID && p.Email == email {
+			return &p, nil
+		}
+	}
+	return nil, nil // Not found
+}
+
+// ListByEmail retrieves the registrations of email for every event, newest first
+func (r *ParticipantRepository) ListByEmail(ctx context.Context, email string) ([]models.Participant, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	participants := []models.Participant{}
+	for _, p := range r.db.participants {
+		if p.Email == email {
+			participants = append(participants, p)
+		}
+	}
+
+	sortParticipants(participants, "created_at", repository.SortDesc)
+	return participants, nil
+}
+
+// List retrieves one page of participants matching the query
+func (r *ParticipantRepository) List(ctx context.Context, q repository.ParticipantQuery) ([]models.Participant, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	participants := []models.Participant{}
+	for _, p := range r.db.participants {
+		if matchesParticipantFilter(p, q.Filter) {
+			participants = append(participants, p)
+		}
+	}
+
+	sortParticipants(participants, q.SortBy, q.SortOrder)
+
+	total := len(participants)
+	start := q.Offset()
+	if start > total {
+		start = total
+	}
+	end := total
+	if q.Limit > 0 && start+q.Limit < total {
+		end = start + q.Limit
+	}
+
+	return participants[start:end], total, nil
+}
+
+// Stream calls fn for every participant matching the query. Matches are
+// copied before iterating so fn may safely call back into the repository.
+func (r *ParticipantRepository) Stream(ctx context.Context, q repository.ParticipantQuery, fn func(p *models.Participant) error) error {
+	r.db.mu.RLock()
+	participants := []models.Participant{}
+	for _, p := range r.db.participants {
+		if matchesParticipantFilter(p, q.Filter) {
+			participants = append(participants, p)
+		}
+	}
+	r.db.mu.RUnlock()
+
+	sortParticipants(participants, q.SortBy, q.SortOrder)
+
+	for i := range participants {
+		if err := fn(&participants[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdatePaymentStatus updates the payment status of a participant
+func (r *ParticipantRepository) UpdatePaymentStatus(ctx context.Context, p *models.Participant, status string) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.participants[p.ID]
+	if !ok {
+		return fmt.Errorf("failed to update payment status: participant %s not found", p.ID)
+	}
+
+	stored.PaymentStatus = status
+	stored.UpdatedAt = time.Now()
+	r.db.participants[p.ID] = stored
+
+	p.PaymentStatus = stored.PaymentStatus
+	p.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// UpdateRegistration saves the registration status, waitlist position and offer deadline
+func (r *ParticipantRepository) UpdateRegistration(ctx context.Context, p *models.Participant) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.participants[p.ID]
+	if !ok {
+		return fmt.Errorf("failed to update registration: participant %s not found", p.ID)
+	}
+
+	stored.RegistrationStatus = p.RegistrationStatus
+	stored.WaitlistPosition = p.WaitlistPosition
+	stored.OfferExpiresAt = p.OfferExpiresAt
+	stored.UpdatedAt = time.Now()
+	r.db.participants[p.ID] = stored
+
+	p.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// ListWaitlist retrieves the waitlisted participants of an event in queue order
+func (r *ParticipantRepository) ListWaitlist(ctx context.Context, eventID string) ([]models.Participant, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	participants := []models.Participant{}
+	for _, p := range r.db.participants {
+		if p.EventID == eventID && p.RegistrationStatus == "WAITLISTED" {
+			participants = append(participants, p)
+		}
+	}
+
+	category := func(p models.Participant) string {
+		if p.CategoryID == nil {
+			return ""
+		}
+		return *p.CategoryID
+	}
+	position := func(p models.Participant) int {
+		if p.WaitlistPosition == nil {
+			return 0
+		}
+		return *p.WaitlistPosition
+	}
+
+	sort.Slice(participants, func(i, j int) bool {
+		a, b := participants[i], participants[j]
+		if category(a) != category(b) {
+			return category(a) < category(b)
+		}
+		if position(a) != position(b) {
+			return position(a) < position(b)
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	return participants, nil
+}
+
+// ListExpiredOffers retrieves unpaid participants whose spot offer expired before t
+func (r *ParticipantRepository) ListExpiredOffers(ctx context.Context, t time.Time) ([]models.Participant, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	participants := []models.Participant{}
+	for _, p := range r.db.participants {
+		if p.RegistrationStatus == "PENDING" && p.PaymentStatus == "UNPAID" && p.OfferExpiresAt != nil && p.OfferExpiresAt.Before(t) {
+			participants = append(participants, p)
+		}
+	}
+
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].OfferExpiresAt.Before(*participants[j].OfferExpiresAt)
+	})
+
+	return participants, nil
+}
+
+// UpdateBibNumber saves the bib number of a participant
+func (r *ParticipantRepository) UpdateBibNumber(ctx context.Context, p *models.Participant) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.participants[p.ID]
+	if !ok {
+		return fmt.Errorf("failed to update bib number: participant %s not found", p.ID)
+	}
+
+	if p.BibNumber != nil {
+		for _, other := range r.db.participants {
+			if other.ID != p.ID && other.EventID == stored.EventID && sameCategory(other.CategoryID, stored.CategoryID) &&
+				other.BibNumber != nil && *other.BibNumber == *p.BibNumber {
+				return repository.ErrDuplicateBibNumber
+			}
+		}
+	}
+
+	stored.BibNumber = p.BibNumber
+	stored.UpdatedAt = time.Now()
+	r.db.participants[p.ID] = stored
+
+	p.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// ListBibNumbers retrieves the bib numbers taken in a race category of an event, lowest first
+func (r *ParticipantRepository) ListBibNumbers(ctx context.Context, eventID string, categoryID *string) ([]int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	numbers := []int{}
+	for _, p := range r.db.participants {
+		if p.EventID == eventID && sameCategory(p.CategoryID, categoryID) && p.BibNumber != nil {
+			numbers = append(numbers, *p.BibNumber)
+		}
+	}
+
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+// UpdateCheckIn saves the race-kit pickup and race-day check-in of a participant
+func (r *ParticipantRepository) UpdateCheckIn(ctx context.Context, p *models.Participant) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.participants[p.ID]
+	if !ok {
+		return fmt.Errorf("failed to update check-in: participant %s not found", p.ID)
+	}
+
+	stored.KitCollectedAt = p.KitCollectedAt
+	stored.KitCollectedBy = p.KitCollectedBy
+	stored.CheckedInAt = p.CheckedInAt
+	stored.CheckedInBy = p.CheckedInBy
+	stored.UpdatedAt = time.Now()
+	r.db.participants[p.ID] = stored
+
+	p.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// UpdateCancellation saves the cancellation and refund of a participant
+func (r *ParticipantRepository) UpdateCancellation(ctx context.Context, p *models.Participant) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.participants[p.ID]
+	if !ok {
+		return fmt.Errorf("failed to update cancellation: participant %s not found", p.ID)
+	}
+
+	stored.CancelledAt = p.CancelledAt
+	stored.CancelledBy = p.CancelledBy
+	stored.CancellationReason = p.CancellationReason
+	stored.RefundAmount = p.RefundAmount
+	stored.RefundCurrency = p.RefundCurrency
+	stored.RefundedAt = p.RefundedAt
+	stored.RefundedBy = p.RefundedBy
+	stored.UpdatedAt = time.Now()
+	r.db.participants[p.ID] = stored
+
+	p.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// UpdateContact updates the phone, address and instagram_handle of a participant
+func (r *ParticipantRepository) UpdateContact(ctx context.Context, p *models.Participant) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.participants[p.ID]
+	if !ok {
+		return fmt.Errorf("failed to update contact details: participant %s not found", p.ID)
+	}
+
+	stored.Phone = p.Phone
+	stored.Address = p.Address
+	stored.InstagramHandle = p.InstagramHandle
+	stored.UpdatedAt = time.Now()
+	r.db.participants[p.ID] = stored
+
+	p.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// UpdateOwner saves who holds a registration: the name, email, contact
+// details and date of birth of p
+func (r *ParticipantRepository) UpdateOwner(ctx context.Context, p *models.Participant) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.participants[p.ID]
+	if !ok {
+		return fmt.Errorf("failed to update participant owner: participant %s not found", p.ID)
+	}
+
+	for id, existing := range r.db.participants {
+		if id != p.ID && existing.EventID == stored.EventID && existing.Email == p.Email {
+			return repository.ErrDuplicateEmail
+		}
+	}
+
+	stored.Name = p.Name
+	stored.Email = p.Email
+	stored.Phone = p.Phone
+	stored.Address = p.Address
+	stored.InstagramHandle = p.InstagramHandle
+	stored.DateOfBirth = p.DateOfBirth
+	stored.CheckInVersion = p.CheckInVersion
+	stored.UpdatedAt = time.Now()
+	r.db.participants[p.ID] = stored
+
+	p.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// ListWithoutEmail retrieves the participants matching f who have neither
+// been queued nor successfully sent an email of emailType, oldest first
+func (r *ParticipantRepository) ListWithoutEmail(ctx context.Context, f repository.ParticipantFilter, emailType string) ([]models.Participant, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	emailed := make(map[string]bool)
+	for _, e := range r.db.emailOutbox {
+		if e.EmailType == emailType {
+			emailed[e.ParticipantID] = true
+		}
+	}
+	for _, log := range r.db.emailLogs {
+		if log.EmailType == emailType && log.Status == "SUCCESS" {
+			emailed[log.ParticipantID] = true
+		}
+	}
+
+	participants := []models.Participant{}
+	for _, p := range r.db.participants {
+		if !emailed[p.ID] && matchesParticipantFilter(p, f) {
+			participants = append(participants, p)
+		}
+	}
+
+	sortParticipants(participants, "created_at", repository.SortAsc)
+	return participants, nil
+}
+
+// sameCategory reports whether two optional race category IDs are equal
+func sameCategory(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// holdsSpot reports whether p counts against event and race category capacity
+func holdsSpot(p models.Participant) bool {
+	return p.RegistrationStatus == "PENDING" || p.RegistrationStatus == "CONFIRMED"
+}
+
+// matchesParticipantFilter reports whether p satisfies every set field of f
+func matchesParticipantFilter(p models.Participant, f repository.ParticipantFilter) bool {
+	if f.EventID != "" && p.EventID != f.EventID {
+		return false
+	}
+	if f.PaymentStatus != "" && p.PaymentStatus != f.PaymentStatus {
+		return false
+	}
+	if f.RegistrationStatus != "" && p.RegistrationStatus != f.RegistrationStatus {
+		return false
+	}
+	if f.CategoryID != "" && (p.CategoryID == nil || *p.CategoryID != f.CategoryID) {
+		return false
+	}
+	if f.CheckedIn != nil && (p.CheckedInAt != nil) != *f.CheckedIn {
+		return false
+	}
+	if f.CreatedFrom != nil && p.CreatedAt.Before(*f.CreatedFrom) {
+		return false
+	}
+	if f.CreatedBefore != nil && !p.CreatedAt.Before(*f.CreatedBefore) {
+		return false
+	}
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		fields := []string{p.Name, p.Email, p.Phone}
+		if p.InstagramHandle != nil {
+			fields = append(fields, *p.InstagramHandle)
+		}
+
+		found := false
+		for _, field := range fields {
+			if strings.Contains(strings.ToLower(field), search) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// sortParticipants sorts in place by a whitelisted field, breaking ties by ID
+func sortParticipants(participants []models.Participant, sortBy, sortOrder string) {
+	compare := func(a, b models.Participant) int {
+		switch sortBy {
+		case "updated_at":
+			return a.UpdatedAt.Compare(b.UpdatedAt)
+		case "name":
+			return strings.Compare(a.Name, b.Name)
+		case "email":
+			return strings.Compare(a.Email, b.Email)
+		case "payment_status":
+			return strings.Compare(a.PaymentStatus, b.PaymentStatus)
+		case "registration_status":
+			return strings.Compare(a.RegistrationStatus, b.RegistrationStatus)
+		case "bib_number":
+			// Like PostgreSQL, participants without a bib sort as the largest
+			switch {
+			case a.BibNumber == nil && b.BibNumber == nil:
+				return 0
+			case a.BibNumber == nil:
+				return 1
+			case b.BibNumber == nil:
+				return -1
+			}
+			return *a.BibNumber - *b.BibNumber
+		default:
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+	}
+
+	sort.Slice(participants, func(i, j int) bool {
+		c := compare(participants[i], participants[j])
+		if c == 0 {
+			c = strings.Compare(participants[i].ID, participants[j].ID)
+		}
+		if sortOrder == repository.SortAsc {
+			return c < 0
+		}
+		return c > 0
+	})
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+func TestParticipantRepositoryList(t *testing.T) {
+	participants := NewParticipantRepository(NewDB())
+	ctx := context.Background()
+
+	instagram := "@citra_runs"
+	for _, p := range []*models.Participant{
+		{Name: "Budi", Email: "budi@example.com", Phone: "+6281111111111"},
+		{Name: "Citra", Email: "citra@example.com", Phone: "+6282222222222", InstagramHandle: &instagram},
+		{Name: "Adi", Email: "adi@example.com", Phone: "+6283333333333"},
+		{Name: "Dewi", Email: "dewi@example.com", Phone: "+6284444444444"},
+	} {
+		if err := participants.Create(ctx, p); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if p.Name == "Budi" || p.Name == "Dewi" {
+			if err := participants.UpdatePaymentStatus(ctx, p, "PAID"); err != nil {
+				t.Fatalf("UpdatePaymentStatus() error = %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name      string
+		query     repository.ParticipantQuery
+		wantNames []string
+		wantTotal int
+	}{
+		{
+			name:      "sorted by name",
+			query:     repository.ParticipantQuery{SortBy: "name", SortOrder: repository.SortAsc},
+			wantNames: []string{"Adi", "Budi", "Citra", "Dewi"},
+			wantTotal: 4,
+		},
+		{
+			name:      "second page",
+			query:     repository.ParticipantQuery{SortBy: "name", SortOrder: repository.SortDesc, Page: 2, Limit: 3},
+			wantNames: []string{"Adi"},
+			wantTotal: 4,
+		},
+		{
+			name:      "page past the end",
+			query:     repository.ParticipantQuery{SortBy: "name", Page: 3, Limit: 3},
+			wantNames: []string{},
+			wantTotal: 4,
+		},
+		{
+			name:      "payment status",
+			query:     repository.ParticipantQuery{Filter: repository.ParticipantFilter{PaymentStatus: "PAID"}, SortBy: "name", SortOrder: repository.SortAsc},
+			wantNames: []string{"Budi", "Dewi"},
+			wantTotal: 2,
+		},
+		{
+			name:      "search instagram handle",
+			query:     repository.ParticipantQuery{Filter: repository.ParticipantFilter{Search: "CITRA_"}},
+			wantNames: []string{"Citra"},
+			wantTotal: 1,
+		},
+		{
+			name:      "search phone",
+			query:     repository.ParticipantQuery{Filter: repository.ParticipantFilter{Search: "3333"}},
+			wantNames: []string{"Adi"},
+			wantTotal: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := participants.List(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", total, tt.wantTotal)
+			}
+
+			names := []string{}
+			for _, p := range got {
+				names = append(names, p.Name)
+			}
+			if len(names) != len(tt.wantNames) {
+				t.Fatalf("names = %v, want %v", names, tt.wantNames)
+			}
+			for i := range names {
+				if names[i] != tt.wantNames[i] {
+					t.Fatalf("names = %v, want %v", names, tt.wantNames)
+				}
+			}
+		})
+	}
+}
+
+func TestParticipantRepositoryListByEvent(t *testing.T) {
+	participants := NewParticipantRepository(NewDB())
+	ctx := context.Background()
+
+	for _, p := range []*models.Participant{
+		{EventID: "city-run", Name: "Budi", Email: "budi@example.com"},
+		{EventID: "trail-run", Name: "Budi", Email: "budi@example.com"},
+		{EventID: "trail-run", Name: "Citra", Email: "citra@example.com"},
+	} {
+		if err := participants.Create(ctx, p); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	got, total, err := participants.List(ctx, repository.ParticipantQuery{Filter: repository.ParticipantFilter{EventID: "trail-run"}})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != 2 || len(got) != 2 {
+		t.Fatalf("List() returned %d of %d participants, want 2 of 2", len(got), total)
+	}
+	for _, p := range got {
+		if p.EventID != "trail-run" {
+			t.Errorf("List() returned %s registered for %s", p.Name, p.EventID)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// PasswordResetRepository stores admin password reset tokens in memory
+type PasswordResetRepository struct {
+	db *DB
+}
+
+// NewPasswordResetRepository creates a new in-memory password reset repository
+func NewPasswordResetRepository(db *DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// Create stores a new reset token
+func (r *PasswordResetRepository) Create(ctx context.Context, reset *models.PasswordReset) error {
+	defer r.db.lock(ctx)()
+
+	for _, existing := range r.db.passwordResets {
+		if existing.TokenHash == reset.TokenHash {
+			return fmt.Errorf("failed to create password reset: duplicate token hash")
+		}
+	}
+
+	reset.ID = newID()
+	reset.CreatedAt = time.Now()
+	r.db.passwordResets[reset.ID] = *reset
+	return nil
+}
+
+// FindByTokenHashForUpdate finds a reset token by hash. Transactions are
+// already serialized in memory, so no row lock is needed.
+func (r *PasswordResetRepository) FindByTokenHashForUpdate(ctx context.Context, tokenHash string) (*models.PasswordReset, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, reset := range r.db.passwordResets {
+		if reset.TokenHash == tokenHash {
+			return &reset, nil
+		}
+	}
+	return nil, nil // Not found
+}
+
+// InvalidateByAdmin marks every unused reset token of an admin used
+func (r *PasswordResetRepository) InvalidateByAdmin(ctx context.Context, adminID string, usedAt time.Time) error {
+	defer r.db.lock(ctx)()
+
+	for id, reset := range r.db.passwordResets {
+		if reset.AdminID == adminID && reset.UsedAt == nil {
+			reset.UsedAt = &usedAt
+			r.db.passwordResets[id] = reset
+		}
+	}
+	return nil
+}
+
+// DeleteExpired removes expired reset tokens
+func (r *PasswordResetRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	defer r.db.lock(ctx)()
+
+	deleted := 0
+	for id, reset := range r.db.passwordResets {
+		if reset.ExpiresAt.Before(now) {
+			delete(r.db.passwordResets, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// PaymentRepository stores payments in memory
+type PaymentRepository struct {
+	db *DB
+}
+
+// NewPaymentRepository creates a new in-memory payment repository
+func NewPaymentRepository(db *DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+// Create stores a new payment
+func (r *PaymentRepository) Create(ctx context.Context, p *models.Payment) error {
+	defer r.db.lock(ctx)()
+
+	for _, existing := range r.db.payments {
+		if existing.Reference == p.Reference {
+			return fmt.Errorf("failed to create payment: duplicate reference %s", p.Reference)
+		}
+	}
+
+	now := time.Now()
+	p.ID = newID()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	r.db.payments[p.ID] = *p
+	return nil
+}
+
+// FindByReferenceForUpdate finds a payment by our reference. Transactions are
+// already serialized in memory, so no row lock is needed.
+func (r *PaymentRepository) FindByReferenceForUpdate(ctx context.Context, reference string) (*models.Payment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, p := range r.db.payments {
+		if p.Reference == reference {
+			return &p, nil
+		}
+	}
+	return nil, nil // Not found
+}
+
+// FindPendingByParticipant finds the newest pending payment of a participant
+func (r *PaymentRepository) FindPendingByParticipant(ctx context.Context, participantID string) (*models.Payment, error) {
+	return r.findNewest(participantID, "PENDING"), nil
+}
+
+// FindPaidByParticipant finds the newest paid payment of a participant
+func (r *PaymentRepository) FindPaidByParticipant(ctx context.Context, participantID string) (*models.Payment, error) {
+	return r.findNewest(participantID, "PAID"), nil
+}
+
+// findNewest returns the newest payment of a participant with status
+func (r *PaymentRepository) findNewest(participantID, status string) *models.Payment {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var newest *models.Payment
+	for _, p := range r.db.payments {
+		if p.ParticipantID != participantID || p.Status != status {
+			continue
+		}
+		if newest == nil || p.CreatedAt.After(newest.CreatedAt) {
+			p := p
+			newest = &p
+		}
+	}
+	return newest
+}
+
+// UpdateStatus updates the status of a payment, recording when it was paid
+func (r *PaymentRepository) UpdateStatus(ctx context.Context, p *models.Payment, status string) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.payments[p.ID]
+	if !ok {
+		return fmt.Errorf("failed to update payment status: payment %s not found", p.ID)
+	}
+
+	now := time.Now()
+	stored.Status = status
+	if status == "PAID" && stored.PaidAt == nil {
+		stored.PaidAt = &now
+	}
+	stored.UpdatedAt = now
+	r.db.payments[p.ID] = stored
+
+	*p = stored
+	return nil
+}
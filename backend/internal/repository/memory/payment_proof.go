@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// PaymentProofRepository stores payment proofs in memory
+type PaymentProofRepository struct {
+	db *DB
+}
+
+// NewPaymentProofRepository creates a new in-memory payment proof repository
+func NewPaymentProofRepository(db *DB) *PaymentProofRepository {
+	return &PaymentProofRepository{db: db}
+}
+
+// Create stores a new pending payment proof
+func (r *PaymentProofRepository) Create(ctx context.Context, p *models.PaymentProof) error {
+	defer r.db.lock(ctx)()
+
+	p.ID = newID()
+	p.Status = "PENDING"
+	p.CreatedAt = time.Now()
+
+	r.db.paymentProofs[p.ID] = *p
+	return nil
+}
+
+// FindByID finds a payment proof by ID
+func (r *PaymentProofRepository) FindByID(ctx context.Context, id string) (*models.PaymentProof, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	p, ok := r.db.paymentProofs[id]
+	if !ok {
+		return nil, nil // Not found
+	}
+	return &p, nil
+}
+
+// FindByIDForUpdate finds a payment proof by ID. Transactions are already
+// serialized in memory, so no row lock is needed.
+func (r *PaymentProofRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.PaymentProof, error) {
+	return r.FindByID(ctx, id)
+}
+
+// List retrieves one page of proofs with the given status, oldest first
+func (r *PaymentProofRepository) List(ctx context.Context, status string, page, limit int) ([]models.PaymentProof, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	proofs := []models.PaymentProof{}
+	for _, p := range r.db.paymentProofs {
+		if p.Status != status {
+			continue
+		}
+		if participant, ok := r.db.participants[p.ParticipantID]; ok {
+			p.ParticipantName = participant.Name
+			p.ParticipantEmail = participant.Email
+		}
+		proofs = append(proofs, p)
+	}
+
+	sort.Slice(proofs, func(i, j int) bool {
+		if proofs[i].CreatedAt.Equal(proofs[j].CreatedAt) {
+			return proofs[i].ID < proofs[j].ID
+		}
+		return proofs[i].CreatedAt.Before(proofs[j].CreatedAt)
+	})
+
+	total := len(proofs)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return proofs[start:end], total, nil
+}
+
+// UpdateReview records an admin's review decision
+func (r *PaymentProofRepository) UpdateReview(ctx context.Context, p *models.PaymentProof, status, reviewedBy string, note *string) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.paymentProofs[p.ID]
+	if !ok {
+		return fmt.Errorf("failed to update payment proof: proof %s not found", p.ID)
+	}
+
+	now := time.Now()
+	stored.Status = status
+	stored.ReviewedBy = &reviewedBy
+	stored.ReviewNote = note
+	stored.ReviewedAt = &now
+	r.db.paymentProofs[p.ID] = stored
+
+	*p = stored
+	return nil
+}
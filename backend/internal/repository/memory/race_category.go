@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+// RaceCategoryRepository stores race categories in memory
+type RaceCategoryRepository struct {
+	db *DB
+}
+
+// NewRaceCategoryRepository creates a new in-memory race category repository
+func NewRaceCategoryRepository(db *DB) *RaceCategoryRepository {
+	return &RaceCategoryRepository{db: db}
+}
+
+// Create stores a new race category
+func (r *RaceCategoryRepository) Create(ctx context.Context, c *models.RaceCategory) error {
+	defer r.db.lock(ctx)()
+
+	if r.hasName(c.EventID, c.Name, "") {
+		return repository.ErrDuplicateCategory
+	}
+
+	now := time.Now()
+	c.ID = newID()
+	c.Registered = 0
+	c.CreatedAt = now
+	c.UpdatedAt = now
+
+	r.db.raceCategories[c.ID] = *c
+	return nil
+}
+
+// FindByID finds a race category by ID
+func (r *RaceCategoryRepository) FindByID(ctx context.Context, id string) (*models.RaceCategory, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	c, ok := r.db.raceCategories[id]
+	if !ok {
+		return nil, nil // Not found
+	}
+	c.Registered = r.registered(id)
+	return &c, nil
+}
+
+// FindByIDForUpdate finds a race category by ID. Transactions are already
+// serialized in memory, so no row lock is needed.
+func (r *RaceCategoryRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.RaceCategory, error) {
+	return r.FindByID(ctx, id)
+}
+
+// ListByEvent retrieves the categories of an event, shortest distance first
+func (r *RaceCategoryRepository) ListByEvent(ctx context.Context, eventID string) ([]models.RaceCategory, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	categories := []models.RaceCategory{}
+	for _, c := range r.db.raceCategories {
+		if c.EventID == eventID {
+			c.Registered = r.registered(c.ID)
+			categories = append(categories, c)
+		}
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].DistanceKM == categories[j].DistanceKM {
+			return categories[i].Name < categories[j].Name
+		}
+		return categories[i].DistanceKM < categories[j].DistanceKM
+	})
+
+	return categories, nil
+}
+
+// Update saves the editable fields of a race category
+func (r *RaceCategoryRepository) Update(ctx context.Context, c *models.RaceCategory) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.raceCategories[c.ID]
+	if !ok {
+		return fmt.Errorf("failed to update race category: category %s not found", c.ID)
+	}
+	if r.hasName(stored.EventID, c.Name, c.ID) {
+		return repository.ErrDuplicateCategory
+	}
+
+	stored.Name = c.Name
+	stored.DistanceKM = c.DistanceKM
+	stored.Price = c.Price
+	stored.Currency = c.Currency
+	stored.Capacity = c.Capacity
+	stored.MinAge = c.MinAge
+	stored.MaxAge = c.MaxAge
+	stored.UpdatedAt = time.Now()
+	r.db.raceCategories[c.ID] = stored
+
+	*c = stored
+	c.Registered = r.registered(c.ID)
+	return nil
+}
+
+// Delete removes a race category without participants
+func (r *RaceCategoryRepository) Delete(ctx context.Context, id string) error {
+	defer r.db.lock(ctx)()
+
+	for _, p := range r.db.participants {
+		if p.CategoryID != nil && *p.CategoryID == id {
+			return repository.ErrCategoryInUse
+		}
+	}
+
+	delete(r.db.raceCategories, id)
+	return nil
+}
+
+// registered counts the participants holding a spot in a category. The caller must hold db.mu.
+func (r *RaceCategoryRepository) registered(categoryID string) int {
+	count := 0
+	for _, p := range r.db.participants {
+		if p.CategoryID != nil && *p.CategoryID == categoryID && holdsSpot(p) {
+			count++
+		}
+	}
+	return count
+}
+
+// hasName reports whether another category of the event uses name. The caller must hold db.mu.
+func (r *RaceCategoryRepository) hasName(eventID, name, exceptID string) bool {
+	for _, c := range r.db.raceCategories {
+		if c.EventID == eventID && c.Name == name && c.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// SessionRepository stores admin sessions in memory
+type SessionRepository struct {
+	db *DB
+}
+
+// NewSessionRepository creates a new in-memory admin session repository
+func NewSessionRepository(db *DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// CreateRefreshToken stores a new refresh token
+func (r *SessionRepository) CreateRefreshToken(ctx context.Context, t *models.RefreshToken) error {
+	defer r.db.lock(ctx)()
+
+	for _, existing := range r.db.refreshTokens {
+		if existing.TokenHash == t.TokenHash {
+			return fmt.Errorf("failed to create refresh token: duplicate token hash")
+		}
+	}
+
+	t.ID = newID()
+	t.CreatedAt = time.Now()
+	r.db.refreshTokens[t.ID] = *t
+	return nil
+}
+
+// FindRefreshTokenForUpdate finds a refresh token by hash. Transactions are
+// already serialized in memory, so no row lock is needed.
+func (r *SessionRepository) FindRefreshTokenForUpdate(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, t := range r.db.refreshTokens {
+		if t.TokenHash == tokenHash {
+			return &t, nil
+		}
+	}
+	return nil, nil // Not found
+}
+
+// MarkRefreshTokenUsed records that a refresh token was exchanged for the next one
+func (r *SessionRepository) MarkRefreshTokenUsed(ctx context.Context, id string, usedAt time.Time) error {
+	defer r.db.lock(ctx)()
+
+	t, ok := r.db.refreshTokens[id]
+	if !ok {
+		return fmt.Errorf("failed to update refresh token: token %s not found", id)
+	}
+
+	t.UsedAt = &usedAt
+	r.db.refreshTokens[id] = t
+	return nil
+}
+
+// RevokeFamily revokes the refresh tokens of a session and their access tokens
+func (r *SessionRepository) RevokeFamily(ctx context.Context, familyID string, now time.Time) error {
+	r.revoke(ctx, func(t models.RefreshToken) bool { return t.FamilyID == familyID }, now)
+	return nil
+}
+
+// RevokeAdmin revokes every session of an admin and their access tokens
+func (r *SessionRepository) RevokeAdmin(ctx context.Context, adminID string, now time.Time) error {
+	r.revoke(ctx, func(t models.RefreshToken) bool { return t.AdminID == adminID }, now)
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether the access token with jti was revoked
+func (r *SessionRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	_, revoked := r.db.revokedTokens[jti]
+	return revoked, nil
+}
+
+// DeleteExpired removes expired refresh tokens and revoked access tokens
+func (r *SessionRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	defer r.db.lock(ctx)()
+
+	deleted := 0
+	for id, t := range r.db.refreshTokens {
+		if t.ExpiresAt.Before(now) {
+			delete(r.db.refreshTokens, id)
+			deleted++
+		}
+	}
+	for jti, expiresAt := range r.db.revokedTokens {
+		if expiresAt.Before(now) {
+			delete(r.db.revokedTokens, jti)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// revoke revokes the refresh tokens that match and the access tokens issued
+// with them that have not expired by now
+func (r *SessionRepository) revoke(ctx context.Context, match func(models.RefreshToken) bool, now time.Time) {
+	defer r.db.lock(ctx)()
+
+	for id, t := range r.db.refreshTokens {
+		if !match(t) || t.RevokedAt != nil {
+			continue
+		}
+
+		t.RevokedAt = &now
+		r.db.refreshTokens[id] = t
+		if t.AccessExpiresAt.After(now) {
+			r.db.revokedTokens[t.AccessJTI] = t.AccessExpiresAt
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// TransferRepository stores registration transfers in memory
+type TransferRepository struct {
+	db *DB
+}
+
+// NewTransferRepository creates a new in-memory registration transfer repository
+func NewTransferRepository(db *DB) *TransferRepository {
+	return &TransferRepository{db: db}
+}
+
+// Create stores a new PENDING transfer
+func (r *TransferRepository) Create(ctx context.Context, t *models.RegistrationTransfer) error {
+	defer r.db.lock(ctx)()
+
+	for _, existing := range r.db.transfers {
+		if existing.ParticipantID == t.ParticipantID && existing.Status == "PENDING" {
+			return fmt.Errorf("failed to create registration transfer: participant %s already has a pending transfer", t.ParticipantID)
+		}
+	}
+
+	now := time.Now()
+	t.ID = newID()
+	t.Status = "PENDING"
+	t.CreatedAt = now
+	t.UpdatedAt = now
+
+	r.db.transfers[t.ID] = *t
+	return nil
+}
+
+// FindByID finds a transfer by ID
+func (r *TransferRepository) FindByID(ctx context.Context, id string) (*models.RegistrationTransfer, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	t, ok := r.db.transfers[id]
+	if !ok {
+		return nil, nil // Not found
+	}
+	return &t, nil
+}
+
+// FindByIDForUpdate finds a transfer by ID. Transactions are already
+// serialized in memory, so no row lock is needed.
+func (r *TransferRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.RegistrationTransfer, error) {
+	return r.FindByID(ctx, id)
+}
+
+// FindPending finds the PENDING transfer of a participant
+func (r *TransferRepository) FindPending(ctx context.Context, participantID string) (*models.RegistrationTransfer, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, t := range r.db.transfers {
+		if t.ParticipantID == participantID && t.Status == "PENDING" {
+			return &t, nil
+		}
+	}
+	return nil, nil // Not found
+}
+
+// FindLatest finds the most recent transfer of a participant
+func (r *TransferRepository) FindLatest(ctx context.Context, participantID string) (*models.RegistrationTransfer, error) {
+	transfers, err := r.ListByParticipant(ctx, participantID)
+	if err != nil || len(transfers) == 0 {
+		return nil, err
+	}
+	return &transfers[0], nil
+}
+
+// ListByParticipant retrieves the transfers of a participant, newest first
+func (r *TransferRepository) ListByParticipant(ctx context.Context, participantID string) ([]models.RegistrationTransfer, error) {
+	return r.list(func(t models.RegistrationTransfer) bool {
+		return t.ParticipantID == participantID
+	}), nil
+}
+
+// ListByEvent retrieves the transfers of an event, optionally only those with a status, newest first
+func (r *TransferRepository) ListByEvent(ctx context.Context, eventID, status string) ([]models.RegistrationTransfer, error) {
+	return r.list(func(t models.RegistrationTransfer) bool {
+		return t.EventID == eventID && (status == "" || t.Status == status)
+	}), nil
+}
+
+// Update saves the status, recipient details and completion and cancellation fields of t
+func (r *TransferRepository) Update(ctx context.Context, t *models.RegistrationTransfer) error {
+	defer r.db.lock(ctx)()
+
+	stored, ok := r.db.transfers[t.ID]
+	if !ok {
+		return fmt.Errorf("failed to update registration transfer: transfer %s not found", t.ID)
+	}
+
+	stored.Status = t.Status
+	stored.ToName = t.ToName
+	stored.ToPhone = t.ToPhone
+	stored.CompletedAt = t.CompletedAt
+	stored.CancelledAt = t.CancelledAt
+	stored.CancelledBy = t.CancelledBy
+	stored.UpdatedAt = time.Now()
+	r.db.transfers[t.ID] = stored
+
+	t.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// list returns the transfers matching keep, newest first
+func (r *TransferRepository) list(keep func(t models.RegistrationTransfer) bool) []models.RegistrationTransfer {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	transfers := []models.RegistrationTransfer{}
+	for _, t := range r.db.transfers {
+		if keep(t) {
+			transfers = append(transfers, t)
+		}
+	}
+
+	sort.Slice(transfers, func(i, j int) bool {
+		if transfers[i].CreatedAt.Equal(transfers[j].CreatedAt) {
+			return transfers[i].ID > transfers[j].ID
+		}
+		return transfers[i].CreatedAt.After(transfers[j].CreatedAt)
+	})
+
+	return transfers
+}
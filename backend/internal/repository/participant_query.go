@@ -0,0 +1,61 @@
+package repository
+
+import "time"
+
+// Sort orders accepted by ParticipantQuery
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// ParticipantSortFields lists the fields participants may be sorted by
+var ParticipantSortFields = []string{
+	"created_at",
+	"updated_at",
+	"name",
+	"email",
+	"payment_status",
+	"registration_status",
+	"bib_number",
+}
+
+// ParticipantFilter narrows down which participants are returned.
+// Zero values mean "no filter".
+type ParticipantFilter struct {
+	EventID            string
+	PaymentStatus      string
+	RegistrationStatus string
+	CategoryID         string
+	CheckedIn          *bool      // Whether the participant checked in on race day
+	CreatedFrom        *time.Time // inclusive
+	CreatedBefore      *time.Time // exclusive
+	// Search matches name, email, phone and instagram_handle case-insensitively
+	Search string
+}
+
+// ParticipantQuery describes a page of filtered, sorted participants
+type ParticipantQuery struct {
+	Filter    ParticipantFilter
+	SortBy    string
+	SortOrder string
+	Page      int
+	Limit     int
+}
+
+// Offset returns the number of rows to skip for the requested page
+func (q ParticipantQuery) Offset() int {
+	if q.Page < 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.Limit
+}
+
+// IsValidParticipantSortField reports whether field is whitelisted for sorting
+func IsValidParticipantSortField(field string) bool {
+	for _, f := range ParticipantSortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+const adminColumns = `id, email, password_hash, role, created_by, disabled_at, created_at`
+
+// AdminRepository stores admins in PostgreSQL
+type AdminRepository struct {
+	db *sql.DB
+}
+
+// NewAdminRepository creates a new PostgreSQL admin repository
+func NewAdminRepository(db *sql.DB) *AdminRepository {
+	return &AdminRepository{db: db}
+}
+
+// Create stores a new admin
+func (r *AdminRepository) Create(ctx context.Context, admin *models.Admin) error {
+	query := `
+		INSERT INTO admins (email, password_hash, role, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, admin.Email, admin.PasswordHash, admin.Role, admin.CreatedBy).
+		Scan(&admin.ID, &admin.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return repository.ErrDuplicateEmail
+		}
+		return fmt.Errorf("failed to create admin: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds an admin by ID
+func (r *AdminRepository) FindByID(ctx context.Context, id string) (*models.Admin, error) {
+	query := `SELECT ` + adminColumns + ` FROM admins WHERE id = $1`
+	return r.findOne(ctx, query, id)
+}
+
+// FindByIDForUpdate finds an admin by ID and locks the row
+func (r *AdminRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.Admin, error) {
+	query := `SELECT ` + adminColumns + ` FROM admins WHERE id = $1 FOR UPDATE`
+	return r.findOne(ctx, query, id)
+}
+
+// FindByEmail finds an admin by email
+func (r *AdminRepository) FindByEmail(ctx context.Context, email string) (*models.Admin, error) {
+	query := `SELECT ` + adminColumns + ` FROM admins WHERE email = $1`
+	return r.findOne(ctx, query, email)
+}
+
+// List retrieves every admin, ordered by email
+func (r *AdminRepository) List(ctx context.Context) ([]models.Admin, error) {
+	query := `SELECT ` + adminColumns + ` FROM admins ORDER BY email`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admins: %w", err)
+	}
+	defer rows.Close()
+
+	admins := []models.Admin{}
+	for rows.Next() {
+		var a models.Admin
+		if err := scanAdmin(rows, &a); err != nil {
+			return nil, fmt.Errorf("failed to scan admin: %w", err)
+		}
+		admins = append(admins, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating admins: %w", err)
+	}
+
+	return admins, nil
+}
+
+// CountActiveByRoleForUpdate returns how many admins that are not disabled
+// have a role and locks their rows. FOR UPDATE cannot be combined with COUNT,
+// so the rows are counted here.
+func (r *AdminRepository) CountActiveByRoleForUpdate(ctx context.Context, role string) (int, error) {
+	query := `SELECT id FROM admins WHERE role = $1 AND disabled_at IS NULL FOR UPDATE`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, role)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count admins: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		count++
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating admins: %w", err)
+	}
+
+	return count, nil
+}
+
+// UpdateRole changes the role of an admin
+func (r *AdminRepository) UpdateRole(ctx context.Context, id, role string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE admins SET role = $1 WHERE id = $2`, role, id)
+	if err != nil {
+		return fmt.Errorf("failed to update admin role: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update admin role: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to update admin role: admin %s not found", id)
+	}
+
+	return nil
+}
+
+// UpdatePassword sets the password hash of an admin
+func (r *AdminRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE admins SET password_hash = $1 WHERE id = $2`, passwordHash, id)
+	if err != nil {
+		return fmt.Errorf("failed to update admin password: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update admin password: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to update admin password: admin %s not found", id)
+	}
+
+	return nil
+}
+
+// SetDisabled disables an admin at disabledAt, or enables them if it is nil
+func (r *AdminRepository) SetDisabled(ctx context.Context, id string, disabledAt *time.Time) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE admins SET disabled_at = $1 WHERE id = $2`, disabledAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update admin: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update admin: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to update admin: admin %s not found", id)
+	}
+
+	return nil
+}
+
+// Delete removes an admin. What they did stays recorded without them, as
+// every reference to admins is set to NULL.
+func (r *AdminRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM admins WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete admin: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete admin: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to delete admin: admin %s not found", id)
+	}
+
+	return nil
+}
+
+// findOne runs a single-row admin query, returning nil if nothing matched
+func (r *AdminRepository) findOne(ctx context.Context, query string, args ...interface{}) (*models.Admin, error) {
+	admin := &models.Admin{}
+	err := scanAdmin(conn(ctx, r.db).QueryRowContext(ctx, query, args...), admin)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	return admin, nil
+}
+
+// scanAdmin scans adminColumns into a
+func scanAdmin(row scanner, a *models.Admin) error {
+	return row.Scan(
+		&a.ID,
+		&a.Email,
+		&a.PasswordHash,
+		&a.Role,
+		&a.CreatedBy,
+		&a.DisabledAt,
+		&a.CreatedAt,
+	)
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+const bibReservationColumns = `id, event_id, category_id, range_start, range_end, label, created_at`
+
+// BibReservationRepository stores bib number reservations in PostgreSQL
+type BibReservationRepository struct {
+	db *sql.DB
+}
+
+// NewBibReservationRepository creates a new PostgreSQL bib reservation repository
+func NewBibReservationRepository(db *sql.DB) *BibReservationRepository {
+	return &BibReservationRepository{db: db}
+}
+
+// Create inserts a new bib reservation
+func (r *BibReservationRepository) Create(ctx context.Context, res *models.BibReservation) error {
+	query := `
+		INSERT INTO bib_reservations (event_id, category_id, range_start, range_end, label)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		res.EventID,
+		res.CategoryID,
+		res.RangeStart,
+		res.RangeEnd,
+		res.Label,
+	).Scan(&res.ID, &res.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create bib reservation: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a bib reservation by ID
+func (r *BibReservationRepository) FindByID(ctx context.Context, id string) (*models.BibReservation, error) {
+	query := `SELECT ` + bibReservationColumns + ` FROM bib_reservations WHERE id = $1`
+
+	res := &models.BibReservation{}
+	err := scanBibReservation(conn(ctx, r.db).QueryRowContext(ctx, query, id), res)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bib reservation: %w", err)
+	}
+
+	return res, nil
+}
+
+// ListByEvent retrieves the reservations of an event, lowest range first
+func (r *BibReservationRepository) ListByEvent(ctx context.Context, eventID string) ([]models.BibReservation, error) {
+	query := `
+		SELECT ` + bibReservationColumns + `
+		FROM bib_reservations
+		WHERE event_id = $1
+		ORDER BY range_start ASC, range_end ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bib reservations: %w", err)
+	}
+	defer rows.Close()
+
+	reservations := []models.BibReservation{}
+	for rows.Next() {
+		var res models.BibReservation
+		if err := scanBibReservation(rows, &res); err != nil {
+			return nil, fmt.Errorf("failed to scan bib reservation: %w", err)
+		}
+		reservations = append(reservations, res)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bib reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// Delete removes a bib reservation
+func (r *BibReservationRepository) Delete(ctx context.Context, id string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM bib_reservations WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete bib reservation: %w", err)
+	}
+	return nil
+}
+
+// scanBibReservation scans bibReservationColumns into res
+func scanBibReservation(row scanner, res *models.BibReservation) error {
+	return row.Scan(
+		&res.ID,
+		&res.EventID,
+		&res.CategoryID,
+		&res.RangeStart,
+		&res.RangeEnd,
+		&res.Label,
+		&res.CreatedAt,
+	)
+}
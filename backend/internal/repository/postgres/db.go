@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+// dbtx is satisfied by both *sql.DB and *sql.Tx
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// conn returns the transaction stored in ctx, or db when there is none
+func conn(ctx context.Context, db *sql.DB) dbtx {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// scanner is satisfied by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Transactor runs functions inside PostgreSQL transactions
+type Transactor struct {
+	db *sql.DB
+}
+
+// NewTransactor creates a new PostgreSQL transactor
+func NewTransactor(db *sql.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+// WithinTx runs fn inside a transaction, committing if it returns nil.
+// Nested calls join the outer transaction.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Compile-time checks that the repositories satisfy their interfaces
+var (
+	_ repository.Transactor               = (*Transactor)(nil)
+	_ repository.EventRepository          = (*EventRepository)(nil)
+	_ repository.ParticipantRepository    = (*ParticipantRepository)(nil)
+	_ repository.AdminRepository          = (*AdminRepository)(nil)
+	_ repository.EmailLogRepository       = (*EmailLogRepository)(nil)
+	_ repository.PaymentRepository        = (*PaymentRepository)(nil)
+	_ repository.PaymentProofRepository   = (*PaymentProofRepository)(nil)
+	_ repository.RaceCategoryRepository   = (*RaceCategoryRepository)(nil)
+	_ repository.BibReservationRepository = (*BibReservationRepository)(nil)
+	_ repository.EmailOutboxRepository    = (*EmailOutboxRepository)(nil)
+	_ repository.EmailTemplateRepository  = (*EmailTemplateRepository)(nil)
+	_ repository.EmailScheduleRepository  = (*EmailScheduleRepository)(nil)
+	_ repository.EmailCampaignRepository  = (*EmailCampaignRepository)(nil)
+	_ repository.TransferRepository       = (*TransferRepository)(nil)
+	_ repository.SessionRepository        = (*SessionRepository)(nil)
+	_ repository.PasswordResetRepository  = (*PasswordResetRepository)(nil)
+)
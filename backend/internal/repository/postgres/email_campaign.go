@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+const emailCampaignColumns = `
+	c.id, c.event_id, c.subject, c.html_body, c.text_body, c.payment_status, c.registration_status,
+	c.category_id, c.checked_in, c.recipients, c.created_by, a.email, c.created_at
+`
+
+// emailCampaignFrom joins the admin who sent each campaign
+const emailCampaignFrom = ` FROM email_campaigns c LEFT JOIN admins a ON a.id = c.created_by`
+
+// EmailCampaignRepository stores email campaigns in PostgreSQL
+type EmailCampaignRepository struct {
+	db *sql.DB
+}
+
+// NewEmailCampaignRepository creates a new PostgreSQL email campaign repository
+func NewEmailCampaignRepository(db *sql.DB) *EmailCampaignRepository {
+	return &EmailCampaignRepository{db: db}
+}
+
+// Create inserts a new campaign
+func (r *EmailCampaignRepository) Create(ctx context.Context, c *models.EmailCampaign) error {
+	query := `
+		WITH c AS (
+			INSERT INTO email_campaigns (
+				event_id, subject, html_body, text_body, payment_status, registration_status,
+				category_id, checked_in, recipients, created_by
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING *
+		)
+		SELECT ` + emailCampaignColumns + ` FROM c LEFT JOIN admins a ON a.id = c.created_by
+	`
+
+	err := scanEmailCampaign(conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		c.EventID,
+		c.Subject,
+		c.HTMLBody,
+		c.TextBody,
+		nullString(c.Segment.PaymentStatus),
+		nullString(c.Segment.RegistrationStatus),
+		nullString(c.Segment.CategoryID),
+		c.Segment.CheckedIn,
+		c.Recipients,
+		c.CreatedBy,
+	), c)
+
+	if err != nil {
+		return fmt.Errorf("failed to create email campaign: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a campaign by ID
+func (r *EmailCampaignRepository) FindByID(ctx context.Context, id string) (*models.EmailCampaign, error) {
+	query := `SELECT ` + emailCampaignColumns + emailCampaignFrom + ` WHERE c.id = $1`
+
+	c := &models.EmailCampaign{}
+	err := scanEmailCampaign(conn(ctx, r.db).QueryRowContext(ctx, query, id), c)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email campaign: %w", err)
+	}
+
+	return c, nil
+}
+
+// ListByEvent retrieves the campaigns of an event, newest first
+func (r *EmailCampaignRepository) ListByEvent(ctx context.Context, eventID string) ([]models.EmailCampaign, error) {
+	query := `SELECT ` + emailCampaignColumns + emailCampaignFrom + `
+		WHERE c.event_id = $1
+		ORDER BY c.created_at DESC, c.id DESC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	campaigns := []models.EmailCampaign{}
+	for rows.Next() {
+		var c models.EmailCampaign
+		if err := scanEmailCampaign(rows, &c); err != nil {
+			return nil, fmt.Errorf("failed to scan email campaign: %w", err)
+		}
+		campaigns = append(campaigns, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating email campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// scanEmailCampaign scans emailCampaignColumns into c
+func scanEmailCampaign(row scanner, c *models.EmailCampaign) error {
+	var paymentStatus, registrationStatus, categoryID sql.NullString
+	err := row.Scan(
+		&c.ID,
+		&c.EventID,
+		&c.Subject,
+		&c.HTMLBody,
+		&c.TextBody,
+		&paymentStatus,
+		&registrationStatus,
+		&categoryID,
+		&c.Segment.CheckedIn,
+		&c.Recipients,
+		&c.CreatedBy,
+		&c.CreatedByEmail,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.Segment.PaymentStatus = paymentStatus.String
+	c.Segment.RegistrationStatus = registrationStatus.String
+	c.Segment.CategoryID = categoryID.String
+	return nil
+}
+
+// nullString stores an empty string as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
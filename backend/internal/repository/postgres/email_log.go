@@ -0,0 +1,250 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+const emailLogColumns = `
+	l.id, l.participant_id, l.recipient_email, l.email_type, l.campaign_id, l.status,
+	l.error_message, l.resend_of, l.resent_by, l.sent_at
+`
+
+// EmailLogRepository stores email logs in PostgreSQL
+type EmailLogRepository struct {
+	db *sql.DB
+}
+
+// NewEmailLogRepository creates a new PostgreSQL email log repository
+func NewEmailLogRepository(db *sql.DB) *EmailLogRepository {
+	return &EmailLogRepository{db: db}
+}
+
+// Create inserts an email log entry
+func (r *EmailLogRepository) Create(ctx context.Context, log *models.EmailLog) error {
+	query := `
+		INSERT INTO email_logs (
+			participant_id, recipient_email, email_type, campaign_id, status, error_message,
+			resend_of, resent_by, sent_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP)
+		RETURNING id, sent_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		log.ParticipantID,
+		log.RecipientEmail,
+		log.EmailType,
+		log.CampaignID,
+		log.Status,
+		log.ErrorMessage,
+		log.ResendOf,
+		log.ResentBy,
+	).Scan(&log.ID, &log.SentAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to log email: %w", err)
+	}
+
+	return nil
+}
+
+// HasSucceeded reports whether an email of emailType was ever sent to a participant
+func (r *EmailLogRepository) HasSucceeded(ctx context.Context, participantID, emailType string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM email_logs
+			WHERE participant_id = $1 AND email_type = $2 AND status = 'SUCCESS'
+		)
+	`
+
+	var sent bool
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, participantID, emailType).Scan(&sent); err != nil {
+		return false, fmt.Errorf("failed to check email logs: %w", err)
+	}
+
+	return sent, nil
+}
+
+// ListByCampaign retrieves one page of the attempts to send a campaign, oldest first
+func (r *EmailLogRepository) ListByCampaign(ctx context.Context, campaignID, status string, page, limit int) ([]models.EmailLog, int, error) {
+	where := ` WHERE campaign_id = $1`
+	args := []interface{}{campaignID}
+	if status != "" {
+		where += ` AND status = $2`
+		args = append(args, status)
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM email_logs` + where
+	if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count email logs: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM email_logs l%s
+		ORDER BY l.sent_at ASC, l.id ASC
+		LIMIT $%d OFFSET $%d
+	`, emailLogColumns, where, len(args)+1, len(args)+2)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get email logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs := []models.EmailLog{}
+	for rows.Next() {
+		var log models.EmailLog
+		if err := scanEmailLog(rows, &log); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan email log: %w", err)
+		}
+		logs = append(logs, log)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating email logs: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// FindByID finds an email log entry by ID
+func (r *EmailLogRepository) FindByID(ctx context.Context, id string) (*models.EmailLog, error) {
+	query := `SELECT ` + emailLogColumns + ` FROM email_logs l WHERE l.id = $1`
+
+	log := &models.EmailLog{}
+	err := scanEmailLog(conn(ctx, r.db).QueryRowContext(ctx, query, id), log)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email log: %w", err)
+	}
+
+	return log, nil
+}
+
+// FindLatest finds the most recent attempt to send a participant an email of emailType
+func (r *EmailLogRepository) FindLatest(ctx context.Context, participantID, emailType string) (*models.EmailLog, error) {
+	query := `SELECT ` + emailLogColumns + ` FROM email_logs l
+		WHERE l.participant_id = $1 AND l.email_type = $2
+		ORDER BY l.sent_at DESC, l.id DESC
+		LIMIT 1
+	`
+
+	log := &models.EmailLog{}
+	err := scanEmailLog(conn(ctx, r.db).QueryRowContext(ctx, query, participantID, emailType), log)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email log: %w", err)
+	}
+
+	return log, nil
+}
+
+// List retrieves one page of the email logs matching f, newest first
+func (r *EmailLogRepository) List(ctx context.Context, f repository.EmailLogFilter, page, limit int) ([]models.EmailLog, int, error) {
+	where, args := emailLogWhere(f)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM email_logs l JOIN participants p ON p.id = l.participant_id` + where
+	if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count email logs: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, p.name, p.event_id
+		FROM email_logs l
+		JOIN participants p ON p.id = l.participant_id%s
+		ORDER BY l.sent_at DESC, l.id DESC
+		LIMIT $%d OFFSET $%d
+	`, emailLogColumns, where, len(args)+1, len(args)+2)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get email logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs := []models.EmailLog{}
+	for rows.Next() {
+		var log models.EmailLog
+		if err := scanEmailLog(rows, &log, &log.ParticipantName, &log.EventID); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan email log: %w", err)
+		}
+		logs = append(logs, log)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating email logs: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// emailLogWhere builds the WHERE clause and arguments for an email log filter
+func emailLogWhere(f repository.EmailLogFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.ParticipantID != "" {
+		add("l.participant_id = $%d", f.ParticipantID)
+	}
+	if f.EventID != "" {
+		add("p.event_id = $%d", f.EventID)
+	}
+	if f.EmailType != "" {
+		add("l.email_type = $%d", f.EmailType)
+	}
+	if f.Status != "" {
+		add("l.status = $%d", f.Status)
+	}
+	if f.SentFrom != nil {
+		add("l.sent_at >= $%d", *f.SentFrom)
+	}
+	if f.SentBefore != nil {
+		add("l.sent_at < $%d", *f.SentBefore)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// scanEmailLog scans emailLogColumns into log, followed by any extra columns
+func scanEmailLog(row scanner, log *models.EmailLog, extra ...interface{}) error {
+	dest := []interface{}{
+		&log.ID,
+		&log.ParticipantID,
+		&log.RecipientEmail,
+		&log.EmailType,
+		&log.CampaignID,
+		&log.Status,
+		&log.ErrorMessage,
+		&log.ResendOf,
+		&log.ResentBy,
+		&log.SentAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+const outboxColumns = `
+	o.id, o.participant_id, o.email_type, o.campaign_id, o.status, o.attempts, o.next_attempt_at,
+	o.last_error, o.sent_at, o.skip_reason, o.created_at, o.updated_at
+`
+
+// EmailOutboxRepository stores the email outbox in PostgreSQL
+type EmailOutboxRepository struct {
+	db *sql.DB
+}
+
+// NewEmailOutboxRepository creates a new PostgreSQL email outbox repository
+func NewEmailOutboxRepository(db *sql.DB) *EmailOutboxRepository {
+	return &EmailOutboxRepository{db: db}
+}
+
+// Create queues a new PENDING email
+func (r *EmailOutboxRepository) Create(ctx context.Context, e *models.OutboxEmail) error {
+	query := `
+		INSERT INTO email_outbox (participant_id, email_type, campaign_id, status, next_attempt_at)
+		VALUES ($1, $2, $3, 'PENDING', COALESCE($4, CURRENT_TIMESTAMP))
+		RETURNING id, next_attempt_at, created_at, updated_at
+	`
+
+	var nextAttemptAt *time.Time
+	if !e.NextAttemptAt.IsZero() {
+		nextAttemptAt = &e.NextAttemptAt
+	}
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, e.ParticipantID, e.EmailType, e.CampaignID, nextAttemptAt).
+		Scan(&e.ID, &e.NextAttemptAt, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to queue email: %w", err)
+	}
+
+	e.Status = "PENDING"
+	e.Attempts = 0
+	return nil
+}
+
+// FindByID finds an outbox email by ID
+func (r *EmailOutboxRepository) FindByID(ctx context.Context, id string) (*models.OutboxEmail, error) {
+	query := `SELECT ` + outboxColumns + ` FROM email_outbox o WHERE o.id = $1`
+	return r.findOne(ctx, query, id)
+}
+
+// FindByIDForUpdate finds an outbox email by ID and locks the row
+func (r *EmailOutboxRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.OutboxEmail, error) {
+	query := `SELECT ` + outboxColumns + ` FROM email_outbox o WHERE o.id = $1 FOR UPDATE`
+	return r.findOne(ctx, query, id)
+}
+
+// Claim marks up to limit due emails SENDING for one worker. SKIP LOCKED lets
+// concurrent workers claim different emails instead of waiting on each other.
+func (r *EmailOutboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEmail, error) {
+	query := `
+		UPDATE email_outbox o
+		SET status = 'SENDING', attempts = o.attempts + 1, next_attempt_at = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE o.id IN (
+			SELECT id FROM email_outbox
+			WHERE status IN ('PENDING', 'SENDING') AND next_attempt_at <= $1
+			ORDER BY next_attempt_at ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxColumns
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox emails: %w", err)
+	}
+	defer rows.Close()
+
+	emails := []models.OutboxEmail{}
+	for rows.Next() {
+		var e models.OutboxEmail
+		if err := scanOutboxEmail(rows, &e); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox email: %w", err)
+		}
+		emails = append(emails, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+// Update saves the delivery state of an outbox email
+func (r *EmailOutboxRepository) Update(ctx context.Context, e *models.OutboxEmail) error {
+	query := `
+		UPDATE email_outbox
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, sent_at = $5,
+			skip_reason = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $7
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		e.Status, e.Attempts, e.NextAttemptAt, e.LastError, e.SentAt, e.SkipReason, e.ID,
+	).Scan(&e.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox email: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves one page of outbox emails with the given status, oldest first
+func (r *EmailOutboxRepository) List(ctx context.Context, status string, page, limit int) ([]models.OutboxEmail, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM email_outbox WHERE status = $1`
+	if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count outbox emails: %w", err)
+	}
+
+	query := `
+		SELECT ` + outboxColumns + `, p.name, p.email
+		FROM email_outbox o
+		JOIN participants p ON p.id = o.participant_id
+		WHERE o.status = $1
+		ORDER BY o.created_at ASC, o.id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get outbox emails: %w", err)
+	}
+	defer rows.Close()
+
+	emails := []models.OutboxEmail{}
+	for rows.Next() {
+		var e models.OutboxEmail
+		if err := rows.Scan(
+			&e.ID,
+			&e.ParticipantID,
+			&e.EmailType,
+			&e.CampaignID,
+			&e.Status,
+			&e.Attempts,
+			&e.NextAttemptAt,
+			&e.LastError,
+			&e.SentAt,
+			&e.SkipReason,
+			&e.CreatedAt,
+			&e.UpdatedAt,
+			&e.ParticipantName,
+			&e.ParticipantEmail,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan outbox email: %w", err)
+		}
+		emails = append(emails, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating outbox emails: %w", err)
+	}
+
+	return emails, total, nil
+}
+
+// CountByCampaign counts the emails of a campaign by delivery status
+func (r *EmailOutboxRepository) CountByCampaign(ctx context.Context, campaignID string) (*models.CampaignDelivery, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE status IN ('PENDING', 'SENDING')),
+			COUNT(*) FILTER (WHERE status = 'SENT'),
+			COUNT(*) FILTER (WHERE status = 'SKIPPED'),
+			COUNT(*) FILTER (WHERE status = 'DEAD')
+		FROM email_outbox
+		WHERE campaign_id = $1
+	`
+
+	delivery := &models.CampaignDelivery{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, campaignID).Scan(&delivery.Pending, &delivery.Sent, &delivery.Skipped, &delivery.Failed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count campaign emails: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// findOne runs a single-row outbox query, returning nil if nothing matched
+func (r *EmailOutboxRepository) findOne(ctx context.Context, query string, args ...interface{}) (*models.OutboxEmail, error) {
+	email := &models.OutboxEmail{}
+	err := scanOutboxEmail(conn(ctx, r.db).QueryRowContext(ctx, query, args...), email)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find outbox email: %w", err)
+	}
+
+	return email, nil
+}
+
+// scanOutboxEmail scans outboxColumns into e
+func scanOutboxEmail(row scanner, e *models.OutboxEmail) error {
+	return row.Scan(
+		&e.ID,
+		&e.ParticipantID,
+		&e.EmailType,
+		&e.CampaignID,
+		&e.Status,
+		&e.Attempts,
+		&e.NextAttemptAt,
+		&e.LastError,
+		&e.SentAt,
+		&e.SkipReason,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+}
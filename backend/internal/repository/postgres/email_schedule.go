@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+const emailScheduleColumns = `event_id, email_type, enabled, days, send_time, updated_by, updated_at`
+
+// EmailScheduleRepository stores email schedules in PostgreSQL
+type EmailScheduleRepository struct {
+	db *sql.DB
+}
+
+// NewEmailScheduleRepository creates a new PostgreSQL email schedule repository
+func NewEmailScheduleRepository(db *sql.DB) *EmailScheduleRepository {
+	return &EmailScheduleRepository{db: db}
+}
+
+// ListByEvent retrieves the saved schedules of an event
+func (r *EmailScheduleRepository) ListByEvent(ctx context.Context, eventID string) ([]models.EmailSchedule, error) {
+	query := `SELECT ` + emailScheduleColumns + ` FROM email_schedules WHERE event_id = $1 ORDER BY email_type`
+	return r.list(ctx, query, eventID)
+}
+
+// ListEnabled retrieves the enabled schedules of every event
+func (r *EmailScheduleRepository) ListEnabled(ctx context.Context) ([]models.EmailSchedule, error) {
+	query := `SELECT ` + emailScheduleColumns + ` FROM email_schedules WHERE enabled ORDER BY event_id, email_type`
+	return r.list(ctx, query)
+}
+
+// Save inserts a schedule or replaces the existing one of its event and email type
+func (r *EmailScheduleRepository) Save(ctx context.Context, s *models.EmailSchedule) error {
+	query := `
+		INSERT INTO email_schedules (event_id, email_type, enabled, days, send_time, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)
+		ON CONFLICT (event_id, email_type) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			days = EXCLUDED.days,
+			send_time = EXCLUDED.send_time,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		s.EventID,
+		s.EmailType,
+		s.Enabled,
+		s.Days,
+		s.SendTime,
+		s.UpdatedBy,
+	).Scan(&s.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save email schedule: %w", err)
+	}
+
+	s.IsDefault = false
+	return nil
+}
+
+// list runs a multi-row schedule query
+func (r *EmailScheduleRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.EmailSchedule, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []models.EmailSchedule{}
+	for rows.Next() {
+		var s models.EmailSchedule
+		if err := rows.Scan(&s.EventID, &s.EmailType, &s.Enabled, &s.Days, &s.SendTime, &s.UpdatedBy, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan email schedule: %w", err)
+		}
+		schedules = append(schedules, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating email schedules: %w", err)
+	}
+
+	return schedules, nil
+}
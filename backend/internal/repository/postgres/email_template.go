@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+const emailTemplateColumns = `
+	t.id, t.event_id, t.email_type, t.version, t.subject, t.html_body, t.text_body,
+	t.restored_from, t.created_by, a.email, t.created_at
+`
+
+// emailTemplateFrom joins the admin who saved each version
+const emailTemplateFrom = ` FROM email_templates t LEFT JOIN admins a ON a.id = t.created_by`
+
+// EmailTemplateRepository stores email template versions in PostgreSQL
+type EmailTemplateRepository struct {
+	db *sql.DB
+}
+
+// NewEmailTemplateRepository creates a new PostgreSQL email template repository
+func NewEmailTemplateRepository(db *sql.DB) *EmailTemplateRepository {
+	return &EmailTemplateRepository{db: db}
+}
+
+// Create inserts t as the next version of its event and email type. Concurrent
+// saves of the same template fail on the unique version constraint, so callers
+// should lock the event first.
+func (r *EmailTemplateRepository) Create(ctx context.Context, t *models.EmailTemplate) error {
+	query := `
+		WITH t AS (
+			INSERT INTO email_templates (event_id, email_type, version, subject, html_body, text_body, restored_from, created_by)
+			SELECT $1::uuid, $2::varchar, COALESCE(MAX(version), 0) + 1, $3::varchar, $4::text, $5::text, $6::integer, $7::uuid
+			FROM email_templates
+			WHERE event_id = $1::uuid AND email_type = $2::varchar
+			RETURNING *
+		)
+		SELECT ` + emailTemplateColumns + ` FROM t LEFT JOIN admins a ON a.id = t.created_by
+	`
+
+	err := scanEmailTemplate(conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		t.EventID,
+		t.EmailType,
+		t.Subject,
+		t.HTMLBody,
+		t.TextBody,
+		t.RestoredFrom,
+		t.CreatedBy,
+	), t)
+
+	if err != nil {
+		return fmt.Errorf("failed to create email template: %w", err)
+	}
+
+	t.IsDefault = false
+	return nil
+}
+
+// FindLatest finds the highest version of a template
+func (r *EmailTemplateRepository) FindLatest(ctx context.Context, eventID, emailType string) (*models.EmailTemplate, error) {
+	query := `SELECT ` + emailTemplateColumns + emailTemplateFrom + `
+		WHERE t.event_id = $1 AND t.email_type = $2
+		ORDER BY t.version DESC
+		LIMIT 1
+	`
+	return r.findOne(ctx, query, eventID, emailType)
+}
+
+// FindVersion finds one version of a template
+func (r *EmailTemplateRepository) FindVersion(ctx context.Context, eventID, emailType string, version int) (*models.EmailTemplate, error) {
+	query := `SELECT ` + emailTemplateColumns + emailTemplateFrom + `
+		WHERE t.event_id = $1 AND t.email_type = $2 AND t.version = $3
+	`
+	return r.findOne(ctx, query, eventID, emailType, version)
+}
+
+// ListVersions retrieves every version of a template, newest first
+func (r *EmailTemplateRepository) ListVersions(ctx context.Context, eventID, emailType string) ([]models.EmailTemplate, error) {
+	query := `SELECT ` + emailTemplateColumns + emailTemplateFrom + `
+		WHERE t.event_id = $1 AND t.email_type = $2
+		ORDER BY t.version DESC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, eventID, emailType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email templates: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []models.EmailTemplate{}
+	for rows.Next() {
+		var t models.EmailTemplate
+		if err := scanEmailTemplate(rows, &t); err != nil {
+			return nil, fmt.Errorf("failed to scan email template: %w", err)
+		}
+		versions = append(versions, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating email templates: %w", err)
+	}
+
+	return versions, nil
+}
+
+// findOne runs a query returning at most one template
+func (r *EmailTemplateRepository) findOne(ctx context.Context, query string, args ...interface{}) (*models.EmailTemplate, error) {
+	t := &models.EmailTemplate{}
+	err := scanEmailTemplate(conn(ctx, r.db).QueryRowContext(ctx, query, args...), t)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email template: %w", err)
+	}
+
+	return t, nil
+}
+
+// scanEmailTemplate scans emailTemplateColumns into t
+func scanEmailTemplate(row scanner, t *models.EmailTemplate) error {
+	return row.Scan(
+		&t.ID,
+		&t.EventID,
+		&t.EmailType,
+		&t.Version,
+		&t.Subject,
+		&t.HTMLBody,
+		&t.TextBody,
+		&t.RestoredFrom,
+		&t.CreatedBy,
+		&t.CreatedByEmail,
+		&t.CreatedAt,
+	)
+}
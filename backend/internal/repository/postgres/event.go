@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+const eventColumns = `
+	e.id, e.name, to_char(e.event_date, 'YYYY-MM-DD'), e.location, e.description,
+	e.registration_open, e.capacity, e.created_at, e.updated_at,
+	to_char(e.refund_full_until, 'YYYY-MM-DD'), e.refund_partial_percent, e.refund_cutoff_days,
+	to_char(e.transfer_deadline, 'YYYY-MM-DD')
+`
+
+// eventRegistered counts the participants holding a spot in event e
+const eventRegistered = `(SELECT COUNT(*) FROM participants p WHERE p.event_id = e.id AND p.` + holdsSpot + `)`
+
+// EventRepository stores events in PostgreSQL
+type EventRepository struct {
+	db *sql.DB
+}
+
+// NewEventRepository creates a new PostgreSQL event repository
+func NewEventRepository(db *sql.DB) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+// Create inserts a new event
+func (r *EventRepository) Create(ctx context.Context, e *models.Event) error {
+	query := `
+		INSERT INTO events (name, event_date, location, description, registration_open, capacity,
+			refund_full_until, refund_partial_percent, refund_cutoff_days, transfer_deadline)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		e.Name,
+		e.EventDate,
+		e.Location,
+		e.Description,
+		e.RegistrationOpen,
+		e.Capacity,
+		e.RefundFullUntil,
+		e.RefundPartialPercent,
+		e.RefundCutoffDays,
+		e.TransferDeadline,
+	).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+
+	e.Registered = 0
+	return nil
+}
+
+// FindByID finds an event by ID
+func (r *EventRepository) FindByID(ctx context.Context, id string) (*models.Event, error) {
+	query := `SELECT ` + eventColumns + `, ` + eventRegistered + ` FROM events e WHERE e.id = $1`
+
+	event := &models.Event{}
+	err := scanEvent(conn(ctx, r.db).QueryRowContext(ctx, query, id), event, &event.Registered)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	return event, nil
+}
+
+// FindByIDForUpdate finds an event by ID and locks the row. Participants are
+// counted in a separate statement after the lock is acquired, so the count
+// includes registrations committed while waiting for it.
+func (r *EventRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events e WHERE e.id = $1 FOR UPDATE`
+
+	event := &models.Event{}
+	err := scanEvent(conn(ctx, r.db).QueryRowContext(ctx, query, id), event)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	countQuery := `SELECT COUNT(*) FROM participants WHERE event_id = $1 AND ` + holdsSpot
+	if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, id).Scan(&event.Registered); err != nil {
+		return nil, fmt.Errorf("failed to count event participants: %w", err)
+	}
+
+	return event, nil
+}
+
+// List retrieves all events, soonest event date first
+func (r *EventRepository) List(ctx context.Context) ([]models.Event, error) {
+	query := `SELECT ` + eventColumns + `, ` + eventRegistered + ` FROM events e ORDER BY e.event_date ASC, e.created_at ASC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.Event{}
+	for rows.Next() {
+		var e models.Event
+		if err := scanEvent(rows, &e, &e.Registered); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return events, nil
+}
+
+// Update saves the editable fields of an event
+func (r *EventRepository) Update(ctx context.Context, e *models.Event) error {
+	query := `
+		UPDATE events
+		SET name = $1, event_date = $2, location = $3, description = $4,
+		    registration_open = $5, capacity = $6, refund_full_until = $7,
+		    refund_partial_percent = $8, refund_cutoff_days = $9, transfer_deadline = $10,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $11
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		e.Name,
+		e.EventDate,
+		e.Location,
+		e.Description,
+		e.RegistrationOpen,
+		e.Capacity,
+		e.RefundFullUntil,
+		e.RefundPartialPercent,
+		e.RefundCutoffDays,
+		e.TransferDeadline,
+		e.ID,
+	).Scan(&e.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an event without participants
+func (r *EventRepository) Delete(ctx context.Context, id string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM events WHERE id = $1`, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return repository.ErrEventInUse
+		}
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+
+	return nil
+}
+
+// scanEvent scans eventColumns into e, followed by any extra columns
+func scanEvent(row scanner, e *models.Event, extra ...interface{}) error {
+	dest := []interface{}{
+		&e.ID,
+		&e.Name,
+		&e.EventDate,
+		&e.Location,
+		&e.Description,
+		&e.RegistrationOpen,
+		&e.Capacity,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+		&e.RefundFullUntil,
+		&e.RefundPartialPercent,
+		&e.RefundCutoffDays,
+		&e.TransferDeadline,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
@@ -0,0 +1,522 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+const participantColumns = `
+	id, event_id, category_id, name, email, phone, instagram_handle, address,
+	to_char(date_of_birth, 'YYYY-MM-DD'), registration_status, payment_status,
+	waitlist_position, offer_expires_at, bib_number, kit_collected_at, kit_collected_by,
+	checked_in_at, checked_in_by, cancelled_at, cancelled_by, cancellation_reason,
+	refund_amount, refund_currency, refunded_at, refunded_by, check_in_version, created_at, updated_at
+`
+
+// holdsSpot matches participants counted against event and race category capacity
+const holdsSpot = `registration_status IN ('PENDING', 'CONFIRMED')`
+
+// ParticipantRepository stores participants in PostgreSQL
+type ParticipantRepository struct {
+	db *sql.DB
+}
+
+// NewParticipantRepository creates a new PostgreSQL participant repository
+func NewParticipantRepository(db *sql.DB) *ParticipantRepository {
+	return &ParticipantRepository{db: db}
+}
+
+// Create creates a new participant in the database. The registration status
+// defaults to PENDING.
+func (r *ParticipantRepository) Create(ctx context.Context, p *models.Participant) error {
+	if p.RegistrationStatus == "" {
+		p.RegistrationStatus = "PENDING"
+	}
+
+	query := `
+		INSERT INTO participants (event_id, category_id, name, email, phone, instagram_handle, address, date_of_birth,
+			registration_status, payment_status, waitlist_position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 'UNPAID', $10)
+		RETURNING id, check_in_version, created_at, updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		p.EventID,
+		p.CategoryID,
+		p.Name,
+		p.Email,
+		p.Phone,
+		p.InstagramHandle,
+		p.Address,
+		p.DateOfBirth,
+		p.RegistrationStatus,
+		p.WaitlistPosition,
+	).Scan(&p.ID, &p.CheckInVersion, &p.CreatedAt, &p.UpdatedAt)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return repository.ErrDuplicateEmail
+		}
+		return fmt.Errorf("failed to create participant: %w", err)
+	}
+
+	p.PaymentStatus = "UNPAID"
+
+	return nil
+}
+
+// FindByID finds a participant by ID
+func (r *ParticipantRepository) FindByID(ctx context.Context, id string) (*models.Participant, error) {
+	query := `SELECT ` + participantColumns + ` FROM participants WHERE id = $1`
+	return r.findOne(ctx, query, id)
+}
+
+// FindByIDForUpdate finds a participant by ID and locks the row
+func (r *ParticipantRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.Participant, error) {
+	query := `SELECT ` + participantColumns + ` FROM participants WHERE id = $1 FOR UPDATE`
+	return r.findOne(ctx, query, id)
+}
+
+// FindByEventAndEmail finds a participant by event and email
+func (r *ParticipantRepository) FindByEventAndEmail(ctx context.Context, eventID, email string) (*models.Participant, error) {
+	query := `SELECT ` + participantColumns + ` FROM participants WHERE event_id = $1 AND email = $2`
+	return r.findOne(ctx, query, eventID, email)
+}
+
+// ListByEmail retrieves the registrations of email for every event, newest first
+func (r *ParticipantRepository) ListByEmail(ctx context.Context, email string) ([]models.Participant, error) {
+	query := `SELECT ` + participantColumns + ` FROM participants WHERE email = $1 ORDER BY created_at DESC`
+	return r.list(ctx, query, email)
+}
+
+// List retrieves one page of participants matching the query
+func (r *ParticipantRepository) List(ctx context.Context, q repository.ParticipantQuery) ([]models.Participant, int, error) {
+	where, args := participantWhere(q.Filter)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM participants` + where
+	if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count participants: %w", err)
+	}
+
+	query := `SELECT ` + participantColumns + ` FROM participants` + where + participantOrderBy(q) +
+		fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	args = append(args, q.Limit, q.Offset())
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get participants: %w", err)
+	}
+	defer rows.Close()
+
+	participants := []models.Participant{}
+	for rows.Next() {
+		var p models.Participant
+		if err := scanParticipant(rows, &p); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan participant: %w", err)
+		}
+		participants = append(participants, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating participants: %w", err)
+	}
+
+	return participants, total, nil
+}
+
+// Stream iterates over all participants matching the query one row at a time
+func (r *ParticipantRepository) Stream(ctx context.Context, q repository.ParticipantQuery, fn func(p *models.Participant) error) error {
+	where, args := participantWhere(q.Filter)
+	query := `SELECT ` + participantColumns + ` FROM participants` + where + participantOrderBy(q)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get participants: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.Participant
+		if err := scanParticipant(rows, &p); err != nil {
+			return fmt.Errorf("failed to scan participant: %w", err)
+		}
+		if err := fn(&p); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating participants: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePaymentStatus updates the payment status of a participant
+func (r *ParticipantRepository) UpdatePaymentStatus(ctx context.Context, p *models.Participant, status string) error {
+	query := `
+		UPDATE participants
+		SET payment_status = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, status, p.ID).Scan(&p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update payment status: %w", err)
+	}
+
+	p.PaymentStatus = status
+	return nil
+}
+
+// UpdateRegistration saves the registration status, waitlist position and offer deadline
+func (r *ParticipantRepository) UpdateRegistration(ctx context.Context, p *models.Participant) error {
+	query := `
+		UPDATE participants
+		SET registration_status = $1, waitlist_position = $2, offer_expires_at = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, p.RegistrationStatus, p.WaitlistPosition, p.OfferExpiresAt, p.ID).Scan(&p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update registration: %w", err)
+	}
+
+	return nil
+}
+
+// ListWaitlist retrieves the waitlisted participants of an event in queue order
+func (r *ParticipantRepository) ListWaitlist(ctx context.Context, eventID string) ([]models.Participant, error) {
+	query := `
+		SELECT ` + participantColumns + `
+		FROM participants
+		WHERE event_id = $1 AND registration_status = 'WAITLISTED'
+		ORDER BY category_id NULLS FIRST, waitlist_position ASC, created_at ASC
+	`
+	return r.list(ctx, query, eventID)
+}
+
+// ListExpiredOffers retrieves unpaid participants whose spot offer expired before t
+func (r *ParticipantRepository) ListExpiredOffers(ctx context.Context, t time.Time) ([]models.Participant, error) {
+	query := `
+		SELECT ` + participantColumns + `
+		FROM participants
+		WHERE registration_status = 'PENDING' AND payment_status = 'UNPAID' AND offer_expires_at < $1
+		ORDER BY offer_expires_at ASC
+	`
+	return r.list(ctx, query, t)
+}
+
+// UpdateBibNumber saves the bib number of a participant
+func (r *ParticipantRepository) UpdateBibNumber(ctx context.Context, p *models.Participant) error {
+	query := `
+		UPDATE participants
+		SET bib_number = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, p.BibNumber, p.ID).Scan(&p.UpdatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return repository.ErrDuplicateBibNumber
+		}
+		return fmt.Errorf("failed to update bib number: %w", err)
+	}
+
+	return nil
+}
+
+// ListBibNumbers retrieves the bib numbers taken in a race category of an event, lowest first
+func (r *ParticipantRepository) ListBibNumbers(ctx context.Context, eventID string, categoryID *string) ([]int, error) {
+	query := `
+		SELECT bib_number
+		FROM participants
+		WHERE event_id = $1 AND category_id IS NOT DISTINCT FROM $2 AND bib_number IS NOT NULL
+		ORDER BY bib_number ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, eventID, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bib numbers: %w", err)
+	}
+	defer rows.Close()
+
+	numbers := []int{}
+	for rows.Next() {
+		var n int
+		if err := rows.Scan(&n); err != nil {
+			return nil, fmt.Errorf("failed to scan bib number: %w", err)
+		}
+		numbers = append(numbers, n)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bib numbers: %w", err)
+	}
+
+	return numbers, nil
+}
+
+// UpdateCheckIn saves the race-kit pickup and race-day check-in of a participant
+func (r *ParticipantRepository) UpdateCheckIn(ctx context.Context, p *models.Participant) error {
+	query := `
+		UPDATE participants
+		SET kit_collected_at = $1, kit_collected_by = $2, checked_in_at = $3, checked_in_by = $4,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		p.KitCollectedAt, p.KitCollectedBy, p.CheckedInAt, p.CheckedInBy, p.ID,
+	).Scan(&p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update check-in: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCancellation saves the cancellation and refund of a participant
+func (r *ParticipantRepository) UpdateCancellation(ctx context.Context, p *models.Participant) error {
+	query := `
+		UPDATE participants
+		SET cancelled_at = $1, cancelled_by = $2, cancellation_reason = $3, refund_amount = $4,
+			refund_currency = $5, refunded_at = $6, refunded_by = $7, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $8
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		p.CancelledAt, p.CancelledBy, p.CancellationReason, p.RefundAmount,
+		p.RefundCurrency, p.RefundedAt, p.RefundedBy, p.ID,
+	).Scan(&p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update cancellation: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateContact updates the phone, address and instagram_handle of a participant
+func (r *ParticipantRepository) UpdateContact(ctx context.Context, p *models.Participant) error {
+	query := `
+		UPDATE participants
+		SET phone = $1, address = $2, instagram_handle = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		p.Phone, p.Address, p.InstagramHandle, p.ID,
+	).Scan(&p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update contact details: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateOwner saves who holds a registration: the name, email, contact
+// details, date of birth and check-in version of p
+func (r *ParticipantRepository) UpdateOwner(ctx context.Context, p *models.Participant) error {
+	query := `
+		UPDATE participants
+		SET name = $1, email = $2, phone = $3, address = $4, instagram_handle = $5, date_of_birth = $6,
+		    check_in_version = $7, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $8
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		p.Name, p.Email, p.Phone, p.Address, p.InstagramHandle, p.DateOfBirth, p.CheckInVersion, p.ID,
+	).Scan(&p.UpdatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return repository.ErrDuplicateEmail
+		}
+		return fmt.Errorf("failed to update participant owner: %w", err)
+	}
+
+	return nil
+}
+
+// ListWithoutEmail retrieves the participants matching f who have neither
+// been queued nor successfully sent an email of emailType, oldest first
+func (r *ParticipantRepository) ListWithoutEmail(ctx context.Context, f repository.ParticipantFilter, emailType string) ([]models.Participant, error) {
+	where, args := participantWhere(f)
+	args = append(args, emailType)
+
+	condition := fmt.Sprintf(`
+		NOT EXISTS (SELECT 1 FROM email_outbox o WHERE o.participant_id = participants.id AND o.email_type = $%[1]d)
+		AND NOT EXISTS (SELECT 1 FROM email_logs l WHERE l.participant_id = participants.id AND l.email_type = $%[1]d AND l.status = 'SUCCESS')`,
+		len(args))
+	if where == "" {
+		where = " WHERE " + condition
+	} else {
+		where += " AND " + condition
+	}
+
+	query := `SELECT ` + participantColumns + ` FROM participants` + where + ` ORDER BY created_at ASC`
+	return r.list(ctx, query, args...)
+}
+
+// list runs a multi-row participant query
+func (r *ParticipantRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.Participant, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+	defer rows.Close()
+
+	participants := []models.Participant{}
+	for rows.Next() {
+		var p models.Participant
+		if err := scanParticipant(rows, &p); err != nil {
+			return nil, fmt.Errorf("failed to scan participant: %w", err)
+		}
+		participants = append(participants, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating participants: %w", err)
+	}
+
+	return participants, nil
+}
+
+// findOne runs a single-row participant query, returning nil if nothing matched
+func (r *ParticipantRepository) findOne(ctx context.Context, query string, args ...interface{}) (*models.Participant, error) {
+	participant := &models.Participant{}
+	err := scanParticipant(conn(ctx, r.db).QueryRowContext(ctx, query, args...), participant)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find participant: %w", err)
+	}
+
+	return participant, nil
+}
+
+// scanParticipant scans participantColumns into p
+func scanParticipant(row scanner, p *models.Participant) error {
+	return row.Scan(
+		&p.ID,
+		&p.EventID,
+		&p.CategoryID,
+		&p.Name,
+		&p.Email,
+		&p.Phone,
+		&p.InstagramHandle,
+		&p.Address,
+		&p.DateOfBirth,
+		&p.RegistrationStatus,
+		&p.PaymentStatus,
+		&p.WaitlistPosition,
+		&p.OfferExpiresAt,
+		&p.BibNumber,
+		&p.KitCollectedAt,
+		&p.KitCollectedBy,
+		&p.CheckedInAt,
+		&p.CheckedInBy,
+		&p.CancelledAt,
+		&p.CancelledBy,
+		&p.CancellationReason,
+		&p.RefundAmount,
+		&p.RefundCurrency,
+		&p.RefundedAt,
+		&p.RefundedBy,
+		&p.CheckInVersion,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+}
+
+// participantWhere builds the WHERE clause and arguments for a filter
+func participantWhere(f repository.ParticipantFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.EventID != "" {
+		add("event_id = $%d", f.EventID)
+	}
+	if f.PaymentStatus != "" {
+		add("payment_status = $%d", f.PaymentStatus)
+	}
+	if f.RegistrationStatus != "" {
+		add("registration_status = $%d", f.RegistrationStatus)
+	}
+	if f.CategoryID != "" {
+		add("category_id = $%d", f.CategoryID)
+	}
+	if f.CheckedIn != nil {
+		if *f.CheckedIn {
+			conditions = append(conditions, "checked_in_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "checked_in_at IS NULL")
+		}
+	}
+	if f.CreatedFrom != nil {
+		add("created_at >= $%d", *f.CreatedFrom)
+	}
+	if f.CreatedBefore != nil {
+		add("created_at < $%d", *f.CreatedBefore)
+	}
+	if f.Search != "" {
+		args = append(args, "%"+escapeLike(f.Search)+"%")
+		n := len(args)
+		conditions = append(conditions, fmt.Sprintf(
+			"(name ILIKE $%[1]d OR email ILIKE $%[1]d OR phone ILIKE $%[1]d OR instagram_handle ILIKE $%[1]d)", n))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// participantOrderBy builds the ORDER BY clause from whitelisted sort fields
+func participantOrderBy(q repository.ParticipantQuery) string {
+	column := "created_at"
+	if repository.IsValidParticipantSortField(q.SortBy) {
+		column = q.SortBy
+	}
+
+	order := "DESC"
+	if q.SortOrder == repository.SortAsc {
+		order = "ASC"
+	}
+
+	// id breaks ties so pages stay stable between requests
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", column, order, order)
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+const passwordResetColumns = `id, admin_id, token_hash, expires_at, used_at, created_at`
+
+// PasswordResetRepository stores admin password reset tokens in PostgreSQL
+type PasswordResetRepository struct {
+	db *sql.DB
+}
+
+// NewPasswordResetRepository creates a new PostgreSQL password reset repository
+func NewPasswordResetRepository(db *sql.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// Create inserts a new reset token
+func (r *PasswordResetRepository) Create(ctx context.Context, reset *models.PasswordReset) error {
+	query := `
+		INSERT INTO admin_password_resets (admin_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING ` + passwordResetColumns
+
+	err := scanPasswordReset(conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		reset.AdminID,
+		reset.TokenHash,
+		reset.ExpiresAt,
+	), reset)
+
+	if err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+
+	return nil
+}
+
+// FindByTokenHashForUpdate finds a reset token by hash and locks the row
+func (r *PasswordResetRepository) FindByTokenHashForUpdate(ctx context.Context, tokenHash string) (*models.PasswordReset, error) {
+	query := `SELECT ` + passwordResetColumns + ` FROM admin_password_resets WHERE token_hash = $1 FOR UPDATE`
+
+	reset := &models.PasswordReset{}
+	err := scanPasswordReset(conn(ctx, r.db).QueryRowContext(ctx, query, tokenHash), reset)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find password reset: %w", err)
+	}
+
+	return reset, nil
+}
+
+// InvalidateByAdmin marks every unused reset token of an admin used
+func (r *PasswordResetRepository) InvalidateByAdmin(ctx context.Context, adminID string, usedAt time.Time) error {
+	query := `UPDATE admin_password_resets SET used_at = $1 WHERE admin_id = $2 AND used_at IS NULL`
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, usedAt, adminID); err != nil {
+		return fmt.Errorf("failed to invalidate password resets: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes expired reset tokens
+func (r *PasswordResetRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM admin_password_resets WHERE expires_at < $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired password resets: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired password resets: %w", err)
+	}
+	return int(rows), nil
+}
+
+// scanPasswordReset scans passwordResetColumns into reset
+func scanPasswordReset(row scanner, reset *models.PasswordReset) error {
+	return row.Scan(
+		&reset.ID,
+		&reset.AdminID,
+		&reset.TokenHash,
+		&reset.ExpiresAt,
+		&reset.UsedAt,
+		&reset.CreatedAt,
+	)
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+const paymentColumns = `
+	id, participant_id, provider, reference, provider_reference, amount, currency,
+	status, checkout_url, expires_at, paid_at, created_at, updated_at
+`
+
+// PaymentRepository stores payments in PostgreSQL
+type PaymentRepository struct {
+	db *sql.DB
+}
+
+// NewPaymentRepository creates a new PostgreSQL payment repository
+func NewPaymentRepository(db *sql.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+// Create inserts a new payment
+func (r *PaymentRepository) Create(ctx context.Context, p *models.Payment) error {
+	query := `
+		INSERT INTO payments (participant_id, provider, reference, provider_reference, amount, currency, status, checkout_url, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		p.ParticipantID,
+		p.Provider,
+		p.Reference,
+		p.ProviderReference,
+		p.Amount,
+		p.Currency,
+		p.Status,
+		p.CheckoutURL,
+		p.ExpiresAt,
+	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	return nil
+}
+
+// FindByReferenceForUpdate finds a payment by our reference and locks the row
+func (r *PaymentRepository) FindByReferenceForUpdate(ctx context.Context, reference string) (*models.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE reference = $1 FOR UPDATE`
+	return r.findOne(ctx, query, reference)
+}
+
+// FindPendingByParticipant finds the newest pending payment of a participant
+func (r *PaymentRepository) FindPendingByParticipant(ctx context.Context, participantID string) (*models.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + ` FROM payments
+		WHERE participant_id = $1 AND status = 'PENDING'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	return r.findOne(ctx, query, participantID)
+}
+
+// FindPaidByParticipant finds the newest paid payment of a participant
+func (r *PaymentRepository) FindPaidByParticipant(ctx context.Context, participantID string) (*models.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + ` FROM payments
+		WHERE participant_id = $1 AND status = 'PAID'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	return r.findOne(ctx, query, participantID)
+}
+
+// UpdateStatus updates the status of a payment, recording when it was paid
+func (r *PaymentRepository) UpdateStatus(ctx context.Context, p *models.Payment, status string) error {
+	query := `
+		UPDATE payments
+		SET status = $1,
+		    paid_at = CASE WHEN $1 = 'PAID' THEN COALESCE(paid_at, CURRENT_TIMESTAMP) ELSE paid_at END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+		RETURNING paid_at, updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, status, p.ID).Scan(&p.PaidAt, &p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update payment status: %w", err)
+	}
+
+	p.Status = status
+	return nil
+}
+
+// findOne runs a single-row payment query, returning nil if nothing matched
+func (r *PaymentRepository) findOne(ctx context.Context, query string, args ...interface{}) (*models.Payment, error) {
+	payment := &models.Payment{}
+	err := scanPayment(conn(ctx, r.db).QueryRowContext(ctx, query, args...), payment)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+// scanPayment scans paymentColumns into p
+func scanPayment(row scanner, p *models.Payment) error {
+	return row.Scan(
+		&p.ID,
+		&p.ParticipantID,
+		&p.Provider,
+		&p.Reference,
+		&p.ProviderReference,
+		&p.Amount,
+		&p.Currency,
+		&p.Status,
+		&p.CheckoutURL,
+		&p.ExpiresAt,
+		&p.PaidAt,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+const paymentProofColumns = `
+	pp.id, pp.participant_id, pp.storage_key, pp.filename, pp.content_type, pp.size,
+	pp.status, pp.review_note, pp.reviewed_by, pp.reviewed_at, pp.created_at
+`
+
+// PaymentProofRepository stores payment proofs in PostgreSQL
+type PaymentProofRepository struct {
+	db *sql.DB
+}
+
+// NewPaymentProofRepository creates a new PostgreSQL payment proof repository
+func NewPaymentProofRepository(db *sql.DB) *PaymentProofRepository {
+	return &PaymentProofRepository{db: db}
+}
+
+// Create inserts a new pending payment proof
+func (r *PaymentProofRepository) Create(ctx context.Context, p *models.PaymentProof) error {
+	query := `
+		INSERT INTO payment_proofs (participant_id, storage_key, filename, content_type, size, status)
+		VALUES ($1, $2, $3, $4, $5, 'PENDING')
+		RETURNING id, created_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		p.ParticipantID,
+		p.StorageKey,
+		p.Filename,
+		p.ContentType,
+		p.Size,
+	).Scan(&p.ID, &p.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create payment proof: %w", err)
+	}
+
+	p.Status = "PENDING"
+	return nil
+}
+
+// FindByID finds a payment proof by ID
+func (r *PaymentProofRepository) FindByID(ctx context.Context, id string) (*models.PaymentProof, error) {
+	query := `SELECT ` + paymentProofColumns + ` FROM payment_proofs pp WHERE pp.id = $1`
+	return r.findOne(ctx, query, id)
+}
+
+// FindByIDForUpdate finds a payment proof by ID and locks the row
+func (r *PaymentProofRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.PaymentProof, error) {
+	query := `SELECT ` + paymentProofColumns + ` FROM payment_proofs pp WHERE pp.id = $1 FOR UPDATE`
+	return r.findOne(ctx, query, id)
+}
+
+// List retrieves one page of proofs with the given status, oldest first
+func (r *PaymentProofRepository) List(ctx context.Context, status string, page, limit int) ([]models.PaymentProof, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM payment_proofs WHERE status = $1`
+	if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count payment proofs: %w", err)
+	}
+
+	query := `
+		SELECT ` + paymentProofColumns + `, p.name, p.email
+		FROM payment_proofs pp
+		JOIN participants p ON p.id = pp.participant_id
+		WHERE pp.status = $1
+		ORDER BY pp.created_at ASC, pp.id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get payment proofs: %w", err)
+	}
+	defer rows.Close()
+
+	proofs := []models.PaymentProof{}
+	for rows.Next() {
+		var p models.PaymentProof
+		if err := rows.Scan(
+			&p.ID,
+			&p.ParticipantID,
+			&p.StorageKey,
+			&p.Filename,
+			&p.ContentType,
+			&p.Size,
+			&p.Status,
+			&p.ReviewNote,
+			&p.ReviewedBy,
+			&p.ReviewedAt,
+			&p.CreatedAt,
+			&p.ParticipantName,
+			&p.ParticipantEmail,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan payment proof: %w", err)
+		}
+		proofs = append(proofs, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating payment proofs: %w", err)
+	}
+
+	return proofs, total, nil
+}
+
+// UpdateReview records an admin's review decision
+func (r *PaymentProofRepository) UpdateReview(ctx context.Context, p *models.PaymentProof, status, reviewedBy string, note *string) error {
+	query := `
+		UPDATE payment_proofs
+		SET status = $1, reviewed_by = $2, review_note = $3, reviewed_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING reviewed_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, status, reviewedBy, note, p.ID).Scan(&p.ReviewedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update payment proof: %w", err)
+	}
+
+	p.Status = status
+	p.ReviewedBy = &reviewedBy
+	p.ReviewNote = note
+	return nil
+}
+
+// findOne runs a single-row payment proof query, returning nil if nothing matched
+func (r *PaymentProofRepository) findOne(ctx context.Context, query string, args ...interface{}) (*models.PaymentProof, error) {
+	proof := &models.PaymentProof{}
+	err := scanPaymentProof(conn(ctx, r.db).QueryRowContext(ctx, query, args...), proof)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payment proof: %w", err)
+	}
+
+	return proof, nil
+}
+
+// scanPaymentProof scans paymentProofColumns into p
+func scanPaymentProof(row scanner, p *models.PaymentProof) error {
+	return row.Scan(
+		&p.ID,
+		&p.ParticipantID,
+		&p.StorageKey,
+		&p.Filename,
+		&p.ContentType,
+		&p.Size,
+		&p.Status,
+		&p.ReviewNote,
+		&p.ReviewedBy,
+		&p.ReviewedAt,
+		&p.CreatedAt,
+	)
+}
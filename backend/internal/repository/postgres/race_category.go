@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+const raceCategoryColumns = `
+	c.id, c.event_id, c.name, c.distance_km, c.price, c.currency, c.capacity,
+	c.min_age, c.max_age, c.created_at, c.updated_at
+`
+
+// raceCategoryRegistered counts the participants holding a spot in category c
+const raceCategoryRegistered = `(SELECT COUNT(*) FROM participants p WHERE p.category_id = c.id AND p.` + holdsSpot + `)`
+
+// RaceCategoryRepository stores race categories in PostgreSQL
+type RaceCategoryRepository struct {
+	db *sql.DB
+}
+
+// NewRaceCategoryRepository creates a new PostgreSQL race category repository
+func NewRaceCategoryRepository(db *sql.DB) *RaceCategoryRepository {
+	return &RaceCategoryRepository{db: db}
+}
+
+// Create inserts a new race category
+func (r *RaceCategoryRepository) Create(ctx context.Context, c *models.RaceCategory) error {
+	query := `
+		INSERT INTO race_categories (event_id, name, distance_km, price, currency, capacity, min_age, max_age)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		c.EventID,
+		c.Name,
+		c.DistanceKM,
+		c.Price,
+		c.Currency,
+		c.Capacity,
+		c.MinAge,
+		c.MaxAge,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return repository.ErrDuplicateCategory
+		}
+		return fmt.Errorf("failed to create race category: %w", err)
+	}
+
+	c.Registered = 0
+	return nil
+}
+
+// FindByID finds a race category by ID
+func (r *RaceCategoryRepository) FindByID(ctx context.Context, id string) (*models.RaceCategory, error) {
+	query := `SELECT ` + raceCategoryColumns + `, ` + raceCategoryRegistered + ` FROM race_categories c WHERE c.id = $1`
+
+	category := &models.RaceCategory{}
+	err := scanRaceCategory(conn(ctx, r.db).QueryRowContext(ctx, query, id), category, &category.Registered)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find race category: %w", err)
+	}
+
+	return category, nil
+}
+
+// FindByIDForUpdate finds a race category by ID and locks the row. Participants
+// are counted in a separate statement after the lock is acquired, so the count
+// includes registrations committed while waiting for it.
+func (r *RaceCategoryRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.RaceCategory, error) {
+	query := `SELECT ` + raceCategoryColumns + ` FROM race_categories c WHERE c.id = $1 FOR UPDATE`
+
+	category := &models.RaceCategory{}
+	err := scanRaceCategory(conn(ctx, r.db).QueryRowContext(ctx, query, id), category)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find race category: %w", err)
+	}
+
+	countQuery := `SELECT COUNT(*) FROM participants WHERE category_id = $1 AND ` + holdsSpot
+	if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, id).Scan(&category.Registered); err != nil {
+		return nil, fmt.Errorf("failed to count race category participants: %w", err)
+	}
+
+	return category, nil
+}
+
+// ListByEvent retrieves the categories of an event, shortest distance first
+func (r *RaceCategoryRepository) ListByEvent(ctx context.Context, eventID string) ([]models.RaceCategory, error) {
+	query := `
+		SELECT ` + raceCategoryColumns + `, ` + raceCategoryRegistered + `
+		FROM race_categories c
+		WHERE c.event_id = $1
+		ORDER BY c.distance_km ASC, c.name ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get race categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := []models.RaceCategory{}
+	for rows.Next() {
+		var c models.RaceCategory
+		if err := scanRaceCategory(rows, &c, &c.Registered); err != nil {
+			return nil, fmt.Errorf("failed to scan race category: %w", err)
+		}
+		categories = append(categories, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating race categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// Update saves the editable fields of a race category
+func (r *RaceCategoryRepository) Update(ctx context.Context, c *models.RaceCategory) error {
+	query := `
+		UPDATE race_categories
+		SET name = $1, distance_km = $2, price = $3, currency = $4, capacity = $5,
+		    min_age = $6, max_age = $7, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $8
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		c.Name,
+		c.DistanceKM,
+		c.Price,
+		c.Currency,
+		c.Capacity,
+		c.MinAge,
+		c.MaxAge,
+		c.ID,
+	).Scan(&c.UpdatedAt)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return repository.ErrDuplicateCategory
+		}
+		return fmt.Errorf("failed to update race category: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a race category without participants
+func (r *RaceCategoryRepository) Delete(ctx context.Context, id string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM race_categories WHERE id = $1`, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return repository.ErrCategoryInUse
+		}
+		return fmt.Errorf("failed to delete race category: %w", err)
+	}
+
+	return nil
+}
+
+// scanRaceCategory scans raceCategoryColumns into c, followed by any extra columns
+func scanRaceCategory(row scanner, c *models.RaceCategory, extra ...interface{}) error {
+	dest := []interface{}{
+		&c.ID,
+		&c.EventID,
+		&c.Name,
+		&c.DistanceKM,
+		&c.Price,
+		&c.Currency,
+		&c.Capacity,
+		&c.MinAge,
+		&c.MaxAge,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
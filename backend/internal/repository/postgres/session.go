@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+const refreshTokenColumns = `
+	id, admin_id, family_id, token_hash, access_jti, access_expires_at, expires_at, used_at, revoked_at, created_at
+`
+
+// revokeAccessTokens adds the access tokens of the refresh tokens revoked by
+// a statement to revoked_access_tokens. The statement is a CTE named revoked
+// returning access_jti and access_expires_at.
+const revokeAccessTokens = `
+	INSERT INTO revoked_access_tokens (jti, expires_at)
+	SELECT access_jti, access_expires_at FROM revoked WHERE access_expires_at > $2
+	ON CONFLICT (jti) DO NOTHING
+`
+
+// SessionRepository stores admin sessions in PostgreSQL
+type SessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository creates a new PostgreSQL admin session repository
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// CreateRefreshToken inserts a new refresh token
+func (r *SessionRepository) CreateRefreshToken(ctx context.Context, t *models.RefreshToken) error {
+	query := `
+		INSERT INTO admin_refresh_tokens (admin_id, family_id, token_hash, access_jti, access_expires_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + refreshTokenColumns
+
+	err := scanRefreshToken(conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		t.AdminID,
+		t.FamilyID,
+		t.TokenHash,
+		t.AccessJTI,
+		t.AccessExpiresAt,
+		t.ExpiresAt,
+	), t)
+
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// FindRefreshTokenForUpdate finds a refresh token by hash and locks the row
+func (r *SessionRepository) FindRefreshTokenForUpdate(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM admin_refresh_tokens WHERE token_hash = $1 FOR UPDATE`
+
+	t := &models.RefreshToken{}
+	err := scanRefreshToken(conn(ctx, r.db).QueryRowContext(ctx, query, tokenHash), t)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+
+	return t, nil
+}
+
+// MarkRefreshTokenUsed records that a refresh token was exchanged for the next one
+func (r *SessionRepository) MarkRefreshTokenUsed(ctx context.Context, id string, usedAt time.Time) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE admin_refresh_tokens SET used_at = $1 WHERE id = $2`, usedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeFamily revokes the refresh tokens of a session and their access tokens
+func (r *SessionRepository) RevokeFamily(ctx context.Context, familyID string, now time.Time) error {
+	query := `
+		WITH revoked AS (
+			UPDATE admin_refresh_tokens SET revoked_at = $2
+			WHERE family_id = $1 AND revoked_at IS NULL
+			RETURNING access_jti, access_expires_at
+		)` + revokeAccessTokens
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, familyID, now); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAdmin revokes every session of an admin and their access tokens
+func (r *SessionRepository) RevokeAdmin(ctx context.Context, adminID string, now time.Time) error {
+	query := `
+		WITH revoked AS (
+			UPDATE admin_refresh_tokens SET revoked_at = $2
+			WHERE admin_id = $1 AND revoked_at IS NULL
+			RETURNING access_jti, access_expires_at
+		)` + revokeAccessTokens
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, adminID, now); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether the access token with jti was revoked
+func (r *SessionRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var revoked bool
+	query := `SELECT EXISTS (SELECT 1 FROM revoked_access_tokens WHERE jti = $1)`
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, jti).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+	return revoked, nil
+}
+
+// DeleteExpired removes expired refresh tokens and revoked access tokens
+func (r *SessionRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	deleted := 0
+	for _, query := range []string{
+		`DELETE FROM admin_refresh_tokens WHERE expires_at < $1`,
+		`DELETE FROM revoked_access_tokens WHERE expires_at < $1`,
+	} {
+		result, err := conn(ctx, r.db).ExecContext(ctx, query, now)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete expired tokens: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete expired tokens: %w", err)
+		}
+		deleted += int(rows)
+	}
+	return deleted, nil
+}
+
+// scanRefreshToken scans refreshTokenColumns into t
+func scanRefreshToken(row scanner, t *models.RefreshToken) error {
+	return row.Scan(
+		&t.ID,
+		&t.AdminID,
+		&t.FamilyID,
+		&t.TokenHash,
+		&t.AccessJTI,
+		&t.AccessExpiresAt,
+		&t.ExpiresAt,
+		&t.UsedAt,
+		&t.RevokedAt,
+		&t.CreatedAt,
+	)
+}
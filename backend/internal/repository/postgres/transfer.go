@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+const transferColumns = `
+	id, participant_id, event_id, status, from_name, from_email, from_phone, to_name, to_email, to_phone,
+	initiated_by, expires_at, completed_at, cancelled_at, cancelled_by, created_at, updated_at
+`
+
+// TransferRepository stores registration transfers in PostgreSQL
+type TransferRepository struct {
+	db *sql.DB
+}
+
+// NewTransferRepository creates a new PostgreSQL registration transfer repository
+func NewTransferRepository(db *sql.DB) *TransferRepository {
+	return &TransferRepository{db: db}
+}
+
+// Create inserts a new PENDING transfer
+func (r *TransferRepository) Create(ctx context.Context, t *models.RegistrationTransfer) error {
+	query := `
+		INSERT INTO registration_transfers (participant_id, event_id, from_name, from_email, from_phone,
+			to_name, to_email, initiated_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + transferColumns
+
+	err := scanTransfer(conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		t.ParticipantID,
+		t.EventID,
+		t.FromName,
+		t.FromEmail,
+		t.FromPhone,
+		t.ToName,
+		t.ToEmail,
+		t.InitiatedBy,
+		t.ExpiresAt,
+	), t)
+
+	if err != nil {
+		return fmt.Errorf("failed to create registration transfer: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a transfer by ID
+func (r *TransferRepository) FindByID(ctx context.Context, id string) (*models.RegistrationTransfer, error) {
+	return r.findOne(ctx, `SELECT `+transferColumns+` FROM registration_transfers WHERE id = $1`, id)
+}
+
+// FindByIDForUpdate finds a transfer by ID and locks the row
+func (r *TransferRepository) FindByIDForUpdate(ctx context.Context, id string) (*models.RegistrationTransfer, error) {
+	return r.findOne(ctx, `SELECT `+transferColumns+` FROM registration_transfers WHERE id = $1 FOR UPDATE`, id)
+}
+
+// FindPending finds the PENDING transfer of a participant
+func (r *TransferRepository) FindPending(ctx context.Context, participantID string) (*models.RegistrationTransfer, error) {
+	query := `SELECT ` + transferColumns + ` FROM registration_transfers WHERE participant_id = $1 AND status = 'PENDING'`
+	return r.findOne(ctx, query, participantID)
+}
+
+// FindLatest finds the most recent transfer of a participant
+func (r *TransferRepository) FindLatest(ctx context.Context, participantID string) (*models.RegistrationTransfer, error) {
+	query := `SELECT ` + transferColumns + ` FROM registration_transfers
+		WHERE participant_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+	return r.findOne(ctx, query, participantID)
+}
+
+// ListByParticipant retrieves the transfers of a participant, newest first
+func (r *TransferRepository) ListByParticipant(ctx context.Context, participantID string) ([]models.RegistrationTransfer, error) {
+	query := `SELECT ` + transferColumns + ` FROM registration_transfers
+		WHERE participant_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	return r.list(ctx, query, participantID)
+}
+
+// ListByEvent retrieves the transfers of an event, optionally only those with a status, newest first
+func (r *TransferRepository) ListByEvent(ctx context.Context, eventID, status string) ([]models.RegistrationTransfer, error) {
+	where := ` WHERE event_id = $1`
+	args := []interface{}{eventID}
+	if status != "" {
+		where += ` AND status = $2`
+		args = append(args, status)
+	}
+
+	query := `SELECT ` + transferColumns + ` FROM registration_transfers` + where + ` ORDER BY created_at DESC, id DESC`
+	return r.list(ctx, query, args...)
+}
+
+// Update saves the status, recipient details and completion and cancellation fields of t
+func (r *TransferRepository) Update(ctx context.Context, t *models.RegistrationTransfer) error {
+	query := `
+		UPDATE registration_transfers
+		SET status = $1, to_name = $2, to_phone = $3, completed_at = $4, cancelled_at = $5, cancelled_by = $6,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $7
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		t.Status, t.ToName, t.ToPhone, t.CompletedAt, t.CancelledAt, t.CancelledBy, t.ID,
+	).Scan(&t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update registration transfer: %w", err)
+	}
+
+	return nil
+}
+
+// findOne runs a query for a single transfer, returning nil if there is none
+func (r *TransferRepository) findOne(ctx context.Context, query string, args ...interface{}) (*models.RegistrationTransfer, error) {
+	t := &models.RegistrationTransfer{}
+	err := scanTransfer(conn(ctx, r.db).QueryRowContext(ctx, query, args...), t)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find registration transfer: %w", err)
+	}
+
+	return t, nil
+}
+
+// list runs a query for transfers
+func (r *TransferRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.RegistrationTransfer, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registration transfers: %w", err)
+	}
+	defer rows.Close()
+
+	transfers := []models.RegistrationTransfer{}
+	for rows.Next() {
+		var t models.RegistrationTransfer
+		if err := scanTransfer(rows, &t); err != nil {
+			return nil, fmt.Errorf("failed to scan registration transfer: %w", err)
+		}
+		transfers = append(transfers, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating registration transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// scanTransfer scans transferColumns into t
+func scanTransfer(row scanner, t *models.RegistrationTransfer) error {
+	return row.Scan(
+		&t.ID,
+		&t.ParticipantID,
+		&t.EventID,
+		&t.Status,
+		&t.FromName,
+		&t.FromEmail,
+		&t.FromPhone,
+		&t.ToName,
+		&t.ToEmail,
+		&t.ToPhone,
+		&t.InitiatedBy,
+		&t.ExpiresAt,
+		&t.CompletedAt,
+		&t.CancelledAt,
+		&t.CancelledBy,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+}
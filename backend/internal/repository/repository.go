@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// ErrDuplicateEmail is returned when a unique email constraint is violated
+var ErrDuplicateEmail = errors.New("email already exists")
+
+// ErrEventInUse is returned when deleting an event that still has participants
+var ErrEventInUse = errors.New("event has participants")
+
+// ErrCategoryInUse is returned when deleting a race category that still has participants
+var ErrCategoryInUse = errors.New("race category has participants")
+
+// ErrDuplicateCategory is returned when an event already has a category with the same name
+var ErrDuplicateCategory = errors.New("race category already exists")
+
+// ErrDuplicateBibNumber is returned when a bib number is already taken in its race category
+var ErrDuplicateBibNumber = errors.New("bib number already taken")
+
+// Transactor runs a function inside a single database transaction.
+// Repositories called with the context passed to fn share that transaction.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ParticipantRepository persists participants
+type ParticipantRepository interface {
+	Create(ctx context.Context, p *models.Participant) error
+	FindByID(ctx context.Context, id string) (*models.Participant, error)
+	// FindByIDForUpdate locks the participant row until the surrounding transaction ends
+	FindByIDForUpdate(ctx context.Context, id string) (*models.Participant, error)
+	// FindByEventAndEmail finds the registration of email for an event
+	FindByEventAndEmail(ctx context.Context, eventID, email string) (*models.Participant, error)
+	// ListByEmail returns the registrations of email for every event, newest first
+	ListByEmail(ctx context.Context, email string) ([]models.Participant, error)
+	// List returns one page of participants matching q and the total number of matches
+	List(ctx context.Context, q ParticipantQuery) ([]models.Participant, int, error)
+	// Stream calls fn for every participant matching q.Filter in q's sort order,
+	// ignoring pagination. Iteration stops at the first error returned by fn.
+	Stream(ctx context.Context, q ParticipantQuery, fn func(p *models.Participant) error) error
+	UpdatePaymentStatus(ctx context.Context, p *models.Participant, status string) error
+	// UpdateRegistration saves the registration status, waitlist position and offer deadline of p
+	UpdateRegistration(ctx context.Context, p *models.Participant) error
+	// ListWaitlist returns the WAITLISTED participants of an event ordered by
+	// race category and waitlist position
+	ListWaitlist(ctx context.Context, eventID string) ([]models.Participant, error)
+	// ListExpiredOffers returns unpaid PENDING participants whose spot offer expired before t
+	ListExpiredOffers(ctx context.Context, t time.Time) ([]models.Participant, error)
+	// UpdateBibNumber saves the bib number of p, returning ErrDuplicateBibNumber if it is taken
+	UpdateBibNumber(ctx context.Context, p *models.Participant) error
+	// ListBibNumbers returns the bib numbers taken in a race category of an event,
+	// or among its participants without one when categoryID is nil, lowest first
+	ListBibNumbers(ctx context.Context, eventID string, categoryID *string) ([]int, error)
+	// UpdateCheckIn saves the race-kit pickup and race-day check-in of p
+	UpdateCheckIn(ctx context.Context, p *models.Participant) error
+	// UpdateCancellation saves the cancellation and refund fields of p
+	UpdateCancellation(ctx context.Context, p *models.Participant) error
+	// UpdateContact saves the phone, address and instagram_handle of p
+	UpdateContact(ctx context.Context, p *models.Participant) error
+	// UpdateOwner saves the name, email, contact details, date of birth and
+	// check-in version of p, returning ErrDuplicateEmail if the email is taken
+	// for its event
+	UpdateOwner(ctx context.Context, p *models.Participant) error
+	// ListWithoutEmail returns the participants matching f who have neither
+	// been queued nor successfully sent an email of emailType, oldest first
+	ListWithoutEmail(ctx context.Context, f ParticipantFilter, emailType string) ([]models.Participant, error)
+}
+
+// EventRepository persists events. Returned events include the number of
+// participants holding a spot in them.
+type EventRepository interface {
+	Create(ctx context.Context, e *models.Event) error
+	FindByID(ctx context.Context, id string) (*models.Event, error)
+	// FindByIDForUpdate locks the event row until the surrounding transaction
+	// ends, serializing registrations that compete for its capacity
+	FindByIDForUpdate(ctx context.Context, id string) (*models.Event, error)
+	// List returns all events, soonest event date first
+	List(ctx context.Context) ([]models.Event, error)
+	Update(ctx context.Context, e *models.Event) error
+	// Delete removes an event, returning ErrEventInUse if anyone registered for it
+	Delete(ctx context.Context, id string) error
+}
+
+// RaceCategoryRepository persists race categories. Returned categories include
+// the number of participants registered in them.
+type RaceCategoryRepository interface {
+	Create(ctx context.Context, c *models.RaceCategory) error
+	FindByID(ctx context.Context, id string) (*models.RaceCategory, error)
+	// FindByIDForUpdate locks the category row until the surrounding transaction
+	// ends, serializing registrations that compete for its capacity
+	FindByIDForUpdate(ctx context.Context, id string) (*models.RaceCategory, error)
+	// ListByEvent returns the categories of an event, shortest distance first
+	ListByEvent(ctx context.Context, eventID string) ([]models.RaceCategory, error)
+	Update(ctx context.Context, c *models.RaceCategory) error
+	// Delete removes a category, returning ErrCategoryInUse if anyone registered for it
+	Delete(ctx context.Context, id string) error
+}
+
+// BibReservationRepository persists bib number ranges held back from automatic assignment
+type BibReservationRepository interface {
+	Create(ctx context.Context, r *models.BibReservation) error
+	FindByID(ctx context.Context, id string) (*models.BibReservation, error)
+	// ListByEvent returns the reservations of an event, lowest range first
+	ListByEvent(ctx context.Context, eventID string) ([]models.BibReservation, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// PaymentRepository persists online payment attempts
+type PaymentRepository interface {
+	Create(ctx context.Context, p *models.Payment) error
+	// FindByReferenceForUpdate locks the payment row until the surrounding transaction ends
+	FindByReferenceForUpdate(ctx context.Context, reference string) (*models.Payment, error)
+	// FindPendingByParticipant returns the newest PENDING payment of a participant
+	FindPendingByParticipant(ctx context.Context, participantID string) (*models.Payment, error)
+	// FindPaidByParticipant returns the newest PAID payment of a participant
+	FindPaidByParticipant(ctx context.Context, participantID string) (*models.Payment, error)
+	UpdateStatus(ctx context.Context, p *models.Payment, status string) error
+}
+
+// PaymentProofRepository persists uploaded proofs of payment
+type PaymentProofRepository interface {
+	Create(ctx context.Context, p *models.PaymentProof) error
+	FindByID(ctx context.Context, id string) (*models.PaymentProof, error)
+	// FindByIDForUpdate locks the proof row until the surrounding transaction ends
+	FindByIDForUpdate(ctx context.Context, id string) (*models.PaymentProof, error)
+	// List returns one page of proofs with the given status, oldest first,
+	// including participant name and email, and the total number of matches
+	List(ctx context.Context, status string, page, limit int) ([]models.PaymentProof, int, error)
+	UpdateReview(ctx context.Context, p *models.PaymentProof, status, reviewedBy string, note *string) error
+}
+
+// AdminRepository persists admin accounts
+type AdminRepository interface {
+	// Create stores a new admin, returning ErrDuplicateEmail if the email is taken
+	Create(ctx context.Context, admin *models.Admin) error
+	FindByID(ctx context.Context, id string) (*models.Admin, error)
+	// FindByIDForUpdate locks the admin row until the surrounding transaction ends
+	FindByIDForUpdate(ctx context.Context, id string) (*models.Admin, error)
+	FindByEmail(ctx context.Context, email string) (*models.Admin, error)
+	// List returns every admin, ordered by email
+	List(ctx context.Context) ([]models.Admin, error)
+	// CountActiveByRoleForUpdate returns how many admins that are not disabled
+	// have a role, locking their rows until the surrounding transaction ends
+	CountActiveByRoleForUpdate(ctx context.Context, role string) (int, error)
+	UpdateRole(ctx context.Context, id, role string) error
+	UpdatePassword(ctx context.Context, id, passwordHash string) error
+	// SetDisabled disables an admin at disabledAt, or enables them if it is nil
+	SetDisabled(ctx context.Context, id string, disabledAt *time.Time) error
+	Delete(ctx context.Context, id string) error
+}
+
+// EmailOutboxRepository persists emails waiting to be delivered
+type EmailOutboxRepository interface {
+	// Create queues a PENDING email, due at e.NextAttemptAt or now if it is zero
+	Create(ctx context.Context, e *models.OutboxEmail) error
+	FindByID(ctx context.Context, id string) (*models.OutboxEmail, error)
+	// FindByIDForUpdate locks the outbox row until the surrounding transaction ends
+	FindByIDForUpdate(ctx context.Context, id string) (*models.OutboxEmail, error)
+	// Claim marks up to limit due emails SENDING, counts the attempt and hides
+	// them from other workers until lease has passed. Due emails are PENDING,
+	// or SENDING with an expired lease, and have next_attempt_at before now.
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEmail, error)
+	// Update saves the status, attempts, next attempt, last error and sent time of e
+	Update(ctx context.Context, e *models.OutboxEmail) error
+	// List returns one page of emails with the given status, oldest first, and
+	// the total number of them
+	List(ctx context.Context, status string, page, limit int) ([]models.OutboxEmail, int, error)
+	// CountByCampaign counts the emails of a campaign by delivery status
+	CountByCampaign(ctx context.Context, campaignID string) (*models.CampaignDelivery, error)
+}
+
+// EmailCampaignRepository persists email campaigns. Returned campaigns include
+// the email of the admin who sent them.
+type EmailCampaignRepository interface {
+	Create(ctx context.Context, c *models.EmailCampaign) error
+	FindByID(ctx context.Context, id string) (*models.EmailCampaign, error)
+	// ListByEvent returns the campaigns of an event, newest first
+	ListByEvent(ctx context.Context, eventID string) ([]models.EmailCampaign, error)
+}
+
+// EmailTemplateRepository persists the versions of event email templates.
+// Returned templates include the email of the admin who saved them.
+type EmailTemplateRepository interface {
+	// Create stores t as the version after the latest one of its event and
+	// email type, setting t.Version
+	Create(ctx context.Context, t *models.EmailTemplate) error
+	// FindLatest returns the highest version, or nil if the event has none
+	FindLatest(ctx context.Context, eventID, emailType string) (*models.EmailTemplate, error)
+	FindVersion(ctx context.Context, eventID, emailType string, version int) (*models.EmailTemplate, error)
+	// ListVersions returns every version, newest first
+	ListVersions(ctx context.Context, eventID, emailType string) ([]models.EmailTemplate, error)
+}
+
+// EmailScheduleRepository persists when events send scheduled emails
+type EmailScheduleRepository interface {
+	// ListByEvent returns the saved schedules of an event
+	ListByEvent(ctx context.Context, eventID string) ([]models.EmailSchedule, error)
+	// ListEnabled returns the enabled schedules of every event
+	ListEnabled(ctx context.Context) ([]models.EmailSchedule, error)
+	// Save creates or replaces the schedule of s's event and email type, setting s.UpdatedAt
+	Save(ctx context.Context, s *models.EmailSchedule) error
+}
+
+// EmailLogRepository persists email sending attempts
+type EmailLogRepository interface {
+	Create(ctx context.Context, log *models.EmailLog) error
+	// HasSucceeded reports whether an email of emailType was ever sent to a participant
+	HasSucceeded(ctx context.Context, participantID, emailType string) (bool, error)
+	// ListByCampaign returns one page of the attempts to send a campaign,
+	// optionally only those with a status, oldest first, and the total number of them
+	ListByCampaign(ctx context.Context, campaignID, status string, page, limit int) ([]models.EmailLog, int, error)
+	FindByID(ctx context.Context, id string) (*models.EmailLog, error)
+	// FindLatest returns the most recent attempt to send a participant an email of emailType
+	FindLatest(ctx context.Context, participantID, emailType string) (*models.EmailLog, error)
+	// List returns one page of the attempts matching f, newest first, with the
+	// names of their participants, and the total number of matches
+	List(ctx context.Context, f EmailLogFilter, page, limit int) ([]models.EmailLog, int, error)
+}
+
+// TransferRepository persists registration transfers
+type TransferRepository interface {
+	Create(ctx context.Context, t *models.RegistrationTransfer) error
+	FindByID(ctx context.Context, id string) (*models.RegistrationTransfer, error)
+	// FindByIDForUpdate locks the transfer row until the surrounding transaction ends
+	FindByIDForUpdate(ctx context.Context, id string) (*models.RegistrationTransfer, error)
+	// FindPending returns the PENDING transfer of a participant, or nil if there is none
+	FindPending(ctx context.Context, participantID string) (*models.RegistrationTransfer, error)
+	// FindLatest returns the most recent transfer of a participant, or nil if there is none
+	FindLatest(ctx context.Context, participantID string) (*models.RegistrationTransfer, error)
+	// ListByParticipant returns the transfers of a participant, newest first
+	ListByParticipant(ctx context.Context, participantID string) ([]models.RegistrationTransfer, error)
+	// ListByEvent returns the transfers of an event, optionally only those
+	// with a status, newest first
+	ListByEvent(ctx context.Context, eventID, status string) ([]models.RegistrationTransfer, error)
+	// Update saves the status, recipient details and completion and
+	// cancellation fields of t
+	Update(ctx context.Context, t *models.RegistrationTransfer) error
+}
+
+// SessionRepository persists the refresh tokens of admin sessions and the
+// access tokens revoked before they expire
+type SessionRepository interface {
+	CreateRefreshToken(ctx context.Context, t *models.RefreshToken) error
+	// FindRefreshTokenForUpdate finds a refresh token by the hash of its value,
+	// locking it until the surrounding transaction ends
+	FindRefreshTokenForUpdate(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id string, usedAt time.Time) error
+	// RevokeFamily revokes the refresh tokens of a session and the access
+	// tokens issued with them that have not expired by now
+	RevokeFamily(ctx context.Context, familyID string, now time.Time) error
+	// RevokeAdmin revokes every session of an admin like RevokeFamily
+	RevokeAdmin(ctx context.Context, adminID string, now time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// DeleteExpired removes refresh tokens and revoked access tokens that
+	// expired before now, returning how many were removed
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// PasswordResetRepository persists the password reset tokens emailed to admins
+type PasswordResetRepository interface {
+	Create(ctx context.Context, r *models.PasswordReset) error
+	// FindByTokenHashForUpdate finds a reset token by the hash of its value,
+	// locking it until the surrounding transaction ends
+	FindByTokenHashForUpdate(ctx context.Context, tokenHash string) (*models.PasswordReset, error)
+	// InvalidateByAdmin marks every unused reset token of an admin used at usedAt
+	InvalidateByAdmin(ctx context.Context, adminID string, usedAt time.Time) error
+	// DeleteExpired removes reset tokens that expired before now, returning
+	// how many were removed
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// Errors returned by AdminService
+var (
+	ErrAdminNotFound = errors.New("admin not found")
+	ErrAdminDisabled = errors.New("admin is disabled")
+	ErrInvalidRole   = errors.New("invalid admin role")
+	ErrLastOwner     = errors.New("the last active owner cannot be given another role, disabled or deleted")
+	ErrOwnAccount    = errors.New("admins cannot disable or delete their own account")
+)
+
+// WeakPasswordError is returned when a new admin password does not meet the
+// password requirements
+type WeakPasswordError struct {
+	Reason string
+}
+
+func (e *WeakPasswordError) Error() string {
+	return e.Reason
+}
+
+// AdminService manages admin accounts and their roles.
+//
+// An admin's role is carried in their token, but every request checks the
+// admin behind it as stored now, so a new role, disabling or deleting an
+// admin takes effect from their next request.
+type AdminService struct {
+	auth     *AuthService
+	admins   repository.AdminRepository
+	sessions repository.SessionRepository
+	tx       repository.Transactor
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(
+	auth *AuthService,
+	admins repository.AdminRepository,
+	sessions repository.SessionRepository,
+	tx repository.Transactor,
+) *AdminService {
+	return &AdminService{
+		auth:     auth,
+		admins:   admins,
+		sessions: sessions,
+		tx:       tx,
+	}
+}
+
+// Create adds an admin with an initial password, to be passed on to them
+// privately. createdBy is the inviting admin, or empty when
+// the admin is created from the command line. Returns
+// repository.ErrDuplicateEmail if the email already belongs to an admin.
+func (s *AdminService) Create(ctx context.Context, email, password, role, createdBy string) (*models.Admin, error) {
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if err := s.auth.ValidatePassword(password); err != nil {
+		return nil, &WeakPasswordError{Reason: err.Error()}
+	}
+
+	hash, err := s.auth.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	admin := &models.Admin{
+		Email:        email,
+		PasswordHash: hash,
+		Role:         role,
+	}
+	if createdBy != "" {
+		admin.CreatedBy = &createdBy
+	}
+	if err := s.admins.Create(ctx, admin); err != nil {
+		return nil, err
+	}
+
+	utils.AuthLogger.Info("Admin %s created with role %s", admin.Email, admin.Role)
+	return admin, nil
+}
+
+// Active returns an admin that exists and is not disabled, for checking the
+// admin behind a token on every request
+func (s *AdminService) Active(ctx context.Context, adminID string) (*models.Admin, error) {
+	admin, err := s.admins.FindByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin == nil {
+		return nil, ErrAdminNotFound
+	}
+	if admin.DisabledAt != nil {
+		return nil, ErrAdminDisabled
+	}
+	return admin, nil
+}
+
+// List returns every admin, ordered by email
+func (s *AdminService) List(ctx context.Context) ([]models.Admin, error) {
+	return s.admins.List(ctx)
+}
+
+// UpdateRole gives an admin another role. There is always at least one owner
+// left to assign roles.
+func (s *AdminService) UpdateRole(ctx context.Context, adminID, role string) (*models.Admin, error) {
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+
+	var admin *models.Admin
+	var previous string
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		admin, err = s.lockAdmin(ctx, adminID, role != RoleOwner)
+		if err != nil {
+			return err
+		}
+		if admin.Role == role {
+			return nil
+		}
+
+		if err := s.admins.UpdateRole(ctx, admin.ID, role); err != nil {
+			return err
+		}
+		previous = admin.Role
+		admin.Role = role
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if previous != "" {
+		utils.AuthLogger.Info("Role of admin %s changed from %s to %s", admin.Email, previous, role)
+	}
+	return admin, nil
+}
+
+// Disable stops an admin from logging in and logs them out of every session.
+// Disabling an admin that is already disabled changes nothing.
+func (s *AdminService) Disable(ctx context.Context, adminID, disabledBy string) (*models.Admin, error) {
+	if adminID == disabledBy {
+		return nil, ErrOwnAccount
+	}
+
+	var admin *models.Admin
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		admin, err = s.lockAdmin(ctx, adminID, true)
+		if err != nil || admin.DisabledAt != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := s.admins.SetDisabled(ctx, admin.ID, &now); err != nil {
+			return err
+		}
+		if err := s.sessions.RevokeAdmin(ctx, admin.ID, now); err != nil {
+			return err
+		}
+		admin.DisabledAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return admin, nil
+}
+
+// Enable lets a disabled admin log in again
+func (s *AdminService) Enable(ctx context.Context, adminID string) (*models.Admin, error) {
+	var admin *models.Admin
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		admin, err = s.admins.FindByIDForUpdate(ctx, adminID)
+		if err != nil {
+			return err
+		}
+		if admin == nil {
+			return ErrAdminNotFound
+		}
+		if admin.DisabledAt == nil {
+			return nil
+		}
+
+		if err := s.admins.SetDisabled(ctx, admin.ID, nil); err != nil {
+			return err
+		}
+		admin.DisabledAt = nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return admin, nil
+}
+
+// Delete removes an admin for good. What they did, like reviewing payment
+// proofs or checking runners in, stays recorded without them.
+func (s *AdminService) Delete(ctx context.Context, adminID, deletedBy string) (*models.Admin, error) {
+	if adminID == deletedBy {
+		return nil, ErrOwnAccount
+	}
+
+	var admin *models.Admin
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		admin, err = s.lockAdmin(ctx, adminID, true)
+		if err != nil {
+			return err
+		}
+		return s.admins.Delete(ctx, admin.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return admin, nil
+}
+
+// lockAdmin locks an admin that is about to change. With keepOwner, which is
+// set when they may stop being an active owner, it returns ErrLastOwner if
+// they are the last one. Owners are locked first, so two owners removing each
+// other at the same time can't leave the system without one. Must run inside
+// a transaction.
+func (s *AdminService) lockAdmin(ctx context.Context, adminID string, keepOwner bool) (*models.Admin, error) {
+	owners, err := s.admins.CountActiveByRoleForUpdate(ctx, RoleOwner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count owners: %w", err)
+	}
+
+	admin, err := s.admins.FindByIDForUpdate(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin == nil {
+		return nil, ErrAdminNotFound
+	}
+	if keepOwner && admin.Role == RoleOwner && admin.DisabledAt == nil && owners <= 1 {
+		return nil, ErrLastOwner
+	}
+
+	return admin, nil
+}
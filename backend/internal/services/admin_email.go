@@ -0,0 +1,120 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"net/mail"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+)
+
+// Emails to admins are not about an event, so unlike participant emails they
+// use fixed templates and are not recorded in email_logs.
+var (
+	passwordResetEmailHTMLTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(passwordResetEmailHTML))
+	passwordResetEmailTextTemplate = texttemplate.Must(texttemplate.New("text").Parse(passwordResetEmailText))
+)
+
+// passwordResetEmailData is the data of the password reset email templates
+type passwordResetEmailData struct {
+	Email       string
+	ResetLink   string
+	LinkMinutes int
+	Team        string
+	Year        int
+}
+
+// SendAdminPasswordResetEmail sends an admin the link that resets their password
+func (s *EmailService) SendAdminPasswordResetEmail(ctx context.Context, admin *models.Admin, resetLink string) error {
+	data := passwordResetEmailData{
+		Email:       admin.Email,
+		ResetLink:   resetLink,
+		LinkMinutes: s.config.PasswordReset.LinkMinutes,
+		Team:        s.config.SMTP.FromName,
+		Year:        time.Now().Year(),
+	}
+
+	var html, text bytes.Buffer
+	if err := passwordResetEmailHTMLTemplate.Execute(&html, data); err != nil {
+		return fmt.Errorf("failed to build email template: %w", err)
+	}
+	if err := passwordResetEmailTextTemplate.Execute(&text, data); err != nil {
+		return fmt.Errorf("failed to build email template: %w", err)
+	}
+
+	return s.mailer.Send(ctx, &mailer.Message{
+		From:    mail.Address{Name: s.config.SMTP.FromName, Address: s.config.SMTP.FromEmail},
+		To:      []mail.Address{{Address: admin.Email}},
+		Subject: "Reset Your Admin Password",
+		Text:    text.String(),
+		HTML:    html.String(),
+	})
+}
+
+const passwordResetEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .button { display: inline-block; background-color: #FF6B35; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; font-weight: bold; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔑 Reset Your Password</h1>
+        </div>
+        <div class="content">
+            <p>Hi,</p>
+            
+            <p>Someone asked to reset the password of the admin account <strong>{{.Email}}</strong>. Use the button below to choose a new one.</p>
+            
+            <p style="text-align: center;">
+                <a class="button" href="{{.ResetLink}}">Reset Password</a>
+            </p>
+            
+            <p>The link works once, for {{.LinkMinutes}} minutes. Resetting your password logs you out everywhere.</p>
+            
+            <p>If you did not ask to reset your password, you can ignore this email; your password stays the same.</p>
+            
+            <p><strong>{{.Team}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.Team}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const passwordResetEmailText = `
+Reset Your Password
+
+Hi,
+
+Someone asked to reset the password of the admin account {{.Email}}. Open the link below to choose a new one:
+
+{{.ResetLink}}
+
+The link works once, for {{.LinkMinutes}} minutes. Resetting your password logs you out everywhere.
+
+If you did not ask to reset your password, you can ignore this email; your password stays the same.
+
+{{.Team}}
+
+---
+This is an automated email. Please do not reply to this message.
+© {{.Year}} {{.Team}}. All rights reserved.
+`
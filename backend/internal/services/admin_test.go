@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// adminFixture is an owner and a viewer with the admin service on an
+// in-memory database
+type adminFixture struct {
+	owner   models.Admin
+	viewer  models.Admin
+	admins  *memory.AdminRepository
+	service *AdminService
+}
+
+func newAdminFixture(t *testing.T) *adminFixture {
+	t.Helper()
+
+	db := memory.NewDB()
+	f := &adminFixture{admins: memory.NewAdminRepository(db)}
+	f.owner = f.admins.Add(models.Admin{Email: "owner@tautaurun.id", Role: RoleOwner})
+	f.viewer = f.admins.Add(models.Admin{Email: "viewer@tautaurun.id", Role: RoleViewer})
+	f.service = NewAdminService(NewAuthService(&config.Config{}), f.admins, memory.NewSessionRepository(db), memory.NewTransactor(db))
+	return f
+}
+
+// role returns the stored role of an admin
+func (f *adminFixture) role(t *testing.T, id string) string {
+	t.Helper()
+
+	admin, err := f.admins.FindByID(context.Background(), id)
+	if err != nil || admin == nil {
+		t.Fatalf("failed to find admin %s: %v", id, err)
+	}
+	return admin.Role
+}
+
+func TestAdminServiceUpdateRole(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+
+	updated, err := f.service.UpdateRole(ctx, f.viewer.ID, RoleFinance)
+	if err != nil {
+		t.Fatalf("UpdateRole() error = %v", err)
+	}
+	if updated.Role != RoleFinance || f.role(t, f.viewer.ID) != RoleFinance {
+		t.Errorf("role = %s, stored %s, want FINANCE", updated.Role, f.role(t, f.viewer.ID))
+	}
+
+	// With a second owner, the first one can step down
+	if _, err := f.service.UpdateRole(ctx, f.viewer.ID, RoleOwner); err != nil {
+		t.Fatalf("UpdateRole() to owner error = %v", err)
+	}
+	if _, err := f.service.UpdateRole(ctx, f.owner.ID, RoleCheckIn); err != nil {
+		t.Fatalf("UpdateRole() of one of two owners error = %v", err)
+	}
+
+	admins, err := f.service.List(ctx)
+	if err != nil || len(admins) != 2 || admins[0].Email != "owner@tautaurun.id" || admins[0].Role != RoleCheckIn {
+		t.Errorf("List() = %+v, %v, want both admins by email with their new roles", admins, err)
+	}
+}
+
+func TestAdminServiceUpdateRoleInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		adminID func(f *adminFixture) string
+		role    string
+		wantErr error
+	}{
+		{
+			name:    "unknown role",
+			adminID: func(f *adminFixture) string { return f.viewer.ID },
+			role:    "SUPERUSER",
+			wantErr: ErrInvalidRole,
+		},
+		{
+			name:    "lowercase role",
+			adminID: func(f *adminFixture) string { return f.viewer.ID },
+			role:    "finance",
+			wantErr: ErrInvalidRole,
+		},
+		{
+			name:    "unknown admin",
+			adminID: func(f *adminFixture) string { return "missing" },
+			role:    RoleViewer,
+			wantErr: ErrAdminNotFound,
+		},
+		{
+			name:    "last owner",
+			adminID: func(f *adminFixture) string { return f.owner.ID },
+			role:    RoleViewer,
+			wantErr: ErrLastOwner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAdminFixture(t)
+
+			if _, err := f.service.UpdateRole(context.Background(), tt.adminID(f), tt.role); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateRole() error = %v, want %v", err, tt.wantErr)
+			}
+			if f.role(t, f.owner.ID) != RoleOwner || f.role(t, f.viewer.ID) != RoleViewer {
+				t.Errorf("roles changed to %s and %s", f.role(t, f.owner.ID), f.role(t, f.viewer.ID))
+			}
+		})
+	}
+}
+
+func TestAdminServiceCreate(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+
+	admin, err := f.service.Create(ctx, "finance@tautaurun.id", "counting123", RoleFinance, f.owner.ID)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if admin.Role != RoleFinance || admin.CreatedBy == nil || *admin.CreatedBy != f.owner.ID {
+		t.Errorf("admin = %s created by %v, want FINANCE created by the owner", admin.Role, admin.CreatedBy)
+	}
+	if err := NewAuthService(&config.Config{}).ComparePassword(admin.PasswordHash, "counting123"); err != nil {
+		t.Error("stored password hash does not match the initial password")
+	}
+
+	fromCommandLine, err := f.service.Create(ctx, "second@tautaurun.id", "counting123", RoleOwner, "")
+	if err != nil || fromCommandLine.CreatedBy != nil {
+		t.Errorf("Create() from the command line = %+v, %v, want no creator", fromCommandLine, err)
+	}
+
+	if _, err := f.service.Create(ctx, "finance@tautaurun.id", "counting123", RoleViewer, f.owner.ID); !errors.Is(err, repository.ErrDuplicateEmail) {
+		t.Errorf("Create() with a taken email error = %v, want ErrDuplicateEmail", err)
+	}
+	if _, err := f.service.Create(ctx, "new@tautaurun.id", "counting123", "ADMIN", f.owner.ID); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("Create() with an unknown role error = %v, want ErrInvalidRole", err)
+	}
+	var weak *WeakPasswordError
+	if _, err := f.service.Create(ctx, "new@tautaurun.id", "password", RoleViewer, f.owner.ID); !errors.As(err, &weak) {
+		t.Errorf("Create() with a weak password error = %v, want WeakPasswordError", err)
+	}
+}
+
+func TestAdminServiceDisable(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.service.Disable(ctx, f.owner.ID, f.owner.ID); !errors.Is(err, ErrOwnAccount) {
+		t.Errorf("Disable() of own account error = %v, want ErrOwnAccount", err)
+	}
+	if _, err := f.service.Disable(ctx, f.owner.ID, f.viewer.ID); !errors.Is(err, ErrLastOwner) {
+		t.Errorf("Disable() of the last owner error = %v, want ErrLastOwner", err)
+	}
+
+	disabled, err := f.service.Disable(ctx, f.viewer.ID, f.owner.ID)
+	if err != nil || disabled.DisabledAt == nil {
+		t.Fatalf("Disable() = %+v, %v, want the viewer disabled", disabled, err)
+	}
+	if _, err := f.service.Active(ctx, f.viewer.ID); !errors.Is(err, ErrAdminDisabled) {
+		t.Errorf("Active() of a disabled admin error = %v, want ErrAdminDisabled", err)
+	}
+
+	// A disabled owner does not count, so the only active one stays
+	second, err := f.service.Create(ctx, "second@tautaurun.id", "counting123", RoleOwner, f.owner.ID)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := f.service.Disable(ctx, second.ID, f.owner.ID); err != nil {
+		t.Fatalf("Disable() of the second owner error = %v", err)
+	}
+	if _, err := f.service.UpdateRole(ctx, f.owner.ID, RoleViewer); !errors.Is(err, ErrLastOwner) {
+		t.Errorf("UpdateRole() of the last active owner error = %v, want ErrLastOwner", err)
+	}
+
+	enabled, err := f.service.Enable(ctx, f.viewer.ID)
+	if err != nil || enabled.DisabledAt != nil {
+		t.Fatalf("Enable() = %+v, %v, want the viewer enabled", enabled, err)
+	}
+	if _, err := f.service.Active(ctx, f.viewer.ID); err != nil {
+		t.Errorf("Active() after enabling error = %v", err)
+	}
+}
+
+func TestAdminServiceDelete(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.service.Delete(ctx, f.viewer.ID, f.viewer.ID); !errors.Is(err, ErrOwnAccount) {
+		t.Errorf("Delete() of own account error = %v, want ErrOwnAccount", err)
+	}
+	if _, err := f.service.Delete(ctx, f.owner.ID, f.viewer.ID); !errors.Is(err, ErrLastOwner) {
+		t.Errorf("Delete() of the last owner error = %v, want ErrLastOwner", err)
+	}
+
+	if _, err := f.service.Delete(ctx, f.viewer.ID, f.owner.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := f.service.Active(ctx, f.viewer.ID); !errors.Is(err, ErrAdminNotFound) {
+		t.Errorf("Active() of a deleted admin error = %v, want ErrAdminNotFound", err)
+	}
+	if _, err := f.service.Delete(ctx, f.viewer.ID, f.owner.ID); !errors.Is(err, ErrAdminNotFound) {
+		t.Errorf("Delete() twice error = %v, want ErrAdminNotFound", err)
+	}
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"time"
@@ -20,21 +22,26 @@ func NewAuthService(cfg *config.Config) *AuthService {
 	return &AuthService{cfg: cfg}
 }
 
-// Claims represents JWT claims
+// Claims represents JWT claims. Role is the admin's role when the token was
+// issued; requests are authorized with the role stored now.
 type Claims struct {
 	AdminID string `json:"admin_id"`
 	Email   string `json:"email"`
+	Role    string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken generates a JWT token for an admin
-func (s *AuthService) GenerateToken(adminID, email string) (string, time.Time, error) {
-	expirationTime := time.Now().Add(time.Duration(s.cfg.JWT.ExpirationHours) * time.Hour)
+// GenerateToken generates a short-lived JWT access token for an admin with
+// role. jti identifies the token, so it can be revoked before it expires.
+func (s *AuthService) GenerateToken(adminID, email, role, jti string) (string, time.Time, error) {
+	expirationTime := time.Now().Add(time.Duration(s.cfg.JWT.AccessTokenMinutes) * time.Minute)
 	
 	claims := &Claims{
 		AdminID: adminID,
 		Email:   email,
+		Role:    role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -113,3 +120,147 @@ func (s *AuthService) ValidatePassword(password string) error {
 
 	return nil
 }
+
+// Audiences of the tokens issued to participants, which keep a login link
+// from being used as a session and the other way round
+const (
+	participantLoginAudience   = "participant-login"
+	participantSessionAudience = "participant"
+)
+
+// ParticipantClaims represents the JWT claims of a participant. A participant
+// is identified by their email address, which covers their registrations for
+// every event.
+type ParticipantClaims struct {
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// GenerateParticipantLoginToken generates the token of a magic link that logs
+// a participant in
+func (s *AuthService) GenerateParticipantLoginToken(email string) (string, error) {
+	token, _, err := s.signParticipantToken(email, participantLoginAudience, time.Duration(s.cfg.Portal.LinkMinutes)*time.Minute)
+	return token, err
+}
+
+// ValidateParticipantLoginToken validates the token of a magic link and
+// returns the email address it logs in
+func (s *AuthService) ValidateParticipantLoginToken(tokenString string) (string, error) {
+	claims, err := s.parseParticipantToken(tokenString, participantLoginAudience)
+	if err != nil {
+		return "", err
+	}
+	return claims.Email, nil
+}
+
+// GenerateParticipantToken generates the session token of a participant
+func (s *AuthService) GenerateParticipantToken(email string) (string, time.Time, error) {
+	return s.signParticipantToken(email, participantSessionAudience, time.Duration(s.cfg.Portal.SessionHours)*time.Hour)
+}
+
+// ValidateParticipantToken validates the session token of a participant and returns the claims
+func (s *AuthService) ValidateParticipantToken(tokenString string) (*ParticipantClaims, error) {
+	return s.parseParticipantToken(tokenString, participantSessionAudience)
+}
+
+// signParticipantToken signs a participant token for audience that expires after ttl
+func (s *AuthService) signParticipantToken(email, audience string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expirationTime := now.Add(ttl)
+
+	claims := &ParticipantClaims{
+		Email: email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(s.participantKey())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return tokenString, expirationTime, nil
+}
+
+// parseParticipantToken validates a participant token issued for audience
+func (s *AuthService) parseParticipantToken(tokenString, audience string) (*ParticipantClaims, error) {
+	claims := &ParticipantClaims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.participantKey(), nil
+	}, jwt.WithAudience(audience), jwt.WithExpirationRequired())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	if !token.Valid || claims.Email == "" {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+// transferAudience is the audience of the tokens in registration transfer links
+const transferAudience = "registration-transfer"
+
+// GenerateTransferToken generates the token of the link the recipient of a
+// registration transfer accepts it with, valid until expiresAt
+func (s *AuthService) GenerateTransferToken(transferID string, expiresAt time.Time) (string, error) {
+	now := time.Now()
+	claims := &jwt.RegisteredClaims{
+		Subject:   transferID,
+		Audience:  jwt.ClaimStrings{transferAudience},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(s.participantKey())
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// ValidateTransferToken validates the token of a transfer link and returns
+// the ID of the transfer
+func (s *AuthService) ValidateTransferToken(tokenString string) (string, error) {
+	claims := &jwt.RegisteredClaims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.participantKey(), nil
+	}, jwt.WithAudience(transferAudience), jwt.WithExpirationRequired())
+
+	if err != nil {
+		return "", fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	if !token.Valid || claims.Subject == "" {
+		return "", errors.New("invalid token")
+	}
+
+	return claims.Subject, nil
+}
+
+// participantKey derives the key of participant tokens from the JWT secret.
+// Admin tokens are signed with the secret itself, so neither kind of token is
+// accepted as the other.
+func (s *AuthService) participantKey() []byte {
+	mac := hmac.New(sha256.New, []byte(s.cfg.JWT.Secret))
+	mac.Write([]byte("participant"))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// ErrBibReservationNotFound is returned when a bib reservation does not exist
+var ErrBibReservationNotFound = errors.New("bib reservation not found")
+
+// BibService assigns bib numbers to paid participants and manages the ranges
+// held back for manual assignment.
+//
+// Bib numbers are unique within a race category, or among an event's
+// participants without one. Each paid participant gets the lowest number that
+// is neither taken nor reserved, so numbers are handed out without gaps.
+type BibService struct {
+	events       repository.EventRepository
+	categories   repository.RaceCategoryRepository
+	participants repository.ParticipantRepository
+	reservations repository.BibReservationRepository
+	tx           repository.Transactor
+}
+
+// NewBibService creates a new bib service
+func NewBibService(
+	events repository.EventRepository,
+	categories repository.RaceCategoryRepository,
+	participants repository.ParticipantRepository,
+	reservations repository.BibReservationRepository,
+	tx repository.Transactor,
+) *BibService {
+	return &BibService{
+		events:       events,
+		categories:   categories,
+		participants: participants,
+		reservations: reservations,
+		tx:           tx,
+	}
+}
+
+// Assign gives a participant without a bib the lowest free number in their
+// race category. It must be called inside a transaction, which keeps the
+// category locked until the number is stored.
+func (s *BibService) Assign(ctx context.Context, participant *models.Participant) error {
+	if participant.BibNumber != nil {
+		return nil
+	}
+
+	if _, _, err := lockScope(ctx, s.events, s.categories, participant.EventID, participant.CategoryID); err != nil {
+		return err
+	}
+
+	taken, err := s.participants.ListBibNumbers(ctx, participant.EventID, participant.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	reserved, err := s.reserved(ctx, participant.EventID, participant.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	bib := nextBibNumber(taken, reserved)
+	participant.BibNumber = &bib
+	if err := s.participants.UpdateBibNumber(ctx, participant); err != nil {
+		return err
+	}
+
+	utils.ServerLogger.Info("Assigned bib number %d to %s", bib, participant.Email)
+	return nil
+}
+
+// Release takes the bib number back from a participant whose payment was
+// reverted, so it can be handed out again. It must be called inside a transaction.
+func (s *BibService) Release(ctx context.Context, participant *models.Participant) error {
+	if participant.BibNumber == nil {
+		return nil
+	}
+
+	released := *participant.BibNumber
+	participant.BibNumber = nil
+	if err := s.participants.UpdateBibNumber(ctx, participant); err != nil {
+		return err
+	}
+
+	utils.ServerLogger.Info("Released bib number %d of %s", released, participant.Email)
+	return nil
+}
+
+// Reassign sets a participant's bib number by hand, including numbers in
+// reserved ranges. A nil bib removes it. It returns
+// repository.ErrDuplicateBibNumber if another participant in the race
+// category already has the number.
+func (s *BibService) Reassign(ctx context.Context, participantID string, bib *int) (*models.Participant, error) {
+	var participant *models.Participant
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		participant, err = s.participants.FindByIDForUpdate(ctx, participantID)
+		if err != nil {
+			return err
+		}
+		if participant == nil {
+			return ErrParticipantNotFound
+		}
+
+		if _, _, err := lockScope(ctx, s.events, s.categories, participant.EventID, participant.CategoryID); err != nil {
+			return err
+		}
+
+		participant.BibNumber = bib
+		return s.participants.UpdateBibNumber(ctx, participant)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return participant, nil
+}
+
+// ListReservations returns the bib reservations of an event, lowest range first
+func (s *BibService) ListReservations(ctx context.Context, eventID string) ([]models.BibReservation, error) {
+	if err := s.requireEvent(ctx, eventID); err != nil {
+		return nil, err
+	}
+	return s.reservations.ListByEvent(ctx, eventID)
+}
+
+// Reserve holds a range of bib numbers back from automatic assignment. Numbers
+// in the range that are already assigned stay with their participants.
+func (s *BibService) Reserve(ctx context.Context, reservation *models.BibReservation) error {
+	if err := s.requireEvent(ctx, reservation.EventID); err != nil {
+		return err
+	}
+
+	if reservation.CategoryID != nil {
+		category, err := s.categories.FindByID(ctx, *reservation.CategoryID)
+		if err != nil {
+			return err
+		}
+		if category == nil || category.EventID != reservation.EventID {
+			return ErrCategoryNotFound
+		}
+	}
+
+	return s.reservations.Create(ctx, reservation)
+}
+
+// DeleteReservation returns a reserved range to automatic assignment
+func (s *BibService) DeleteReservation(ctx context.Context, id string) error {
+	reservation, err := s.reservations.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if reservation == nil {
+		return ErrBibReservationNotFound
+	}
+	return s.reservations.Delete(ctx, id)
+}
+
+// reserved returns the reservations that apply to a race category of an event
+func (s *BibService) reserved(ctx context.Context, eventID string, categoryID *string) ([]models.BibReservation, error) {
+	reservations, err := s.reservations.ListByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	applicable := []models.BibReservation{}
+	for _, r := range reservations {
+		if r.CategoryID == nil || sameCategory(r.CategoryID, categoryID) {
+			applicable = append(applicable, r)
+		}
+	}
+	return applicable, nil
+}
+
+// requireEvent returns ErrEventNotFound if the event does not exist
+func (s *BibService) requireEvent(ctx context.Context, eventID string) error {
+	event, err := s.events.FindByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return ErrEventNotFound
+	}
+	return nil
+}
+
+// nextBibNumber returns the lowest positive number that is neither taken nor reserved
+func nextBibNumber(taken []int, reserved []models.BibReservation) int {
+	used := make(map[int]bool, len(taken))
+	for _, n := range taken {
+		used[n] = true
+	}
+
+	n := 1
+	for {
+		skipped := false
+		for i := range reserved {
+			if reserved[i].Contains(n) {
+				n = reserved[i].RangeEnd + 1
+				skipped = true
+			}
+		}
+		if skipped {
+			continue
+		}
+		if !used[n] {
+			return n
+		}
+		n++
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// bibFixture is an event with bib and payment services on an in-memory database
+type bibFixture struct {
+	event        *models.Event
+	categories   *memory.RaceCategoryRepository
+	participants *memory.ParticipantRepository
+	payments     *PaymentService
+	service      *BibService
+}
+
+func newBibFixture(t *testing.T) *bibFixture {
+	t.Helper()
+
+	cfg := &config.Config{}
+	db := memory.NewDB()
+	tx := memory.NewTransactor(db)
+	events := memory.NewEventRepository(db)
+	f := &bibFixture{
+		event:        &models.Event{Name: "City Run", EventDate: "2026-12-06", Location: "Jakarta", RegistrationOpen: true},
+		categories:   memory.NewRaceCategoryRepository(db),
+		participants: memory.NewParticipantRepository(db),
+	}
+	if err := events.Create(context.Background(), f.event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+
+	f.service = NewBibService(events, f.categories, f.participants, memory.NewBibReservationRepository(db), tx)
+	outbox := NewEmailOutbox(cfg, NewEmailService(cfg, mailer.NewMemoryMailer(), nil, events, memory.NewEmailLogRepository(db), nil, nil, nil), f.participants, memory.NewEmailOutboxRepository(db), tx)
+	f.payments = NewPaymentService(cfg, nil, outbox, f.service, events, f.categories, f.participants, memory.NewPaymentRepository(db), tx)
+	return f
+}
+
+// category adds a race category to the event
+func (f *bibFixture) category(t *testing.T, name string) *models.RaceCategory {
+	t.Helper()
+
+	c := &models.RaceCategory{EventID: f.event.ID, Name: name, DistanceKM: 10, Price: 200000, Currency: "IDR", Capacity: 100}
+	if err := f.categories.Create(context.Background(), c); err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+	return c
+}
+
+// reserve holds bib numbers start to end back in categoryID, or in every
+// category if it is nil
+func (f *bibFixture) reserve(t *testing.T, categoryID *string, start, end int) {
+	t.Helper()
+
+	reservation := &models.BibReservation{EventID: f.event.ID, CategoryID: categoryID, RangeStart: start, RangeEnd: end, Label: "Elite"}
+	if err := f.service.Reserve(context.Background(), reservation); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+}
+
+// pay registers a runner in categoryID and marks them PAID
+func (f *bibFixture) pay(t *testing.T, email string, categoryID *string) *models.Participant {
+	t.Helper()
+
+	p := &models.Participant{EventID: f.event.ID, CategoryID: categoryID, Name: "Runner", Email: email, Phone: "+6281234567890", Address: "Jalan Merdeka 1"}
+	if err := f.participants.Create(context.Background(), p); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+	change, err := f.payments.UpdateStatus(context.Background(), p.ID, "PAID")
+	if err != nil {
+		t.Fatalf("UpdateStatus(%s, PAID) error = %v", email, err)
+	}
+	return change.Participant
+}
+
+func bibOf(p *models.Participant) int {
+	if p.BibNumber == nil {
+		return 0
+	}
+	return *p.BibNumber
+}
+
+func TestNextBibNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		taken    []int
+		reserved []models.BibReservation
+		want     int
+	}{
+		{name: "first", want: 1},
+		{name: "gap", taken: []int{1, 2, 4}, want: 3},
+		{name: "after reserved range", taken: []int{1}, reserved: []models.BibReservation{{RangeStart: 1, RangeEnd: 10}}, want: 11},
+		{name: "adjacent ranges", reserved: []models.BibReservation{{RangeStart: 11, RangeEnd: 20}, {RangeStart: 1, RangeEnd: 10}}, want: 21},
+		{name: "taken after range", taken: []int{1, 6}, reserved: []models.BibReservation{{RangeStart: 2, RangeEnd: 5}}, want: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextBibNumber(tt.taken, tt.reserved); got != tt.want {
+				t.Errorf("nextBibNumber() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBibServiceAssignOnPayment(t *testing.T) {
+	f := newBibFixture(t)
+	ctx := context.Background()
+	tenK := f.category(t, "10K").ID
+	half := f.category(t, "Half Marathon").ID
+	f.reserve(t, nil, 1, 3)
+	f.reserve(t, &half, 4, 10)
+
+	a := f.pay(t, "a@example.com", &tenK)
+	b := f.pay(t, "b@example.com", &tenK)
+	c := f.pay(t, "c@example.com", &half)
+	if bibOf(a) != 4 || bibOf(b) != 5 || bibOf(c) != 11 {
+		t.Fatalf("bibs = %d, %d, %d, want 4, 5 in 10K and 11 in the half marathon", bibOf(a), bibOf(b), bibOf(c))
+	}
+
+	// A reverted payment frees the number for the next runner
+	change, err := f.payments.UpdateStatus(ctx, a.ID, "UNPAID")
+	if err != nil {
+		t.Fatalf("UpdateStatus(UNPAID) error = %v", err)
+	}
+	if change.Participant.BibNumber != nil {
+		t.Errorf("bib after reverting payment = %d, want none", bibOf(change.Participant))
+	}
+	if d := f.pay(t, "d@example.com", &tenK); bibOf(d) != 4 {
+		t.Errorf("bib of next runner = %d, want the released 4", bibOf(d))
+	}
+}
+
+func TestBibServiceReassign(t *testing.T) {
+	f := newBibFixture(t)
+	ctx := context.Background()
+	f.reserve(t, nil, 1, 10)
+	a := f.pay(t, "a@example.com", nil)
+	b := f.pay(t, "b@example.com", nil)
+
+	bib := 7
+	got, err := f.service.Reassign(ctx, a.ID, &bib)
+	if err != nil {
+		t.Fatalf("Reassign() into a reserved range error = %v", err)
+	}
+	if bibOf(got) != 7 {
+		t.Errorf("Reassign() bib = %d, want 7", bibOf(got))
+	}
+
+	if _, err := f.service.Reassign(ctx, b.ID, &bib); !errors.Is(err, repository.ErrDuplicateBibNumber) {
+		t.Errorf("Reassign() to a taken number error = %v, want %v", err, repository.ErrDuplicateBibNumber)
+	}
+
+	got, err = f.service.Reassign(ctx, a.ID, nil)
+	if err != nil {
+		t.Fatalf("Reassign() to no bib error = %v", err)
+	}
+	if got.BibNumber != nil {
+		t.Errorf("Reassign() to no bib = %d, want none", bibOf(got))
+	}
+
+	if _, err := f.service.Reassign(ctx, "00000000-0000-4000-8000-000000000000", &bib); !errors.Is(err, ErrParticipantNotFound) {
+		t.Errorf("Reassign() of an unknown participant error = %v, want %v", err, ErrParticipantNotFound)
+	}
+}
+
+func TestBibServiceReservations(t *testing.T) {
+	f := newBibFixture(t)
+	ctx := context.Background()
+
+	other := &models.RaceCategory{EventID: "other-event-id", Name: "5K", DistanceKM: 5, Capacity: 100}
+	if err := f.categories.Create(ctx, other); err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+	reservation := &models.BibReservation{EventID: f.event.ID, CategoryID: &other.ID, RangeStart: 1, RangeEnd: 10}
+	if err := f.service.Reserve(ctx, reservation); !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("Reserve() in another event's category error = %v, want %v", err, ErrCategoryNotFound)
+	}
+	if err := f.service.Reserve(ctx, &models.BibReservation{EventID: "00000000-0000-4000-8000-000000000000", RangeStart: 1, RangeEnd: 10}); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("Reserve() for an unknown event error = %v, want %v", err, ErrEventNotFound)
+	}
+
+	f.reserve(t, nil, 1, 10)
+	reservations, err := f.service.ListReservations(ctx, f.event.ID)
+	if err != nil || len(reservations) != 1 {
+		t.Fatalf("ListReservations() = %d reservations, %v, want 1", len(reservations), err)
+	}
+	if err := f.service.DeleteReservation(ctx, reservations[0].ID); err != nil {
+		t.Fatalf("DeleteReservation() error = %v", err)
+	}
+	if err := f.service.DeleteReservation(ctx, reservations[0].ID); !errors.Is(err, ErrBibReservationNotFound) {
+		t.Errorf("second DeleteReservation() error = %v, want %v", err, ErrBibReservationNotFound)
+	}
+
+	if p := f.pay(t, "a@example.com", nil); bibOf(p) != 1 {
+		t.Errorf("bib after deleting the reservation = %d, want 1", bibOf(p))
+	}
+}
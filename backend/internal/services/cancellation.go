@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// Errors returned by CancellationService
+var (
+	ErrCancelled          = errors.New("registration is cancelled")
+	ErrCancellationClosed = errors.New("registration can no longer be cancelled")
+	ErrNoRefundPending    = errors.New("no refund is pending for this registration")
+)
+
+// CancellationService cancels registrations, whether by the participant
+// through the participant portal or by an admin, and tracks the refunds owed
+// for them.
+//
+// A cancelled registration gives up its bib number and its spot, which is
+// offered to the waitlist. A paid one is owed a share of what was paid under
+// the event's refund policy on the day it is cancelled. It is REFUND_PENDING until
+// an admin records that the money was sent, and then REFUNDED. The participant
+// is emailed at each step.
+type CancellationService struct {
+	config       *config.Config
+	payments     *PaymentService
+	bibs         *BibService
+	waitlist     *WaitlistService
+	outbox       *EmailOutbox
+	events       repository.EventRepository
+	participants repository.ParticipantRepository
+	tx           repository.Transactor
+	location     *time.Location
+}
+
+// NewCancellationService creates a new cancellation service
+func NewCancellationService(
+	cfg *config.Config,
+	payments *PaymentService,
+	bibs *BibService,
+	waitlist *WaitlistService,
+	outbox *EmailOutbox,
+	events repository.EventRepository,
+	participants repository.ParticipantRepository,
+	tx repository.Transactor,
+) *CancellationService {
+	return &CancellationService{
+		config:       cfg,
+		payments:     payments,
+		bibs:         bibs,
+		waitlist:     waitlist,
+		outbox:       outbox,
+		events:       events,
+		participants: participants,
+		tx:           tx,
+		location:     cfg.EventLocation(),
+	}
+}
+
+// Cancel cancels a registration on behalf of an admin. A non-nil
+// refundPercent overrides the refund policy of the event.
+func (s *CancellationService) Cancel(ctx context.Context, participantID, reason, adminID string, refundPercent *int) (*models.Participant, error) {
+	return s.cancel(ctx, participantID, reason, &adminID, refundPercent, nil)
+}
+
+// CancelOwn cancels a registration of email on behalf of the participant.
+// Participants can cancel until the day before the event.
+func (s *CancellationService) CancelOwn(ctx context.Context, email, participantID, reason string) (*models.Participant, error) {
+	return s.cancel(ctx, participantID, reason, nil, nil, func(p *models.Participant, event *models.Event) error {
+		// Other people's registrations look the same as ones that don't exist
+		if p.Email != email {
+			return ErrParticipantNotFound
+		}
+
+		eventDay, err := time.ParseInLocation("2006-01-02", event.EventDate, s.location)
+		if err != nil {
+			return fmt.Errorf("invalid date %q of event %s: %w", event.EventDate, event.ID, err)
+		}
+		if !s.today().Before(eventDay) {
+			return ErrCancellationClosed
+		}
+		return nil
+	})
+}
+
+// Refund records that the refund owed for a cancelled registration was sent
+// and emails the participant
+func (s *CancellationService) Refund(ctx context.Context, participantID, adminID string) (*models.Participant, error) {
+	var participant *models.Participant
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.participants.FindByIDForUpdate(ctx, participantID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return ErrParticipantNotFound
+		}
+		if p.PaymentStatus != "REFUND_PENDING" {
+			return ErrNoRefundPending
+		}
+
+		if err := s.participants.UpdatePaymentStatus(ctx, p, "REFUNDED"); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		p.RefundedAt = &now
+		p.RefundedBy = &adminID
+		if err := s.participants.UpdateCancellation(ctx, p); err != nil {
+			return err
+		}
+
+		participant = p
+		return s.outbox.Enqueue(ctx, p.ID, EmailTypeRefund)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.ServerLogger.Info("Refunded %s to %s", formatAmount(*participant.RefundAmount, *participant.RefundCurrency), participant.Email)
+	return participant, nil
+}
+
+// cancel cancels a registration after check, if given, accepts it. A nil
+// adminID means the participant cancelled it themselves.
+func (s *CancellationService) cancel(
+	ctx context.Context,
+	participantID, reason string,
+	adminID *string,
+	refundPercent *int,
+	check func(p *models.Participant, event *models.Event) error,
+) (*models.Participant, error) {
+	// The scope is locked before the participant, like registrations and the
+	// waitlist do, so the scope is needed before the participant is locked
+	existing, err := s.participants.FindByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	var participant *models.Participant
+	heldSpot := false
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, _, err := s.waitlist.lockSpots(ctx, existing.EventID, existing.CategoryID); err != nil {
+			return err
+		}
+
+		p, err := s.participants.FindByIDForUpdate(ctx, participantID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return ErrParticipantNotFound
+		}
+
+		event, err := s.events.FindByID(ctx, p.EventID)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			return fmt.Errorf("event %s of participant %s not found", p.EventID, p.ID)
+		}
+
+		if check != nil {
+			if err := check(p, event); err != nil {
+				return err
+			}
+		}
+		if p.RegistrationStatus == "CANCELLED" {
+			return ErrCancelled
+		}
+		if p.CheckedInAt != nil {
+			return ErrAlreadyCheckedIn
+		}
+
+		waitlisted := p.RegistrationStatus == "WAITLISTED"
+		heldSpot = p.RegistrationStatus == "PENDING" || p.RegistrationStatus == "CONFIRMED"
+
+		p.RegistrationStatus = "CANCELLED"
+		p.WaitlistPosition = nil
+		p.OfferExpiresAt = nil
+		if err := s.participants.UpdateRegistration(ctx, p); err != nil {
+			return err
+		}
+
+		if p.PaymentStatus == "PAID" {
+			if err := s.owe(ctx, p, event, refundPercent); err != nil {
+				return err
+			}
+		}
+
+		if err := s.bibs.Release(ctx, p); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		p.CancelledAt = &now
+		p.CancelledBy = adminID
+		p.CancellationReason = &reason
+		if err := s.participants.UpdateCancellation(ctx, p); err != nil {
+			return err
+		}
+
+		if err := s.outbox.Enqueue(ctx, p.ID, EmailTypeCancellation); err != nil {
+			return err
+		}
+
+		// Close the gap the participant leaves in the queue
+		if waitlisted {
+			queue, err := s.waitlist.queue(ctx, p.EventID, p.CategoryID)
+			if err != nil {
+				return err
+			}
+			if err := s.waitlist.renumber(ctx, queue); err != nil {
+				return err
+			}
+		}
+
+		participant = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.ServerLogger.Info("Cancelled registration of %s for event %s: %s", participant.Email, participant.EventID, reason)
+
+	// The registration is cancelled either way, so a failed fill is only logged
+	if heldSpot {
+		if err := s.waitlist.Fill(ctx, participant.EventID, participant.CategoryID); err != nil {
+			utils.ServerLogger.Error("Failed to fill waitlist of event %s: %v", participant.EventID, err)
+		}
+	}
+
+	return participant, nil
+}
+
+// owe records the refund owed for a paid registration being cancelled: the
+// share of what was paid given by refundPercent, or by the refund policy of
+// the event if it is nil. It must be called inside a transaction.
+func (s *CancellationService) owe(ctx context.Context, p *models.Participant, event *models.Event, refundPercent *int) error {
+	paid, currency, err := s.payments.paidAmount(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	percent := s.refundPercent(event)
+	if refundPercent != nil {
+		percent = *refundPercent
+	}
+
+	amount := paid * int64(percent) / 100
+	p.RefundAmount = &amount
+	p.RefundCurrency = &currency
+
+	// Without a refund the registration stays PAID
+	if amount == 0 {
+		return nil
+	}
+	return s.participants.UpdatePaymentStatus(ctx, p, "REFUND_PENDING")
+}
+
+// refundPercent returns the share of the price refunded under the refund
+// policy of event for a registration cancelled today
+func (s *CancellationService) refundPercent(event *models.Event) int {
+	today := s.today()
+
+	if event.RefundCutoffDays > 0 {
+		eventDay, err := time.ParseInLocation("2006-01-02", event.EventDate, s.location)
+		if err == nil && !today.Before(eventDay.AddDate(0, 0, -event.RefundCutoffDays)) {
+			return 0
+		}
+	}
+
+	if event.RefundFullUntil != nil {
+		fullUntil, err := time.ParseInLocation("2006-01-02", *event.RefundFullUntil, s.location)
+		if err == nil && !today.After(fullUntil) {
+			return 100
+		}
+	}
+
+	return event.RefundPartialPercent
+}
+
+// today returns the start of the current day in the time zone of events
+func (s *CancellationService) today() time.Time {
+	now := time.Now().In(s.location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location)
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/payment"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// cancellationFixture is an event in Jakarta a month away with one spot and
+// a waitlist, paid at 150,000 IDR, with the cancellation service on an
+// in-memory database
+type cancellationFixture struct {
+	event        *models.Event
+	events       *memory.EventRepository
+	participants *memory.ParticipantRepository
+	outbox       *memory.EmailOutboxRepository
+	registration *RegistrationService
+	payments     *PaymentService
+	service      *CancellationService
+	today        time.Time
+}
+
+func newCancellationFixture(t *testing.T) *cancellationFixture {
+	t.Helper()
+
+	cfg := &config.Config{
+		Payment:  config.PaymentConfig{Provider: "fake", WebhookSecret: testWebhookSecret, Amount: 150000, Currency: "IDR"},
+		Waitlist: config.WaitlistConfig{OfferHours: 48},
+		Events:   config.EventsConfig{Timezone: "Asia/Jakarta"},
+	}
+	db := memory.NewDB()
+	tx := memory.NewTransactor(db)
+	categories := memory.NewRaceCategoryRepository(db)
+
+	now := time.Now().In(cfg.EventLocation())
+	capacity := 1
+	f := &cancellationFixture{
+		event: &models.Event{
+			Name:                 "City Run",
+			EventDate:            now.AddDate(0, 1, 0).Format("2006-01-02"),
+			Location:             "Jakarta",
+			RegistrationOpen:     true,
+			Capacity:             &capacity,
+			RefundPartialPercent: 50,
+		},
+		events:       memory.NewEventRepository(db),
+		participants: memory.NewParticipantRepository(db),
+		outbox:       memory.NewEmailOutboxRepository(db),
+		today:        time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+	}
+	if err := f.events.Create(context.Background(), f.event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+
+	outbox := NewEmailOutbox(cfg, NewEmailService(cfg, mailer.NewMemoryMailer(), nil, f.events, memory.NewEmailLogRepository(db), nil, nil, nil), f.participants, f.outbox, tx)
+	waitlist := NewWaitlistService(cfg, f.events, categories, f.participants, outbox, tx)
+	bibs := NewBibService(f.events, categories, f.participants, memory.NewBibReservationRepository(db), tx)
+	f.registration = NewRegistrationService(categories, f.participants, waitlist, tx)
+	f.payments = NewPaymentService(cfg, payment.NewFakeProvider(testWebhookSecret), outbox, bibs, f.events, categories, f.participants, memory.NewPaymentRepository(db), tx)
+	f.service = NewCancellationService(cfg, f.payments, bibs, waitlist, outbox, f.events, f.participants, tx)
+	return f
+}
+
+// register registers a runner, who gets the spot or is waitlisted
+func (f *cancellationFixture) register(t *testing.T, email string) *models.Participant {
+	t.Helper()
+
+	p, err := f.registration.Register(context.Background(), f.event, request(email, "", nil))
+	if err != nil {
+		t.Fatalf("Register(%s) error = %v", email, err)
+	}
+	return p
+}
+
+// pay registers a runner who pays for the spot
+func (f *cancellationFixture) pay(t *testing.T, email string) *models.Participant {
+	t.Helper()
+
+	p := f.register(t, email)
+	change, err := f.payments.UpdateStatus(context.Background(), p.ID, "PAID")
+	if err != nil {
+		t.Fatalf("UpdateStatus(%s, PAID) error = %v", email, err)
+	}
+	return change.Participant
+}
+
+// stored returns the stored state of p
+func (f *cancellationFixture) stored(t *testing.T, p *models.Participant) *models.Participant {
+	t.Helper()
+
+	stored, err := f.participants.FindByID(context.Background(), p.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("failed to find participant %s: %v", p.Email, err)
+	}
+	return stored
+}
+
+// queued returns the types of the emails queued for p
+func (f *cancellationFixture) queued(t *testing.T, p *models.Participant) []string {
+	t.Helper()
+
+	emails, _, err := f.outbox.List(context.Background(), OutboxStatusPending, 1, 100)
+	if err != nil {
+		t.Fatalf("failed to list outbox: %v", err)
+	}
+	types := []string{}
+	for _, e := range emails {
+		if e.ParticipantID == p.ID {
+			types = append(types, e.EmailType)
+		}
+	}
+	return types
+}
+
+// day returns the date days from today
+func (f *cancellationFixture) day(days int) string {
+	return f.today.AddDate(0, 0, days).Format("2006-01-02")
+}
+
+func TestCancellationServiceCancelPaid(t *testing.T) {
+	f := newCancellationFixture(t)
+	ctx := context.Background()
+	paid := f.pay(t, "paid@example.com")
+	waiting := f.register(t, "waiting@example.com")
+	if paid.BibNumber == nil || waiting.RegistrationStatus != "WAITLISTED" {
+		t.Fatalf("setup: bib %v, waiting %s, want a bib for the paid runner and the other waitlisted", paid.BibNumber, waiting.RegistrationStatus)
+	}
+
+	cancelled, err := f.service.Cancel(ctx, paid.ID, "Injured", "admin-1", nil)
+	if err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	stored := f.stored(t, paid)
+	if stored.RegistrationStatus != "CANCELLED" || stored.PaymentStatus != "REFUND_PENDING" || stored.BibNumber != nil {
+		t.Errorf("cancelled = %s/%s with bib %v, want CANCELLED/REFUND_PENDING without a bib",
+			stored.RegistrationStatus, stored.PaymentStatus, stored.BibNumber)
+	}
+	if stored.RefundAmount == nil || *stored.RefundAmount != 75000 || *stored.RefundCurrency != "IDR" {
+		t.Errorf("refund = %v %v, want half of 150000 IDR", stored.RefundAmount, stored.RefundCurrency)
+	}
+	if stored.CancelledAt == nil || *stored.CancelledBy != "admin-1" || *stored.CancellationReason != "Injured" {
+		t.Errorf("cancellation = at %v by %v for %v, want now by admin-1 for Injured", stored.CancelledAt, stored.CancelledBy, stored.CancellationReason)
+	}
+	if cancelled.PaymentStatus != stored.PaymentStatus {
+		t.Errorf("Cancel() = %s, want the stored participant", cancelled.PaymentStatus)
+	}
+	if got := f.queued(t, paid); !equalStrings(got, []string{EmailTypePaymentConfirmation, EmailTypeCancellation}) {
+		t.Errorf("queued = %v, want the cancellation email after the confirmation", got)
+	}
+
+	// The spot goes to the waitlist
+	if offered := f.stored(t, waiting); offered.RegistrationStatus != "PENDING" || offered.OfferExpiresAt == nil {
+		t.Errorf("waitlisted runner = %s with offer %v, want offered the spot", offered.RegistrationStatus, offered.OfferExpiresAt)
+	}
+
+	if _, err := f.service.Cancel(ctx, paid.ID, "Again", "admin-1", nil); !errors.Is(err, ErrCancelled) {
+		t.Errorf("Cancel() twice error = %v, want ErrCancelled", err)
+	}
+	if _, err := f.payments.UpdateStatus(ctx, paid.ID, "PAID"); !errors.Is(err, ErrCancelled) {
+		t.Errorf("UpdateStatus() of a cancelled registration error = %v, want ErrCancelled", err)
+	}
+}
+
+func TestCancellationServiceCancelPaidBeforePriceChange(t *testing.T) {
+	f := newCancellationFixture(t)
+	ctx := context.Background()
+	p := f.register(t, "paid@example.com")
+
+	// The runner paid online at 150,000 IDR, and the fee went up afterwards
+	charge, err := f.payments.CreateCharge(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+	if _, err := f.payments.applyStatus(ctx, charge.Reference, payment.StatusPaid, charge.Amount); err != nil {
+		t.Fatalf("applyStatus() error = %v", err)
+	}
+	f.payments.config.Payment.Amount = 200000
+
+	if _, err := f.service.Cancel(ctx, p.ID, "Injured", "admin-1", nil); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if stored := f.stored(t, p); stored.RefundAmount == nil || *stored.RefundAmount != 75000 {
+		t.Errorf("refund = %v, want half of the 150000 paid", stored.RefundAmount)
+	}
+
+	// A runner an admin marked paid has no payment, so they are owed a share of the current fee
+	marked := f.register(t, "marked@example.com")
+	if _, err := f.payments.UpdateStatus(ctx, marked.ID, "PAID"); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+	if _, err := f.service.Cancel(ctx, marked.ID, "Injured", "admin-1", nil); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if stored := f.stored(t, marked); stored.RefundAmount == nil || *stored.RefundAmount != 100000 {
+		t.Errorf("refund = %v, want half of the 200000 fee", stored.RefundAmount)
+	}
+}
+
+func TestCancellationServiceRefund(t *testing.T) {
+	f := newCancellationFixture(t)
+	ctx := context.Background()
+	p := f.pay(t, "paid@example.com")
+
+	if _, err := f.service.Refund(ctx, p.ID, "admin-1"); !errors.Is(err, ErrNoRefundPending) {
+		t.Errorf("Refund() before cancelling error = %v, want ErrNoRefundPending", err)
+	}
+
+	if _, err := f.service.Cancel(ctx, p.ID, "Injured", "admin-1", nil); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	refunded, err := f.service.Refund(ctx, p.ID, "admin-2")
+	if err != nil {
+		t.Fatalf("Refund() error = %v", err)
+	}
+	if refunded.PaymentStatus != "REFUNDED" || refunded.RefundedAt == nil || *refunded.RefundedBy != "admin-2" {
+		t.Errorf("Refund() = %s at %v by %v, want REFUNDED now by admin-2", refunded.PaymentStatus, refunded.RefundedAt, refunded.RefundedBy)
+	}
+	if got := f.queued(t, p); got[len(got)-1] != EmailTypeRefund {
+		t.Errorf("queued = %v, want the refund email last", got)
+	}
+
+	if _, err := f.service.Refund(ctx, p.ID, "admin-2"); !errors.Is(err, ErrNoRefundPending) {
+		t.Errorf("Refund() twice error = %v, want ErrNoRefundPending", err)
+	}
+}
+
+func TestCancellationServiceCancelWithoutRefund(t *testing.T) {
+	f := newCancellationFixture(t)
+	ctx := context.Background()
+
+	unpaid := f.register(t, "unpaid@example.com")
+	if _, err := f.service.Cancel(ctx, unpaid.ID, "Changed plans", "admin-1", nil); err != nil {
+		t.Fatalf("Cancel() unpaid error = %v", err)
+	}
+	if stored := f.stored(t, unpaid); stored.PaymentStatus != "UNPAID" || stored.RefundAmount != nil {
+		t.Errorf("unpaid cancelled = %s owed %v, want UNPAID and nothing owed", stored.PaymentStatus, stored.RefundAmount)
+	}
+
+	// An admin waiving the refund leaves the registration paid
+	paid := f.pay(t, "paid@example.com")
+	if _, err := f.service.Cancel(ctx, paid.ID, "Disqualified", "admin-1", ptrInt(0)); err != nil {
+		t.Fatalf("Cancel() paid error = %v", err)
+	}
+	if stored := f.stored(t, paid); stored.PaymentStatus != "PAID" || stored.RefundAmount == nil || *stored.RefundAmount != 0 {
+		t.Errorf("paid cancelled without refund = %s owed %v, want PAID and 0 owed", stored.PaymentStatus, stored.RefundAmount)
+	}
+}
+
+func TestCancellationServiceCancelWaitlisted(t *testing.T) {
+	f := newCancellationFixture(t)
+	ctx := context.Background()
+	f.register(t, "spot@example.com")
+	first := f.register(t, "first@example.com")
+	second := f.register(t, "second@example.com")
+
+	if _, err := f.service.Cancel(ctx, first.ID, "Changed plans", "admin-1", nil); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	stored := f.stored(t, first)
+	if stored.RegistrationStatus != "CANCELLED" || stored.WaitlistPosition != nil {
+		t.Errorf("cancelled = %s at position %v, want CANCELLED off the waitlist", stored.RegistrationStatus, stored.WaitlistPosition)
+	}
+	if moved := f.stored(t, second); moved.WaitlistPosition == nil || *moved.WaitlistPosition != 1 {
+		t.Errorf("next runner at position %v, want 1", moved.WaitlistPosition)
+	}
+}
+
+func TestCancellationServiceCancelOwn(t *testing.T) {
+	f := newCancellationFixture(t)
+	ctx := context.Background()
+	p := f.register(t, "runner@example.com")
+
+	if _, err := f.service.CancelOwn(ctx, "someone@example.com", p.ID, "Mine now"); !errors.Is(err, ErrParticipantNotFound) {
+		t.Errorf("CancelOwn() of someone else's registration error = %v, want ErrParticipantNotFound", err)
+	}
+
+	f.event.EventDate = f.day(0)
+	if err := f.events.Update(ctx, f.event); err != nil {
+		t.Fatalf("failed to move event: %v", err)
+	}
+	if _, err := f.service.CancelOwn(ctx, p.Email, p.ID, "Overslept"); !errors.Is(err, ErrCancellationClosed) {
+		t.Errorf("CancelOwn() on race day error = %v, want ErrCancellationClosed", err)
+	}
+
+	f.event.EventDate = f.day(1)
+	if err := f.events.Update(ctx, f.event); err != nil {
+		t.Fatalf("failed to move event: %v", err)
+	}
+	cancelled, err := f.service.CancelOwn(ctx, p.Email, p.ID, "Sick")
+	if err != nil {
+		t.Fatalf("CancelOwn() the day before error = %v", err)
+	}
+	if cancelled.RegistrationStatus != "CANCELLED" || cancelled.CancelledBy != nil {
+		t.Errorf("CancelOwn() = %s by %v, want CANCELLED by the participant", cancelled.RegistrationStatus, cancelled.CancelledBy)
+	}
+}
+
+func TestCancellationServiceRefundPercent(t *testing.T) {
+	f := newCancellationFixture(t)
+
+	tests := []struct {
+		name       string
+		fullUntil  *string
+		cutoffDays int
+		want       int
+	}{
+		{name: "no full refund date", want: 50},
+		{name: "full refund until today", fullUntil: ptrStr(f.day(0)), want: 100},
+		{name: "full refund period over", fullUntil: ptrStr(f.day(-1)), want: 50},
+		{name: "before the cutoff", cutoffDays: 10, want: 50},
+		{name: "within the cutoff", fullUntil: ptrStr(f.day(0)), cutoffDays: 30, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &models.Event{
+				EventDate:            f.day(30),
+				RefundFullUntil:      tt.fullUntil,
+				RefundPartialPercent: 50,
+				RefundCutoffDays:     tt.cutoffDays,
+			}
+			if got := f.service.refundPercent(event); got != tt.want {
+				t.Errorf("refundPercent() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
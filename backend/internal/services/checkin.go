@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// Errors returned by CheckInService
+var (
+	ErrInvalidCheckInToken = errors.New("invalid check-in token")
+	ErrNotPaid             = errors.New("participant has not paid")
+	ErrWrongEvent          = errors.New("participant is registered for another event")
+	ErrKitAlreadyCollected = errors.New("race kit already collected")
+	ErrAlreadyCheckedIn    = errors.New("participant already checked in")
+)
+
+// qrCodeSize is the width and height in pixels of generated QR codes
+const qrCodeSize = 256
+
+// CheckInStatus is a participant as shown to the staff member scanning them,
+// with the emails of the admins who scanned their earlier pickups
+type CheckInStatus struct {
+	Participant         *models.Participant `json:"participant"`
+	KitCollectedByEmail string              `json:"kit_collected_by_email,omitempty"`
+	CheckedInByEmail    string              `json:"checked_in_by_email,omitempty"`
+}
+
+// CheckInService issues the signed QR codes of paid participants and records
+// race-kit pickup and race-day check-in when staff scan them.
+//
+// A token is the participant ID with their check-in version and a truncated
+// HMAC-SHA256 of them, so participants cannot forge one for somebody else.
+// Transferring a registration bumps the version, so the QR code of the
+// previous holder stops working. Each scan is recorded once; scanning again
+// is reported as a double scan along with who scanned first and when.
+type CheckInService struct {
+	config       *config.Config
+	admins       repository.AdminRepository
+	participants repository.ParticipantRepository
+	tx           repository.Transactor
+}
+
+// NewCheckInService creates a new check-in service
+func NewCheckInService(
+	cfg *config.Config,
+	admins repository.AdminRepository,
+	participants repository.ParticipantRepository,
+	tx repository.Transactor,
+) *CheckInService {
+	return &CheckInService{
+		config:       cfg,
+		admins:       admins,
+		participants: participants,
+		tx:           tx,
+	}
+}
+
+// Token returns the check-in token of a participant
+func (s *CheckInService) Token(participant *models.Participant) string {
+	payload := participant.ID + ":" + strconv.Itoa(participant.CheckInVersion)
+	return payload + "." + s.sign(payload)
+}
+
+// QRCode returns the check-in token of a participant as a PNG QR code
+func (s *CheckInService) QRCode(participant *models.Participant) ([]byte, error) {
+	return qrcode.Encode(s.Token(participant), qrcode.Medium, qrCodeSize)
+}
+
+// ParticipantQRCode returns the QR code of a paid participant, e.g. to reprint
+// one for a runner who lost their email
+func (s *CheckInService) ParticipantQRCode(ctx context.Context, participantID string) ([]byte, error) {
+	participant, err := s.participants.FindByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	if participant.PaymentStatus != "PAID" {
+		return nil, ErrNotPaid
+	}
+
+	return s.QRCode(participant)
+}
+
+// Verify returns the participant a token belongs to without recording a scan.
+// A non-empty eventID rejects participants of other events.
+func (s *CheckInService) Verify(ctx context.Context, token, eventID string) (*CheckInStatus, error) {
+	participantID, version, err := s.parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	participant, err := s.participants.FindByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScannable(participant, version, eventID); err != nil {
+		return nil, err
+	}
+
+	return s.status(ctx, participant)
+}
+
+// CollectKit records that the participant a token belongs to collected their
+// race kit. A double scan returns ErrKitAlreadyCollected together with the
+// status recorded by the first scan.
+func (s *CheckInService) CollectKit(ctx context.Context, token, eventID, adminID string) (*CheckInStatus, error) {
+	return s.scan(ctx, token, eventID, ErrKitAlreadyCollected, func(p *models.Participant, now time.Time) bool {
+		if p.KitCollectedAt != nil {
+			return false
+		}
+		p.KitCollectedAt = &now
+		p.KitCollectedBy = &adminID
+		return true
+	})
+}
+
+// CheckIn records that the participant a token belongs to checked in on race
+// day. A double scan returns ErrAlreadyCheckedIn together with the status
+// recorded by the first scan.
+func (s *CheckInService) CheckIn(ctx context.Context, token, eventID, adminID string) (*CheckInStatus, error) {
+	return s.scan(ctx, token, eventID, ErrAlreadyCheckedIn, func(p *models.Participant, now time.Time) bool {
+		if p.CheckedInAt != nil {
+			return false
+		}
+		p.CheckedInAt = &now
+		p.CheckedInBy = &adminID
+		return true
+	})
+}
+
+// scan records a scan with mark, which reports false if the participant was
+// already scanned, in which case scan returns duplicate
+func (s *CheckInService) scan(
+	ctx context.Context,
+	token, eventID string,
+	duplicate error,
+	mark func(p *models.Participant, now time.Time) bool,
+) (*CheckInStatus, error) {
+	participantID, version, err := s.parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var participant *models.Participant
+	scanned := false
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		participant, err = s.participants.FindByIDForUpdate(ctx, participantID)
+		if err != nil {
+			return err
+		}
+		if err := checkScannable(participant, version, eventID); err != nil {
+			return err
+		}
+
+		scanned = mark(participant, time.Now())
+		if !scanned {
+			return nil
+		}
+		return s.participants.UpdateCheckIn(ctx, participant)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := s.status(ctx, participant)
+	if err != nil {
+		return nil, err
+	}
+	if !scanned {
+		utils.ServerLogger.Warning("Double scan of %s: %v", participant.Email, duplicate)
+		return status, duplicate
+	}
+	return status, nil
+}
+
+// status looks up the admins who scanned a participant
+func (s *CheckInService) status(ctx context.Context, participant *models.Participant) (*CheckInStatus, error) {
+	status := &CheckInStatus{Participant: participant}
+
+	var err error
+	if status.KitCollectedByEmail, err = s.adminEmail(ctx, participant.KitCollectedBy); err != nil {
+		return nil, err
+	}
+	if status.CheckedInByEmail, err = s.adminEmail(ctx, participant.CheckedInBy); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// adminEmail returns the email of an admin, or "" if there is none
+func (s *CheckInService) adminEmail(ctx context.Context, adminID *string) (string, error) {
+	if adminID == nil {
+		return "", nil
+	}
+	admin, err := s.admins.FindByID(ctx, *adminID)
+	if err != nil || admin == nil {
+		return "", err
+	}
+	return admin.Email, nil
+}
+
+// parse returns the participant ID and check-in version of a token after
+// checking its signature
+func (s *CheckInService) parse(token string) (string, int, error) {
+	payload, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok || payload == "" {
+		return "", 0, ErrInvalidCheckInToken
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(payload))) {
+		return "", 0, ErrInvalidCheckInToken
+	}
+
+	participantID, versionText, ok := strings.Cut(payload, ":")
+	if !ok {
+		return "", 0, ErrInvalidCheckInToken
+	}
+	version, err := strconv.Atoi(versionText)
+	if err != nil || version < 1 {
+		return "", 0, ErrInvalidCheckInToken
+	}
+	return participantID, version, nil
+}
+
+// sign returns the signature of a token payload. It is truncated to 128 bits
+// to keep the QR code small enough to scan from a phone screen.
+func (s *CheckInService) sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.CheckIn.TokenSecret))
+	mac.Write([]byte("checkin:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// checkScannable returns an error unless participant is a paid participant of
+// eventID, or of any event when eventID is empty, who has not cancelled and
+// whose token is of their current check-in version
+func checkScannable(participant *models.Participant, version int, eventID string) error {
+	if participant == nil {
+		return ErrParticipantNotFound
+	}
+	if participant.CheckInVersion != version {
+		return ErrInvalidCheckInToken
+	}
+	if eventID != "" && participant.EventID != eventID {
+		return ErrWrongEvent
+	}
+	if participant.RegistrationStatus == "CANCELLED" {
+		return ErrCancelled
+	}
+	if participant.PaymentStatus != "PAID" {
+		return ErrNotPaid
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+const testCheckInSecret = "test-check-in-secret-at-least-32-bytes"
+
+// checkInFixture is a CheckInService with one staff admin on an in-memory database
+type checkInFixture struct {
+	admin        models.Admin
+	participants *memory.ParticipantRepository
+	service      *CheckInService
+}
+
+func newCheckInFixture(t *testing.T) *checkInFixture {
+	t.Helper()
+
+	cfg := &config.Config{CheckIn: config.CheckInConfig{TokenSecret: testCheckInSecret}}
+	db := memory.NewDB()
+	admins := memory.NewAdminRepository(db)
+	f := &checkInFixture{
+		admin:        admins.Add(models.Admin{Email: "staff@example.com"}),
+		participants: memory.NewParticipantRepository(db),
+	}
+	f.service = NewCheckInService(cfg, admins, f.participants, memory.NewTransactor(db))
+	return f
+}
+
+// participant registers a runner for eventID with the given payment status
+func (f *checkInFixture) participant(t *testing.T, eventID, email, paymentStatus string) *models.Participant {
+	t.Helper()
+
+	ctx := context.Background()
+	p := &models.Participant{EventID: eventID, Name: "Runner", Email: email, Phone: "+6281234567890", Address: "Jalan Merdeka 1"}
+	if err := f.participants.Create(ctx, p); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+	if err := f.participants.UpdatePaymentStatus(ctx, p, paymentStatus); err != nil {
+		t.Fatalf("failed to set payment status: %v", err)
+	}
+	return p
+}
+
+func TestCheckInServiceVerify(t *testing.T) {
+	f := newCheckInFixture(t)
+	paid := f.participant(t, "city-run", "paid@example.com", "PAID")
+	unpaid := f.participant(t, "city-run", "unpaid@example.com", "UNPAID")
+
+	otherSecret := NewCheckInService(&config.Config{CheckIn: config.CheckInConfig{TokenSecret: strings.Repeat("x", 32)}}, nil, nil, nil)
+	token := f.service.Token(paid)
+	if !strings.HasPrefix(token, paid.ID+":1.") {
+		t.Fatalf("Token() = %q, want the participant ID and version 1", token)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		eventID string
+		wantErr error
+	}{
+		{name: "paid participant", token: token, eventID: "city-run"},
+		{name: "any event", token: token},
+		{name: "surrounding whitespace", token: " " + token + "\n"},
+		{name: "another event", token: token, eventID: "trail-run", wantErr: ErrWrongEvent},
+		{name: "unpaid participant", token: f.service.Token(unpaid), wantErr: ErrNotPaid},
+		{name: "unknown participant", token: f.service.Token(&models.Participant{ID: "00000000-0000-4000-8000-000000000000", CheckInVersion: 1}), wantErr: ErrParticipantNotFound},
+		{name: "signed with another secret", token: otherSecret.Token(paid), wantErr: ErrInvalidCheckInToken},
+		{name: "signature of another participant", token: unpaid.ID + token[strings.Index(token, ":"):], wantErr: ErrInvalidCheckInToken},
+		{name: "no version", token: paid.ID + "." + f.service.sign(paid.ID), wantErr: ErrInvalidCheckInToken},
+		{name: "version zero", token: paid.ID + ":0." + f.service.sign(paid.ID+":0"), wantErr: ErrInvalidCheckInToken},
+		{name: "no signature", token: paid.ID, wantErr: ErrInvalidCheckInToken},
+		{name: "empty", token: "", wantErr: ErrInvalidCheckInToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := f.service.Verify(context.Background(), tt.token, tt.eventID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && status.Participant.ID != paid.ID {
+				t.Errorf("Verify() = participant %s, want %s", status.Participant.ID, paid.ID)
+			}
+		})
+	}
+}
+
+func TestCheckInServiceDoubleScan(t *testing.T) {
+	tests := []struct {
+		name      string
+		scan      func(s *CheckInService, token, adminID string) (*CheckInStatus, error)
+		duplicate error
+		scannedBy func(s *CheckInStatus) string
+	}{
+		{
+			name: "race kit",
+			scan: func(s *CheckInService, token, adminID string) (*CheckInStatus, error) {
+				return s.CollectKit(context.Background(), token, "", adminID)
+			},
+			duplicate: ErrKitAlreadyCollected,
+			scannedBy: func(s *CheckInStatus) string { return s.KitCollectedByEmail },
+		},
+		{
+			name: "race day",
+			scan: func(s *CheckInService, token, adminID string) (*CheckInStatus, error) {
+				return s.CheckIn(context.Background(), token, "", adminID)
+			},
+			duplicate: ErrAlreadyCheckedIn,
+			scannedBy: func(s *CheckInStatus) string { return s.CheckedInByEmail },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCheckInFixture(t)
+			token := f.service.Token(f.participant(t, "city-run", "runner@example.com", "PAID"))
+
+			first, err := tt.scan(f.service, token, f.admin.ID)
+			if err != nil {
+				t.Fatalf("first scan error = %v", err)
+			}
+			if got := tt.scannedBy(first); got != f.admin.Email {
+				t.Errorf("first scan by %q, want %q", got, f.admin.Email)
+			}
+
+			second, err := tt.scan(f.service, token, "another-admin-id")
+			if !errors.Is(err, tt.duplicate) {
+				t.Fatalf("second scan error = %v, want %v", err, tt.duplicate)
+			}
+			if got := tt.scannedBy(second); got != f.admin.Email {
+				t.Errorf("second scan reports first scan by %q, want %q", got, f.admin.Email)
+			}
+		})
+	}
+}
+
+func TestCheckInServiceParticipantQRCode(t *testing.T) {
+	f := newCheckInFixture(t)
+	ctx := context.Background()
+
+	png, err := f.service.ParticipantQRCode(ctx, f.participant(t, "city-run", "paid@example.com", "PAID").ID)
+	if err != nil {
+		t.Fatalf("ParticipantQRCode() error = %v", err)
+	}
+	if !bytes.HasPrefix(png, []byte("\x89PNG")) {
+		t.Errorf("ParticipantQRCode() is not a PNG image")
+	}
+
+	if _, err := f.service.ParticipantQRCode(ctx, f.participant(t, "city-run", "unpaid@example.com", "UNPAID").ID); !errors.Is(err, ErrNotPaid) {
+		t.Errorf("ParticipantQRCode() of an unpaid participant error = %v, want %v", err, ErrNotPaid)
+	}
+}
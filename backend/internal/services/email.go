@@ -1,294 +1,428 @@
 package services
 
 import (
-	"bytes"
-	"database/sql"
+	"context"
+	"errors"
 	"fmt"
-	"html/template"
-	"net/smtp"
+	"net/mail"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tau-tau-run/backend/config"
-	"github.com/tau-tau-run/backend/internal/database"
+	"github.com/tau-tau-run/backend/internal/mailer"
 	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
 	"github.com/tau-tau-run/backend/internal/utils"
 )
 
+// Email types, as queued in the outbox and recorded in email_logs
+const (
+	EmailTypePaymentConfirmation = "PAYMENT_CONFIRMATION"
+	EmailTypeWaitlistOffer       = "WAITLIST_OFFER"
+	EmailTypePaymentReminder     = "PAYMENT_REMINDER"
+	EmailTypeKitPickup           = "KIT_PICKUP"
+	EmailTypeRaceDayBriefing     = "RACE_DAY_BRIEFING"
+	EmailTypeCampaign            = "CAMPAIGN"
+	EmailTypePortalLogin         = "PORTAL_LOGIN"
+	EmailTypeCancellation        = "CANCELLATION"
+	EmailTypeRefund              = "REFUND"
+	EmailTypeTransferOffer       = "TRANSFER_OFFER"
+	EmailTypeTransferWelcome     = "TRANSFER_WELCOME"
+	EmailTypeTransferCompleted   = "TRANSFER_COMPLETED"
+)
+
+// ErrUnknownEmailType is returned when sending an email type that does not exist
+var ErrUnknownEmailType = errors.New("unknown email type")
+
+// qrCodeContentID identifies the check-in QR code image inline in the confirmation email
+const qrCodeContentID = "checkin-qr"
+
 // EmailService handles email operations
 type EmailService struct {
-	config *config.Config
+	config    *config.Config
+	mailer    mailer.Mailer
+	templates *EmailTemplateService
+	events    repository.EventRepository
+	emailLogs repository.EmailLogRepository
+	campaigns repository.EmailCampaignRepository
+	transfers repository.TransferRepository
+	checkIn   *CheckInService
 }
 
 // NewEmailService creates a new email service
-func NewEmailService(cfg *config.Config) *EmailService {
+func NewEmailService(cfg *config.Config, m mailer.Mailer, templates *EmailTemplateService, events repository.EventRepository, emailLogs repository.EmailLogRepository, campaigns repository.EmailCampaignRepository, transfers repository.TransferRepository, checkIn *CheckInService) *EmailService {
 	return &EmailService{
-		config: cfg,
+		config:    cfg,
+		mailer:    m,
+		templates: templates,
+		events:    events,
+		emailLogs: emailLogs,
+		campaigns: campaigns,
+		transfers: transfers,
+		checkIn:   checkIn,
+	}
+}
+
+// Send sends the email of emailType to a participant
+func (s *EmailService) Send(ctx context.Context, emailType string, participant *models.Participant) error {
+	switch emailType {
+	case EmailTypePaymentConfirmation:
+		return s.SendConfirmationEmail(ctx, participant)
+	case EmailTypeWaitlistOffer:
+		return s.SendWaitlistOfferEmail(ctx, participant)
+	case EmailTypePaymentReminder, EmailTypeKitPickup, EmailTypeRaceDayBriefing:
+		return s.SendScheduledEmail(ctx, emailType, participant)
+	case EmailTypePortalLogin:
+		return s.SendPortalLoginEmail(ctx, participant)
+	case EmailTypeCancellation, EmailTypeRefund:
+		return s.SendCancellationEmail(ctx, emailType, participant)
+	case EmailTypeTransferOffer, EmailTypeTransferWelcome, EmailTypeTransferCompleted:
+		return s.SendTransferEmail(ctx, emailType, participant)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownEmailType, emailType)
 	}
 }
 
 // SendConfirmationEmail sends a payment confirmation email to the participant
-func (s *EmailService) SendConfirmationEmail(participant *models.Participant) error {
-	// Build email content
-	subject := fmt.Sprintf("Payment Confirmed - %s", s.config.Event.Name)
-	htmlBody, err := s.buildConfirmationEmailHTML(participant)
+func (s *EmailService) SendConfirmationEmail(ctx context.Context, participant *models.Participant) error {
+	// Event details come from the event the participant registered for
+	event, err := s.events.FindByID(ctx, participant.EventID)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return fmt.Errorf("event %s of participant %s not found", participant.EventID, participant.ID)
+	}
+
+	rendered, err := s.templates.Render(ctx, EmailTypePaymentConfirmation, participant, event)
 	if err != nil {
 		return fmt.Errorf("failed to build email template: %w", err)
 	}
-	
-	plainBody := s.buildConfirmationEmailPlain(participant)
+
+	msg := s.newMessage(participant, rendered)
+	if err := s.attachQRCode(msg, participant); err != nil {
+		return err
+	}
+
+	// Invite to add the race to the participant's calendar
+	if date, err := time.Parse("2006-01-02", event.EventDate); err == nil {
+		msg.Attachments = append(msg.Attachments, s.calendarEvent(participant, event, date).Attachment("event.ics"))
+	} else {
+		utils.EmailLogger.Warning("Not attaching a calendar invite for event %s with date %q: %v", event.ID, event.EventDate, err)
+	}
 
 	// Send email
-	return s.sendEmail(participant.Email, subject, htmlBody, plainBody)
+	return s.mailer.Send(ctx, msg)
 }
 
-// sendEmail sends an email via SMTP
-func (s *EmailService) sendEmail(to, subject, htmlBody, plainBody string) error {
-	// Check if SMTP is configured
-	if s.config.SMTP.Host == "" {
-		return fmt.Errorf("SMTP not configured")
+// SendWaitlistOfferEmail tells a participant promoted from the waitlist that a
+// spot is theirs if they pay before the offer expires
+func (s *EmailService) SendWaitlistOfferEmail(ctx context.Context, participant *models.Participant) error {
+	event, err := s.events.FindByID(ctx, participant.EventID)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return fmt.Errorf("event %s of participant %s not found", participant.EventID, participant.ID)
 	}
+	if participant.OfferExpiresAt == nil {
+		return fmt.Errorf("participant %s has no spot offer", participant.ID)
+	}
+
+	rendered, err := s.templates.Render(ctx, EmailTypeWaitlistOffer, participant, event)
+	if err != nil {
+		return fmt.Errorf("failed to build email template: %w", err)
+	}
+
+	msg := s.newMessage(participant, rendered)
+	if err := s.attachQRCode(msg, participant); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, msg)
+}
 
-	// Build email message
-	from := s.config.SMTP.FromEmail
-	fromName := s.config.SMTP.FromName
+// SendScheduledEmail sends a reminder or announcement of emailType to a participant
+func (s *EmailService) SendScheduledEmail(ctx context.Context, emailType string, participant *models.Participant) error {
+	return s.sendTemplated(ctx, emailType, participant)
+}
+
+// SendPortalLoginEmail sends a participant a magic link to the participant portal
+func (s *EmailService) SendPortalLoginEmail(ctx context.Context, participant *models.Participant) error {
+	return s.sendTemplated(ctx, EmailTypePortalLogin, participant)
+}
 
-	headers := make(map[string]string)
-	headers["From"] = fmt.Sprintf("%s <%s>", fromName, from)
-	headers["To"] = to
-	headers["Subject"] = subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/html; charset=UTF-8"
-	headers["Date"] = time.Now().Format(time.RFC1123Z)
+// SendCancellationEmail tells a participant their registration was cancelled,
+// or that their refund was paid
+func (s *EmailService) SendCancellationEmail(ctx context.Context, emailType string, participant *models.Participant) error {
+	return s.sendTemplated(ctx, emailType, participant)
+}
 
-	// Build message
-	message := ""
-	for k, v := range headers {
-		message += fmt.Sprintf("%s: %s\r\n", k, v)
+// SendTransferEmail sends an email about the latest transfer of a
+// participant's registration: the offer to its recipient, the welcome to its
+// new holder or the notice to its previous one
+func (s *EmailService) SendTransferEmail(ctx context.Context, emailType string, participant *models.Participant) error {
+	transfer, err := s.transfer(ctx, emailType, participant)
+	if err != nil {
+		return err
 	}
-	message += "\r\n" + htmlBody
 
-	// SMTP authentication
-	auth := smtp.PlainAuth("", s.config.SMTP.Username, s.config.SMTP.Password, s.config.SMTP.Host)
+	event, err := s.events.FindByID(ctx, participant.EventID)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return fmt.Errorf("event %s of participant %s not found", participant.EventID, participant.ID)
+	}
 
-	// Send email
-	addr := fmt.Sprintf("%s:%s", s.config.SMTP.Host, s.config.SMTP.Port)
-	err := smtp.SendMail(addr, auth, from, []string{to}, []byte(message))
-	
+	rendered, err := s.templates.Render(ctx, emailType, participant, event)
 	if err != nil {
-		return fmt.Errorf("failed to send email: %w", err)
+		return fmt.Errorf("failed to build email template: %w", err)
 	}
 
-	return nil
+	msg := s.newMessage(participant, rendered)
+	switch emailType {
+	case EmailTypeTransferOffer:
+		msg.To = []mail.Address{{Name: transfer.ToName, Address: transfer.ToEmail}}
+	case EmailTypeTransferCompleted:
+		msg.To = []mail.Address{{Name: transfer.FromName, Address: transfer.FromEmail}}
+	}
+	if err := s.attachQRCode(msg, participant); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, msg)
+}
+
+// Recipient returns the address the email of emailType to a participant goes
+// to, which for transfers is not necessarily the participant's own
+func (s *EmailService) Recipient(ctx context.Context, emailType string, participant *models.Participant) (string, error) {
+	switch emailType {
+	case EmailTypeTransferOffer, EmailTypeTransferCompleted:
+		transfer, err := s.transfer(ctx, emailType, participant)
+		if err != nil {
+			return "", err
+		}
+		if emailType == EmailTypeTransferOffer {
+			return transfer.ToEmail, nil
+		}
+		return transfer.FromEmail, nil
+	default:
+		return participant.Email, nil
+	}
 }
 
-// buildConfirmationEmailHTML creates HTML email template
-func (s *EmailService) buildConfirmationEmailHTML(participant *models.Participant) (string, error) {
-	tmpl := `
-<!DOCTYPE html>
-<html>
-<head>
-    <meta charset="UTF-8">
-    <style>
-        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
-        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
-        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
-        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
-        .info-box { background-color: white; padding: 15px; margin: 20px 0; border-left: 4px solid #FF6B35; }
-        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
-        .highlight { color: #FF6B35; font-weight: bold; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <div class="header">
-            <h1>🎉 Payment Confirmed!</h1>
-        </div>
-        <div class="content">
-            <p>Dear <strong>{{.Name}}</strong>,</p>
-            
-            <p>Great news! We have successfully received your payment for <span class="highlight">{{.EventName}}</span>.</p>
-            
-            <div class="info-box">
-                <h3>Event Details:</h3>
-                <p><strong>Event:</strong> {{.EventName}}</p>
-                <p><strong>Date:</strong> {{.EventDate}}</p>
-                <p><strong>Location:</strong> {{.EventLocation}}</p>
-            </div>
-            
-            <div class="info-box">
-                <h3>Your Registration:</h3>
-                <p><strong>Name:</strong> {{.Name}}</p>
-                <p><strong>Email:</strong> {{.Email}}</p>
-                <p><strong>Phone:</strong> {{.Phone}}</p>
-                {{if .InstagramHandle}}
-                <p><strong>Instagram:</strong> {{.InstagramHandle}}</p>
-                {{end}}
-                <p><strong>Registration Status:</strong> <span class="highlight">CONFIRMED</span></p>
-                <p><strong>Payment Status:</strong> <span class="highlight">PAID</span></p>
-            </div>
-            
-            <p><strong>What's Next?</strong></p>
-            <ul>
-                <li>We'll send you more details about the event as we get closer to the date</li>
-                <li>Please arrive at least 30 minutes before the event starts</li>
-                <li>Bring your ID for registration check-in</li>
-                <li>Get ready to have fun! 🏃‍♂️</li>
-            </ul>
-            
-            <p>If you have any questions, please don't hesitate to contact us.</p>
-            
-            <p>See you at the event!</p>
-            
-            <p><strong>{{.EventTeam}}</strong></p>
-        </div>
-        <div class="footer">
-            <p>This is an automated confirmation email. Please do not reply to this message.</p>
-            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
-        </div>
-    </div>
-</body>
-</html>
-`
-
-	t, err := template.New("confirmation").Parse(tmpl)
+// transfer returns the transfer of a participant's registration an email of
+// emailType is about: the pending one for its offer, or the latest completed
+// one for the others
+func (s *EmailService) transfer(ctx context.Context, emailType string, participant *models.Participant) (*models.RegistrationTransfer, error) {
+	if emailType == EmailTypeTransferOffer {
+		transfer, err := s.transfers.FindPending(ctx, participant.ID)
+		if err != nil {
+			return nil, err
+		}
+		if transfer == nil {
+			return nil, fmt.Errorf("%w: participant %s", ErrNoTransferPending, participant.ID)
+		}
+		return transfer, nil
+	}
+
+	transfers, err := s.transfers.ListByParticipant(ctx, participant.ID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	for i := range transfers {
+		if transfers[i].Status == "COMPLETED" {
+			return &transfers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("participant %s has no completed transfer", participant.ID)
+}
 
-	data := map[string]interface{}{
-		"Name":             participant.Name,
-		"Email":            participant.Email,
-		"Phone":            participant.Phone,
-		"InstagramHandle":  participant.InstagramHandle,
-		"EventName":        s.config.Event.Name,
-		"EventDate":        s.config.Event.Date,
-		"EventLocation":    s.config.Event.Location,
-		"EventTeam":        s.config.SMTP.FromName,
-		"Year":             time.Now().Year(),
+// sendTemplated renders the template of emailType for a participant and sends it
+func (s *EmailService) sendTemplated(ctx context.Context, emailType string, participant *models.Participant) error {
+	event, err := s.events.FindByID(ctx, participant.EventID)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return fmt.Errorf("event %s of participant %s not found", participant.EventID, participant.ID)
 	}
 
-	var buf bytes.Buffer
-	if err := t.Execute(&buf, data); err != nil {
-		return "", err
+	rendered, err := s.templates.Render(ctx, emailType, participant, event)
+	if err != nil {
+		return fmt.Errorf("failed to build email template: %w", err)
 	}
 
-	return buf.String(), nil
+	msg := s.newMessage(participant, rendered)
+	if err := s.attachQRCode(msg, participant); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, msg)
 }
 
-// buildConfirmationEmailPlain creates plain text email template
-func (s *EmailService) buildConfirmationEmailPlain(participant *models.Participant) string {
-	instagram := ""
-	if participant.InstagramHandle != nil {
-		instagram = fmt.Sprintf("\nInstagram: %s", *participant.InstagramHandle)
-	}
-
-	return fmt.Sprintf(`
-🎉 Payment Confirmed!
-
-Dear %s,
-
-Great news! We have successfully received your payment for %s.
-
-EVENT DETAILS:
-- Event: %s
-- Date: %s
-- Location: %s
-
-YOUR REGISTRATION:
-- Name: %s
-- Email: %s
-- Phone: %s%s
-- Registration Status: CONFIRMED
-- Payment Status: PAID
-
-WHAT'S NEXT?
-- We'll send you more details about the event as we get closer to the date
-- Please arrive at least 30 minutes before the event starts
-- Bring your ID for registration check-in
-- Get ready to have fun! 🏃‍♂️
-
-If you have any questions, please don't hesitate to contact us.
-
-See you at the event!
-
-%s
-
----
-This is an automated confirmation email. Please do not reply to this message.
-© %d %s. All rights reserved.
-`,
-		participant.Name,
-		s.config.Event.Name,
-		s.config.Event.Name,
-		s.config.Event.Date,
-		s.config.Event.Location,
-		participant.Name,
-		participant.Email,
-		participant.Phone,
-		instagram,
-		s.config.SMTP.FromName,
-		time.Now().Year(),
-		s.config.Event.Name,
-	)
+// SendCampaignEmail sends the email of a campaign to one of its recipients
+func (s *EmailService) SendCampaignEmail(ctx context.Context, campaignID string, participant *models.Participant) error {
+	campaign, err := s.campaigns.FindByID(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	if campaign == nil {
+		return ErrCampaignNotFound
+	}
+
+	event, err := s.events.FindByID(ctx, campaign.EventID)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return fmt.Errorf("event %s of campaign %s not found", campaign.EventID, campaign.ID)
+	}
+
+	rendered, err := s.templates.RenderTemplate(ctx, campaign.Template(), participant, event)
+	if err != nil {
+		return fmt.Errorf("failed to build email template: %w", err)
+	}
+
+	msg := s.newMessage(participant, rendered)
+	if err := s.attachQRCode(msg, participant); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, msg)
 }
 
-// LogEmail logs email sending attempts to the database
-func (s *EmailService) LogEmail(participantID, recipientEmail, emailType, status, errorMessage string) error {
-	query := `
-		INSERT INTO email_logs (participant_id, recipient_email, email_type, status, error_message, sent_at)
-		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
-	`
+// newMessage creates an email to a participant from the configured sender
+func (s *EmailService) newMessage(participant *models.Participant, rendered *models.RenderedEmail) *mailer.Message {
+	return &mailer.Message{
+		From:    mail.Address{Name: s.config.SMTP.FromName, Address: s.config.SMTP.FromEmail},
+		To:      []mail.Address{{Name: participant.Name, Address: participant.Email}},
+		Subject: rendered.Subject,
+		Text:    rendered.Text,
+		HTML:    rendered.HTML,
+	}
+}
 
-	var errMsg sql.NullString
-	if errorMessage != "" {
-		errMsg = sql.NullString{String: errorMessage, Valid: true}
+// attachQRCode adds the participant's check-in QR code as an inline image if
+// the HTML body shows it
+func (s *EmailService) attachQRCode(msg *mailer.Message, participant *models.Participant) error {
+	if !strings.Contains(msg.HTML, "cid:"+qrCodeContentID) {
+		return nil
 	}
 
-	_, err := database.DB.Exec(query, participantID, recipientEmail, emailType, status, errMsg)
+	qrCode, err := s.checkIn.QRCode(participant)
 	if err != nil {
-		return fmt.Errorf("failed to log email: %w", err)
+		return fmt.Errorf("failed to generate QR code: %w", err)
 	}
 
+	msg.Attachments = append(msg.Attachments, mailer.Attachment{
+		Filename:    "check-in-qr.png",
+		ContentType: "image/png",
+		Data:        qrCode,
+		ContentID:   qrCodeContentID,
+	})
 	return nil
 }
 
-// SendConfirmationEmailAsync sends confirmation email asynchronously
-func (s *EmailService) SendConfirmationEmailAsync(participant *models.Participant) {
-	go func() {
-		utils.EmailLogger.Info("Sending confirmation email to %s (ID: %s)", participant.Email, participant.ID)
-		
-		err := s.SendConfirmationEmail(participant)
-		
-		if err != nil {
-			utils.EmailLogger.Error("Failed to send email to %s: %v", participant.Email, err)
-			// Log failure to database
-			logErr := s.LogEmail(participant.ID, participant.Email, "PAYMENT_CONFIRMATION", "FAILED", err.Error())
-			if logErr != nil {
-				utils.EmailLogger.Error("Failed to log email failure: %v", logErr)
-			}
-		} else {
-			utils.EmailLogger.Info("Successfully sent confirmation email to %s", participant.Email)
-			// Log success to database
-			logErr := s.LogEmail(participant.ID, participant.Email, "PAYMENT_CONFIRMATION", "SUCCESS", "")
-			if logErr != nil {
-				utils.EmailLogger.Error("Failed to log email success: %v", logErr)
-			}
+// calendarEvent describes the race day of an event for a participant's calendar
+func (s *EmailService) calendarEvent(participant *models.Participant, event *models.Event, date time.Time) mailer.CalendarEvent {
+	var paragraphs []string
+	if participant.BibNumber != nil {
+		paragraphs = append(paragraphs, fmt.Sprintf("Bib number: %d", *participant.BibNumber))
+	}
+	if event.Description != "" {
+		paragraphs = append(paragraphs, event.Description)
+	}
+	paragraphs = append(paragraphs, "Bring the check-in QR code from your confirmation email.")
+
+	return mailer.CalendarEvent{
+		UID:         fmt.Sprintf("event-%s@%s", event.ID, senderDomain(s.config.SMTP.FromEmail)),
+		Summary:     event.Name,
+		Location:    event.Location,
+		Description: strings.Join(paragraphs, "\n\n"),
+		Date:        date,
+	}
+}
+
+// senderDomain returns the domain of the sender address, for globally unique IDs
+func senderDomain(from string) string {
+	if _, domain, ok := strings.Cut(from, "@"); ok && domain != "" {
+		return domain
+	}
+	return "localhost"
+}
+
+// formatDeadline formats a deadline for display in emails
+func formatDeadline(t time.Time) string {
+	return t.Format("Monday, 2 January 2006 at 15:04 MST")
+}
+
+// formatAmount formats an amount in the smallest currency unit for display in
+// emails, grouping thousands
+func formatAmount(amount int64, currency string) string {
+	digits := strconv.FormatInt(amount, 10)
+
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
 		}
-	}()
+		b.WriteRune(d)
+	}
+
+	return currency + " " + b.String()
 }
 
-// ValidateSMTPConfig checks if SMTP configuration is valid
-func ValidateSMTPConfig(cfg *config.Config) error {
-	if cfg.SMTP.Host == "" {
-		return fmt.Errorf("SMTP_HOST is not configured")
+// LogEmail logs an email sending attempt to the database, which failed if sendErr is set
+func (s *EmailService) LogEmail(ctx context.Context, entry *models.EmailLog, sendErr error) error {
+	entry.Status = "SUCCESS"
+	if sendErr != nil {
+		errorMessage := sendErr.Error()
+		entry.Status = "FAILED"
+		entry.ErrorMessage = &errorMessage
 	}
-	if cfg.SMTP.Port == "" {
-		return fmt.Errorf("SMTP_PORT is not configured")
+
+	return s.emailLogs.Create(ctx, entry)
+}
+
+// WasSent reports whether an email of emailType was ever sent to a participant successfully
+func (s *EmailService) WasSent(ctx context.Context, participantID, emailType string) (bool, error) {
+	return s.emailLogs.HasSucceeded(ctx, participantID, emailType)
+}
+
+// ValidateSMTPConfig checks if the configuration of the email driver is valid
+func ValidateSMTPConfig(cfg *config.Config) error {
+	switch cfg.SMTP.Driver {
+	case "smtp":
+		if cfg.SMTP.Host == "" {
+			return fmt.Errorf("SMTP_HOST is not configured")
+		}
+		if cfg.SMTP.Port == "" {
+			return fmt.Errorf("SMTP_PORT is not configured")
+		}
+	case "api":
+		if cfg.SMTP.APIKey == "" {
+			return fmt.Errorf("SMTP_API_KEY is not configured")
+		}
 	}
 	if cfg.SMTP.FromEmail == "" {
 		return fmt.Errorf("SMTP_FROM_EMAIL is not configured")
 	}
-	
-	utils.EmailLogger.Info("SMTP configuration validated: %s:%s", cfg.SMTP.Host, cfg.SMTP.Port)
+
+	switch cfg.SMTP.Driver {
+	case "smtp":
+		utils.EmailLogger.Info("SMTP configuration validated: %s:%s (%s)", cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.TLS)
+	case "file":
+		utils.EmailLogger.Info("Emails are written to %s instead of being sent", cfg.SMTP.FileDir)
+	case "memory":
+		utils.EmailLogger.Info("Emails are kept in memory instead of being sent")
+	default:
+		utils.EmailLogger.Info("Email configuration validated: %s driver", cfg.SMTP.Driver)
+	}
+	if !cfg.IsProduction() && (cfg.SMTP.Driver == "smtp" || cfg.SMTP.Driver == "api") {
+		utils.EmailLogger.Warning("Only sending email to %s outside production", strings.Join(cfg.SMTP.AllowedRecipients, ", "))
+	}
 	return nil
 }
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+var (
+	// ErrCampaignNotFound is returned when an email campaign does not exist
+	ErrCampaignNotFound = errors.New("email campaign not found")
+	// ErrNoRecipients is returned when sending a campaign to a segment nobody is in
+	ErrNoRecipients = errors.New("no participants match the campaign segment")
+)
+
+// EmailCampaignService sends one-off emails from admins to a segment of an
+// event's participants, such as everyone unpaid in the 10K.
+//
+// A campaign is a template like the email templates, rendered for each
+// recipient when it is delivered. Recipients are chosen when the campaign is
+// created; it goes to one outbox email each, spread out at the configured rate
+// so a large event doesn't trip the limits of the email provider. The outcome
+// of every delivery attempt is kept in email_logs.
+type EmailCampaignService struct {
+	config       *config.Config
+	templates    *EmailTemplateService
+	events       repository.EventRepository
+	categories   repository.RaceCategoryRepository
+	participants repository.ParticipantRepository
+	campaigns    repository.EmailCampaignRepository
+	emailLogs    repository.EmailLogRepository
+	outbox       *EmailOutbox
+	tx           repository.Transactor
+}
+
+// NewEmailCampaignService creates a new email campaign service
+func NewEmailCampaignService(
+	cfg *config.Config,
+	templates *EmailTemplateService,
+	events repository.EventRepository,
+	categories repository.RaceCategoryRepository,
+	participants repository.ParticipantRepository,
+	campaigns repository.EmailCampaignRepository,
+	emailLogs repository.EmailLogRepository,
+	outbox *EmailOutbox,
+	tx repository.Transactor,
+) *EmailCampaignService {
+	return &EmailCampaignService{
+		config:       cfg,
+		templates:    templates,
+		events:       events,
+		categories:   categories,
+		participants: participants,
+		campaigns:    campaigns,
+		emailLogs:    emailLogs,
+		outbox:       outbox,
+		tx:           tx,
+	}
+}
+
+// List returns the campaigns of an event, newest first
+func (s *EmailCampaignService) List(ctx context.Context, eventID string) ([]models.EmailCampaign, error) {
+	event, err := s.events.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, ErrEventNotFound
+	}
+
+	return s.campaigns.ListByEvent(ctx, eventID)
+}
+
+// Get returns a campaign with how far its delivery has come
+func (s *EmailCampaignService) Get(ctx context.Context, id string) (*models.EmailCampaign, error) {
+	campaign, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign.Delivery, err = s.outbox.CampaignDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// Logs returns one page of the delivery attempts of a campaign, optionally
+// only those with status, newest first
+func (s *EmailCampaignService) Logs(ctx context.Context, id, status string, page, limit int) ([]models.EmailLog, int, error) {
+	if _, err := s.find(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	return s.emailLogs.ListByCampaign(ctx, id, status, page, limit)
+}
+
+// Preview counts the participants a campaign would go to and renders it for
+// the first of them, or for a sample participant when nobody matches
+func (s *EmailCampaignService) Preview(ctx context.Context, eventID string, req models.EmailCampaignRequest) (*models.CampaignPreview, error) {
+	event, err := s.events.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, ErrEventNotFound
+	}
+
+	if err := s.checkSegment(ctx, event, req.Segment); err != nil {
+		return nil, err
+	}
+
+	matches, total, err := s.participants.List(ctx, repository.ParticipantQuery{
+		Filter:    segmentFilter(event.ID, req.Segment),
+		SortBy:    "created_at",
+		SortOrder: repository.SortAsc,
+		Page:      1,
+		Limit:     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	participant := s.templates.sampleParticipant(ctx, event)
+	if len(matches) > 0 {
+		participant = &matches[0]
+	}
+
+	data, err := s.templates.previewData(ctx, participant, event)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := renderEmailTemplate(campaignTemplate(event.ID, req), data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CampaignPreview{Recipients: total, Email: rendered}, nil
+}
+
+// Create stores a campaign and queues it to every participant in its segment
+func (s *EmailCampaignService) Create(ctx context.Context, eventID string, req models.EmailCampaignRequest, adminID string) (*models.EmailCampaign, error) {
+	campaign := &models.EmailCampaign{
+		EventID:   eventID,
+		Subject:   req.Subject,
+		HTMLBody:  req.HTMLBody,
+		TextBody:  req.TextBody,
+		Segment:   req.Segment,
+		CreatedBy: &adminID,
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Locking the event keeps registrations and payments from changing the
+		// segment while its recipients are queued
+		event, err := s.events.FindByIDForUpdate(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			return ErrEventNotFound
+		}
+
+		if err := s.checkSegment(ctx, event, req.Segment); err != nil {
+			return err
+		}
+		if err := s.templates.check(ctx, campaign.Template(), event); err != nil {
+			return err
+		}
+
+		// Collected before queueing, as the transaction can't run other
+		// queries while it streams
+		var recipients []string
+		err = s.participants.Stream(ctx, repository.ParticipantQuery{
+			Filter:    segmentFilter(event.ID, req.Segment),
+			SortBy:    "created_at",
+			SortOrder: repository.SortAsc,
+		}, func(p *models.Participant) error {
+			recipients = append(recipients, p.ID)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(recipients) == 0 {
+			return ErrNoRecipients
+		}
+
+		campaign.Recipients = len(recipients)
+		if err := s.campaigns.Create(ctx, campaign); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		spacing := time.Minute / time.Duration(s.config.Outbox.CampaignRate)
+		for i, participantID := range recipients {
+			if err := s.outbox.EnqueueCampaign(ctx, campaign.ID, participantID, now.Add(time.Duration(i)*spacing)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.EmailLogger.Info("Queued campaign %s of event %s to %d participants", campaign.ID, eventID, campaign.Recipients)
+
+	return campaign, nil
+}
+
+// find returns a campaign or ErrCampaignNotFound
+func (s *EmailCampaignService) find(ctx context.Context, id string) (*models.EmailCampaign, error) {
+	campaign, err := s.campaigns.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign == nil {
+		return nil, ErrCampaignNotFound
+	}
+	return campaign, nil
+}
+
+// checkSegment returns ErrCategoryNotFound if a segment selects a race
+// category that is not part of event
+func (s *EmailCampaignService) checkSegment(ctx context.Context, event *models.Event, segment models.CampaignSegment) error {
+	if segment.CategoryID == "" {
+		return nil
+	}
+
+	category, err := s.categories.FindByID(ctx, segment.CategoryID)
+	if err != nil {
+		return err
+	}
+	if category == nil || category.EventID != event.ID {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// segmentFilter returns the participant filter selecting a segment of an event
+func segmentFilter(eventID string, segment models.CampaignSegment) repository.ParticipantFilter {
+	return repository.ParticipantFilter{
+		EventID:            eventID,
+		PaymentStatus:      segment.PaymentStatus,
+		RegistrationStatus: segment.RegistrationStatus,
+		CategoryID:         segment.CategoryID,
+		CheckedIn:          segment.CheckedIn,
+	}
+}
+
+// campaignTemplate returns the subject and bodies of a campaign request as a template
+func campaignTemplate(eventID string, req models.EmailCampaignRequest) *models.EmailTemplate {
+	return (&models.EmailCampaign{
+		EventID:  eventID,
+		Subject:  req.Subject,
+		HTMLBody: req.HTMLBody,
+		TextBody: req.TextBody,
+	}).Template()
+}
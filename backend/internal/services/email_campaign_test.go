@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// campaignFixture is an event with a 10K and a 5K, the email campaign
+// service and an outbox that delivers to a memory mailer
+type campaignFixture struct {
+	event        *models.Event
+	tenK         *models.RaceCategory
+	fiveK        *models.RaceCategory
+	participants *memory.ParticipantRepository
+	mailer       *mailer.MemoryMailer
+	outbox       *EmailOutbox
+	service      *EmailCampaignService
+	registered   int
+}
+
+func newCampaignFixture(t *testing.T) *campaignFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memory.NewDB()
+	events := memory.NewEventRepository(db)
+	categories := memory.NewRaceCategoryRepository(db)
+	campaigns := memory.NewEmailCampaignRepository(db)
+	emailLogs := memory.NewEmailLogRepository(db)
+	tx := memory.NewTransactor(db)
+	cfg := &config.Config{
+		SMTP:    config.SMTPConfig{FromEmail: "noreply@tautaurun.id", FromName: "Tau-Tau Run"},
+		Outbox:  config.OutboxConfig{Workers: 1, MaxAttempts: 3, RetrySeconds: 60, CampaignRate: 30},
+		CheckIn: config.CheckInConfig{TokenSecret: testCheckInSecret},
+		JWT:     config.JWTConfig{Secret: testJWTSecret},
+		Portal:  config.PortalConfig{URL: "https://tautaurun.id/portal", LinkMinutes: 15, SessionHours: 24},
+	}
+
+	f := &campaignFixture{
+		event:        &models.Event{Name: "City Run", EventDate: "2026-12-06", Location: "Jakarta"},
+		participants: memory.NewParticipantRepository(db),
+		mailer:       mailer.NewMemoryMailer(),
+	}
+	if err := events.Create(ctx, f.event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	f.tenK = &models.RaceCategory{EventID: f.event.ID, Name: "10K", Capacity: 100}
+	f.fiveK = &models.RaceCategory{EventID: f.event.ID, Name: "5K", Capacity: 100}
+	for _, c := range []*models.RaceCategory{f.tenK, f.fiveK} {
+		if err := categories.Create(ctx, c); err != nil {
+			t.Fatalf("failed to create category: %v", err)
+		}
+	}
+
+	checkIn := NewCheckInService(cfg, memory.NewAdminRepository(db), f.participants, tx)
+	templates := NewEmailTemplateService(cfg, checkIn, NewAuthService(cfg), events, categories, f.participants, memory.NewEmailTemplateRepository(db), memory.NewTransferRepository(db), tx)
+	emailService := NewEmailService(cfg, f.mailer, templates, events, emailLogs, campaigns, memory.NewTransferRepository(db), checkIn)
+	f.outbox = NewEmailOutbox(cfg, emailService, f.participants, memory.NewEmailOutboxRepository(db), tx)
+	f.service = NewEmailCampaignService(cfg, templates, events, categories, f.participants, campaigns, emailLogs, f.outbox, tx)
+	return f
+}
+
+// participant registers a runner in a category with a payment status
+func (f *campaignFixture) participant(t *testing.T, category *models.RaceCategory, paymentStatus string) *models.Participant {
+	t.Helper()
+
+	f.registered++
+	p := &models.Participant{
+		EventID:            f.event.ID,
+		CategoryID:         &category.ID,
+		Name:               fmt.Sprintf("Runner %d", f.registered),
+		Email:              fmt.Sprintf("runner%d@example.com", f.registered),
+		Phone:              "+6281234567890",
+		RegistrationStatus: "PENDING",
+	}
+	if err := f.participants.Create(context.Background(), p); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+	if err := f.participants.UpdatePaymentStatus(context.Background(), p, paymentStatus); err != nil {
+		t.Fatalf("failed to set payment status: %v", err)
+	}
+	return p
+}
+
+// unpaid10K is a campaign request to the unpaid runners of the 10K
+func (f *campaignFixture) unpaid10K() models.EmailCampaignRequest {
+	return models.EmailCampaignRequest{
+		Subject:  "Last call, {{.Name}}",
+		HTMLBody: "<p>Pay for the {{.Category}} today</p>",
+		TextBody: "Pay for the {{.Category}} today",
+		Segment:  models.CampaignSegment{PaymentStatus: "UNPAID", CategoryID: f.tenK.ID},
+	}
+}
+
+func TestEmailCampaignServiceCreate(t *testing.T) {
+	f := newCampaignFixture(t)
+	ctx := context.Background()
+
+	first := f.participant(t, f.tenK, "UNPAID")
+	second := f.participant(t, f.tenK, "UNPAID")
+	f.participant(t, f.tenK, "PAID")
+	f.participant(t, f.fiveK, "UNPAID")
+
+	before := time.Now()
+	campaign, err := f.service.Create(ctx, f.event.ID, f.unpaid10K(), "admin-1")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if campaign.Recipients != 2 {
+		t.Errorf("Recipients = %d, want the 2 unpaid 10K runners", campaign.Recipients)
+	}
+
+	emails, _, err := f.outbox.List(ctx, OutboxStatusPending, 1, 10)
+	if err != nil || len(emails) != 2 {
+		t.Fatalf("outbox = %d emails, %v, want 2", len(emails), err)
+	}
+	if emails[0].ParticipantID != first.ID || emails[1].ParticipantID != second.ID {
+		t.Errorf("queued to %s and %s, want the segment in registration order", emails[0].ParticipantID, emails[1].ParticipantID)
+	}
+	for _, e := range emails {
+		if e.EmailType != EmailTypeCampaign || e.CampaignID == nil || *e.CampaignID != campaign.ID {
+			t.Errorf("queued email = %+v, want one of campaign %s", e, campaign.ID)
+		}
+	}
+
+	// 30 emails a minute are spaced two seconds apart
+	if emails[0].NextAttemptAt.Before(before) || emails[0].NextAttemptAt.After(before.Add(time.Second)) {
+		t.Errorf("first email due at %v, want straight away", emails[0].NextAttemptAt)
+	}
+	if gap := emails[1].NextAttemptAt.Sub(emails[0].NextAttemptAt); gap != 2*time.Second {
+		t.Errorf("emails spaced %v apart, want 2s", gap)
+	}
+}
+
+func TestEmailCampaignServiceCreateInvalid(t *testing.T) {
+	f := newCampaignFixture(t)
+	ctx := context.Background()
+	f.participant(t, f.tenK, "PAID")
+
+	otherEvent := f.unpaid10K()
+	otherEvent.Segment.CategoryID = "00000000-0000-4000-8000-000000000000"
+	unknownField := f.unpaid10K()
+	unknownField.Segment = models.CampaignSegment{}
+	unknownField.TextBody = "{{.ShoeSize}}"
+
+	tests := []struct {
+		name    string
+		eventID string
+		req     models.EmailCampaignRequest
+		wantErr error
+	}{
+		{name: "nobody in the segment", eventID: f.event.ID, req: f.unpaid10K(), wantErr: ErrNoRecipients},
+		{name: "unknown category", eventID: f.event.ID, req: otherEvent, wantErr: ErrCategoryNotFound},
+		{name: "unknown event", eventID: "missing", req: f.unpaid10K(), wantErr: ErrEventNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.service.Create(ctx, tt.eventID, tt.req, "admin-1"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	var templateErr *EmailTemplateError
+	if _, err := f.service.Create(ctx, f.event.ID, unknownField, "admin-1"); !errors.As(err, &templateErr) || templateErr.Field != "text_body" {
+		t.Errorf("Create() with an unknown field error = %v, want a text_body template error", err)
+	}
+
+	if campaigns, err := f.service.List(ctx, f.event.ID); err != nil || len(campaigns) != 0 {
+		t.Errorf("List() = %d campaigns, %v, want none stored", len(campaigns), err)
+	}
+	if emails, total, err := f.outbox.List(ctx, OutboxStatusPending, 1, 10); err != nil || total != 0 {
+		t.Errorf("outbox = %d emails, %v, want none queued", len(emails), err)
+	}
+}
+
+func TestEmailCampaignServicePreview(t *testing.T) {
+	f := newCampaignFixture(t)
+	ctx := context.Background()
+
+	preview, err := f.service.Preview(ctx, f.event.ID, f.unpaid10K())
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if preview.Recipients != 0 || preview.Email.Subject != "Last call, Alex Runner" {
+		t.Errorf("Preview() of an empty segment = %d recipients, %q, want the sample participant", preview.Recipients, preview.Email.Subject)
+	}
+
+	first := f.participant(t, f.tenK, "UNPAID")
+	f.participant(t, f.tenK, "UNPAID")
+
+	preview, err = f.service.Preview(ctx, f.event.ID, f.unpaid10K())
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if preview.Recipients != 2 || preview.Email.Subject != "Last call, "+first.Name || preview.Email.Text != "Pay for the 10K today" {
+		t.Errorf("Preview() = %d recipients, %+v, want 2 rendered for the first runner", preview.Recipients, preview.Email)
+	}
+}
+
+func TestEmailCampaignServiceDelivery(t *testing.T) {
+	f := newCampaignFixture(t)
+	ctx := context.Background()
+	p := f.participant(t, f.tenK, "UNPAID")
+
+	campaign, err := f.service.Create(ctx, f.event.ID, f.unpaid10K(), "admin-1")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	got, err := f.service.Get(ctx, campaign.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if *got.Delivery != (models.CampaignDelivery{Pending: 1}) {
+		t.Errorf("Delivery before sending = %+v, want 1 pending", *got.Delivery)
+	}
+
+	if !f.outbox.deliverNext(ctx) {
+		t.Fatal("deliverNext() = false, want the campaign email delivered")
+	}
+
+	sent := f.mailer.Sent()
+	if len(sent) != 1 || sent[0].Message.To[0].Address != p.Email || sent[0].Message.Subject != "Last call, "+p.Name {
+		t.Fatalf("sent = %+v, want the campaign rendered for %s", sent, p.Email)
+	}
+
+	got, err = f.service.Get(ctx, campaign.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if *got.Delivery != (models.CampaignDelivery{Sent: 1}) {
+		t.Errorf("Delivery after sending = %+v, want 1 sent", *got.Delivery)
+	}
+
+	logs, total, err := f.service.Logs(ctx, campaign.ID, "SUCCESS", 1, 10)
+	if err != nil || total != 1 || logs[0].ParticipantID != p.ID || logs[0].CampaignID == nil || *logs[0].CampaignID != campaign.ID {
+		t.Errorf("Logs() = %+v, %v, want the successful delivery", logs, err)
+	}
+
+	if _, err := f.service.Get(ctx, "missing"); !errors.Is(err, ErrCampaignNotFound) {
+		t.Errorf("Get() unknown campaign error = %v, want ErrCampaignNotFound", err)
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// ErrEmailLogNotFound is returned when an email log entry does not exist
+var ErrEmailLogNotFound = errors.New("email log not found")
+
+// EmailLogService lets admins see which emails were sent to participants and
+// send them again, for example when a runner never got their confirmation.
+//
+// Resends are sent right away rather than through the outbox, so the admin
+// sees whether it worked. Each is logged as a new entry linked to the one it
+// resent.
+type EmailLogService struct {
+	emailService *EmailService
+	participants repository.ParticipantRepository
+	emailLogs    repository.EmailLogRepository
+}
+
+// NewEmailLogService creates a new email log service
+func NewEmailLogService(emailService *EmailService, participants repository.ParticipantRepository, emailLogs repository.EmailLogRepository) *EmailLogService {
+	return &EmailLogService{
+		emailService: emailService,
+		participants: participants,
+		emailLogs:    emailLogs,
+	}
+}
+
+// List returns one page of the email logs matching f, newest first
+func (s *EmailLogService) List(ctx context.Context, f repository.EmailLogFilter, page, limit int) ([]models.EmailLog, int, error) {
+	return s.emailLogs.List(ctx, f, page, limit)
+}
+
+// Get returns an email log entry
+func (s *EmailLogService) Get(ctx context.Context, id string) (*models.EmailLog, error) {
+	log, err := s.emailLogs.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if log == nil {
+		return nil, ErrEmailLogNotFound
+	}
+	return log, nil
+}
+
+// Resend sends the email of a log entry to its participant again. The
+// returned entry records the outcome; a failed send is not an error.
+func (s *EmailLogService) Resend(ctx context.Context, id, adminID string) (*models.EmailLog, error) {
+	original, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The campaign was deleted with its event
+	if original.EmailType == EmailTypeCampaign && original.CampaignID == nil {
+		return nil, ErrCampaignNotFound
+	}
+
+	participant, err := s.findParticipant(ctx, original.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.send(ctx, participant, original.EmailType, original.CampaignID, original, adminID)
+}
+
+// ResendType sends a participant the email of emailType, linked to the latest
+// attempt to send it if there was one. The returned entry records the
+// outcome; a failed send is not an error.
+func (s *EmailLogService) ResendType(ctx context.Context, participantID, emailType, adminID string) (*models.EmailLog, error) {
+	// Campaign emails are resent from their log entry, which names the campaign
+	if _, ok := defaultEmailTemplates[emailType]; !ok {
+		return nil, ErrUnknownEmailType
+	}
+
+	participant, err := s.findParticipant(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.emailLogs.FindLatest(ctx, participant.ID, emailType)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.send(ctx, participant, emailType, nil, original, adminID)
+}
+
+// send sends an email to a participant and logs it as a resend of original
+func (s *EmailLogService) send(ctx context.Context, participant *models.Participant, emailType string, campaignID *string, original *models.EmailLog, adminID string) (*models.EmailLog, error) {
+	recipient := participant.Email
+	var err error
+	if campaignID != nil {
+		err = s.emailService.SendCampaignEmail(ctx, *campaignID, participant)
+	} else {
+		var to string
+		if to, err = s.emailService.Recipient(ctx, emailType, participant); err == nil {
+			recipient = to
+			err = s.emailService.Send(ctx, emailType, participant)
+		}
+	}
+	if err != nil {
+		utils.EmailLogger.Error("Failed to resend %s email to %s: %v", emailType, participant.Email, err)
+	}
+
+	entry := &models.EmailLog{
+		ParticipantID:  participant.ID,
+		RecipientEmail: recipient,
+		EmailType:      emailType,
+		CampaignID:     campaignID,
+		ResentBy:       &adminID,
+	}
+	if original != nil {
+		entry.ResendOf = &original.ID
+	}
+
+	if logErr := s.emailService.LogEmail(ctx, entry, err); logErr != nil {
+		return nil, logErr
+	}
+
+	return entry, nil
+}
+
+// findParticipant returns a participant or ErrParticipantNotFound
+func (s *EmailLogService) findParticipant(ctx context.Context, id string) (*models.Participant, error) {
+	participant, err := s.participants.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	return participant, nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// emailLogFixture is a runner of an event whose emails go to a mailer that
+// can be made to fail, with the email log service on an in-memory database
+type emailLogFixture struct {
+	participant *models.Participant
+	emailLogs   *memory.EmailLogRepository
+	mailer      *flakyMailer
+	service     *EmailLogService
+}
+
+func newEmailLogFixture(t *testing.T) *emailLogFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memory.NewDB()
+	events := memory.NewEventRepository(db)
+	participants := memory.NewParticipantRepository(db)
+	tx := memory.NewTransactor(db)
+	cfg := &config.Config{
+		SMTP:    config.SMTPConfig{FromEmail: "noreply@tautaurun.id", FromName: "Tau-Tau Run"},
+		CheckIn: config.CheckInConfig{TokenSecret: testCheckInSecret},
+		JWT:     config.JWTConfig{Secret: testJWTSecret},
+		Portal:  config.PortalConfig{URL: "https://tautaurun.id/portal", LinkMinutes: 15, SessionHours: 24},
+	}
+
+	event := &models.Event{Name: "City Run", EventDate: "2026-12-06", Location: "Jakarta"}
+	if err := events.Create(ctx, event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+
+	f := &emailLogFixture{
+		participant: &models.Participant{EventID: event.ID, Name: "Runner", Email: "runner@example.com", Phone: "+6281234567890"},
+		emailLogs:   memory.NewEmailLogRepository(db),
+		mailer:      &flakyMailer{MemoryMailer: mailer.NewMemoryMailer()},
+	}
+	if err := participants.Create(ctx, f.participant); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+
+	checkIn := NewCheckInService(cfg, memory.NewAdminRepository(db), participants, tx)
+	templates := NewEmailTemplateService(cfg, checkIn, NewAuthService(cfg), events, memory.NewRaceCategoryRepository(db), participants, memory.NewEmailTemplateRepository(db), memory.NewTransferRepository(db), tx)
+	emailService := NewEmailService(cfg, f.mailer, templates, events, f.emailLogs, memory.NewEmailCampaignRepository(db), memory.NewTransferRepository(db), checkIn)
+	f.service = NewEmailLogService(emailService, participants, f.emailLogs)
+	return f
+}
+
+// logged records an earlier attempt to send the participant an email
+func (f *emailLogFixture) logged(t *testing.T, emailType, status string) *models.EmailLog {
+	t.Helper()
+
+	entry := &models.EmailLog{ParticipantID: f.participant.ID, RecipientEmail: f.participant.Email, EmailType: emailType, Status: status}
+	if err := f.emailLogs.Create(context.Background(), entry); err != nil {
+		t.Fatalf("failed to log email: %v", err)
+	}
+	return entry
+}
+
+func TestEmailLogServiceResend(t *testing.T) {
+	f := newEmailLogFixture(t)
+	original := f.logged(t, EmailTypePaymentConfirmation, "FAILED")
+
+	resent, err := f.service.Resend(context.Background(), original.ID, "admin-1")
+	if err != nil {
+		t.Fatalf("Resend() error = %v", err)
+	}
+
+	if resent.Status != "SUCCESS" || resent.EmailType != EmailTypePaymentConfirmation || resent.ID == original.ID {
+		t.Errorf("Resend() = %+v, want a new successful entry", resent)
+	}
+	if resent.ResendOf == nil || *resent.ResendOf != original.ID || resent.ResentBy == nil || *resent.ResentBy != "admin-1" {
+		t.Errorf("Resend() = resend of %v by %v, want %s by admin-1", resent.ResendOf, resent.ResentBy, original.ID)
+	}
+	if sent := f.mailer.Sent(); len(sent) != 1 || sent[0].Message.To[0].Address != f.participant.Email {
+		t.Errorf("sent %d messages, want the confirmation to %s", len(sent), f.participant.Email)
+	}
+	if logs := f.emailLogs.All(); len(logs) != 2 {
+		t.Errorf("email logs = %d entries, want the original and the resend", len(logs))
+	}
+}
+
+func TestEmailLogServiceResendFailed(t *testing.T) {
+	f := newEmailLogFixture(t)
+	original := f.logged(t, EmailTypePaymentConfirmation, "SUCCESS")
+	f.mailer.err = errors.New("connection reset")
+
+	resent, err := f.service.Resend(context.Background(), original.ID, "admin-1")
+	if err != nil {
+		t.Fatalf("Resend() error = %v, want the failure recorded in the entry", err)
+	}
+	if resent.Status != "FAILED" || resent.ErrorMessage == nil || *resent.ErrorMessage != "connection reset" {
+		t.Errorf("Resend() = %s with error %v, want FAILED with the send error", resent.Status, resent.ErrorMessage)
+	}
+}
+
+func TestEmailLogServiceResendType(t *testing.T) {
+	f := newEmailLogFixture(t)
+	ctx := context.Background()
+
+	first, err := f.service.ResendType(ctx, f.participant.ID, EmailTypePaymentConfirmation, "admin-1")
+	if err != nil {
+		t.Fatalf("ResendType() error = %v", err)
+	}
+	if first.Status != "SUCCESS" || first.ResendOf != nil {
+		t.Errorf("ResendType() without an earlier email = %s, resend of %v, want SUCCESS linked to nothing", first.Status, first.ResendOf)
+	}
+
+	second, err := f.service.ResendType(ctx, f.participant.ID, EmailTypePaymentConfirmation, "admin-1")
+	if err != nil {
+		t.Fatalf("ResendType() error = %v", err)
+	}
+	if second.ResendOf == nil || *second.ResendOf != first.ID {
+		t.Errorf("ResendType() = resend of %v, want the latest attempt %s", second.ResendOf, first.ID)
+	}
+}
+
+func TestEmailLogServiceResendErrors(t *testing.T) {
+	f := newEmailLogFixture(t)
+	ctx := context.Background()
+	campaign := f.logged(t, EmailTypeCampaign, "SUCCESS")
+
+	if _, err := f.service.Resend(ctx, "missing", "admin-1"); !errors.Is(err, ErrEmailLogNotFound) {
+		t.Errorf("Resend() unknown log error = %v, want ErrEmailLogNotFound", err)
+	}
+	if _, err := f.service.Resend(ctx, campaign.ID, "admin-1"); !errors.Is(err, ErrCampaignNotFound) {
+		t.Errorf("Resend() of a deleted campaign error = %v, want ErrCampaignNotFound", err)
+	}
+	if _, err := f.service.ResendType(ctx, f.participant.ID, EmailTypeCampaign, "admin-1"); !errors.Is(err, ErrUnknownEmailType) {
+		t.Errorf("ResendType() of a campaign error = %v, want ErrUnknownEmailType", err)
+	}
+	if _, err := f.service.ResendType(ctx, "missing", EmailTypePaymentConfirmation, "admin-1"); !errors.Is(err, ErrParticipantNotFound) {
+		t.Errorf("ResendType() unknown participant error = %v, want ErrParticipantNotFound", err)
+	}
+	if sent := f.mailer.Sent(); len(sent) != 0 {
+		t.Errorf("sent %d messages, want none", len(sent))
+	}
+}
+
+func TestEmailLogServiceList(t *testing.T) {
+	f := newEmailLogFixture(t)
+	f.logged(t, EmailTypePaymentConfirmation, "FAILED")
+	f.logged(t, EmailTypePaymentConfirmation, "SUCCESS")
+	f.logged(t, EmailTypeKitPickup, "SUCCESS")
+
+	logs, total, err := f.service.List(context.Background(), repository.EmailLogFilter{EmailType: EmailTypePaymentConfirmation, Status: "SUCCESS"}, 1, 10)
+	if err != nil || total != 1 || logs[0].EmailType != EmailTypePaymentConfirmation || logs[0].Status != "SUCCESS" {
+		t.Errorf("List() = %+v, %d, %v, want the successful confirmation", logs, total, err)
+	}
+
+	_, total, err = f.service.List(context.Background(), repository.EmailLogFilter{ParticipantID: f.participant.ID}, 1, 10)
+	if err != nil || total != 3 {
+		t.Errorf("List() by participant = %d, %v, want 3", total, err)
+	}
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// Outbox email delivery statuses
+const (
+	OutboxStatusPending = "PENDING"
+	OutboxStatusSending = "SENDING"
+	OutboxStatusSent    = "SENT"
+	OutboxStatusSkipped = "SKIPPED" // Not sent because it no longer applied
+	OutboxStatusDead    = "DEAD"
+)
+
+// Errors returned by EmailOutbox
+var (
+	ErrOutboxEmailNotFound = errors.New("outbox email not found")
+	ErrNotDeadLetter       = errors.New("outbox email is not dead-lettered")
+)
+
+const (
+	// outboxLease is how long a claimed email is hidden from other workers. A
+	// worker that stops mid-send leaves it to be retried once the lease passes.
+	outboxLease = 5 * time.Minute
+
+	// maxOutboxRetryDelay caps the exponential backoff between attempts
+	maxOutboxRetryDelay = 6 * time.Hour
+)
+
+// EmailOutbox queues emails in the database and delivers them with a pool of
+// background workers.
+//
+// Emails are queued in the same transaction as the change that triggers them,
+// so they are neither lost when the process stops nor sent for a change that
+// was rolled back. Failed deliveries are retried with exponential backoff; an
+// email that still fails after the configured number of attempts is
+// dead-lettered until an admin requeues it. Every attempt is also recorded in
+// email_logs.
+type EmailOutbox struct {
+	config       *config.Config
+	emailService *EmailService
+	participants repository.ParticipantRepository
+	outbox       repository.EmailOutboxRepository
+	tx           repository.Transactor
+}
+
+// NewEmailOutbox creates a new email outbox
+func NewEmailOutbox(
+	cfg *config.Config,
+	emailService *EmailService,
+	participants repository.ParticipantRepository,
+	outbox repository.EmailOutboxRepository,
+	tx repository.Transactor,
+) *EmailOutbox {
+	return &EmailOutbox{
+		config:       cfg,
+		emailService: emailService,
+		participants: participants,
+		outbox:       outbox,
+		tx:           tx,
+	}
+}
+
+// Enqueue queues an email of emailType to a participant. Called inside a
+// transaction, the email is only delivered if the transaction commits.
+func (o *EmailOutbox) Enqueue(ctx context.Context, participantID, emailType string) error {
+	email := &models.OutboxEmail{ParticipantID: participantID, EmailType: emailType}
+	if err := o.outbox.Create(ctx, email); err != nil {
+		return err
+	}
+
+	utils.EmailLogger.Info("Queued %s email %s for participant %s", emailType, email.ID, participantID)
+	return nil
+}
+
+// EnqueueCampaign queues the email of a campaign to one of its recipients, to be sent at a given time
+func (o *EmailOutbox) EnqueueCampaign(ctx context.Context, campaignID, participantID string, at time.Time) error {
+	return o.outbox.Create(ctx, &models.OutboxEmail{
+		ParticipantID: participantID,
+		EmailType:     EmailTypeCampaign,
+		CampaignID:    &campaignID,
+		NextAttemptAt: at,
+	})
+}
+
+// CampaignDelivery counts the emails of a campaign by delivery status
+func (o *EmailOutbox) CampaignDelivery(ctx context.Context, campaignID string) (*models.CampaignDelivery, error) {
+	return o.outbox.CountByCampaign(ctx, campaignID)
+}
+
+// List returns one page of outbox emails with the given status, oldest first
+func (o *EmailOutbox) List(ctx context.Context, status string, page, limit int) ([]models.OutboxEmail, int, error) {
+	return o.outbox.List(ctx, status, page, limit)
+}
+
+// Requeue gives a dead-lettered email a fresh set of attempts, starting now
+func (o *EmailOutbox) Requeue(ctx context.Context, id string) (*models.OutboxEmail, error) {
+	var email *models.OutboxEmail
+	err := o.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		email, err = o.outbox.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if email == nil {
+			return ErrOutboxEmailNotFound
+		}
+		if email.Status != OutboxStatusDead {
+			return ErrNotDeadLetter
+		}
+
+		email.Status = OutboxStatusPending
+		email.Attempts = 0
+		email.NextAttemptAt = time.Now()
+		return o.outbox.Update(ctx, email)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return email, nil
+}
+
+// Run delivers due emails with the configured number of workers, each checking
+// for new emails every interval while the outbox is empty. When ctx is
+// cancelled the workers stop claiming emails, and Run returns once the sends
+// already in flight have finished.
+func (o *EmailOutbox) Run(ctx context.Context, interval time.Duration) {
+	var wg sync.WaitGroup
+	for i := 0; i < o.config.Outbox.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			o.work(ctx, interval)
+		}()
+	}
+	wg.Wait()
+}
+
+// work delivers emails one at a time until ctx is cancelled
+func (o *EmailOutbox) work(ctx context.Context, interval time.Duration) {
+	for ctx.Err() == nil {
+		if o.deliverNext(ctx) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+		}
+	}
+}
+
+// deliverNext claims and delivers one due email, reporting whether there was one
+func (o *EmailOutbox) deliverNext(ctx context.Context) bool {
+	emails, err := o.outbox.Claim(ctx, time.Now(), outboxLease, 1)
+	if err != nil {
+		if ctx.Err() == nil {
+			utils.EmailLogger.Error("Failed to claim outbox emails: %v", err)
+		}
+		return false
+	}
+	if len(emails) == 0 {
+		return false
+	}
+
+	// A claimed email is delivered even if shutdown begins during the send
+	o.deliver(context.WithoutCancel(ctx), &emails[0])
+	return true
+}
+
+// deliver sends a claimed email and records the outcome
+func (o *EmailOutbox) deliver(ctx context.Context, email *models.OutboxEmail) {
+	participant, err := o.participants.FindByID(ctx, email.ParticipantID)
+	if err != nil {
+		o.fail(ctx, email, err)
+		return
+	}
+	if participant == nil {
+		o.fail(ctx, email, ErrParticipantNotFound)
+		return
+	}
+
+	// Emails queued before the registration was cancelled, such as a waitlist
+	// offer, no longer apply to it
+	if participant.RegistrationStatus == "CANCELLED" && email.CampaignID == nil &&
+		email.EmailType != EmailTypeCancellation && email.EmailType != EmailTypeRefund {
+		utils.EmailLogger.Info("Not sending %s email to %s, whose registration is cancelled", email.EmailType, participant.Email)
+		o.skip(ctx, email, "registration cancelled")
+		return
+	}
+
+	// Scheduled emails go to each participant once. One sent just before the
+	// process stopped, but not yet marked sent, is not sent again.
+	if _, scheduled := defaultEmailSchedules[email.EmailType]; scheduled {
+		sent, err := o.emailService.WasSent(ctx, participant.ID, email.EmailType)
+		if err != nil {
+			o.fail(ctx, email, err)
+			return
+		}
+		if sent {
+			utils.EmailLogger.Info("Not sending %s email to %s again", email.EmailType, participant.Email)
+			o.skip(ctx, email, "already sent")
+			return
+		}
+	}
+
+	// Transfer emails go to the runner giving or receiving the registration
+	recipient, err := o.emailService.Recipient(ctx, email.EmailType, participant)
+	if errors.Is(err, ErrNoTransferPending) {
+		utils.EmailLogger.Info("Not sending %s email for %s, whose transfer was withdrawn", email.EmailType, participant.Email)
+		o.skip(ctx, email, "transfer withdrawn")
+		return
+	}
+	if err != nil {
+		o.fail(ctx, email, err)
+		return
+	}
+
+	utils.EmailLogger.Info("Sending %s email to %s (ID: %s, attempt %d)", email.EmailType, recipient, participant.ID, email.Attempts)
+
+	if email.CampaignID != nil {
+		err = o.emailService.SendCampaignEmail(ctx, *email.CampaignID, participant)
+	} else {
+		err = o.emailService.Send(ctx, email.EmailType, participant)
+	}
+
+	entry := &models.EmailLog{
+		ParticipantID:  participant.ID,
+		RecipientEmail: recipient,
+		EmailType:      email.EmailType,
+		CampaignID:     email.CampaignID,
+	}
+	if logErr := o.emailService.LogEmail(ctx, entry, err); logErr != nil {
+		utils.EmailLogger.Error("Failed to log email attempt: %v", logErr)
+	}
+
+	if err != nil {
+		utils.EmailLogger.Error("Failed to send email to %s: %v", recipient, err)
+		o.fail(ctx, email, err)
+		return
+	}
+
+	utils.EmailLogger.Info("Successfully sent %s email to %s", email.EmailType, recipient)
+	o.markSent(ctx, email)
+}
+
+// markSent records that an email was delivered
+func (o *EmailOutbox) markSent(ctx context.Context, email *models.OutboxEmail) {
+	now := time.Now()
+	email.Status = OutboxStatusSent
+	email.SentAt = &now
+	email.LastError = nil
+	if err := o.outbox.Update(ctx, email); err != nil {
+		// The lease expiring would send the email again, so this is worth shouting about
+		utils.EmailLogger.Error("Failed to mark outbox email %s as sent: %v", email.ID, err)
+	}
+}
+
+// skip records that an email was not sent because it no longer applied
+func (o *EmailOutbox) skip(ctx context.Context, email *models.OutboxEmail, reason string) {
+	email.Status = OutboxStatusSkipped
+	email.SkipReason = &reason
+	email.LastError = nil
+	if err := o.outbox.Update(ctx, email); err != nil {
+		utils.EmailLogger.Error("Failed to mark outbox email %s as skipped: %v", email.ID, err)
+	}
+}
+
+// fail schedules a retry of a failed email, or dead-letters it once it has no
+// attempts left or can never succeed
+func (o *EmailOutbox) fail(ctx context.Context, email *models.OutboxEmail, cause error) {
+	message := cause.Error()
+	email.LastError = &message
+
+	permanent := errors.Is(cause, ErrUnknownEmailType) || errors.Is(cause, ErrParticipantNotFound) || errors.Is(cause, ErrCampaignNotFound) ||
+		errors.Is(cause, mailer.ErrRejected) || errors.Is(cause, mailer.ErrRecipientNotAllowed)
+	if permanent || email.Attempts >= o.config.Outbox.MaxAttempts {
+		email.Status = OutboxStatusDead
+		utils.EmailLogger.Error("Giving up on outbox email %s after %d attempts: %v", email.ID, email.Attempts, cause)
+	} else {
+		email.Status = OutboxStatusPending
+		email.NextAttemptAt = time.Now().Add(o.retryDelay(email.Attempts))
+		utils.EmailLogger.Warning("Retrying outbox email %s at %s", email.ID, email.NextAttemptAt.Format(time.RFC3339))
+	}
+
+	if err := o.outbox.Update(ctx, email); err != nil {
+		utils.EmailLogger.Error("Failed to update outbox email %s: %v", email.ID, err)
+	}
+}
+
+// retryDelay returns the wait before the attempt after the given one: the
+// configured delay, doubled for every earlier failure, up to maxOutboxRetryDelay
+func (o *EmailOutbox) retryDelay(attempts int) time.Duration {
+	delay := time.Duration(o.config.Outbox.RetrySeconds) * time.Second
+	for i := 1; i < attempts && delay < maxOutboxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxOutboxRetryDelay)
+}
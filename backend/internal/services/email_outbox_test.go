@@ -0,0 +1,368 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// flakyMailer keeps sent messages in memory, or fails with err while it is set
+type flakyMailer struct {
+	*mailer.MemoryMailer
+	err error
+}
+
+func (m *flakyMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	return m.MemoryMailer.Send(ctx, msg)
+}
+
+// outboxFixture is an EmailOutbox delivering through a flakyMailer on an
+// in-memory database
+type outboxFixture struct {
+	config       *config.Config
+	event        *models.Event
+	participants *memory.ParticipantRepository
+	emailLogs    *memory.EmailLogRepository
+	repo         *memory.EmailOutboxRepository
+	tx           *memory.Transactor
+	mailer       *flakyMailer
+	outbox       *EmailOutbox
+}
+
+func newOutboxFixture(t *testing.T) *outboxFixture {
+	t.Helper()
+
+	db := memory.NewDB()
+	events := memory.NewEventRepository(db)
+	f := &outboxFixture{
+		config: &config.Config{
+			SMTP:    config.SMTPConfig{FromEmail: "noreply@tautaurun.id", FromName: "Tau-Tau Run"},
+			Outbox:  config.OutboxConfig{Workers: 1, MaxAttempts: 3, RetrySeconds: 60},
+			CheckIn: config.CheckInConfig{TokenSecret: testCheckInSecret},
+			JWT:     config.JWTConfig{Secret: testJWTSecret},
+			Portal:  config.PortalConfig{URL: "https://tautaurun.id/portal", LinkMinutes: 15, SessionHours: 24},
+		},
+		event:        &models.Event{Name: "City Run", EventDate: "2026-12-06", Location: "Jakarta"},
+		participants: memory.NewParticipantRepository(db),
+		emailLogs:    memory.NewEmailLogRepository(db),
+		repo:         memory.NewEmailOutboxRepository(db),
+		tx:           memory.NewTransactor(db),
+		mailer:       &flakyMailer{MemoryMailer: mailer.NewMemoryMailer()},
+	}
+	if err := events.Create(context.Background(), f.event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+
+	checkIn := NewCheckInService(f.config, memory.NewAdminRepository(db), f.participants, f.tx)
+	templates := NewEmailTemplateService(f.config, checkIn, NewAuthService(f.config), events, memory.NewRaceCategoryRepository(db), f.participants, memory.NewEmailTemplateRepository(db), memory.NewTransferRepository(db), f.tx)
+	emailService := NewEmailService(f.config, f.mailer, templates, events, f.emailLogs, memory.NewEmailCampaignRepository(db), memory.NewTransferRepository(db), checkIn)
+	f.outbox = NewEmailOutbox(f.config, emailService, f.participants, f.repo, f.tx)
+	return f
+}
+
+// participant registers a runner to send email to
+func (f *outboxFixture) participant(t *testing.T) *models.Participant {
+	t.Helper()
+
+	p := &models.Participant{EventID: f.event.ID, Name: "Runner", Email: "runner@example.com", Phone: "+6281234567890", Address: "Jalan Merdeka 1"}
+	if err := f.participants.Create(context.Background(), p); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+	return p
+}
+
+// stored returns the outbox email with id
+func (f *outboxFixture) stored(t *testing.T, id string) *models.OutboxEmail {
+	t.Helper()
+
+	email, err := f.repo.FindByID(context.Background(), id)
+	if err != nil || email == nil {
+		t.Fatalf("failed to find outbox email %s: %v", id, err)
+	}
+	return email
+}
+
+func TestEmailOutboxEnqueueWithinTx(t *testing.T) {
+	f := newOutboxFixture(t)
+	ctx := context.Background()
+	p := f.participant(t)
+
+	rollback := errors.New("rollback")
+	err := f.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := f.outbox.Enqueue(ctx, p.ID, EmailTypePaymentConfirmation); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("WithinTx() error = %v, want %v", err, rollback)
+	}
+
+	emails, total, err := f.outbox.List(ctx, OutboxStatusPending, 1, 10)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != 0 || len(emails) != 0 {
+		t.Errorf("List() after rollback = %d emails, want none", total)
+	}
+
+	if err := f.outbox.Enqueue(ctx, p.ID, EmailTypePaymentConfirmation); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	emails, total, err = f.outbox.List(ctx, OutboxStatusPending, 1, 10)
+	if err != nil || total != 1 {
+		t.Fatalf("List() = %d emails, %v, want 1", total, err)
+	}
+	if emails[0].ParticipantID != p.ID || emails[0].EmailType != EmailTypePaymentConfirmation || emails[0].Attempts != 0 {
+		t.Errorf("queued email = %+v, want an unattempted %s for %s", emails[0], EmailTypePaymentConfirmation, p.ID)
+	}
+}
+
+func TestEmailOutboxDeliverNext(t *testing.T) {
+	f := newOutboxFixture(t)
+	ctx := context.Background()
+	p := f.participant(t)
+
+	if f.outbox.deliverNext(ctx) {
+		t.Fatal("deliverNext() on an empty outbox = true")
+	}
+
+	if err := f.outbox.Enqueue(ctx, p.ID, EmailTypePaymentConfirmation); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	emails, _, err := f.outbox.List(ctx, OutboxStatusPending, 1, 10)
+	if err != nil || len(emails) != 1 {
+		t.Fatalf("List() = %d emails, %v, want 1", len(emails), err)
+	}
+	id := emails[0].ID
+
+	// A failed attempt is logged and retried later, not straight away
+	f.mailer.err = errors.New("connection reset")
+	before := time.Now()
+	if !f.outbox.deliverNext(ctx) {
+		t.Fatal("deliverNext() = false, want an email delivered")
+	}
+
+	email := f.stored(t, id)
+	if email.Status != OutboxStatusPending || email.Attempts != 1 || email.NextAttemptAt.Before(before.Add(time.Minute)) {
+		t.Errorf("email after a failed send = %s, %d attempts, next at %v, want PENDING, 1 attempt, in a minute",
+			email.Status, email.Attempts, email.NextAttemptAt)
+	}
+	if f.outbox.deliverNext(ctx) {
+		t.Error("deliverNext() claimed an email that is not due")
+	}
+
+	// The retry succeeds once it is due
+	f.mailer.err = nil
+	email.NextAttemptAt = time.Now()
+	if err := f.repo.Update(ctx, email); err != nil {
+		t.Fatalf("failed to make email due: %v", err)
+	}
+	if !f.outbox.deliverNext(ctx) {
+		t.Fatal("deliverNext() of the retry = false")
+	}
+
+	email = f.stored(t, id)
+	if email.Status != OutboxStatusSent || email.Attempts != 2 || email.SentAt == nil || email.LastError != nil {
+		t.Errorf("email after the retry = %s, %d attempts, sent at %v, last error %v, want SENT after 2 attempts",
+			email.Status, email.Attempts, email.SentAt, email.LastError)
+	}
+
+	sent := f.mailer.Sent()
+	if len(sent) != 1 || sent[0].Message.To[0].Address != p.Email {
+		t.Errorf("sent %d messages, want one to %s", len(sent), p.Email)
+	}
+
+	logs := f.emailLogs.All()
+	if len(logs) != 2 || logs[0].Status != "FAILED" || logs[1].Status != "SUCCESS" {
+		t.Errorf("email logs = %+v, want FAILED then SUCCESS", logs)
+	}
+}
+
+func TestEmailOutboxDeliverDeletedParticipant(t *testing.T) {
+	f := newOutboxFixture(t)
+	ctx := context.Background()
+
+	email := &models.OutboxEmail{ParticipantID: "00000000-0000-4000-8000-000000000000", EmailType: EmailTypePaymentConfirmation}
+	if err := f.repo.Create(ctx, email); err != nil {
+		t.Fatalf("failed to queue email: %v", err)
+	}
+	f.outbox.deliverNext(ctx)
+
+	if got := f.stored(t, email.ID); got.Status != OutboxStatusDead {
+		t.Errorf("status = %s, want %s", got.Status, OutboxStatusDead)
+	}
+}
+
+func TestEmailOutboxRequeue(t *testing.T) {
+	f := newOutboxFixture(t)
+	ctx := context.Background()
+
+	email := &models.OutboxEmail{ParticipantID: f.participant(t).ID, EmailType: EmailTypePaymentConfirmation}
+	if err := f.repo.Create(ctx, email); err != nil {
+		t.Fatalf("failed to queue email: %v", err)
+	}
+	if _, err := f.outbox.Requeue(ctx, email.ID); !errors.Is(err, ErrNotDeadLetter) {
+		t.Errorf("Requeue() of a pending email error = %v, want %v", err, ErrNotDeadLetter)
+	}
+
+	email.Status = OutboxStatusDead
+	email.Attempts = 3
+	if err := f.repo.Update(ctx, email); err != nil {
+		t.Fatalf("failed to dead-letter email: %v", err)
+	}
+
+	requeued, err := f.outbox.Requeue(ctx, email.ID)
+	if err != nil {
+		t.Fatalf("Requeue() error = %v", err)
+	}
+	if requeued.Status != OutboxStatusPending || requeued.Attempts != 0 || requeued.NextAttemptAt.After(time.Now()) {
+		t.Errorf("Requeue() = %s, %d attempts, next at %v, want PENDING, 0 attempts, due now",
+			requeued.Status, requeued.Attempts, requeued.NextAttemptAt)
+	}
+
+	if _, err := f.outbox.Requeue(ctx, "00000000-0000-4000-8000-000000000000"); !errors.Is(err, ErrOutboxEmailNotFound) {
+		t.Errorf("Requeue() of an unknown email error = %v, want %v", err, ErrOutboxEmailNotFound)
+	}
+}
+
+func TestEmailOutboxRetryDelay(t *testing.T) {
+	tests := []struct {
+		retrySeconds int
+		attempts     int
+		want         time.Duration
+	}{
+		{retrySeconds: 60, attempts: 0, want: time.Minute},
+		{retrySeconds: 60, attempts: 1, want: time.Minute},
+		{retrySeconds: 60, attempts: 2, want: 2 * time.Minute},
+		{retrySeconds: 60, attempts: 3, want: 4 * time.Minute},
+		{retrySeconds: 60, attempts: 9, want: 256 * time.Minute},
+		{retrySeconds: 60, attempts: 10, want: maxOutboxRetryDelay},
+		{retrySeconds: 60, attempts: 1000, want: maxOutboxRetryDelay},
+		{retrySeconds: 24 * 60 * 60, attempts: 1, want: maxOutboxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%ds after attempt %d", tt.retrySeconds, tt.attempts), func(t *testing.T) {
+			f := newOutboxFixture(t)
+			f.config.Outbox.RetrySeconds = tt.retrySeconds
+
+			if got := f.outbox.retryDelay(tt.attempts); got != tt.want {
+				t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailOutboxFail(t *testing.T) {
+	tests := []struct {
+		name      string
+		attempts  int
+		cause     error
+		wantDead  bool
+		wantDelay time.Duration // Until the next attempt, when retried
+	}{
+		{name: "first failure", attempts: 1, cause: errors.New("connection reset"), wantDelay: time.Minute},
+		{name: "second failure", attempts: 2, cause: errors.New("connection reset"), wantDelay: 2 * time.Minute},
+		{name: "last attempt", attempts: 3, cause: errors.New("connection reset"), wantDead: true},
+		{name: "participant deleted", attempts: 1, cause: ErrParticipantNotFound, wantDead: true},
+		{name: "unknown email type", attempts: 1, cause: fmt.Errorf("%w: NEWSLETTER", ErrUnknownEmailType), wantDead: true},
+		{name: "rejected by provider", attempts: 1, cause: fmt.Errorf("send failed: %w", mailer.ErrRejected), wantDead: true},
+		{name: "recipient not allowed", attempts: 1, cause: mailer.ErrRecipientNotAllowed, wantDead: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOutboxFixture(t)
+			ctx := context.Background()
+
+			email := &models.OutboxEmail{ParticipantID: "participant-id", EmailType: EmailTypePaymentConfirmation}
+			if err := f.repo.Create(ctx, email); err != nil {
+				t.Fatalf("failed to queue email: %v", err)
+			}
+			email.Status = OutboxStatusSending
+			email.Attempts = tt.attempts
+
+			before := time.Now()
+			f.outbox.fail(ctx, email, tt.cause)
+
+			stored := f.stored(t, email.ID)
+			if stored.LastError == nil || *stored.LastError != tt.cause.Error() {
+				t.Errorf("last error = %v, want %q", stored.LastError, tt.cause.Error())
+			}
+
+			if tt.wantDead {
+				if stored.Status != OutboxStatusDead {
+					t.Errorf("status = %s, want %s", stored.Status, OutboxStatusDead)
+				}
+				return
+			}
+
+			if stored.Status != OutboxStatusPending {
+				t.Errorf("status = %s, want %s", stored.Status, OutboxStatusPending)
+			}
+			if delay := stored.NextAttemptAt.Sub(before); delay < tt.wantDelay || delay > tt.wantDelay+time.Second {
+				t.Errorf("next attempt in %v, want %v", delay, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestEmailOutboxDeliverScheduledOnce(t *testing.T) {
+	f := newOutboxFixture(t)
+	ctx := context.Background()
+	p := f.participant(t)
+
+	// The kit pickup email went out, but the process stopped before marking it sent
+	if err := f.emailLogs.Create(ctx, &models.EmailLog{ParticipantID: p.ID, RecipientEmail: p.Email, EmailType: EmailTypeKitPickup, Status: "SUCCESS"}); err != nil {
+		t.Fatalf("failed to log email: %v", err)
+	}
+	email := &models.OutboxEmail{ParticipantID: p.ID, EmailType: EmailTypeKitPickup}
+	if err := f.repo.Create(ctx, email); err != nil {
+		t.Fatalf("failed to queue email: %v", err)
+	}
+
+	f.outbox.deliverNext(ctx)
+
+	if got := f.stored(t, email.ID); got.Status != OutboxStatusSkipped || got.SkipReason == nil || *got.SkipReason != "already sent" || got.SentAt != nil {
+		t.Errorf("status = %s, skip reason = %v, sent at = %v, want %s because it was already sent", got.Status, got.SkipReason, got.SentAt, OutboxStatusSkipped)
+	}
+	if sent := f.mailer.Sent(); len(sent) != 0 {
+		t.Errorf("sent %d messages, want the scheduled email not sent twice", len(sent))
+	}
+}
+
+func TestEmailOutboxDeliverCancelled(t *testing.T) {
+	f := newOutboxFixture(t)
+	ctx := context.Background()
+	p := f.participant(t)
+
+	// A spot offer queued just before the runner cancelled
+	offer := &models.OutboxEmail{ParticipantID: p.ID, EmailType: EmailTypeWaitlistOffer}
+	if err := f.repo.Create(ctx, offer); err != nil {
+		t.Fatalf("failed to queue email: %v", err)
+	}
+	p.RegistrationStatus = "CANCELLED"
+	if err := f.participants.UpdateRegistration(ctx, p); err != nil {
+		t.Fatalf("failed to cancel registration: %v", err)
+	}
+
+	f.outbox.deliverNext(ctx)
+
+	if got := f.stored(t, offer.ID); got.Status != OutboxStatusSkipped || got.SkipReason == nil || *got.SkipReason != "registration cancelled" || got.SentAt != nil {
+		t.Errorf("status = %s, skip reason = %v, sent at = %v, want %s because the registration was cancelled", got.Status, got.SkipReason, got.SentAt, OutboxStatusSkipped)
+	}
+	if sent := f.mailer.Sent(); len(sent) != 0 {
+		t.Errorf("sent %d messages, want the offer not sent to a cancelled registration", len(sent))
+	}
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// ErrNotScheduledEmailType is returned when scheduling an email type that is
+// only sent in response to a change, such as a payment
+var ErrNotScheduledEmailType = errors.New("email type is not sent on a schedule")
+
+// scheduledEmailTypes are the email types sent on a schedule, in the order
+// admins see them
+var scheduledEmailTypes = []string{
+	EmailTypePaymentReminder,
+	EmailTypeKitPickup,
+	EmailTypeRaceDayBriefing,
+}
+
+// defaultEmailSchedules apply to events that have not saved a schedule of
+// their own. They are disabled, so nothing is sent until an admin opts in.
+var defaultEmailSchedules = map[string]models.EmailSchedule{
+	EmailTypePaymentReminder: {Days: 3, SendTime: "09:00"},
+	EmailTypeKitPickup:       {Days: 7, SendTime: "09:00"},
+	EmailTypeRaceDayBriefing: {Days: 0, SendTime: "05:00"},
+}
+
+// EmailScheduleService queues reminder and announcement emails when they fall
+// due.
+//
+// Each event configures every scheduled email type separately. Payment
+// reminders go to participants who have not paid some days after registering,
+// race-kit pickup information to confirmed participants some days before the
+// event, and the race-day briefing to confirmed participants on the morning of
+// the event. Times are in the event time zone.
+//
+// Every participant gets each scheduled email once: participants who already
+// have one queued or sent are skipped, and the outbox does not deliver one that
+// email_logs shows was already sent, so restarts never send twice.
+type EmailScheduleService struct {
+	config       *config.Config
+	events       repository.EventRepository
+	participants repository.ParticipantRepository
+	schedules    repository.EmailScheduleRepository
+	outbox       *EmailOutbox
+	tx           repository.Transactor
+	location     *time.Location
+}
+
+// NewEmailScheduleService creates a new email schedule service
+func NewEmailScheduleService(
+	cfg *config.Config,
+	events repository.EventRepository,
+	participants repository.ParticipantRepository,
+	schedules repository.EmailScheduleRepository,
+	outbox *EmailOutbox,
+	tx repository.Transactor,
+) *EmailScheduleService {
+	return &EmailScheduleService{
+		config:       cfg,
+		events:       events,
+		participants: participants,
+		schedules:    schedules,
+		outbox:       outbox,
+		tx:           tx,
+		location:     cfg.EventLocation(),
+	}
+}
+
+// List returns the schedule of every scheduled email type of an event
+func (s *EmailScheduleService) List(ctx context.Context, eventID string) ([]models.EmailSchedule, error) {
+	event, err := s.events.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, ErrEventNotFound
+	}
+
+	saved, err := s.schedules.ListByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]models.EmailSchedule, 0, len(scheduledEmailTypes))
+	for _, emailType := range scheduledEmailTypes {
+		schedules = append(schedules, *s.current(saved, eventID, emailType))
+	}
+
+	return schedules, nil
+}
+
+// Update changes when an event sends an email type. Days and SendTime keep
+// their current values when omitted from req.
+func (s *EmailScheduleService) Update(ctx context.Context, eventID, emailType string, req models.EmailScheduleRequest, adminID string) (*models.EmailSchedule, error) {
+	if err := checkScheduledEmailType(emailType); err != nil {
+		return nil, err
+	}
+
+	var schedule *models.EmailSchedule
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		event, err := s.events.FindByIDForUpdate(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			return ErrEventNotFound
+		}
+
+		saved, err := s.schedules.ListByEvent(ctx, eventID)
+		if err != nil {
+			return err
+		}
+
+		schedule = s.current(saved, eventID, emailType)
+		schedule.Enabled = *req.Enabled
+		if req.Days != nil {
+			schedule.Days = *req.Days
+		}
+		if req.SendTime != "" {
+			schedule.SendTime = req.SendTime
+		}
+		schedule.UpdatedBy = &adminID
+
+		return s.schedules.Save(ctx, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// QueueDue queues every scheduled email that has fallen due, returning how
+// many were queued
+func (s *EmailScheduleService) QueueDue(ctx context.Context) (int, error) {
+	schedules, err := s.schedules.ListEnabled(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().In(s.location)
+	queued := 0
+	for _, schedule := range schedules {
+		n, err := s.queue(ctx, schedule, now)
+		queued += n
+		if err != nil {
+			return queued, err
+		}
+		if n > 0 {
+			utils.EmailLogger.Info("Queued %d %s emails for event %s", n, schedule.EmailType, schedule.EventID)
+		}
+	}
+
+	return queued, nil
+}
+
+// Run queues due emails every interval until ctx is cancelled
+func (s *EmailScheduleService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.QueueDue(ctx); err != nil {
+				utils.EmailLogger.Error("Failed to queue scheduled emails: %v", err)
+			}
+		}
+	}
+}
+
+// queue queues the emails of one schedule that are due at now
+func (s *EmailScheduleService) queue(ctx context.Context, schedule models.EmailSchedule, now time.Time) (int, error) {
+	event, err := s.events.FindByID(ctx, schedule.EventID)
+	if err != nil {
+		return 0, err
+	}
+	if event == nil {
+		return 0, nil // Deleted since the schedules were listed
+	}
+
+	filter, ok := s.recipients(schedule, event, now)
+	if !ok {
+		return 0, nil
+	}
+
+	queued := 0
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		queued = 0
+
+		// Locking the event keeps another server from queueing the same
+		// emails between the lookup and the inserts
+		if _, err := s.events.FindByIDForUpdate(ctx, event.ID); err != nil {
+			return err
+		}
+
+		participants, err := s.participants.ListWithoutEmail(ctx, filter, schedule.EmailType)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range participants {
+			// Waitlisted participants offered a spot already have a payment deadline
+			if schedule.EmailType == EmailTypePaymentReminder && p.OfferExpiresAt != nil {
+				continue
+			}
+			if err := s.outbox.Enqueue(ctx, p.ID, schedule.EmailType); err != nil {
+				return err
+			}
+			queued++
+		}
+		return nil
+	})
+
+	return queued, err
+}
+
+// recipients returns which participants of an event are due the email of a
+// schedule at now, or false if nobody is
+func (s *EmailScheduleService) recipients(schedule models.EmailSchedule, event *models.Event, now time.Time) (repository.ParticipantFilter, bool) {
+	filter := repository.ParticipantFilter{EventID: event.ID, RegistrationStatus: "CONFIRMED"}
+
+	eventDay, err := time.ParseInLocation("2006-01-02", event.EventDate, s.location)
+	if err != nil {
+		utils.EmailLogger.Warning("Not sending scheduled emails for event %s with date %q: %v", event.ID, event.EventDate, err)
+		return filter, false
+	}
+
+	switch schedule.EmailType {
+	case EmailTypePaymentReminder:
+		if !now.Before(eventDay) {
+			return filter, false
+		}
+
+		// Participants who registered on a day are reminded Days later at the
+		// send time, so the latest registration day due is Days before today,
+		// or a day earlier while today's send time is still to come
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location)
+		due := today.AddDate(0, 0, -schedule.Days)
+		if now.Before(s.sendTimeOn(today, schedule.SendTime)) {
+			due = due.AddDate(0, 0, -1)
+		}
+		registeredBefore := due.AddDate(0, 0, 1)
+
+		filter.RegistrationStatus = "PENDING"
+		filter.PaymentStatus = "UNPAID"
+		filter.CreatedBefore = &registeredBefore
+		return filter, true
+
+	case EmailTypeKitPickup:
+		// Sent until race day, so participants who pay late still get it
+		start := s.sendTimeOn(eventDay.AddDate(0, 0, -schedule.Days), schedule.SendTime)
+		return filter, !now.Before(start) && now.Before(eventDay)
+
+	case EmailTypeRaceDayBriefing:
+		start := s.sendTimeOn(eventDay, schedule.SendTime)
+		return filter, !now.Before(start) && now.Before(eventDay.AddDate(0, 0, 1))
+	}
+
+	return filter, false
+}
+
+// sendTimeOn returns the time of day sendTime (HH:MM) on the date of day
+func (s *EmailScheduleService) sendTimeOn(day time.Time, sendTime string) time.Time {
+	t, err := time.Parse("15:04", sendTime)
+	if err != nil {
+		t = time.Time{} // Validated when saved; midnight is the safe fallback
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, s.location)
+}
+
+// current returns the saved schedule of an email type, or the default
+func (s *EmailScheduleService) current(saved []models.EmailSchedule, eventID, emailType string) *models.EmailSchedule {
+	for _, schedule := range saved {
+		if schedule.EmailType == emailType {
+			return &schedule
+		}
+	}
+
+	schedule := defaultEmailSchedules[emailType]
+	schedule.EventID = eventID
+	schedule.EmailType = emailType
+	schedule.IsDefault = true
+	return &schedule
+}
+
+// checkScheduledEmailType returns an error unless emailType is sent on a schedule
+func checkScheduledEmailType(emailType string) error {
+	if _, ok := defaultEmailSchedules[emailType]; ok {
+		return nil
+	}
+	if _, ok := defaultEmailTemplates[emailType]; ok {
+		return ErrNotScheduledEmailType
+	}
+	return ErrUnknownEmailType
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// scheduleFixture is an event in Jakarta with the email schedule service on an
+// in-memory database
+type scheduleFixture struct {
+	event        *models.Event
+	participants *memory.ParticipantRepository
+	outbox       *EmailOutbox
+	service      *EmailScheduleService
+	jakarta      *time.Location
+	registered   int
+}
+
+func newScheduleFixture(t *testing.T, eventDate string) *scheduleFixture {
+	t.Helper()
+
+	db := memory.NewDB()
+	events := memory.NewEventRepository(db)
+	tx := memory.NewTransactor(db)
+	cfg := &config.Config{Events: config.EventsConfig{Timezone: "Asia/Jakarta"}}
+
+	f := &scheduleFixture{
+		event:        &models.Event{Name: "City Run", EventDate: eventDate, Location: "Jakarta"},
+		participants: memory.NewParticipantRepository(db),
+		jakarta:      cfg.EventLocation(),
+	}
+	if err := events.Create(context.Background(), f.event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+
+	emailService := NewEmailService(cfg, mailer.NewMemoryMailer(), nil, events, memory.NewEmailLogRepository(db), nil, nil, nil)
+	f.outbox = NewEmailOutbox(cfg, emailService, f.participants, memory.NewEmailOutboxRepository(db), tx)
+	f.service = NewEmailScheduleService(cfg, events, f.participants, memory.NewEmailScheduleRepository(db), f.outbox, tx)
+	return f
+}
+
+// participant registers a runner with the given statuses
+func (f *scheduleFixture) participant(t *testing.T, registrationStatus, paymentStatus string) *models.Participant {
+	t.Helper()
+
+	f.registered++
+	p := &models.Participant{
+		EventID:            f.event.ID,
+		Name:               "Runner",
+		Email:              fmt.Sprintf("runner%d@example.com", f.registered),
+		Phone:              "+6281234567890",
+		RegistrationStatus: registrationStatus,
+	}
+	if err := f.participants.Create(context.Background(), p); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+	if err := f.participants.UpdatePaymentStatus(context.Background(), p, paymentStatus); err != nil {
+		t.Fatalf("failed to set payment status: %v", err)
+	}
+	return p
+}
+
+// at returns a time in Jakarta
+func (f *scheduleFixture) at(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, f.jakarta)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestEmailScheduleServiceUpdate(t *testing.T) {
+	f := newScheduleFixture(t, "2026-12-06")
+	ctx := context.Background()
+
+	schedules, err := f.service.List(ctx, f.event.ID)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(schedules) != 3 {
+		t.Fatalf("List() = %d schedules, want one per scheduled email type", len(schedules))
+	}
+	for _, s := range schedules {
+		if !s.IsDefault || s.Enabled {
+			t.Errorf("schedule %s = %+v, want the disabled default", s.EmailType, s)
+		}
+	}
+
+	enabled := true
+	updated, err := f.service.Update(ctx, f.event.ID, EmailTypeKitPickup, models.EmailScheduleRequest{Enabled: &enabled, SendTime: "10:30"}, "admin-1")
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if !updated.Enabled || updated.Days != 7 || updated.SendTime != "10:30" || updated.IsDefault {
+		t.Errorf("Update() = %+v, want enabled at 10:30 keeping the default 7 days", updated)
+	}
+
+	days := 2
+	updated, err = f.service.Update(ctx, f.event.ID, EmailTypeKitPickup, models.EmailScheduleRequest{Enabled: &enabled, Days: &days}, "admin-1")
+	if err != nil || updated.Days != 2 || updated.SendTime != "10:30" {
+		t.Errorf("Update() = %+v, %v, want 2 days keeping the saved send time", updated, err)
+	}
+
+	tests := []struct {
+		emailType string
+		eventID   string
+		wantErr   error
+	}{
+		{emailType: EmailTypePaymentConfirmation, eventID: f.event.ID, wantErr: ErrNotScheduledEmailType},
+		{emailType: "NEWSLETTER", eventID: f.event.ID, wantErr: ErrUnknownEmailType},
+		{emailType: EmailTypeKitPickup, eventID: "missing", wantErr: ErrEventNotFound},
+	}
+	for _, tt := range tests {
+		if _, err := f.service.Update(ctx, tt.eventID, tt.emailType, models.EmailScheduleRequest{Enabled: &enabled}, "admin-1"); !errors.Is(err, tt.wantErr) {
+			t.Errorf("Update(%s, %s) error = %v, want %v", tt.eventID, tt.emailType, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEmailScheduleServiceRecipients(t *testing.T) {
+	f := newScheduleFixture(t, "2026-12-06")
+
+	tests := []struct {
+		name     string
+		schedule models.EmailSchedule
+		now      string
+		wantDue  bool
+	}{
+		{name: "kit pickup before its day", schedule: models.EmailSchedule{EmailType: EmailTypeKitPickup, Days: 7, SendTime: "09:00"}, now: "2026-11-29 08:59"},
+		{name: "kit pickup at its send time", schedule: models.EmailSchedule{EmailType: EmailTypeKitPickup, Days: 7, SendTime: "09:00"}, now: "2026-11-29 09:00", wantDue: true},
+		{name: "kit pickup the day before the race", schedule: models.EmailSchedule{EmailType: EmailTypeKitPickup, Days: 7, SendTime: "09:00"}, now: "2026-12-05 23:59", wantDue: true},
+		{name: "kit pickup on race day", schedule: models.EmailSchedule{EmailType: EmailTypeKitPickup, Days: 7, SendTime: "09:00"}, now: "2026-12-06 00:00"},
+		{name: "briefing before its send time", schedule: models.EmailSchedule{EmailType: EmailTypeRaceDayBriefing, SendTime: "05:00"}, now: "2026-12-06 04:59"},
+		{name: "briefing at its send time", schedule: models.EmailSchedule{EmailType: EmailTypeRaceDayBriefing, SendTime: "05:00"}, now: "2026-12-06 05:00", wantDue: true},
+		{name: "briefing after race day", schedule: models.EmailSchedule{EmailType: EmailTypeRaceDayBriefing, SendTime: "05:00"}, now: "2026-12-07 00:00"},
+		{name: "reminder on race day", schedule: models.EmailSchedule{EmailType: EmailTypePaymentReminder, Days: 3, SendTime: "09:00"}, now: "2026-12-06 00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, due := f.service.recipients(tt.schedule, f.event, f.at(tt.now))
+			if due != tt.wantDue {
+				t.Fatalf("recipients() due = %t, want %t", due, tt.wantDue)
+			}
+			if due && (filter.EventID != f.event.ID || filter.RegistrationStatus != "CONFIRMED") {
+				t.Errorf("recipients() = %+v, want the confirmed participants of the event", filter)
+			}
+		})
+	}
+}
+
+func TestEmailScheduleServiceRecipientsPaymentReminder(t *testing.T) {
+	f := newScheduleFixture(t, "2026-12-06")
+	schedule := models.EmailSchedule{EmailType: EmailTypePaymentReminder, Days: 3, SendTime: "09:00"}
+
+	tests := []struct {
+		now                  string
+		wantRegisteredBefore string
+	}{
+		// Runners who registered on the 7th are reminded on the 10th at 09:00
+		{now: "2026-11-10 08:59", wantRegisteredBefore: "2026-11-07 00:00"},
+		{now: "2026-11-10 09:00", wantRegisteredBefore: "2026-11-08 00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.now, func(t *testing.T) {
+			filter, due := f.service.recipients(schedule, f.event, f.at(tt.now))
+			if !due {
+				t.Fatal("recipients() due = false before the event")
+			}
+			if filter.RegistrationStatus != "PENDING" || filter.PaymentStatus != "UNPAID" {
+				t.Errorf("recipients() = %s/%s, want unpaid pending participants", filter.RegistrationStatus, filter.PaymentStatus)
+			}
+			if want := f.at(tt.wantRegisteredBefore); filter.CreatedBefore == nil || !filter.CreatedBefore.Equal(want) {
+				t.Errorf("CreatedBefore = %v, want %v", filter.CreatedBefore, want)
+			}
+		})
+	}
+}
+
+func TestEmailScheduleServiceQueue(t *testing.T) {
+	now := time.Now()
+	f := newScheduleFixture(t, now.AddDate(0, 0, 3).Format("2006-01-02"))
+	ctx := context.Background()
+
+	confirmed := f.participant(t, "CONFIRMED", "PAID")
+	f.participant(t, "PENDING", "UNPAID")
+	f.participant(t, "CANCELLED", "REFUNDED")
+
+	schedule := models.EmailSchedule{EventID: f.event.ID, EmailType: EmailTypeKitPickup, Enabled: true, Days: 7, SendTime: "00:00"}
+	queued, err := f.service.queue(ctx, schedule, now.In(f.jakarta))
+	if err != nil {
+		t.Fatalf("queue() error = %v", err)
+	}
+	if queued != 1 {
+		t.Fatalf("queue() = %d, want the confirmed participant only", queued)
+	}
+
+	emails, _, err := f.outbox.List(ctx, OutboxStatusPending, 1, 10)
+	if err != nil || len(emails) != 1 || emails[0].ParticipantID != confirmed.ID || emails[0].EmailType != EmailTypeKitPickup {
+		t.Errorf("outbox = %+v, %v, want a kit pickup email to the confirmed participant", emails, err)
+	}
+
+	// Participants who already have the email queued are skipped
+	queued, err = f.service.queue(ctx, schedule, now.In(f.jakarta))
+	if err != nil || queued != 0 {
+		t.Errorf("queue() again = %d, %v, want nothing queued twice", queued, err)
+	}
+}
+
+func TestEmailScheduleServiceQueuePaymentReminder(t *testing.T) {
+	now := time.Now()
+	f := newScheduleFixture(t, now.AddDate(0, 1, 0).Format("2006-01-02"))
+	ctx := context.Background()
+
+	unpaid := f.participant(t, "PENDING", "UNPAID")
+	offered := f.participant(t, "PENDING", "UNPAID")
+	offerExpiresAt := now.Add(48 * time.Hour)
+	offered.OfferExpiresAt = &offerExpiresAt
+	if err := f.participants.UpdateRegistration(ctx, offered); err != nil {
+		t.Fatalf("failed to offer spot: %v", err)
+	}
+	f.participant(t, "CONFIRMED", "PAID")
+
+	// Everyone registered today, so reminders with no delay are due tomorrow
+	schedule := models.EmailSchedule{EventID: f.event.ID, EmailType: EmailTypePaymentReminder, Enabled: true, Days: 0, SendTime: "00:00"}
+	queued, err := f.service.queue(ctx, schedule, now.AddDate(0, 0, 1).In(f.jakarta))
+	if err != nil {
+		t.Fatalf("queue() error = %v", err)
+	}
+	if queued != 1 {
+		t.Fatalf("queue() = %d, want only the unpaid participant without an offer", queued)
+	}
+
+	emails, _, err := f.outbox.List(ctx, OutboxStatusPending, 1, 10)
+	if err != nil || len(emails) != 1 || emails[0].ParticipantID != unpaid.ID {
+		t.Errorf("outbox = %+v, %v, want a reminder to %s", emails, err, unpaid.ID)
+	}
+}
@@ -0,0 +1,557 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	htmltemplate "html/template"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+// ErrEmailTemplateVersionNotFound is returned when rolling back to a version that does not exist
+var ErrEmailTemplateVersionNotFound = errors.New("email template version not found")
+
+// sampleParticipantID identifies the made-up participant templates are previewed with
+const sampleParticipantID = "00000000-0000-0000-0000-000000000000"
+
+// Placeholders previews show instead of a participant's check-in token and the
+// tokens of their links, which would let whoever sees the preview check in,
+// log in or accept a transfer as them
+const (
+	previewCheckInToken = "PREVIEW-CHECK-IN-TOKEN"
+	previewLinkToken    = "preview"
+)
+
+// EmailTemplateError is returned when a template does not parse or fails to render
+type EmailTemplateError struct {
+	Field string // subject, html_body or text_body
+	Err   error
+}
+
+func (e *EmailTemplateError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *EmailTemplateError) Unwrap() error {
+	return e.Err
+}
+
+// EmailTemplateData is what email templates are rendered with. Fields that do
+// not apply to a participant are empty.
+type EmailTemplateData struct {
+	Name            string
+	Email           string
+	Phone           string
+	InstagramHandle string
+	Category        string
+	BibNumber       string
+	CheckInToken    string
+	QRCode          htmltemplate.URL // Image source of the check-in QR code
+	Deadline        string           // Payment deadline of a spot offered from the waitlist
+	PortalLink      string           // Magic link that logs the participant in to the participant portal
+
+	CancellationReason string
+	RefundAmount       string // Refund owed or paid for a cancelled registration, e.g. "IDR 150,000"
+
+	TransferFrom    string // Runner the latest transfer of the registration is from
+	TransferTo      string // Runner the latest transfer of the registration is to
+	TransferLink    string // Link the recipient of a pending transfer accepts it with
+	TransferExpires string // When the link of a pending transfer expires
+
+	EventName        string
+	EventDate        string
+	EventLocation    string
+	EventDescription string
+	EventTeam        string
+	Year             int
+}
+
+// EmailTemplateService manages the templates emails are rendered from.
+//
+// Each event can override the built-in template of every email type. Saving a
+// template adds a version and the latest version is the one sent; rolling back
+// copies an older version into a new one, so the history is kept. HTML bodies
+// are html/template templates, so participant data is always escaped.
+type EmailTemplateService struct {
+	config       *config.Config
+	checkIn      *CheckInService
+	auth         *AuthService
+	events       repository.EventRepository
+	categories   repository.RaceCategoryRepository
+	participants repository.ParticipantRepository
+	templates    repository.EmailTemplateRepository
+	transfers    repository.TransferRepository
+	tx           repository.Transactor
+}
+
+// NewEmailTemplateService creates a new email template service
+func NewEmailTemplateService(
+	cfg *config.Config,
+	checkIn *CheckInService,
+	auth *AuthService,
+	events repository.EventRepository,
+	categories repository.RaceCategoryRepository,
+	participants repository.ParticipantRepository,
+	templates repository.EmailTemplateRepository,
+	transfers repository.TransferRepository,
+	tx repository.Transactor,
+) *EmailTemplateService {
+	return &EmailTemplateService{
+		config:       cfg,
+		checkIn:      checkIn,
+		auth:         auth,
+		events:       events,
+		categories:   categories,
+		participants: participants,
+		templates:    templates,
+		transfers:    transfers,
+		tx:           tx,
+	}
+}
+
+// List returns the current template of every email type of an event
+func (s *EmailTemplateService) List(ctx context.Context, eventID string) ([]models.EmailTemplate, error) {
+	if _, err := s.findEvent(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	templates := make([]models.EmailTemplate, 0, len(templatedEmailTypes))
+	for _, emailType := range templatedEmailTypes {
+		t, err := s.current(ctx, eventID, emailType)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+
+	return templates, nil
+}
+
+// Get returns the current template of an email type of an event
+func (s *EmailTemplateService) Get(ctx context.Context, eventID, emailType string) (*models.EmailTemplate, error) {
+	if _, err := s.findEvent(ctx, eventID); err != nil {
+		return nil, err
+	}
+	return s.current(ctx, eventID, emailType)
+}
+
+// Versions returns the saved versions of a template, newest first
+func (s *EmailTemplateService) Versions(ctx context.Context, eventID, emailType string) ([]models.EmailTemplate, error) {
+	if _, ok := defaultEmailTemplates[emailType]; !ok {
+		return nil, ErrUnknownEmailType
+	}
+	if _, err := s.findEvent(ctx, eventID); err != nil {
+		return nil, err
+	}
+	return s.templates.ListVersions(ctx, eventID, emailType)
+}
+
+// Save validates a template and stores it as the new current version
+func (s *EmailTemplateService) Save(ctx context.Context, eventID, emailType string, req models.EmailTemplateRequest, adminID string) (*models.EmailTemplate, error) {
+	t := &models.EmailTemplate{
+		EventID:   eventID,
+		EmailType: emailType,
+		Subject:   req.Subject,
+		HTMLBody:  req.HTMLBody,
+		TextBody:  req.TextBody,
+		CreatedBy: &adminID,
+	}
+	return t, s.create(ctx, t)
+}
+
+// Rollback makes an earlier version current again by saving a copy of it.
+// Version 0 restores the built-in default.
+func (s *EmailTemplateService) Rollback(ctx context.Context, eventID, emailType string, version int, adminID string) (*models.EmailTemplate, error) {
+	defaultTemplate, ok := defaultEmailTemplates[emailType]
+	if !ok {
+		return nil, ErrUnknownEmailType
+	}
+	if _, err := s.findEvent(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	source := &defaultTemplate
+	if version != 0 {
+		var err error
+		source, err = s.templates.FindVersion(ctx, eventID, emailType, version)
+		if err != nil {
+			return nil, err
+		}
+		if source == nil {
+			return nil, ErrEmailTemplateVersionNotFound
+		}
+	}
+
+	t := &models.EmailTemplate{
+		EventID:      eventID,
+		EmailType:    emailType,
+		Subject:      source.Subject,
+		HTMLBody:     source.HTMLBody,
+		TextBody:     source.TextBody,
+		RestoredFrom: &version,
+		CreatedBy:    &adminID,
+	}
+	return t, s.create(ctx, t)
+}
+
+// Preview renders the current template of an email type, with any fields in
+// req replacing the saved ones, for a participant of the event or a sample one
+func (s *EmailTemplateService) Preview(ctx context.Context, eventID, emailType string, req models.EmailTemplatePreviewRequest) (*models.RenderedEmail, error) {
+	event, err := s.findEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.current(ctx, eventID, emailType)
+	if err != nil {
+		return nil, err
+	}
+	if req.Subject != nil {
+		t.Subject = *req.Subject
+	}
+	if req.HTMLBody != nil {
+		t.HTMLBody = *req.HTMLBody
+	}
+	if req.TextBody != nil {
+		t.TextBody = *req.TextBody
+	}
+
+	participant := s.sampleParticipant(ctx, event)
+	if req.ParticipantID != "" {
+		participant, err = s.participants.FindByID(ctx, req.ParticipantID)
+		if err != nil {
+			return nil, err
+		}
+		if participant == nil {
+			return nil, ErrParticipantNotFound
+		}
+		if participant.EventID != event.ID {
+			return nil, ErrWrongEvent
+		}
+	}
+
+	data, err := s.previewData(ctx, participant, event)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderEmailTemplate(t, data)
+}
+
+// Render renders the current template of an email type for a participant
+func (s *EmailTemplateService) Render(ctx context.Context, emailType string, participant *models.Participant, event *models.Event) (*models.RenderedEmail, error) {
+	t, err := s.current(ctx, event.ID, emailType)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.RenderTemplate(ctx, t, participant, event)
+}
+
+// RenderTemplate renders t for a participant
+func (s *EmailTemplateService) RenderTemplate(ctx context.Context, t *models.EmailTemplate, participant *models.Participant, event *models.Event) (*models.RenderedEmail, error) {
+	data, err := s.sendData(ctx, participant, event)
+	if err != nil {
+		return nil, err
+	}
+
+	// The offer of a transfer goes to its recipient and the notice of a
+	// completed one to the previous holder, neither of whom may use the
+	// registration's check-in code or log in as its holder
+	if t.EmailType == EmailTypeTransferOffer || t.EmailType == EmailTypeTransferCompleted {
+		data.CheckInToken = ""
+		data.QRCode = ""
+		data.PortalLink = ""
+	}
+
+	return renderEmailTemplate(t, data)
+}
+
+// current returns the latest version of a template, or the default
+func (s *EmailTemplateService) current(ctx context.Context, eventID, emailType string) (*models.EmailTemplate, error) {
+	defaultTemplate, ok := defaultEmailTemplates[emailType]
+	if !ok {
+		return nil, ErrUnknownEmailType
+	}
+
+	t, err := s.templates.FindLatest(ctx, eventID, emailType)
+	if err != nil {
+		return nil, err
+	}
+	if t != nil {
+		return t, nil
+	}
+
+	defaultTemplate.EventID = eventID
+	defaultTemplate.EmailType = emailType
+	defaultTemplate.IsDefault = true
+	return &defaultTemplate, nil
+}
+
+// create checks that t renders and stores it as the next version
+func (s *EmailTemplateService) create(ctx context.Context, t *models.EmailTemplate) error {
+	if _, ok := defaultEmailTemplates[t.EmailType]; !ok {
+		return ErrUnknownEmailType
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Locking the event serializes saves, which number versions one after another
+		event, err := s.events.FindByIDForUpdate(ctx, t.EventID)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			return ErrEventNotFound
+		}
+
+		if err := s.check(ctx, t, event); err != nil {
+			return err
+		}
+
+		return s.templates.Create(ctx, t)
+	})
+}
+
+// check renders t for a sample participant, which catches references to
+// fields that do not exist before they fail when sending
+func (s *EmailTemplateService) check(ctx context.Context, t *models.EmailTemplate, event *models.Event) error {
+	data, err := s.templateData(ctx, s.sampleParticipant(ctx, event), event)
+	if err != nil {
+		return err
+	}
+
+	_, err = renderEmailTemplate(t, data)
+	return err
+}
+
+// findEvent returns an event or ErrEventNotFound
+func (s *EmailTemplateService) findEvent(ctx context.Context, eventID string) (*models.Event, error) {
+	event, err := s.events.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, ErrEventNotFound
+	}
+	return event, nil
+}
+
+// templateData collects what templates can show about a participant and event.
+// The check-in token and links are placeholders, so previews and checks never
+// hand out a working credential; sendData puts the real ones in.
+func (s *EmailTemplateService) templateData(ctx context.Context, participant *models.Participant, event *models.Event) (EmailTemplateData, error) {
+	data := EmailTemplateData{
+		Name:             participant.Name,
+		Email:            participant.Email,
+		Phone:            participant.Phone,
+		CheckInToken:     previewCheckInToken,
+		QRCode:           htmltemplate.URL("cid:" + qrCodeContentID),
+		PortalLink:       portalLink(s.config, previewLinkToken),
+		EventName:        event.Name,
+		EventDate:        event.EventDate,
+		EventLocation:    event.Location,
+		EventDescription: event.Description,
+		EventTeam:        s.config.SMTP.FromName,
+		Year:             time.Now().Year(),
+	}
+	if participant.InstagramHandle != nil {
+		data.InstagramHandle = *participant.InstagramHandle
+	}
+	if participant.BibNumber != nil {
+		data.BibNumber = strconv.Itoa(*participant.BibNumber)
+	}
+	if participant.OfferExpiresAt != nil {
+		data.Deadline = formatDeadline(*participant.OfferExpiresAt)
+	}
+	if participant.CancellationReason != nil {
+		data.CancellationReason = *participant.CancellationReason
+	}
+	if participant.RefundAmount != nil && *participant.RefundAmount > 0 && participant.RefundCurrency != nil {
+		data.RefundAmount = formatAmount(*participant.RefundAmount, *participant.RefundCurrency)
+	}
+
+	if participant.CategoryID != nil {
+		category, err := s.categories.FindByID(ctx, *participant.CategoryID)
+		if err != nil {
+			return data, err
+		}
+		if category != nil {
+			data.Category = category.Name
+		}
+	}
+
+	transfer, err := s.latestTransfer(ctx, participant)
+	if err != nil {
+		return data, err
+	}
+	if transfer != nil {
+		data.TransferFrom = transfer.FromName
+		data.TransferTo = transfer.ToName
+		if transfer.Status == "PENDING" && time.Now().Before(transfer.ExpiresAt) {
+			data.TransferLink = transferLink(s.config, previewLinkToken)
+			data.TransferExpires = formatDeadline(transfer.ExpiresAt)
+		}
+	}
+
+	return data, nil
+}
+
+// sendData is templateData for an email about to be sent, with the
+// participant's check-in token and working links
+func (s *EmailTemplateService) sendData(ctx context.Context, participant *models.Participant, event *models.Event) (EmailTemplateData, error) {
+	data, err := s.templateData(ctx, participant, event)
+	if err != nil {
+		return data, err
+	}
+
+	data.CheckInToken = s.checkIn.Token(participant)
+
+	// The link is valid from when the email is rendered, shortly before it is sent
+	loginToken, err := s.auth.GenerateParticipantLoginToken(participant.Email)
+	if err != nil {
+		return data, err
+	}
+	data.PortalLink = portalLink(s.config, loginToken)
+
+	if data.TransferLink != "" {
+		transfer, err := s.latestTransfer(ctx, participant)
+		if err != nil {
+			return data, err
+		}
+		transferToken, err := s.auth.GenerateTransferToken(transfer.ID, transfer.ExpiresAt)
+		if err != nil {
+			return data, err
+		}
+		data.TransferLink = transferLink(s.config, transferToken)
+	}
+
+	return data, nil
+}
+
+// latestTransfer returns the latest transfer of a participant's registration,
+// or a made-up pending one for the sample participant
+func (s *EmailTemplateService) latestTransfer(ctx context.Context, participant *models.Participant) (*models.RegistrationTransfer, error) {
+	if participant.ID != sampleParticipantID {
+		return s.transfers.FindLatest(ctx, participant.ID)
+	}
+
+	return &models.RegistrationTransfer{
+		ID:            sampleParticipantID,
+		ParticipantID: participant.ID,
+		EventID:       participant.EventID,
+		Status:        "PENDING",
+		FromName:      participant.Name,
+		FromEmail:     participant.Email,
+		FromPhone:     participant.Phone,
+		ToName:        "Sam Runner",
+		ToEmail:       "sam.runner@example.com",
+		ExpiresAt:     time.Now().Add(time.Duration(s.config.Transfer.LinkHours) * time.Hour),
+	}, nil
+}
+
+// previewData is templateData for showing an email in a browser, which cannot
+// resolve the cid: reference of a sent email, so it embeds a QR code of the
+// placeholder token itself
+func (s *EmailTemplateService) previewData(ctx context.Context, participant *models.Participant, event *models.Event) (EmailTemplateData, error) {
+	data, err := s.templateData(ctx, participant, event)
+	if err != nil {
+		return data, err
+	}
+
+	qrCode, err := qrcode.Encode(data.CheckInToken, qrcode.Medium, qrCodeSize)
+	if err != nil {
+		return data, err
+	}
+	data.QRCode = htmltemplate.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(qrCode))
+
+	return data, nil
+}
+
+// sampleParticipant makes up a paid participant of an event to preview and
+// check templates with, in its first race category if it has any. Every field
+// templates can show is set, including those of a spot offer and a refund.
+func (s *EmailTemplateService) sampleParticipant(ctx context.Context, event *models.Event) *models.Participant {
+	bib := 1234
+	offerExpiresAt := time.Now().Add(time.Duration(s.config.Waitlist.OfferHours) * time.Hour)
+	instagram := "@alex.runs"
+	reason := "Injured and unable to run"
+	refundAmount := s.config.Payment.Amount
+	refundCurrency := s.config.Payment.Currency
+
+	participant := &models.Participant{
+		ID:                 sampleParticipantID,
+		EventID:            event.ID,
+		Name:               "Alex Runner",
+		Email:              "alex.runner@example.com",
+		Phone:              "+6281234567890",
+		InstagramHandle:    &instagram,
+		RegistrationStatus: "CONFIRMED",
+		PaymentStatus:      "PAID",
+		BibNumber:          &bib,
+		OfferExpiresAt:     &offerExpiresAt,
+		CancellationReason: &reason,
+		RefundAmount:       &refundAmount,
+		RefundCurrency:     &refundCurrency,
+	}
+
+	// The sample is only for display, so it goes without a category if they can't be listed
+	if categories, err := s.categories.ListByEvent(ctx, event.ID); err == nil && len(categories) > 0 {
+		participant.CategoryID = &categories[0].ID
+	}
+
+	return participant
+}
+
+// renderEmailTemplate renders the subject and plain text body of t as text
+// templates and its HTML body as an html/template
+func renderEmailTemplate(t *models.EmailTemplate, data EmailTemplateData) (*models.RenderedEmail, error) {
+	subject, err := executeTextTemplate("subject", t.Subject, data)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := executeTextTemplate("text_body", t.TextBody, data)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := htmltemplate.New("html_body").Parse(t.HTMLBody)
+	if err != nil {
+		return nil, &EmailTemplateError{Field: "html_body", Err: err}
+	}
+	var html bytes.Buffer
+	if err := tmpl.Execute(&html, data); err != nil {
+		return nil, &EmailTemplateError{Field: "html_body", Err: err}
+	}
+
+	return &models.RenderedEmail{
+		// A subject is a single line
+		Subject: strings.Join(strings.Fields(subject), " "),
+		HTML:    html.String(),
+		Text:    text,
+	}, nil
+}
+
+// executeTextTemplate parses and renders a text template
+func executeTextTemplate(field, text string, data EmailTemplateData) (string, error) {
+	tmpl, err := template.New(field).Parse(text)
+	if err != nil {
+		return "", &EmailTemplateError{Field: field, Err: err}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", &EmailTemplateError{Field: field, Err: err}
+	}
+
+	return buf.String(), nil
+}
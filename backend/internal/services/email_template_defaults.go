@@ -0,0 +1,1004 @@
+package services
+
+import "github.com/tau-tau-run/backend/internal/models"
+
+// templatedEmailTypes are the email types rendered from templates, in the
+// order admins see them
+var templatedEmailTypes = []string{
+	EmailTypePaymentConfirmation,
+	EmailTypeWaitlistOffer,
+	EmailTypePaymentReminder,
+	EmailTypeKitPickup,
+	EmailTypeRaceDayBriefing,
+	EmailTypePortalLogin,
+	EmailTypeCancellation,
+	EmailTypeRefund,
+	EmailTypeTransferOffer,
+	EmailTypeTransferWelcome,
+	EmailTypeTransferCompleted,
+}
+
+// defaultEmailTemplates are sent for events without a template of their own
+var defaultEmailTemplates = map[string]models.EmailTemplate{
+	EmailTypePaymentConfirmation: {
+		Subject:  "Payment Confirmed - {{.EventName}}",
+		HTMLBody: confirmationEmailHTML,
+		TextBody: confirmationEmailText,
+	},
+	EmailTypeWaitlistOffer: {
+		Subject:  "A Spot Opened Up - {{.EventName}}",
+		HTMLBody: waitlistOfferEmailHTML,
+		TextBody: waitlistOfferEmailText,
+	},
+	EmailTypePaymentReminder: {
+		Subject:  "Complete Your Registration - {{.EventName}}",
+		HTMLBody: paymentReminderEmailHTML,
+		TextBody: paymentReminderEmailText,
+	},
+	EmailTypeKitPickup: {
+		Subject:  "Race Kit Pickup - {{.EventName}}",
+		HTMLBody: kitPickupEmailHTML,
+		TextBody: kitPickupEmailText,
+	},
+	EmailTypeRaceDayBriefing: {
+		Subject:  "Race Day Briefing - {{.EventName}}",
+		HTMLBody: raceDayBriefingEmailHTML,
+		TextBody: raceDayBriefingEmailText,
+	},
+	EmailTypePortalLogin: {
+		Subject:  "Your Login Link - {{.EventName}}",
+		HTMLBody: portalLoginEmailHTML,
+		TextBody: portalLoginEmailText,
+	},
+	EmailTypeCancellation: {
+		Subject:  "Registration Cancelled - {{.EventName}}",
+		HTMLBody: cancellationEmailHTML,
+		TextBody: cancellationEmailText,
+	},
+	EmailTypeRefund: {
+		Subject:  "Your Refund Has Been Sent - {{.EventName}}",
+		HTMLBody: refundEmailHTML,
+		TextBody: refundEmailText,
+	},
+	EmailTypeTransferOffer: {
+		Subject:  "{{.TransferFrom}} Is Giving You Their Spot - {{.EventName}}",
+		HTMLBody: transferOfferEmailHTML,
+		TextBody: transferOfferEmailText,
+	},
+	EmailTypeTransferWelcome: {
+		Subject:  "Welcome to {{.EventName}}",
+		HTMLBody: transferWelcomeEmailHTML,
+		TextBody: transferWelcomeEmailText,
+	},
+	EmailTypeTransferCompleted: {
+		Subject:  "Your Registration Was Transferred - {{.EventName}}",
+		HTMLBody: transferCompletedEmailHTML,
+		TextBody: transferCompletedEmailText,
+	},
+}
+
+const confirmationEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .info-box { background-color: white; padding: 15px; margin: 20px 0; border-left: 4px solid #FF6B35; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+        .highlight { color: #FF6B35; font-weight: bold; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🎉 Payment Confirmed!</h1>
+        </div>
+        <div class="content">
+            <p>Dear <strong>{{.Name}}</strong>,</p>
+            
+            <p>Great news! We have successfully received your payment for <span class="highlight">{{.EventName}}</span>.</p>
+            
+            <div class="info-box">
+                <h3>Event Details:</h3>
+                <p><strong>Event:</strong> {{.EventName}}</p>
+                <p><strong>Date:</strong> {{.EventDate}}</p>
+                <p><strong>Location:</strong> {{.EventLocation}}</p>
+            </div>
+            
+            <div class="info-box">
+                <h3>Your Registration:</h3>
+                <p><strong>Name:</strong> {{.Name}}</p>
+                <p><strong>Email:</strong> {{.Email}}</p>
+                <p><strong>Phone:</strong> {{.Phone}}</p>
+                {{if .InstagramHandle}}
+                <p><strong>Instagram:</strong> {{.InstagramHandle}}</p>
+                {{end}}
+                {{if .Category}}
+                <p><strong>Category:</strong> {{.Category}}</p>
+                {{end}}
+                {{if .BibNumber}}
+                <p><strong>Bib Number:</strong> <span class="highlight">{{.BibNumber}}</span></p>
+                {{end}}
+                <p><strong>Registration Status:</strong> <span class="highlight">CONFIRMED</span></p>
+                <p><strong>Payment Status:</strong> <span class="highlight">PAID</span></p>
+            </div>
+            
+            <div class="info-box" style="text-align: center;">
+                <h3>Your Check-in QR Code:</h3>
+                <p>Show this code when you collect your race kit and when you check in on race day.</p>
+                <img src="{{.QRCode}}" alt="Check-in QR code" width="256" height="256">
+                <p style="font-size: 12px; color: #666;">Code: {{.CheckInToken}}</p>
+            </div>
+            
+            <p><strong>What's Next?</strong></p>
+            <ul>
+                <li>We'll send you more details about the event as we get closer to the date</li>
+                <li>Please arrive at least 30 minutes before the event starts</li>
+                <li>Bring your ID for registration check-in</li>
+                <li>Get ready to have fun! 🏃‍♂️</li>
+            </ul>
+            
+            <p>If you have any questions, please don't hesitate to contact us.</p>
+            
+            <p>See you at the event!</p>
+            
+            <p><strong>{{.EventTeam}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated confirmation email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const confirmationEmailText = `
+🎉 Payment Confirmed!
+
+Dear {{.Name}},
+
+Great news! We have successfully received your payment for {{.EventName}}.
+
+EVENT DETAILS:
+- Event: {{.EventName}}
+- Date: {{.EventDate}}
+- Location: {{.EventLocation}}
+
+YOUR REGISTRATION:
+- Name: {{.Name}}
+- Email: {{.Email}}
+- Phone: {{.Phone}}{{if .InstagramHandle}}
+- Instagram: {{.InstagramHandle}}{{end}}{{if .Category}}
+- Category: {{.Category}}{{end}}{{if .BibNumber}}
+- Bib Number: {{.BibNumber}}{{end}}
+- Registration Status: CONFIRMED
+- Payment Status: PAID
+
+YOUR CHECK-IN CODE:
+{{.CheckInToken}}
+Show this code when you collect your race kit and when you check in on race day.
+
+WHAT'S NEXT?
+- We'll send you more details about the event as we get closer to the date
+- Please arrive at least 30 minutes before the event starts
+- Bring your ID for registration check-in
+- Get ready to have fun! 🏃‍♂️
+
+If you have any questions, please don't hesitate to contact us.
+
+See you at the event!
+
+{{.EventTeam}}
+
+---
+This is an automated confirmation email. Please do not reply to this message.
+© {{.Year}} {{.EventName}}. All rights reserved.
+`
+
+const waitlistOfferEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .info-box { background-color: white; padding: 15px; margin: 20px 0; border-left: 4px solid #FF6B35; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+        .highlight { color: #FF6B35; font-weight: bold; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>A Spot Opened Up!</h1>
+        </div>
+        <div class="content">
+            <p>Dear <strong>{{.Name}}</strong>,</p>
+            
+            <p>Good news! A spot has opened up for <span class="highlight">{{.EventName}}</span> and it is now reserved for you.</p>
+            
+            <div class="info-box">
+                <h3>Event Details:</h3>
+                <p><strong>Event:</strong> {{.EventName}}</p>
+                <p><strong>Date:</strong> {{.EventDate}}</p>
+                <p><strong>Location:</strong> {{.EventLocation}}</p>
+            </div>
+            
+            <div class="info-box">
+                <h3>Complete Your Payment By:</h3>
+                <p class="highlight">{{.Deadline}}</p>
+                <p>If we have not received your payment by then, the spot will be offered to the next person on the waitlist.</p>
+            </div>
+            
+            <p>If you no longer wish to take part, you can simply ignore this email.</p>
+            
+            <p><strong>{{.EventTeam}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const waitlistOfferEmailText = `
+A Spot Opened Up!
+
+Dear {{.Name}},
+
+Good news! A spot has opened up for {{.EventName}} and it is now reserved for you.
+
+EVENT DETAILS:
+- Event: {{.EventName}}
+- Date: {{.EventDate}}
+- Location: {{.EventLocation}}
+
+COMPLETE YOUR PAYMENT BY:
+{{.Deadline}}
+
+If we have not received your payment by then, the spot will be offered to the next person on the waitlist.
+
+If you no longer wish to take part, you can simply ignore this email.
+
+{{.EventTeam}}
+
+---
+This is an automated email. Please do not reply to this message.
+© {{.Year}} {{.EventName}}. All rights reserved.
+`
+
+const paymentReminderEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .info-box { background-color: white; padding: 15px; margin: 20px 0; border-left: 4px solid #FF6B35; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+        .highlight { color: #FF6B35; font-weight: bold; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Complete Your Registration</h1>
+        </div>
+        <div class="content">
+            <p>Dear <strong>{{.Name}}</strong>,</p>
+            
+            <p>Thank you for registering for <span class="highlight">{{.EventName}}</span>. We have not received your payment yet, so your registration is not confirmed.</p>
+            
+            <div class="info-box">
+                <h3>Event Details:</h3>
+                <p><strong>Event:</strong> {{.EventName}}</p>
+                <p><strong>Date:</strong> {{.EventDate}}</p>
+                <p><strong>Location:</strong> {{.EventLocation}}</p>
+            </div>
+            
+            <p>Please complete your payment to secure your spot. Once it has been received, we will send you a confirmation email with your check-in QR code.</p>
+            
+            <p>If you have already paid, thank you! You can ignore this email; it can take a little while for payments to be processed.</p>
+            
+            <p><strong>{{.EventTeam}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const paymentReminderEmailText = `
+Complete Your Registration
+
+Dear {{.Name}},
+
+Thank you for registering for {{.EventName}}. We have not received your payment yet, so your registration is not confirmed.
+
+EVENT DETAILS:
+- Event: {{.EventName}}
+- Date: {{.EventDate}}
+- Location: {{.EventLocation}}
+
+Please complete your payment to secure your spot. Once it has been received, we will send you a confirmation email with your check-in code.
+
+If you have already paid, thank you! You can ignore this email; it can take a little while for payments to be processed.
+
+{{.EventTeam}}
+
+---
+This is an automated email. Please do not reply to this message.
+© {{.Year}} {{.EventName}}. All rights reserved.
+`
+
+const kitPickupEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .info-box { background-color: white; padding: 15px; margin: 20px 0; border-left: 4px solid #FF6B35; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+        .highlight { color: #FF6B35; font-weight: bold; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Race Kit Pickup</h1>
+        </div>
+        <div class="content">
+            <p>Dear <strong>{{.Name}}</strong>,</p>
+            
+            <p><span class="highlight">{{.EventName}}</span> is almost here! Your race kit, with your bib, is ready to be collected.</p>
+            
+            <div class="info-box">
+                <h3>Event Details:</h3>
+                <p><strong>Event:</strong> {{.EventName}}</p>
+                <p><strong>Date:</strong> {{.EventDate}}</p>
+                <p><strong>Location:</strong> {{.EventLocation}}</p>
+            </div>
+            
+            <div class="info-box">
+                <h3>Your Registration:</h3>
+                <p><strong>Name:</strong> {{.Name}}</p>
+                {{if .Category}}
+                <p><strong>Category:</strong> {{.Category}}</p>
+                {{end}}
+                {{if .BibNumber}}
+                <p><strong>Bib Number:</strong> <span class="highlight">{{.BibNumber}}</span></p>
+                {{end}}
+            </div>
+            
+            <div class="info-box" style="text-align: center;">
+                <h3>Your Check-in QR Code:</h3>
+                <p>Show this code at the race-kit pickup desk.</p>
+                <img src="{{.QRCode}}" alt="Check-in QR code" width="256" height="256">
+                <p style="font-size: 12px; color: #666;">Code: {{.CheckInToken}}</p>
+            </div>
+            
+            <p><strong>Please Bring:</strong></p>
+            <ul>
+                <li>This QR code, on your phone or printed</li>
+                <li>Your ID</li>
+                <li>A signed letter and a copy of your ID if someone else collects your kit for you</li>
+            </ul>
+            
+            <p>Pickup times and location will be announced by the organizers. If you have any questions, please don't hesitate to contact us.</p>
+            
+            <p><strong>{{.EventTeam}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const kitPickupEmailText = `
+Race Kit Pickup
+
+Dear {{.Name}},
+
+{{.EventName}} is almost here! Your race kit, with your bib, is ready to be collected.
+
+EVENT DETAILS:
+- Event: {{.EventName}}
+- Date: {{.EventDate}}
+- Location: {{.EventLocation}}
+
+YOUR REGISTRATION:
+- Name: {{.Name}}{{if .Category}}
+- Category: {{.Category}}{{end}}{{if .BibNumber}}
+- Bib Number: {{.BibNumber}}{{end}}
+
+YOUR CHECK-IN CODE:
+{{.CheckInToken}}
+Show this code at the race-kit pickup desk.
+
+PLEASE BRING:
+- This code, on your phone or printed
+- Your ID
+- A signed letter and a copy of your ID if someone else collects your kit for you
+
+Pickup times and location will be announced by the organizers. If you have any questions, please don't hesitate to contact us.
+
+{{.EventTeam}}
+
+---
+This is an automated email. Please do not reply to this message.
+© {{.Year}} {{.EventName}}. All rights reserved.
+`
+
+const raceDayBriefingEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .info-box { background-color: white; padding: 15px; margin: 20px 0; border-left: 4px solid #FF6B35; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+        .highlight { color: #FF6B35; font-weight: bold; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🏃 It's Race Day!</h1>
+        </div>
+        <div class="content">
+            <p>Good morning <strong>{{.Name}}</strong>,</p>
+            
+            <p>Today is the day! Here is everything you need for <span class="highlight">{{.EventName}}</span>.</p>
+            
+            <div class="info-box">
+                <h3>Event Details:</h3>
+                <p><strong>Event:</strong> {{.EventName}}</p>
+                <p><strong>Date:</strong> {{.EventDate}}</p>
+                <p><strong>Location:</strong> {{.EventLocation}}</p>
+            </div>
+            
+            <div class="info-box">
+                <h3>Your Registration:</h3>
+                <p><strong>Name:</strong> {{.Name}}</p>
+                {{if .Category}}
+                <p><strong>Category:</strong> {{.Category}}</p>
+                {{end}}
+                {{if .BibNumber}}
+                <p><strong>Bib Number:</strong> <span class="highlight">{{.BibNumber}}</span></p>
+                {{end}}
+            </div>
+            
+            <div class="info-box" style="text-align: center;">
+                <h3>Your Check-in QR Code:</h3>
+                <p>Show this code at check-in before the start.</p>
+                <img src="{{.QRCode}}" alt="Check-in QR code" width="256" height="256">
+                <p style="font-size: 12px; color: #666;">Code: {{.CheckInToken}}</p>
+            </div>
+            
+            <p><strong>Before You Start:</strong></p>
+            <ul>
+                <li>Arrive at least 30 minutes before the start</li>
+                <li>Pin your bib to the front of your shirt</li>
+                <li>Drink water and warm up</li>
+                <li>Have fun! 🏃‍♂️</li>
+            </ul>
+            
+            <p>See you at the start line!</p>
+            
+            <p><strong>{{.EventTeam}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const raceDayBriefingEmailText = `
+It's Race Day!
+
+Good morning {{.Name}},
+
+Today is the day! Here is everything you need for {{.EventName}}.
+
+EVENT DETAILS:
+- Event: {{.EventName}}
+- Date: {{.EventDate}}
+- Location: {{.EventLocation}}
+
+YOUR REGISTRATION:
+- Name: {{.Name}}{{if .Category}}
+- Category: {{.Category}}{{end}}{{if .BibNumber}}
+- Bib Number: {{.BibNumber}}{{end}}
+
+YOUR CHECK-IN CODE:
+{{.CheckInToken}}
+Show this code at check-in before the start.
+
+BEFORE YOU START:
+- Arrive at least 30 minutes before the start
+- Pin your bib to the front of your shirt
+- Drink water and warm up
+- Have fun! 🏃‍♂️
+
+See you at the start line!
+
+{{.EventTeam}}
+
+---
+This is an automated email. Please do not reply to this message.
+© {{.Year}} {{.EventName}}. All rights reserved.
+`
+
+const portalLoginEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .button { display: inline-block; background-color: #FF6B35; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; font-weight: bold; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔑 Your Login Link</h1>
+        </div>
+        <div class="content">
+            <p>Hi <strong>{{.Name}}</strong>,</p>
+            
+            <p>Use the button below to see your registration for {{.EventName}}, check your payment and bib number, and update your contact details.</p>
+            
+            <p style="text-align: center;">
+                <a class="button" href="{{.PortalLink}}">View My Registration</a>
+            </p>
+            
+            <p>The link can only be used for a short time. If it has expired, request a new one on the login page.</p>
+            
+            <p>If you did not ask to log in, you can ignore this email.</p>
+            
+            <p><strong>{{.EventTeam}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const portalLoginEmailText = `
+Your Login Link
+
+Hi {{.Name}},
+
+Open the link below to see your registration for {{.EventName}}, check your payment and bib number, and update your contact details:
+
+{{.PortalLink}}
+
+The link can only be used for a short time. If it has expired, request a new one on the login page.
+
+If you did not ask to log in, you can ignore this email.
+
+{{.EventTeam}}
+
+---
+This is an automated email. Please do not reply to this message.
+© {{.Year}} {{.EventName}}. All rights reserved.
+`
+
+const cancellationEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .info-box { background-color: white; padding: 15px; margin: 20px 0; border-left: 4px solid #FF6B35; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+        .highlight { color: #FF6B35; font-weight: bold; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Registration Cancelled</h1>
+        </div>
+        <div class="content">
+            <p>Dear <strong>{{.Name}}</strong>,</p>
+            
+            <p>Your registration for <span class="highlight">{{.EventName}}</span> has been cancelled.</p>
+            
+            <div class="info-box">
+                <h3>Cancellation Details:</h3>
+                <p><strong>Event:</strong> {{.EventName}}</p>
+                <p><strong>Date:</strong> {{.EventDate}}</p>
+                {{if .Category}}
+                <p><strong>Category:</strong> {{.Category}}</p>
+                {{end}}
+                {{if .CancellationReason}}
+                <p><strong>Reason:</strong> {{.CancellationReason}}</p>
+                {{end}}
+            </div>
+            
+            {{if .RefundAmount}}
+            <p>Under the event's refund policy you will receive a refund of <span class="highlight">{{.RefundAmount}}</span>. We will email you again once it has been sent.</p>
+            {{else}}
+            <p>No refund is due for this registration.</p>
+            {{end}}
+            
+            <p>We're sorry you can't make it, and hope to see you at a future race!</p>
+            
+            <p>If you did not ask to cancel, please contact us as soon as possible.</p>
+            
+            <p><strong>{{.EventTeam}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const cancellationEmailText = `
+Registration Cancelled
+
+Dear {{.Name}},
+
+Your registration for {{.EventName}} has been cancelled.
+
+CANCELLATION DETAILS:
+- Event: {{.EventName}}
+- Date: {{.EventDate}}{{if .Category}}
+- Category: {{.Category}}{{end}}{{if .CancellationReason}}
+- Reason: {{.CancellationReason}}{{end}}
+
+{{if .RefundAmount}}Under the event's refund policy you will receive a refund of {{.RefundAmount}}. We will email you again once it has been sent.{{else}}No refund is due for this registration.{{end}}
+
+We're sorry you can't make it, and hope to see you at a future race!
+
+If you did not ask to cancel, please contact us as soon as possible.
+
+{{.EventTeam}}
+
+---
+This is an automated email. Please do not reply to this message.
+© {{.Year}} {{.EventName}}. All rights reserved.
+`
+
+const refundEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+        .highlight { color: #FF6B35; font-weight: bold; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Your Refund Has Been Sent</h1>
+        </div>
+        <div class="content">
+            <p>Dear <strong>{{.Name}}</strong>,</p>
+            
+            <p>We have sent your refund of <span class="highlight">{{.RefundAmount}}</span> for your cancelled registration for {{.EventName}}.</p>
+            
+            <p>Depending on your bank or payment method, it may take a few days to reach your account.</p>
+            
+            <p><strong>{{.EventTeam}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const refundEmailText = `
+Your Refund Has Been Sent
+
+Dear {{.Name}},
+
+We have sent your refund of {{.RefundAmount}} for your cancelled registration for {{.EventName}}.
+
+Depending on your bank or payment method, it may take a few days to reach your account.
+
+{{.EventTeam}}
+
+---
+This is an automated email. Please do not reply to this message.
+© {{.Year}} {{.EventName}}. All rights reserved.
+`
+
+const transferOfferEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .info-box { background-color: white; padding: 15px; margin: 20px 0; border-left: 4px solid #FF6B35; }
+        .button { display: inline-block; background-color: #FF6B35; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; font-weight: bold; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+        .highlight { color: #FF6B35; font-weight: bold; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🎁 A Race Spot for You</h1>
+        </div>
+        <div class="content">
+            <p>Hi <strong>{{.TransferTo}}</strong>,</p>
+            
+            <p>{{.TransferFrom}} can't make it to <span class="highlight">{{.EventName}}</span> and is giving you their paid registration.</p>
+            
+            <div class="info-box">
+                <h3>Event Details:</h3>
+                <p><strong>Event:</strong> {{.EventName}}</p>
+                <p><strong>Date:</strong> {{.EventDate}}</p>
+                <p><strong>Location:</strong> {{.EventLocation}}</p>
+                {{if .Category}}
+                <p><strong>Category:</strong> {{.Category}}</p>
+                {{end}}
+            </div>
+            
+            {{if .TransferLink}}
+            <p>To take the spot, fill in your details using the button below before <span class="highlight">{{.TransferExpires}}</span>. Nothing more needs to be paid.</p>
+            
+            <p style="text-align: center;">
+                <a class="button" href="{{.TransferLink}}">Accept the Registration</a>
+            </p>
+            {{end}}
+            
+            <p>If you don't want the spot, you can ignore this email.</p>
+            
+            <p><strong>{{.EventTeam}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const transferOfferEmailText = `
+A Race Spot for You
+
+Hi {{.TransferTo}},
+
+{{.TransferFrom}} can't make it to {{.EventName}} and is giving you their paid registration.
+
+EVENT DETAILS:
+- Event: {{.EventName}}
+- Date: {{.EventDate}}
+- Location: {{.EventLocation}}{{if .Category}}
+- Category: {{.Category}}{{end}}
+{{if .TransferLink}}
+To take the spot, fill in your details at the link below before {{.TransferExpires}}. Nothing more needs to be paid.
+
+{{.TransferLink}}
+{{end}}
+If you don't want the spot, you can ignore this email.
+
+{{.EventTeam}}
+
+---
+This is an automated email. Please do not reply to this message.
+© {{.Year}} {{.EventName}}. All rights reserved.
+`
+
+const transferWelcomeEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .info-box { background-color: white; padding: 15px; margin: 20px 0; border-left: 4px solid #FF6B35; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+        .highlight { color: #FF6B35; font-weight: bold; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🎉 Welcome to the Race!</h1>
+        </div>
+        <div class="content">
+            <p>Dear <strong>{{.Name}}</strong>,</p>
+            
+            <p>The registration {{.TransferFrom}} gave you for <span class="highlight">{{.EventName}}</span> is now yours, and it's already paid.</p>
+            
+            <div class="info-box">
+                <h3>Event Details:</h3>
+                <p><strong>Event:</strong> {{.EventName}}</p>
+                <p><strong>Date:</strong> {{.EventDate}}</p>
+                <p><strong>Location:</strong> {{.EventLocation}}</p>
+            </div>
+            
+            <div class="info-box">
+                <h3>Your Registration:</h3>
+                <p><strong>Name:</strong> {{.Name}}</p>
+                <p><strong>Email:</strong> {{.Email}}</p>
+                <p><strong>Phone:</strong> {{.Phone}}</p>
+                {{if .Category}}
+                <p><strong>Category:</strong> {{.Category}}</p>
+                {{end}}
+                {{if .BibNumber}}
+                <p><strong>Bib Number:</strong> <span class="highlight">{{.BibNumber}}</span></p>
+                {{end}}
+                <p><strong>Payment Status:</strong> <span class="highlight">PAID</span></p>
+            </div>
+            
+            <div class="info-box" style="text-align: center;">
+                <h3>Your Check-in QR Code:</h3>
+                <p>Show this code when you collect your race kit and when you check in on race day.</p>
+                <img src="{{.QRCode}}" alt="Check-in QR code" width="256" height="256">
+                <p style="font-size: 12px; color: #666;">Code: {{.CheckInToken}}</p>
+            </div>
+            
+            <p>You can see your registration and update your contact details in the <a href="{{.PortalLink}}">participant portal</a>.</p>
+            
+            <p>See you at the event!</p>
+            
+            <p><strong>{{.EventTeam}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const transferWelcomeEmailText = `
+Welcome to the Race!
+
+Dear {{.Name}},
+
+The registration {{.TransferFrom}} gave you for {{.EventName}} is now yours, and it's already paid.
+
+EVENT DETAILS:
+- Event: {{.EventName}}
+- Date: {{.EventDate}}
+- Location: {{.EventLocation}}
+
+YOUR REGISTRATION:
+- Name: {{.Name}}
+- Email: {{.Email}}
+- Phone: {{.Phone}}{{if .Category}}
+- Category: {{.Category}}{{end}}{{if .BibNumber}}
+- Bib Number: {{.BibNumber}}{{end}}
+- Payment Status: PAID
+
+YOUR CHECK-IN CODE:
+{{.CheckInToken}}
+Show this code when you collect your race kit and when you check in on race day.
+
+See your registration and update your contact details in the participant portal:
+{{.PortalLink}}
+
+See you at the event!
+
+{{.EventTeam}}
+
+---
+This is an automated email. Please do not reply to this message.
+© {{.Year}} {{.EventName}}. All rights reserved.
+`
+
+const transferCompletedEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF6B35; color: white; padding: 20px; text-align: center; border-radius: 5px 5px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border: 1px solid #ddd; border-radius: 0 0 5px 5px; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+        .highlight { color: #FF6B35; font-weight: bold; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Registration Transferred</h1>
+        </div>
+        <div class="content">
+            <p>Dear <strong>{{.TransferFrom}}</strong>,</p>
+            
+            <p>{{.TransferTo}} has accepted your registration for <span class="highlight">{{.EventName}}</span>, which is now theirs. Your bib number and check-in code now belong to them.</p>
+            
+            <p>Thank you for passing your spot on, and we hope to see you at a future race!</p>
+            
+            <p>If you did not ask to transfer your registration, please contact us as soon as possible.</p>
+            
+            <p><strong>{{.EventTeam}}</strong></p>
+        </div>
+        <div class="footer">
+            <p>This is an automated email. Please do not reply to this message.</p>
+            <p>&copy; {{.Year}} {{.EventName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const transferCompletedEmailText = `
+Registration Transferred
+
+Dear {{.TransferFrom}},
+
+{{.TransferTo}} has accepted your registration for {{.EventName}}, which is now theirs. Your bib number and check-in code now belong to them.
+
+Thank you for passing your spot on, and we hope to see you at a future race!
+
+If you did not ask to transfer your registration, please contact us as soon as possible.
+
+{{.EventTeam}}
+
+---
+This is an automated email. Please do not reply to this message.
+© {{.Year}} {{.EventName}}. All rights reserved.
+`
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// templateFixture is an event with a category, a paid runner and the email
+// template service on an in-memory database
+type templateFixture struct {
+	event       *models.Event
+	participant *models.Participant
+	auth        *AuthService
+	checkIn     *CheckInService
+	transfers   *memory.TransferRepository
+	service     *EmailTemplateService
+}
+
+func newTemplateFixture(t *testing.T) *templateFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memory.NewDB()
+	events := memory.NewEventRepository(db)
+	categories := memory.NewRaceCategoryRepository(db)
+	participants := memory.NewParticipantRepository(db)
+	tx := memory.NewTransactor(db)
+	cfg := &config.Config{
+		SMTP:     config.SMTPConfig{FromName: "Tau-Tau Run"},
+		CheckIn:  config.CheckInConfig{TokenSecret: testCheckInSecret},
+		JWT:      config.JWTConfig{Secret: testJWTSecret},
+		Portal:   config.PortalConfig{URL: "https://tautaurun.id/portal", LinkMinutes: 15, SessionHours: 24},
+		Transfer: config.TransferConfig{URL: "https://tautaurun.id/transfer", LinkHours: 72},
+		Waitlist: config.WaitlistConfig{OfferHours: 48},
+	}
+
+	f := &templateFixture{event: &models.Event{Name: "City Run", EventDate: "2026-12-06", Location: "Jakarta"}}
+	if err := events.Create(ctx, f.event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	category := &models.RaceCategory{EventID: f.event.ID, Name: "10K", Capacity: 100}
+	if err := categories.Create(ctx, category); err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+	bib := 42
+	f.participant = &models.Participant{
+		EventID:    f.event.ID,
+		CategoryID: &category.ID,
+		Name:       "Dewi <b>Ayu</b>",
+		Email:      "dewi@example.com",
+		Phone:      "+6281234567890",
+		BibNumber:  &bib,
+	}
+	if err := participants.Create(ctx, f.participant); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+
+	f.auth = NewAuthService(cfg)
+	f.checkIn = NewCheckInService(cfg, memory.NewAdminRepository(db), participants, tx)
+	f.transfers = memory.NewTransferRepository(db)
+	f.service = NewEmailTemplateService(cfg, f.checkIn, f.auth, events, categories, participants, memory.NewEmailTemplateRepository(db), f.transfers, tx)
+	return f
+}
+
+// save stores a template version of the payment confirmation
+func (f *templateFixture) save(t *testing.T, subject string) *models.EmailTemplate {
+	t.Helper()
+
+	saved, err := f.service.Save(context.Background(), f.event.ID, EmailTypePaymentConfirmation, models.EmailTemplateRequest{
+		Subject:  subject,
+		HTMLBody: "<p>Hi {{.Name}}</p>",
+		TextBody: "Hi {{.Name}}",
+	}, "admin-1")
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	return saved
+}
+
+func TestEmailTemplateServiceDefault(t *testing.T) {
+	f := newTemplateFixture(t)
+
+	current, err := f.service.Get(context.Background(), f.event.ID, EmailTypePaymentConfirmation)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !current.IsDefault || current.Version != 0 {
+		t.Errorf("Get() = version %d, default %t, want the built-in default", current.Version, current.IsDefault)
+	}
+
+	if _, err := f.service.Get(context.Background(), f.event.ID, "NEWSLETTER"); !errors.Is(err, ErrUnknownEmailType) {
+		t.Errorf("Get() unknown type error = %v, want ErrUnknownEmailType", err)
+	}
+	if _, err := f.service.Get(context.Background(), "missing", EmailTypePaymentConfirmation); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("Get() unknown event error = %v, want ErrEventNotFound", err)
+	}
+}
+
+func TestEmailTemplateServiceVersions(t *testing.T) {
+	f := newTemplateFixture(t)
+	ctx := context.Background()
+
+	first := f.save(t, "First {{.EventName}}")
+	second := f.save(t, "Second {{.EventName}}")
+	if first.Version != 1 || second.Version != 2 {
+		t.Fatalf("saved versions %d and %d, want 1 and 2", first.Version, second.Version)
+	}
+
+	restored, err := f.service.Rollback(ctx, f.event.ID, EmailTypePaymentConfirmation, 1, "admin-1")
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if restored.Version != 3 || restored.Subject != first.Subject || restored.RestoredFrom == nil || *restored.RestoredFrom != 1 {
+		t.Errorf("Rollback() = version %d %q from %v, want version 3 copying version 1", restored.Version, restored.Subject, restored.RestoredFrom)
+	}
+
+	current, err := f.service.Get(ctx, f.event.ID, EmailTypePaymentConfirmation)
+	if err != nil || current.Version != 3 || current.IsDefault {
+		t.Errorf("Get() = %+v, %v, want the restored version", current, err)
+	}
+
+	versions, err := f.service.Versions(ctx, f.event.ID, EmailTypePaymentConfirmation)
+	if err != nil || len(versions) != 3 || versions[0].Version != 3 || versions[2].Version != 1 {
+		t.Errorf("Versions() = %d versions, %v, want 3, newest first", len(versions), err)
+	}
+
+	if _, err := f.service.Rollback(ctx, f.event.ID, EmailTypePaymentConfirmation, 9, "admin-1"); !errors.Is(err, ErrEmailTemplateVersionNotFound) {
+		t.Errorf("Rollback() to a missing version error = %v, want ErrEmailTemplateVersionNotFound", err)
+	}
+
+	restoredDefault, err := f.service.Rollback(ctx, f.event.ID, EmailTypePaymentConfirmation, 0, "admin-1")
+	if err != nil {
+		t.Fatalf("Rollback() to the default error = %v", err)
+	}
+	if restoredDefault.Version != 4 || restoredDefault.Subject != defaultEmailTemplates[EmailTypePaymentConfirmation].Subject {
+		t.Errorf("Rollback() to the default = version %d %q", restoredDefault.Version, restoredDefault.Subject)
+	}
+}
+
+func TestEmailTemplateServiceSaveInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       models.EmailTemplateRequest
+		wantField string
+	}{
+		{
+			name:      "subject does not parse",
+			req:       models.EmailTemplateRequest{Subject: "{{.Name", HTMLBody: "<p></p>", TextBody: "Hi"},
+			wantField: "subject",
+		},
+		{
+			name:      "HTML body references an unknown field",
+			req:       models.EmailTemplateRequest{Subject: "Hi", HTMLBody: "<p>{{.ShoeSize}}</p>", TextBody: "Hi"},
+			wantField: "html_body",
+		},
+		{
+			name:      "text body references an unknown field",
+			req:       models.EmailTemplateRequest{Subject: "Hi", HTMLBody: "<p></p>", TextBody: "{{.ShoeSize}}"},
+			wantField: "text_body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTemplateFixture(t)
+
+			_, err := f.service.Save(context.Background(), f.event.ID, EmailTypePaymentConfirmation, tt.req, "admin-1")
+			var templateErr *EmailTemplateError
+			if !errors.As(err, &templateErr) || templateErr.Field != tt.wantField {
+				t.Fatalf("Save() error = %v, want an error in %s", err, tt.wantField)
+			}
+
+			versions, err := f.service.Versions(context.Background(), f.event.ID, EmailTypePaymentConfirmation)
+			if err != nil || len(versions) != 0 {
+				t.Errorf("Versions() = %d versions, %v, want the invalid template not stored", len(versions), err)
+			}
+		})
+	}
+}
+
+func TestEmailTemplateServiceRender(t *testing.T) {
+	f := newTemplateFixture(t)
+	f.save(t, "Bib {{.BibNumber}} for {{.Name}}\nin {{.Category}}")
+
+	rendered, err := f.service.Render(context.Background(), EmailTypePaymentConfirmation, f.participant, f.event)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if rendered.Subject != "Bib 42 for Dewi <b>Ayu</b> in 10K" {
+		t.Errorf("subject = %q, want a single line with the participant's data", rendered.Subject)
+	}
+	if rendered.Text != "Hi Dewi <b>Ayu</b>" {
+		t.Errorf("text = %q, want the name as written", rendered.Text)
+	}
+	if rendered.HTML != "<p>Hi Dewi &lt;b&gt;Ayu&lt;/b&gt;</p>" {
+		t.Errorf("HTML = %q, want the name escaped", rendered.HTML)
+	}
+}
+
+func TestEmailTemplateServicePreview(t *testing.T) {
+	f := newTemplateFixture(t)
+	ctx := context.Background()
+	subject := "Preview for {{.Name}}"
+
+	sample, err := f.service.Preview(ctx, f.event.ID, EmailTypePaymentConfirmation, models.EmailTemplatePreviewRequest{Subject: &subject})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if sample.Subject != "Preview for Alex Runner" {
+		t.Errorf("sample subject = %q, want the unsaved subject for the sample participant", sample.Subject)
+	}
+	if !strings.Contains(sample.HTML, "data:image/png;base64,") {
+		t.Errorf("preview HTML does not embed the QR code")
+	}
+
+	preview, err := f.service.Preview(ctx, f.event.ID, EmailTypePaymentConfirmation, models.EmailTemplatePreviewRequest{Subject: &subject, ParticipantID: f.participant.ID})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if preview.Subject != "Preview for Dewi <b>Ayu</b>" {
+		t.Errorf("subject = %q, want the chosen participant", preview.Subject)
+	}
+
+	versions, err := f.service.Versions(ctx, f.event.ID, EmailTypePaymentConfirmation)
+	if err != nil || len(versions) != 0 {
+		t.Errorf("Versions() = %d versions, %v, want previews not saved", len(versions), err)
+	}
+
+	if _, err := f.service.Preview(ctx, f.event.ID, EmailTypePaymentConfirmation, models.EmailTemplatePreviewRequest{ParticipantID: "missing"}); !errors.Is(err, ErrParticipantNotFound) {
+		t.Errorf("Preview() unknown participant error = %v, want ErrParticipantNotFound", err)
+	}
+}
+
+func TestEmailTemplateServicePreviewCredentials(t *testing.T) {
+	f := newTemplateFixture(t)
+	ctx := context.Background()
+
+	transfer := &models.RegistrationTransfer{
+		ParticipantID: f.participant.ID,
+		EventID:       f.event.ID,
+		FromName:      f.participant.Name,
+		FromEmail:     f.participant.Email,
+		ToName:        "Sam Runner",
+		ToEmail:       "sam@example.com",
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+	if err := f.transfers.Create(ctx, transfer); err != nil {
+		t.Fatalf("failed to create transfer: %v", err)
+	}
+
+	text := "{{.CheckInToken}}\n{{.PortalLink}}\n{{.TransferLink}}"
+	if _, err := f.service.Save(ctx, f.event.ID, EmailTypePaymentConfirmation, models.EmailTemplateRequest{Subject: "Links", HTMLBody: "<p>Links</p>", TextBody: text}, "admin-1"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A preview of a real runner's email does not let whoever sees it act as them
+	preview, err := f.service.Preview(ctx, f.event.ID, EmailTypePaymentConfirmation, models.EmailTemplatePreviewRequest{ParticipantID: f.participant.ID})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	want := "PREVIEW-CHECK-IN-TOKEN\nhttps://tautaurun.id/portal?token=preview\nhttps://tautaurun.id/transfer?token=preview"
+	if preview.Text != want {
+		t.Errorf("preview text = %q, want placeholders %q", preview.Text, want)
+	}
+
+	// The email that is sent has the working ones
+	rendered, err := f.service.Render(ctx, EmailTypePaymentConfirmation, f.participant, f.event)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	lines := strings.Split(rendered.Text, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("rendered text = %q, want three lines", rendered.Text)
+	}
+	if lines[0] != f.checkIn.Token(f.participant) {
+		t.Errorf("check-in token = %q, want the participant's", lines[0])
+	}
+	if email, err := f.auth.ValidateParticipantLoginToken(strings.TrimPrefix(lines[1], "https://tautaurun.id/portal?token=")); err != nil || email != f.participant.Email {
+		t.Errorf("portal link %q logs in %q, %v, want %s", lines[1], email, err, f.participant.Email)
+	}
+	if id, err := f.auth.ValidateTransferToken(strings.TrimPrefix(lines[2], "https://tautaurun.id/transfer?token=")); err != nil || id != transfer.ID {
+		t.Errorf("transfer link %q accepts %q, %v, want transfer %s", lines[2], id, err, transfer.ID)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// Errors returned by EventService
+var (
+	ErrEventNotFound      = errors.New("event not found")
+	ErrEventRequired      = errors.New("event_id is required when more than one event is open")
+	ErrRegistrationClosed = errors.New("registration for this event is closed")
+)
+
+// EventService manages events and decides which event a registration is for
+type EventService struct {
+	events   repository.EventRepository
+	waitlist *WaitlistService
+}
+
+// NewEventService creates a new event service
+func NewEventService(events repository.EventRepository, waitlist *WaitlistService) *EventService {
+	return &EventService{events: events, waitlist: waitlist}
+}
+
+// List returns all events, soonest event date first
+func (s *EventService) List(ctx context.Context) ([]models.Event, error) {
+	return s.events.List(ctx)
+}
+
+// Get returns an event by ID
+func (s *EventService) Get(ctx context.Context, id string) (*models.Event, error) {
+	event, err := s.events.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, ErrEventNotFound
+	}
+	return event, nil
+}
+
+// Create stores a new event
+func (s *EventService) Create(ctx context.Context, event *models.Event) error {
+	return s.events.Create(ctx, event)
+}
+
+// Update replaces the details of an existing event. Raising or removing its
+// capacity offers the new spots to the waitlist.
+func (s *EventService) Update(ctx context.Context, event *models.Event) error {
+	existing, err := s.Get(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+
+	event.CreatedAt = existing.CreatedAt
+	if err := s.events.Update(ctx, event); err != nil {
+		return err
+	}
+
+	// The event is saved either way, so a failed fill is only logged
+	if err := s.waitlist.Fill(ctx, event.ID, nil); err != nil {
+		utils.ServerLogger.Error("Failed to fill waitlist of event %s: %v", event.ID, err)
+	}
+
+	updated, err := s.Get(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+	*event = *updated
+	return nil
+}
+
+// Delete removes an event nobody has registered for
+func (s *EventService) Delete(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	return s.events.Delete(ctx, id)
+}
+
+// ResolveForRegistration returns the event a new registration is for. An empty
+// eventID selects the only event open for registration, so single-event
+// deployments do not need to send one.
+func (s *EventService) ResolveForRegistration(ctx context.Context, eventID string) (*models.Event, error) {
+	if eventID != "" {
+		event, err := s.Get(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		if !event.RegistrationOpen {
+			return nil, ErrRegistrationClosed
+		}
+		return event, nil
+	}
+
+	events, err := s.events.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var open *models.Event
+	for i := range events {
+		if !events[i].RegistrationOpen {
+			continue
+		}
+		if open != nil {
+			return nil, ErrEventRequired
+		}
+		open = &events[i]
+	}
+
+	if open == nil {
+		return nil, ErrRegistrationClosed
+	}
+	return open, nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+func TestEventServiceResolveForRegistration(t *testing.T) {
+	tests := []struct {
+		name    string
+		open    []bool // Registration state of each event created
+		eventID int    // Index of the event asked for, or -1 for none
+		want    int    // Index of the event resolved
+		wantErr error
+	}{
+		{name: "only open event", open: []bool{false, true}, eventID: -1, want: 1},
+		{name: "several open events", open: []bool{true, true}, eventID: -1, wantErr: ErrEventRequired},
+		{name: "no open event", open: []bool{false}, eventID: -1, wantErr: ErrRegistrationClosed},
+		{name: "no events", eventID: -1, wantErr: ErrRegistrationClosed},
+		{name: "named open event", open: []bool{true, true}, eventID: 1, want: 1},
+		{name: "named closed event", open: []bool{true, false}, eventID: 1, wantErr: ErrRegistrationClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := memory.NewEventRepository(memory.NewDB())
+			service := NewEventService(events, nil)
+			ctx := context.Background()
+
+			var ids []string
+			for _, open := range tt.open {
+				event := &models.Event{Name: "Run", EventDate: "2026-12-06", Location: "Jakarta", RegistrationOpen: open}
+				if err := events.Create(ctx, event); err != nil {
+					t.Fatalf("failed to create event: %v", err)
+				}
+				ids = append(ids, event.ID)
+			}
+
+			eventID := ""
+			if tt.eventID >= 0 {
+				eventID = ids[tt.eventID]
+			}
+
+			got, err := service.ResolveForRegistration(ctx, eventID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveForRegistration() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.ID != ids[tt.want] {
+				t.Errorf("ResolveForRegistration() = event %s, want %s", got.ID, ids[tt.want])
+			}
+		})
+	}
+}
+
+func TestEventServiceResolveUnknownEvent(t *testing.T) {
+	service := NewEventService(memory.NewEventRepository(memory.NewDB()), nil)
+
+	_, err := service.ResolveForRegistration(context.Background(), "00000000-0000-4000-8000-000000000000")
+	if !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("ResolveForRegistration() error = %v, want %v", err, ErrEventNotFound)
+	}
+}
+
+func TestEventServiceUpdateKeepsCreationTime(t *testing.T) {
+	cfg := &config.Config{Waitlist: config.WaitlistConfig{OfferHours: 48}}
+	db := memory.NewDB()
+	events := memory.NewEventRepository(db)
+	participants := memory.NewParticipantRepository(db)
+	tx := memory.NewTransactor(db)
+	outbox := NewEmailOutbox(cfg, NewEmailService(cfg, mailer.NewMemoryMailer(), nil, events, memory.NewEmailLogRepository(db), nil, nil, nil), participants, memory.NewEmailOutboxRepository(db), tx)
+	service := NewEventService(events, NewWaitlistService(cfg, events, memory.NewRaceCategoryRepository(db), participants, outbox, tx))
+	ctx := context.Background()
+
+	event := &models.Event{Name: "City Run", EventDate: "2026-12-06", Location: "Jakarta"}
+	if err := service.Create(ctx, event); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	update := &models.Event{ID: event.ID, Name: "City Night Run", EventDate: "2026-12-07", Location: "Jakarta"}
+	if err := service.Update(ctx, update); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	stored, err := service.Get(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if stored.Name != "City Night Run" || !stored.CreatedAt.Equal(event.CreatedAt) {
+		t.Errorf("stored event = %s created %v, want City Night Run created %v", stored.Name, stored.CreatedAt, event.CreatedAt)
+	}
+
+	if err := service.Update(ctx, &models.Event{ID: "00000000-0000-4000-8000-000000000000", Name: "Ghost Run"}); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("Update() of an unknown event error = %v, want %v", err, ErrEventNotFound)
+	}
+}
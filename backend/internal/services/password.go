@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// Errors returned by PasswordService
+var (
+	ErrWrongPassword     = errors.New("current password is incorrect")
+	ErrInvalidResetToken = errors.New("password reset link is invalid, expired or already used")
+)
+
+// PasswordService lets admins change their password, or reset it with a link
+// emailed to them when they have forgotten it.
+//
+// A reset link works once and expires after PASSWORD_RESET_LINK_MINUTES;
+// asking for a new link makes the earlier ones stop working. Changing or
+// resetting a password logs the admin out of every session.
+type PasswordService struct {
+	cfg            *config.Config
+	auth           *AuthService
+	adminService   *AdminService
+	sessionService *SessionService
+	emailService   *EmailService
+	admins         repository.AdminRepository
+	resets         repository.PasswordResetRepository
+	tx             repository.Transactor
+
+	// sends tracks the reset links still being sent in the background
+	sends sync.WaitGroup
+}
+
+// NewPasswordService creates a new admin password service
+func NewPasswordService(
+	cfg *config.Config,
+	auth *AuthService,
+	adminService *AdminService,
+	sessionService *SessionService,
+	emailService *EmailService,
+	admins repository.AdminRepository,
+	resets repository.PasswordResetRepository,
+	tx repository.Transactor,
+) *PasswordService {
+	return &PasswordService{
+		cfg:            cfg,
+		auth:           auth,
+		adminService:   adminService,
+		sessionService: sessionService,
+		emailService:   emailService,
+		admins:         admins,
+		resets:         resets,
+		tx:             tx,
+	}
+}
+
+// ChangePassword changes the password of a logged-in admin who knows their
+// current one. Their other sessions are logged out, so it returns a new one.
+func (s *PasswordService) ChangePassword(ctx context.Context, adminID, currentPassword, newPassword string) (*models.LoginResponse, error) {
+	if err := s.auth.ValidatePassword(newPassword); err != nil {
+		return nil, &WeakPasswordError{Reason: err.Error()}
+	}
+
+	admin, err := s.adminService.Active(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auth.ComparePassword(admin.PasswordHash, currentPassword); err != nil {
+		utils.AuthLogger.Warning("Admin %s entered a wrong current password", admin.Email)
+		return nil, ErrWrongPassword
+	}
+
+	if err := s.setPassword(ctx, admin, newPassword); err != nil {
+		return nil, err
+	}
+
+	utils.AuthLogger.Info("Admin %s changed their password", admin.Email)
+	return s.sessionService.Start(ctx, admin)
+}
+
+// RequestReset emails a password reset link to email if it belongs to an
+// admin who is not disabled. Nothing tells the caller whether it does: the
+// link is created and sent in the background, so the call takes as long
+// either way.
+func (s *PasswordService) RequestReset(ctx context.Context, email string) error {
+	admin, err := s.admins.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if admin == nil {
+		utils.AuthLogger.Info("Password reset requested for unknown admin email %s", email)
+		return nil
+	}
+	if admin.DisabledAt != nil {
+		utils.AuthLogger.Warning("Password reset requested for disabled admin %s", admin.Email)
+		return nil
+	}
+
+	s.sends.Add(1)
+	go func() {
+		defer s.sends.Done()
+		s.sendReset(context.WithoutCancel(ctx), admin)
+	}()
+	return nil
+}
+
+// sendReset replaces the reset links of an admin with a new one and emails it
+// to them. Failures are only logged, as the request has already been answered.
+func (s *PasswordService) sendReset(ctx context.Context, admin *models.Admin) {
+	token, err := newSecretToken()
+	if err != nil {
+		utils.AuthLogger.Error("Failed to create password reset link for admin %s: %v", admin.Email, err)
+		return
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		if err := s.resets.InvalidateByAdmin(ctx, admin.ID, now); err != nil {
+			return err
+		}
+		return s.resets.Create(ctx, &models.PasswordReset{
+			AdminID:   admin.ID,
+			TokenHash: hashToken(token),
+			ExpiresAt: now.Add(time.Duration(s.cfg.PasswordReset.LinkMinutes) * time.Minute),
+		})
+	})
+	if err != nil {
+		utils.AuthLogger.Error("Failed to save password reset link for admin %s: %v", admin.Email, err)
+		return
+	}
+
+	if err := s.emailService.SendAdminPasswordResetEmail(ctx, admin, passwordResetLink(s.cfg, token)); err != nil {
+		utils.EmailLogger.Error("Failed to send password reset email to admin %s: %v", admin.Email, err)
+		return
+	}
+
+	utils.AuthLogger.Info("Password reset link sent to admin %s", admin.Email)
+}
+
+// Wait returns once the reset links requested so far have been sent
+func (s *PasswordService) Wait() {
+	s.sends.Wait()
+}
+
+// Reset sets a new password with the token of a reset link. It returns
+// ErrInvalidResetToken if the link is unknown, expired or already used, or
+// its admin has since been disabled or deleted.
+func (s *PasswordService) Reset(ctx context.Context, token, newPassword string) (*models.Admin, error) {
+	if err := s.auth.ValidatePassword(newPassword); err != nil {
+		return nil, &WeakPasswordError{Reason: err.Error()}
+	}
+
+	var admin *models.Admin
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		reset, err := s.resets.FindByTokenHashForUpdate(ctx, hashToken(token))
+		if err != nil {
+			return err
+		}
+		if reset == nil || reset.UsedAt != nil || !reset.ExpiresAt.After(time.Now()) {
+			return ErrInvalidResetToken
+		}
+
+		admin, err = s.adminService.Active(ctx, reset.AdminID)
+		if errors.Is(err, ErrAdminNotFound) || errors.Is(err, ErrAdminDisabled) {
+			return ErrInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.setPassword(ctx, admin, newPassword)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.AuthLogger.Info("Admin %s reset their password", admin.Email)
+	return admin, nil
+}
+
+// DeleteExpired removes password reset tokens that have expired
+func (s *PasswordService) DeleteExpired(ctx context.Context) (int, error) {
+	return s.resets.DeleteExpired(ctx, time.Now())
+}
+
+// Run deletes expired password reset tokens every interval until ctx is cancelled
+func (s *PasswordService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DeleteExpired(ctx); err != nil {
+				utils.AuthLogger.Error("Failed to delete expired password resets: %v", err)
+			}
+		}
+	}
+}
+
+// setPassword saves a new password of an admin, uses up their reset links and
+// logs them out of every session
+func (s *PasswordService) setPassword(ctx context.Context, admin *models.Admin, password string) error {
+	hash, err := s.auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.admins.UpdatePassword(ctx, admin.ID, hash); err != nil {
+			return err
+		}
+		admin.PasswordHash = hash
+		if err := s.resets.InvalidateByAdmin(ctx, admin.ID, time.Now()); err != nil {
+			return err
+		}
+		return s.sessionService.LogoutAll(ctx, admin.ID)
+	})
+}
+
+// passwordResetLink returns the link of a password reset token
+func passwordResetLink(cfg *config.Config, token string) string {
+	separator := "?"
+	if strings.Contains(cfg.PasswordReset.URL, "?") {
+		separator = "&"
+	}
+	return cfg.PasswordReset.URL + separator + url.Values{"token": {token}}.Encode()
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// resetLinkPattern finds the token of a password reset link in an email
+var resetLinkPattern = regexp.MustCompile(`https://tautaurun\.id/admin/reset-password\?token=([A-Za-z0-9_-]+)`)
+
+// gatedMailer keeps sent messages in memory, holding each send until gate is
+// closed
+type gatedMailer struct {
+	*mailer.MemoryMailer
+	gate chan struct{}
+}
+
+func (m *gatedMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	<-m.gate
+	return m.MemoryMailer.Send(ctx, msg)
+}
+
+// passwordFixture is an owner with a known password and the password service
+// on an in-memory database
+type passwordFixture struct {
+	config   *config.Config
+	admin    models.Admin
+	admins   *memory.AdminRepository
+	mailer   *mailer.MemoryMailer
+	sessions *SessionService
+	service  *PasswordService
+}
+
+func newPasswordFixture(t *testing.T) *passwordFixture {
+	t.Helper()
+
+	cfg := &config.Config{
+		JWT:           config.JWTConfig{Secret: testJWTSecret, AccessTokenMinutes: 15, RefreshTokenDays: 30},
+		SMTP:          config.SMTPConfig{FromEmail: "noreply@tautaurun.id", FromName: "Tau-Tau Run"},
+		PasswordReset: config.PasswordResetConfig{URL: "https://tautaurun.id/admin/reset-password", LinkMinutes: 30},
+	}
+	db := memory.NewDB()
+	tx := memory.NewTransactor(db)
+	auth := NewAuthService(cfg)
+	sessionRepo := memory.NewSessionRepository(db)
+
+	hash, err := auth.HashPassword("oldpassword1")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+
+	f := &passwordFixture{
+		config: cfg,
+		admins: memory.NewAdminRepository(db),
+		mailer: mailer.NewMemoryMailer(),
+	}
+	f.admin = f.admins.Add(models.Admin{Email: "owner@tautaurun.id", PasswordHash: hash, Role: RoleOwner})
+
+	adminService := NewAdminService(auth, f.admins, sessionRepo, tx)
+	f.sessions = NewSessionService(cfg, auth, adminService, sessionRepo, tx)
+	emailService := NewEmailService(cfg, f.mailer, nil, memory.NewEventRepository(db), memory.NewEmailLogRepository(db), nil, nil, nil)
+	f.service = NewPasswordService(cfg, auth, adminService, f.sessions, emailService, f.admins, memory.NewPasswordResetRepository(db), tx)
+	return f
+}
+
+// requestReset asks for a reset link for the owner and returns its token
+func (f *passwordFixture) requestReset(t *testing.T) string {
+	t.Helper()
+
+	f.mailer.Reset()
+	if err := f.service.RequestReset(context.Background(), f.admin.Email); err != nil {
+		t.Fatalf("RequestReset() error = %v", err)
+	}
+	f.service.Wait()
+	sent := f.mailer.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d emails, want the reset link", len(sent))
+	}
+	match := resetLinkPattern.FindStringSubmatch(sent[0].Message.Text)
+	if match == nil {
+		t.Fatalf("reset email has no reset link:\n%s", sent[0].Message.Text)
+	}
+	return match[1]
+}
+
+// checkPassword fails the test unless password is the owner's stored password
+func (f *passwordFixture) checkPassword(t *testing.T, password string) {
+	t.Helper()
+
+	admin, err := f.admins.FindByID(context.Background(), f.admin.ID)
+	if err != nil || admin == nil {
+		t.Fatalf("failed to find admin: %v", err)
+	}
+	if err := NewAuthService(f.config).ComparePassword(admin.PasswordHash, password); err != nil {
+		t.Errorf("stored password is not %q", password)
+	}
+}
+
+func TestPasswordServiceChangePassword(t *testing.T) {
+	f := newPasswordFixture(t)
+	ctx := context.Background()
+
+	old, err := f.sessions.Start(ctx, &f.admin)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if _, err := f.service.ChangePassword(ctx, f.admin.ID, "wrongpassword1", "newpassword1"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("ChangePassword() with a wrong password error = %v, want ErrWrongPassword", err)
+	}
+	var weak *WeakPasswordError
+	if _, err := f.service.ChangePassword(ctx, f.admin.ID, "oldpassword1", "short"); !errors.As(err, &weak) {
+		t.Errorf("ChangePassword() to a weak password error = %v, want WeakPasswordError", err)
+	}
+	f.checkPassword(t, "oldpassword1")
+
+	session, err := f.service.ChangePassword(ctx, f.admin.ID, "oldpassword1", "newpassword1")
+	if err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+	f.checkPassword(t, "newpassword1")
+
+	if _, err := f.sessions.Authenticate(ctx, old.Token); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Authenticate() of the earlier session error = %v, want ErrTokenRevoked", err)
+	}
+	if _, err := f.sessions.Authenticate(ctx, session.Token); err != nil {
+		t.Errorf("Authenticate() of the new session error = %v", err)
+	}
+}
+
+func TestPasswordServiceReset(t *testing.T) {
+	f := newPasswordFixture(t)
+	ctx := context.Background()
+
+	old, err := f.sessions.Start(ctx, &f.admin)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	earlier := f.requestReset(t)
+	token := f.requestReset(t)
+
+	if _, err := f.service.Reset(ctx, earlier, "newpassword1"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("Reset() with a replaced link error = %v, want ErrInvalidResetToken", err)
+	}
+	var weak *WeakPasswordError
+	if _, err := f.service.Reset(ctx, token, "short"); !errors.As(err, &weak) {
+		t.Errorf("Reset() to a weak password error = %v, want WeakPasswordError", err)
+	}
+
+	admin, err := f.service.Reset(ctx, token, "newpassword1")
+	if err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if admin.ID != f.admin.ID {
+		t.Errorf("Reset() admin = %s, want %s", admin.ID, f.admin.ID)
+	}
+	f.checkPassword(t, "newpassword1")
+
+	if _, err := f.service.Reset(ctx, token, "otherpassword1"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("Reset() with a used link error = %v, want ErrInvalidResetToken", err)
+	}
+	if _, err := f.sessions.Authenticate(ctx, old.Token); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Authenticate() of a session from before the reset error = %v, want ErrTokenRevoked", err)
+	}
+}
+
+func TestPasswordServiceResetInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepare changes the state after the link was sent
+		prepare     func(t *testing.T, f *passwordFixture)
+		linkMinutes int // Overrides how long the link is valid
+	}{
+		{
+			name:        "expired",
+			prepare:     func(t *testing.T, f *passwordFixture) {},
+			linkMinutes: -1,
+		},
+		{
+			name: "admin disabled",
+			prepare: func(t *testing.T, f *passwordFixture) {
+				now := time.Now()
+				if err := f.admins.SetDisabled(context.Background(), f.admin.ID, &now); err != nil {
+					t.Fatalf("failed to disable admin: %v", err)
+				}
+			},
+		},
+		{
+			name: "admin deleted",
+			prepare: func(t *testing.T, f *passwordFixture) {
+				if err := f.admins.Delete(context.Background(), f.admin.ID); err != nil {
+					t.Fatalf("failed to delete admin: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPasswordFixture(t)
+			if tt.linkMinutes != 0 {
+				f.config.PasswordReset.LinkMinutes = tt.linkMinutes
+			}
+
+			token := f.requestReset(t)
+			tt.prepare(t, f)
+
+			if _, err := f.service.Reset(context.Background(), token, "newpassword1"); !errors.Is(err, ErrInvalidResetToken) {
+				t.Fatalf("Reset() error = %v, want ErrInvalidResetToken", err)
+			}
+		})
+	}
+
+	f := newPasswordFixture(t)
+	if _, err := f.service.Reset(context.Background(), "not-a-reset-token", "newpassword1"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("Reset() with an unknown token error = %v, want ErrInvalidResetToken", err)
+	}
+}
+
+func TestPasswordServiceRequestResetNoAdmin(t *testing.T) {
+	f := newPasswordFixture(t)
+	ctx := context.Background()
+
+	if err := f.service.RequestReset(ctx, "nobody@tautaurun.id"); err != nil {
+		t.Errorf("RequestReset() of an unknown email error = %v, want nil", err)
+	}
+
+	now := time.Now()
+	if err := f.admins.SetDisabled(ctx, f.admin.ID, &now); err != nil {
+		t.Fatalf("failed to disable admin: %v", err)
+	}
+	if err := f.service.RequestReset(ctx, f.admin.Email); err != nil {
+		t.Errorf("RequestReset() of a disabled admin error = %v, want nil", err)
+	}
+	f.service.Wait()
+
+	if sent := f.mailer.Sent(); len(sent) != 0 {
+		t.Errorf("sent %d emails, want none", len(sent))
+	}
+}
+
+func TestPasswordServiceRequestResetInBackground(t *testing.T) {
+	f := newPasswordFixture(t)
+	gated := &gatedMailer{MemoryMailer: f.mailer, gate: make(chan struct{})}
+	db := memory.NewDB()
+	f.service.emailService = NewEmailService(f.config, gated, nil, memory.NewEventRepository(db), memory.NewEmailLogRepository(db), nil, nil, nil)
+
+	// The request is answered while the email is still being sent, so an
+	// admin's email takes no longer than an unknown one
+	requested := make(chan error, 1)
+	go func() {
+		requested <- f.service.RequestReset(context.Background(), f.admin.Email)
+	}()
+	select {
+	case err := <-requested:
+		if err != nil {
+			t.Fatalf("RequestReset() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		close(gated.gate)
+		t.Fatal("RequestReset() waited for the reset email to be sent")
+	}
+	if sent := f.mailer.Sent(); len(sent) != 0 {
+		t.Errorf("sent %d emails before the mailer finished, want none", len(sent))
+	}
+
+	close(gated.gate)
+	f.service.Wait()
+	if sent := f.mailer.Sent(); len(sent) != 1 {
+		t.Errorf("sent %d emails, want the reset link", len(sent))
+	}
+}
@@ -0,0 +1,463 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/payment"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// Errors returned by PaymentService
+var (
+	ErrParticipantNotFound = errors.New("participant not found")
+	ErrPaymentNotFound     = errors.New("payment not found")
+	ErrAlreadyPaid         = errors.New("participant has already paid")
+	ErrPaymentsDisabled    = errors.New("online payments are not enabled")
+	ErrAmountMismatch      = errors.New("paid amount does not match the payment")
+	ErrWaitlisted          = errors.New("participant is on the waitlist")
+	ErrOfferExpired        = errors.New("participant did not pay for their offered spot in time")
+)
+
+// PaymentStatusChange describes a participant payment status update
+type PaymentStatusChange struct {
+	Participant *models.Participant
+	OldStatus   string
+	EmailSent   bool // The confirmation email was queued
+}
+
+// PaymentService changes participant payment status, whether by an admin or
+// through a payment provider. On UNPAID → PAID it assigns a bib number and
+// queues the confirmation email.
+type PaymentService struct {
+	config       *config.Config
+	provider     payment.Provider
+	outbox       *EmailOutbox
+	bibs         *BibService
+	events       repository.EventRepository
+	categories   repository.RaceCategoryRepository
+	participants repository.ParticipantRepository
+	payments     repository.PaymentRepository
+	tx           repository.Transactor
+}
+
+// NewPaymentService creates a new payment service. provider may be nil when
+// online payments are disabled.
+func NewPaymentService(
+	cfg *config.Config,
+	provider payment.Provider,
+	outbox *EmailOutbox,
+	bibs *BibService,
+	events repository.EventRepository,
+	categories repository.RaceCategoryRepository,
+	participants repository.ParticipantRepository,
+	payments repository.PaymentRepository,
+	tx repository.Transactor,
+) *PaymentService {
+	return &PaymentService{
+		config:       cfg,
+		provider:     provider,
+		outbox:       outbox,
+		bibs:         bibs,
+		events:       events,
+		categories:   categories,
+		participants: participants,
+		payments:     payments,
+		tx:           tx,
+	}
+}
+
+// UpdateStatus sets a participant's payment status
+func (s *PaymentService) UpdateStatus(ctx context.Context, participantID, status string) (*PaymentStatusChange, error) {
+	return s.UpdateStatusWithin(ctx, participantID, status, nil)
+}
+
+// UpdateStatusWithin sets a participant's payment status in the same transaction
+// as fn, which runs first and aborts the update if it returns an error. The
+// confirmation email is queued in the same transaction, so it is only sent if
+// the transaction commits.
+func (s *PaymentService) UpdateStatusWithin(ctx context.Context, participantID, status string, fn func(ctx context.Context) error) (*PaymentStatusChange, error) {
+	// Find and update the participant in one transaction so concurrent
+	// updates cannot both observe UNPAID and queue duplicate emails
+	var change *PaymentStatusChange
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if fn != nil {
+			if err := fn(ctx); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		change, err = s.setParticipantStatus(ctx, participantID, status)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(change)
+	return change, nil
+}
+
+// CreateCharge returns a payment the participant can complete at the provider,
+// reusing their pending payment if it is still open. The participant is locked
+// while the payment is created, so concurrent requests cannot each open one.
+func (s *PaymentService) CreateCharge(ctx context.Context, participantID string) (*models.Payment, error) {
+	if s.provider == nil {
+		return nil, ErrPaymentsDisabled
+	}
+
+	var p *models.Payment
+	var paid bool
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		participant, err := s.participants.FindByIDForUpdate(ctx, participantID)
+		if err != nil {
+			return err
+		}
+		if participant == nil {
+			return ErrParticipantNotFound
+		}
+		if err := checkPayable(participant); err != nil {
+			return err
+		}
+
+		pending, err := s.payments.FindPendingByParticipant(ctx, participantID)
+		if err != nil {
+			return err
+		}
+		if pending != nil {
+			open, err := s.reconcile(ctx, pending)
+			if err != nil {
+				return err
+			}
+			if open {
+				p = pending
+				return nil
+			}
+			// Commit the payment the provider reported as paid instead of
+			// opening another one
+			if paid = pending.Status == payment.StatusPaid; paid {
+				return nil
+			}
+		}
+
+		p, err = s.createPayment(ctx, participant)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if paid {
+		return nil, ErrAlreadyPaid
+	}
+
+	return p, nil
+}
+
+// createPayment opens a charge for the participant's price at the provider
+// and stores it as a pending payment
+func (s *PaymentService) createPayment(ctx context.Context, participant *models.Participant) (*models.Payment, error) {
+	event, err := s.events.FindByID(ctx, participant.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, fmt.Errorf("event %s of participant %s not found", participant.EventID, participant.ID)
+	}
+
+	amount, currency, err := s.price(ctx, participant)
+	if err != nil {
+		return nil, err
+	}
+
+	reference, err := newPaymentReference()
+	if err != nil {
+		return nil, err
+	}
+
+	charge, err := s.provider.CreateCharge(ctx, payment.ChargeRequest{
+		Reference:     reference,
+		Amount:        amount,
+		Currency:      currency,
+		Description:   fmt.Sprintf("%s registration", event.Name),
+		CustomerName:  participant.Name,
+		CustomerEmail: participant.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p := &models.Payment{
+		ParticipantID:     participant.ID,
+		Provider:          s.provider.Name(),
+		Reference:         reference,
+		ProviderReference: charge.ProviderReference,
+		Amount:            amount,
+		Currency:          currency,
+		Status:            payment.StatusPending,
+		CheckoutURL:       charge.CheckoutURL,
+		ExpiresAt:         charge.ExpiresAt,
+	}
+	if err := s.payments.Create(ctx, p); err != nil {
+		return nil, err
+	}
+
+	utils.ServerLogger.Info("Created %s payment %s for participant %s", p.Provider, p.Reference, participant.Email)
+	return p, nil
+}
+
+// HandleWebhook verifies a provider callback and applies the reported status.
+// The returned change is nil when the participant's payment status did not change.
+func (s *PaymentService) HandleWebhook(ctx context.Context, payload []byte, headers http.Header) (*PaymentStatusChange, error) {
+	if s.provider == nil {
+		return nil, ErrPaymentsDisabled
+	}
+
+	event, err := s.provider.ParseWebhook(payload, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.applyStatus(ctx, event.Reference, event.Status, event.Amount)
+}
+
+// price returns what a participant pays: the price of their race category, or
+// the configured registration fee if they have none
+func (s *PaymentService) price(ctx context.Context, participant *models.Participant) (int64, string, error) {
+	if participant.CategoryID == nil {
+		return s.config.Payment.Amount, s.config.Payment.Currency, nil
+	}
+
+	category, err := s.categories.FindByID(ctx, *participant.CategoryID)
+	if err != nil {
+		return 0, "", err
+	}
+	if category == nil {
+		return 0, "", fmt.Errorf("race category %s of participant %s not found", *participant.CategoryID, participant.ID)
+	}
+	return category.Price, category.Currency, nil
+}
+
+// reconcile asks the provider about a pending payment we may have missed a
+// webhook for, records the status it reports on p, and reports whether p is
+// still open for the participant to pay
+func (s *PaymentService) reconcile(ctx context.Context, p *models.Payment) (bool, error) {
+	status, err := s.provider.QueryStatus(ctx, p.ProviderReference)
+	if err != nil {
+		// The provider being unreachable should not block a checkout that may still work
+		utils.ServerLogger.Warning("Could not query status of payment %s: %v", p.Reference, err)
+		status = payment.StatusPending
+	}
+
+	if status == payment.StatusPending && p.ExpiresAt != nil && time.Now().After(*p.ExpiresAt) {
+		status = payment.StatusExpired
+	}
+
+	if status == payment.StatusPending {
+		return true, nil
+	}
+
+	// The status comes from the provider's API for the charge we created, so
+	// it is for the amount we asked for
+	if _, err := s.applyStatus(ctx, p.Reference, status, p.Amount); err != nil {
+		return false, err
+	}
+	p.Status = status
+
+	return false, nil
+}
+
+// applyStatus records a provider status for a payment and marks the participant
+// PAID when the payment succeeds. A successful payment must report exactly the
+// amount that was charged.
+func (s *PaymentService) applyStatus(ctx context.Context, reference, status string, amount int64) (*PaymentStatusChange, error) {
+	var change *PaymentStatusChange
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.payments.FindByReferenceForUpdate(ctx, reference)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return ErrPaymentNotFound
+		}
+
+		if status == payment.StatusPaid && amount != p.Amount {
+			return ErrAmountMismatch
+		}
+
+		// Providers retry callbacks, and a paid payment is never downgraded
+		if p.Status == status || p.Status == payment.StatusPaid {
+			return nil
+		}
+
+		if err := s.payments.UpdateStatus(ctx, p, status); err != nil {
+			return err
+		}
+
+		utils.ServerLogger.Info("Payment %s is now %s", p.Reference, status)
+
+		if status != payment.StatusPaid {
+			return nil
+		}
+
+		change, err = s.setParticipantStatus(ctx, p.ParticipantID, "PAID")
+		if errors.Is(err, ErrCancelled) || errors.Is(err, ErrWaitlisted) || errors.Is(err, ErrOfferExpired) {
+			utils.ServerLogger.Warning("Payment %s was paid but must be refunded: %v", p.Reference, err)
+			return s.oweLatePayment(ctx, p)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if change != nil {
+		s.notify(change)
+	}
+	return change, nil
+}
+
+// oweLatePayment records that a payment which arrived for a registration
+// holding no spot is owed back in full, so it is paid out with the other
+// refunds. It must be called inside a transaction.
+func (s *PaymentService) oweLatePayment(ctx context.Context, p *models.Payment) error {
+	participant, err := s.participants.FindByIDForUpdate(ctx, p.ParticipantID)
+	if err != nil {
+		return err
+	}
+	if participant == nil {
+		return ErrParticipantNotFound
+	}
+
+	// A refund still owed for the registration is paid out together with this one
+	amount := p.Amount
+	if participant.PaymentStatus == "REFUND_PENDING" && participant.RefundAmount != nil &&
+		participant.RefundCurrency != nil && *participant.RefundCurrency == p.Currency {
+		amount += *participant.RefundAmount
+	}
+
+	participant.RefundAmount = &amount
+	participant.RefundCurrency = &p.Currency
+	participant.RefundedAt = nil
+	participant.RefundedBy = nil
+	if err := s.participants.UpdateCancellation(ctx, participant); err != nil {
+		return err
+	}
+	return s.participants.UpdatePaymentStatus(ctx, participant, "REFUND_PENDING")
+}
+
+// paidAmount returns what a participant paid: the amount of their PAID
+// payment, or their price if an admin marked them PAID without one
+func (s *PaymentService) paidAmount(ctx context.Context, participant *models.Participant) (int64, string, error) {
+	p, err := s.payments.FindPaidByParticipant(ctx, participant.ID)
+	if err != nil {
+		return 0, "", err
+	}
+	if p == nil {
+		return s.price(ctx, participant)
+	}
+	return p.Amount, p.Currency, nil
+}
+
+// setParticipantStatus updates a participant's payment status, assigning a bib
+// number and queueing the confirmation email when they become PAID, and
+// releasing the bib number if that is reverted. Only a registration holding a
+// spot can become PAID; it returns ErrWaitlisted or ErrOfferExpired otherwise.
+// It must be called inside a transaction.
+func (s *PaymentService) setParticipantStatus(ctx context.Context, participantID, status string) (*PaymentStatusChange, error) {
+	participant, err := s.participants.FindByIDForUpdate(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	// Refunds of cancelled registrations are tracked by CancellationService
+	if participant.RegistrationStatus == "CANCELLED" {
+		return nil, ErrCancelled
+	}
+	// A late payment must not give a spot past capacity to someone still
+	// waiting for one or whose offer expired
+	if status == "PAID" {
+		switch participant.RegistrationStatus {
+		case "WAITLISTED":
+			return nil, ErrWaitlisted
+		case "EXPIRED":
+			return nil, ErrOfferExpired
+		}
+	}
+
+	// Store old status for email trigger logic
+	oldStatus := participant.PaymentStatus
+
+	if err := s.participants.UpdatePaymentStatus(ctx, participant, status); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case oldStatus != "PAID" && status == "PAID":
+		err = s.bibs.Assign(ctx, participant)
+	case oldStatus == "PAID" && status != "PAID":
+		err = s.bibs.Release(ctx, participant)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	change := &PaymentStatusChange{Participant: participant, OldStatus: oldStatus}
+	if oldStatus == "UNPAID" && status == "PAID" {
+		if err := s.outbox.Enqueue(ctx, participant.ID, EmailTypePaymentConfirmation); err != nil {
+			return nil, err
+		}
+		change.EmailSent = true
+	}
+
+	return change, nil
+}
+
+// notify logs whether the change queued the confirmation email
+func (s *PaymentService) notify(change *PaymentStatusChange) {
+	participant := change.Participant
+
+	if change.EmailSent {
+		utils.EmailLogger.Info("Payment status changed to PAID for %s - confirmation email queued", participant.Email)
+	} else if change.OldStatus == "PAID" && participant.PaymentStatus == "PAID" {
+		utils.EmailLogger.Info("Payment status already PAID for %s - skipping duplicate email", participant.Email)
+	}
+}
+
+// checkPayable returns an error if the participant cannot pay now: they
+// cancelled, have already paid, are still waiting for a spot, or let their
+// spot offer expire
+func checkPayable(participant *models.Participant) error {
+	switch {
+	case participant.RegistrationStatus == "CANCELLED":
+		return ErrCancelled
+	case participant.PaymentStatus == "PAID":
+		return ErrAlreadyPaid
+	case participant.RegistrationStatus == "WAITLISTED":
+		return ErrWaitlisted
+	case participant.RegistrationStatus == "EXPIRED":
+		return ErrOfferExpired
+	}
+	return nil
+}
+
+// newPaymentReference generates a unique reference sent to the provider
+func newPaymentReference() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate payment reference: %w", err)
+	}
+	return "TTR-" + strings.ToUpper(hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/storage"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// MaxPaymentProofSize is the largest proof of payment that can be uploaded
+const MaxPaymentProofSize = 5 << 20 // 5 MB
+
+// Payment proof review statuses
+const (
+	ProofStatusPending  = "PENDING"
+	ProofStatusApproved = "APPROVED"
+	ProofStatusRejected = "REJECTED"
+)
+
+// Errors returned by PaymentProofService
+var (
+	ErrProofTooLarge        = errors.New("payment proof exceeds the maximum size")
+	ErrProofEmpty           = errors.New("payment proof is empty")
+	ErrProofUnsupportedType = errors.New("payment proof must be a JPEG, PNG, WebP image or PDF")
+	ErrProofNotFound        = errors.New("payment proof not found")
+	ErrProofAlreadyReviewed = errors.New("payment proof has already been reviewed")
+)
+
+// proofExtensions maps accepted (sniffed) MIME types to file extensions
+var proofExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// PaymentProofService handles proof-of-payment uploads and their review
+type PaymentProofService struct {
+	storage        storage.Storage
+	proofs         repository.PaymentProofRepository
+	participants   repository.ParticipantRepository
+	paymentService *PaymentService
+	tx             repository.Transactor
+}
+
+// NewPaymentProofService creates a new payment proof service
+func NewPaymentProofService(
+	store storage.Storage,
+	proofs repository.PaymentProofRepository,
+	participants repository.ParticipantRepository,
+	paymentService *PaymentService,
+	tx repository.Transactor,
+) *PaymentProofService {
+	return &PaymentProofService{
+		storage:        store,
+		proofs:         proofs,
+		participants:   participants,
+		paymentService: paymentService,
+		tx:             tx,
+	}
+}
+
+// Upload stores a proof of payment for a participant. email must match the
+// registered email so a leaked participant ID alone is not enough to upload.
+func (s *PaymentProofService) Upload(ctx context.Context, participantID, email, filename string, r io.Reader) (*models.PaymentProof, error) {
+	participant, err := s.participants.FindByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil || !strings.EqualFold(participant.Email, strings.TrimSpace(email)) {
+		return nil, ErrParticipantNotFound
+	}
+	if err := checkPayable(participant); err != nil {
+		return nil, err
+	}
+
+	// Read one byte past the limit to detect oversized files
+	data, err := io.ReadAll(io.LimitReader(r, MaxPaymentProofSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payment proof: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, ErrProofEmpty
+	}
+	if len(data) > MaxPaymentProofSize {
+		return nil, ErrProofTooLarge
+	}
+
+	// Trust the content, not the client-supplied filename or Content-Type
+	contentType := http.DetectContentType(data)
+	ext, ok := proofExtensions[contentType]
+	if !ok {
+		return nil, ErrProofUnsupportedType
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate storage key: %w", err)
+	}
+
+	proof := &models.PaymentProof{
+		ParticipantID: participant.ID,
+		StorageKey:    fmt.Sprintf("payment-proofs/%s/%s%s", participant.ID, hex.EncodeToString(suffix), ext),
+		Filename:      sanitizeFilename(filename, ext),
+		ContentType:   contentType,
+		Size:          int64(len(data)),
+	}
+
+	if err := s.storage.Save(ctx, proof.StorageKey, bytes.NewReader(data), contentType); err != nil {
+		return nil, err
+	}
+
+	if err := s.proofs.Create(ctx, proof); err != nil {
+		if delErr := s.storage.Delete(ctx, proof.StorageKey); delErr != nil {
+			utils.ServerLogger.Error("Failed to clean up orphaned payment proof %s: %v", proof.StorageKey, delErr)
+		}
+		return nil, err
+	}
+
+	utils.ServerLogger.Info("Participant %s uploaded payment proof %s", participant.Email, proof.ID)
+	return proof, nil
+}
+
+// List returns one page of proofs with the given status, oldest first
+func (s *PaymentProofService) List(ctx context.Context, status string, page, limit int) ([]models.PaymentProof, int, error) {
+	return s.proofs.List(ctx, status, page, limit)
+}
+
+// Open returns a proof and a reader for its file. The caller must close the reader.
+func (s *PaymentProofService) Open(ctx context.Context, proofID string) (*models.PaymentProof, io.ReadCloser, error) {
+	proof, err := s.proofs.FindByID(ctx, proofID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if proof == nil {
+		return nil, nil, ErrProofNotFound
+	}
+
+	file, err := s.storage.Open(ctx, proof.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrProofNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return proof, file, nil
+}
+
+// Approve accepts a pending proof and marks the participant PAID, sending the
+// confirmation email exactly as a manual payment status update would
+func (s *PaymentProofService) Approve(ctx context.Context, proofID, adminID string) (*models.PaymentProof, *PaymentStatusChange, error) {
+	var proof *models.PaymentProof
+
+	// The participant is only known once the proof has been read, so look it
+	// up first; the review itself happens in the same transaction as the update
+	existing, err := s.proofs.FindByID(ctx, proofID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing == nil {
+		return nil, nil, ErrProofNotFound
+	}
+
+	change, err := s.paymentService.UpdateStatusWithin(ctx, existing.ParticipantID, "PAID", func(ctx context.Context) error {
+		var err error
+		proof, err = s.lockPending(ctx, proofID)
+		if err != nil {
+			return err
+		}
+		return s.proofs.UpdateReview(ctx, proof, ProofStatusApproved, adminID, nil)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return proof, change, nil
+}
+
+// Reject declines a pending proof with a reason shown to admins
+func (s *PaymentProofService) Reject(ctx context.Context, proofID, adminID, reason string) (*models.PaymentProof, error) {
+	var proof *models.PaymentProof
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		proof, err = s.lockPending(ctx, proofID)
+		if err != nil {
+			return err
+		}
+
+		var note *string
+		if reason = strings.TrimSpace(reason); reason != "" {
+			note = &reason
+		}
+		return s.proofs.UpdateReview(ctx, proof, ProofStatusRejected, adminID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+// lockPending locks a proof for review, failing if it was already reviewed
+func (s *PaymentProofService) lockPending(ctx context.Context, proofID string) (*models.PaymentProof, error) {
+	proof, err := s.proofs.FindByIDForUpdate(ctx, proofID)
+	if err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		return nil, ErrProofNotFound
+	}
+	if proof.Status != ProofStatusPending {
+		return nil, ErrProofAlreadyReviewed
+	}
+	return proof, nil
+}
+
+// sanitizeFilename keeps the base name of an uploaded file for display,
+// falling back to a generic name with the detected extension
+func sanitizeFilename(name, ext string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+
+	if name == "" || name == "." || name == "/" {
+		return "payment-proof" + ext
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
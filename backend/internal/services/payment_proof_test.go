@@ -0,0 +1,224 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+	"github.com/tau-tau-run/backend/internal/storage"
+)
+
+// pngReceipt is the start of a PNG file, enough for content sniffing
+var pngReceipt = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// proofFixture is a PaymentProofService storing files in a temporary directory
+type proofFixture struct {
+	events       *memory.EventRepository
+	participants *memory.ParticipantRepository
+	proofs       *memory.PaymentProofRepository
+	service      *PaymentProofService
+}
+
+func newProofFixture(t *testing.T) *proofFixture {
+	t.Helper()
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+
+	cfg := &config.Config{}
+	db := memory.NewDB()
+	tx := memory.NewTransactor(db)
+	f := &proofFixture{
+		events:       memory.NewEventRepository(db),
+		participants: memory.NewParticipantRepository(db),
+		proofs:       memory.NewPaymentProofRepository(db),
+	}
+	categories := memory.NewRaceCategoryRepository(db)
+	outbox := NewEmailOutbox(cfg, NewEmailService(cfg, mailer.NewMemoryMailer(), nil, f.events, memory.NewEmailLogRepository(db), nil, nil, nil), f.participants, memory.NewEmailOutboxRepository(db), tx)
+	bibs := NewBibService(f.events, categories, f.participants, memory.NewBibReservationRepository(db), tx)
+	paymentService := NewPaymentService(cfg, nil, outbox, bibs, f.events, categories, f.participants, memory.NewPaymentRepository(db), tx)
+	f.service = NewPaymentProofService(store, f.proofs, f.participants, paymentService, tx)
+	return f
+}
+
+// participant registers a runner for a new event who has not paid yet
+func (f *proofFixture) participant(t *testing.T) *models.Participant {
+	t.Helper()
+
+	event := &models.Event{Name: "City Run", EventDate: "2026-12-06", RegistrationOpen: true}
+	if err := f.events.Create(context.Background(), event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	p := &models.Participant{EventID: event.ID, Name: "Runner", Email: "runner@example.com", Phone: "+6281234567890", Address: "Jalan Merdeka 1"}
+	if err := f.participants.Create(context.Background(), p); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+	return p
+}
+
+func TestPaymentProofServiceUpload(t *testing.T) {
+	tests := []struct {
+		name         string
+		email        string
+		filename     string
+		data         []byte
+		paid         bool
+		wantErr      error
+		wantFilename string
+		wantType     string
+	}{
+		{
+			name:         "PNG receipt",
+			email:        " Runner@Example.com ",
+			filename:     `C:\Users\runner\receipt "BCA".png`,
+			data:         pngReceipt,
+			wantFilename: "receipt BCA.png",
+			wantType:     "image/png",
+		},
+		{
+			name:         "PDF without a filename",
+			email:        "runner@example.com",
+			data:         []byte("%PDF-1.7\n"),
+			wantFilename: "payment-proof.pdf",
+			wantType:     "application/pdf",
+		},
+		{name: "another runner's email", email: "other@example.com", data: pngReceipt, wantErr: ErrParticipantNotFound},
+		{name: "already paid", email: "runner@example.com", data: pngReceipt, paid: true, wantErr: ErrAlreadyPaid},
+		{name: "empty file", email: "runner@example.com", data: nil, wantErr: ErrProofEmpty},
+		{name: "HTML named as an image", email: "runner@example.com", filename: "receipt.png", data: []byte("<html><script>alert(1)</script>"), wantErr: ErrProofUnsupportedType},
+		{name: "too large", email: "runner@example.com", data: append(pngReceipt, make([]byte, MaxPaymentProofSize)...), wantErr: ErrProofTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newProofFixture(t)
+			ctx := context.Background()
+			p := f.participant(t)
+			if tt.paid {
+				if err := f.participants.UpdatePaymentStatus(ctx, p, "PAID"); err != nil {
+					t.Fatalf("failed to mark participant paid: %v", err)
+				}
+			}
+
+			proof, err := f.service.Upload(ctx, p.ID, tt.email, tt.filename, bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Upload() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if proof.Filename != tt.wantFilename || proof.ContentType != tt.wantType || proof.Status != ProofStatusPending {
+				t.Errorf("Upload() = %s %s %s, want %s %s %s",
+					proof.Filename, proof.ContentType, proof.Status, tt.wantFilename, tt.wantType, ProofStatusPending)
+			}
+
+			_, file, err := f.service.Open(ctx, proof.ID)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer file.Close()
+			stored, err := io.ReadAll(file)
+			if err != nil || !bytes.Equal(stored, tt.data) {
+				t.Errorf("stored file = %q, %v, want %q", stored, err, tt.data)
+			}
+		})
+	}
+}
+
+func TestPaymentProofServiceReview(t *testing.T) {
+	tests := []struct {
+		name       string
+		approve    bool
+		wantStatus string
+		wantPaid   bool
+	}{
+		{name: "approve", approve: true, wantStatus: ProofStatusApproved, wantPaid: true},
+		{name: "reject", wantStatus: ProofStatusRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newProofFixture(t)
+			ctx := context.Background()
+			p := f.participant(t)
+
+			proof, err := f.service.Upload(ctx, p.ID, p.Email, "receipt.png", bytes.NewReader(pngReceipt))
+			if err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+
+			review := func() error {
+				if tt.approve {
+					_, _, err := f.service.Approve(ctx, proof.ID, "admin-id")
+					return err
+				}
+				_, err := f.service.Reject(ctx, proof.ID, "admin-id", "  Amount is wrong  ")
+				return err
+			}
+			if err := review(); err != nil {
+				t.Fatalf("review error = %v", err)
+			}
+			if err := review(); !errors.Is(err, ErrProofAlreadyReviewed) {
+				t.Errorf("second review error = %v, want %v", err, ErrProofAlreadyReviewed)
+			}
+
+			stored, err := f.proofs.FindByID(ctx, proof.ID)
+			if err != nil {
+				t.Fatalf("failed to find proof: %v", err)
+			}
+			if stored.Status != tt.wantStatus || stored.ReviewedBy == nil || *stored.ReviewedBy != "admin-id" {
+				t.Errorf("proof = %s reviewed by %v, want %s by admin-id", stored.Status, stored.ReviewedBy, tt.wantStatus)
+			}
+			if !tt.approve && (stored.ReviewNote == nil || *stored.ReviewNote != "Amount is wrong") {
+				t.Errorf("review note = %v, want %q", stored.ReviewNote, "Amount is wrong")
+			}
+
+			got, err := f.participants.FindByID(ctx, p.ID)
+			if err != nil {
+				t.Fatalf("failed to find participant: %v", err)
+			}
+			if paid := got.PaymentStatus == "PAID"; paid != tt.wantPaid {
+				t.Errorf("participant payment status = %s, want PAID %t", got.PaymentStatus, tt.wantPaid)
+			}
+		})
+	}
+}
+
+func TestPaymentProofServiceApproveUnknownProof(t *testing.T) {
+	f := newProofFixture(t)
+
+	if _, _, err := f.service.Approve(context.Background(), "00000000-0000-4000-8000-000000000000", "admin-id"); !errors.Is(err, ErrProofNotFound) {
+		t.Errorf("Approve() error = %v, want %v", err, ErrProofNotFound)
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "receipt.png", want: "receipt.png"},
+		{name: "../../etc/passwd", want: "passwd"},
+		{name: `C:\Users\runner\receipt.pdf`, want: "receipt.pdf"},
+		{name: "re\x00ce\nipt\".png", want: "receipt.png"},
+		{name: "", want: "payment-proof.png"},
+		{name: "folder/", want: "folder"},
+		{name: strings.Repeat("a", 300) + ".png", want: strings.Repeat("a", 251) + ".png"},
+	}
+
+	for _, tt := range tests {
+		if got := sanitizeFilename(tt.name, ".png"); got != tt.want {
+			t.Errorf("sanitizeFilename(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
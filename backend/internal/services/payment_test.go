@@ -0,0 +1,394 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/payment"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+const testWebhookSecret = "test-webhook-secret"
+
+// paymentFixture is a PaymentService using the fake provider and an
+// in-memory database
+type paymentFixture struct {
+	events       *memory.EventRepository
+	categories   *memory.RaceCategoryRepository
+	participants *memory.ParticipantRepository
+	payments     *memory.PaymentRepository
+	outbox       *memory.EmailOutboxRepository
+	service      *PaymentService
+}
+
+func newPaymentFixture(t *testing.T) *paymentFixture {
+	t.Helper()
+
+	cfg := &config.Config{
+		Payment: config.PaymentConfig{Provider: "fake", WebhookSecret: testWebhookSecret, Amount: 150000, Currency: "IDR"},
+	}
+	db := memory.NewDB()
+	f := &paymentFixture{
+		events:       memory.NewEventRepository(db),
+		categories:   memory.NewRaceCategoryRepository(db),
+		participants: memory.NewParticipantRepository(db),
+		payments:     memory.NewPaymentRepository(db),
+		outbox:       memory.NewEmailOutboxRepository(db),
+	}
+	tx := memory.NewTransactor(db)
+	outbox := NewEmailOutbox(cfg, NewEmailService(cfg, mailer.NewMemoryMailer(), nil, f.events, memory.NewEmailLogRepository(db), nil, nil, nil), f.participants, f.outbox, tx)
+	bibs := NewBibService(f.events, f.categories, f.participants, memory.NewBibReservationRepository(db), tx)
+	f.service = NewPaymentService(cfg, payment.NewFakeProvider(testWebhookSecret), outbox, bibs, f.events, f.categories, f.participants, f.payments, tx)
+	return f
+}
+
+// participant registers a runner for a new event who has not paid yet
+func (f *paymentFixture) participant(t *testing.T) *models.Participant {
+	t.Helper()
+
+	event := &models.Event{Name: "City Run", EventDate: "2026-12-06", RegistrationOpen: true}
+	if err := f.events.Create(context.Background(), event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	p := &models.Participant{EventID: event.ID, Name: "Runner", Email: "runner@example.com", Phone: "+6281234567890", Address: "Jalan Merdeka 1"}
+	if err := f.participants.Create(context.Background(), p); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+	return p
+}
+
+// webhook delivers a fake provider callback signed with secret
+func (f *paymentFixture) webhook(secret, reference, status string, amount int64) (*PaymentStatusChange, error) {
+	payload := []byte(fmt.Sprintf(`{"reference":%q,"status":%q,"amount":%d}`, reference, status, amount))
+	headers := http.Header{}
+	headers.Set(payment.SignatureHeader, payment.Sign(secret, payload))
+	return f.service.HandleWebhook(context.Background(), payload, headers)
+}
+
+// queued returns the types of the emails waiting in the outbox
+func (f *paymentFixture) queued(t *testing.T) []string {
+	t.Helper()
+
+	emails, _, err := f.outbox.List(context.Background(), OutboxStatusPending, 1, 100)
+	if err != nil {
+		t.Fatalf("failed to list outbox: %v", err)
+	}
+	types := []string{}
+	for _, e := range emails {
+		types = append(types, e.EmailType)
+	}
+	return types
+}
+
+func TestPaymentServiceCreateCharge(t *testing.T) {
+	f := newPaymentFixture(t)
+	ctx := context.Background()
+	p := f.participant(t)
+
+	first, err := f.service.CreateCharge(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+	if first.Status != payment.StatusPending || first.Amount != 150000 || first.CheckoutURL == "" {
+		t.Errorf("CreateCharge() = %+v, want a pending charge of 150000 with a checkout URL", first)
+	}
+
+	second, err := f.service.CreateCharge(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("second CreateCharge() error = %v", err)
+	}
+	if second.Reference != first.Reference {
+		t.Errorf("second CreateCharge() reference = %s, want the open payment %s", second.Reference, first.Reference)
+	}
+
+	if err := f.participants.UpdatePaymentStatus(ctx, p, "PAID"); err != nil {
+		t.Fatalf("failed to mark participant paid: %v", err)
+	}
+	if _, err := f.service.CreateCharge(ctx, p.ID); !errors.Is(err, ErrAlreadyPaid) {
+		t.Errorf("CreateCharge() for a paid participant error = %v, want %v", err, ErrAlreadyPaid)
+	}
+	if _, err := f.service.CreateCharge(ctx, "00000000-0000-4000-8000-000000000000"); !errors.Is(err, ErrParticipantNotFound) {
+		t.Errorf("CreateCharge() for an unknown participant error = %v, want %v", err, ErrParticipantNotFound)
+	}
+}
+
+// slowProvider is the fake provider taking a while to open a charge, like a
+// real gateway does
+type slowProvider struct {
+	*payment.FakeProvider
+}
+
+func (p slowProvider) CreateCharge(ctx context.Context, req payment.ChargeRequest) (*payment.Charge, error) {
+	time.Sleep(10 * time.Millisecond)
+	return p.FakeProvider.CreateCharge(ctx, req)
+}
+
+func TestPaymentServiceCreateChargeConcurrent(t *testing.T) {
+	f := newPaymentFixture(t)
+	f.service.provider = slowProvider{payment.NewFakeProvider(testWebhookSecret)}
+	p := f.participant(t)
+
+	// Requests racing for the same participant all get the one payment
+	references := make([]string, 8)
+	var wg sync.WaitGroup
+	for i := range references {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			charge, err := f.service.CreateCharge(context.Background(), p.ID)
+			if err != nil {
+				t.Errorf("CreateCharge() error = %v", err)
+				return
+			}
+			references[i] = charge.Reference
+		}()
+	}
+	wg.Wait()
+
+	for _, reference := range references {
+		if reference != references[0] {
+			t.Fatalf("CreateCharge() references = %v, want a single payment", references)
+		}
+	}
+}
+
+func TestPaymentServiceCreateChargeCategoryPrice(t *testing.T) {
+	f := newPaymentFixture(t)
+	ctx := context.Background()
+	event := f.participant(t).EventID
+
+	category := &models.RaceCategory{EventID: event, Name: "Half Marathon", DistanceKM: 21.1, Price: 350000, Currency: "IDR", Capacity: 100}
+	if err := f.categories.Create(ctx, category); err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+	p := &models.Participant{EventID: event, CategoryID: &category.ID, Name: "Half Runner", Email: "half@example.com", Phone: "+6281234567890", Address: "Jalan Merdeka 1"}
+	if err := f.participants.Create(ctx, p); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+
+	charge, err := f.service.CreateCharge(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+	if charge.Amount != 350000 || charge.Currency != "IDR" {
+		t.Errorf("CreateCharge() = %d %s, want the category price 350000 IDR", charge.Amount, charge.Currency)
+	}
+}
+
+func TestPaymentServiceCreateChargeDisabled(t *testing.T) {
+	db := memory.NewDB()
+	service := NewPaymentService(&config.Config{}, nil, nil, nil, memory.NewEventRepository(db), memory.NewRaceCategoryRepository(db), memory.NewParticipantRepository(db), memory.NewPaymentRepository(db), memory.NewTransactor(db))
+
+	if _, err := service.CreateCharge(context.Background(), "participant-id"); !errors.Is(err, ErrPaymentsDisabled) {
+		t.Errorf("CreateCharge() error = %v, want %v", err, ErrPaymentsDisabled)
+	}
+}
+
+func TestPaymentServiceHandleWebhook(t *testing.T) {
+	tests := []struct {
+		name              string
+		secret            string
+		status            string
+		amount            int64
+		wantErr           error
+		wantPaymentStatus string
+		wantPaid          bool
+	}{
+		{
+			name:              "paid",
+			secret:            testWebhookSecret,
+			status:            "PAID",
+			amount:            150000,
+			wantPaymentStatus: payment.StatusPaid,
+			wantPaid:          true,
+		},
+		{
+			name:              "paid with another amount",
+			secret:            testWebhookSecret,
+			status:            "PAID",
+			amount:            1000,
+			wantErr:           ErrAmountMismatch,
+			wantPaymentStatus: payment.StatusPending,
+		},
+		{
+			name:              "paid without an amount",
+			secret:            testWebhookSecret,
+			status:            "PAID",
+			wantErr:           ErrAmountMismatch,
+			wantPaymentStatus: payment.StatusPending,
+		},
+		{
+			name:              "forged signature",
+			secret:            "guessed-secret",
+			status:            "PAID",
+			amount:            150000,
+			wantErr:           payment.ErrInvalidSignature,
+			wantPaymentStatus: payment.StatusPending,
+		},
+		{
+			name:              "expired",
+			secret:            testWebhookSecret,
+			status:            "EXPIRED",
+			amount:            150000,
+			wantPaymentStatus: payment.StatusExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPaymentFixture(t)
+			ctx := context.Background()
+			p := f.participant(t)
+
+			charge, err := f.service.CreateCharge(ctx, p.ID)
+			if err != nil {
+				t.Fatalf("CreateCharge() error = %v", err)
+			}
+
+			change, err := f.webhook(tt.secret, charge.Reference, tt.status, tt.amount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("HandleWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+
+			stored, err := f.payments.FindByReferenceForUpdate(ctx, charge.Reference)
+			if err != nil {
+				t.Fatalf("failed to find payment: %v", err)
+			}
+			if stored.Status != tt.wantPaymentStatus {
+				t.Errorf("payment status = %s, want %s", stored.Status, tt.wantPaymentStatus)
+			}
+
+			got, err := f.participants.FindByID(ctx, p.ID)
+			if err != nil {
+				t.Fatalf("failed to find participant: %v", err)
+			}
+			if paid := got.PaymentStatus == "PAID"; paid != tt.wantPaid {
+				t.Errorf("participant payment status = %s, want PAID %t", got.PaymentStatus, tt.wantPaid)
+			}
+			if tt.wantPaid && (change == nil || !change.EmailSent) {
+				t.Errorf("HandleWebhook() change = %+v, want the confirmation email sent", change)
+			}
+			if !tt.wantPaid && change != nil {
+				t.Errorf("HandleWebhook() change = %+v, want none", change)
+			}
+
+			queued := f.queued(t)
+			if tt.wantPaid && (len(queued) != 1 || queued[0] != EmailTypePaymentConfirmation) {
+				t.Errorf("queued emails = %v, want one %s", queued, EmailTypePaymentConfirmation)
+			}
+			if !tt.wantPaid && len(queued) != 0 {
+				t.Errorf("queued emails = %v, want none", queued)
+			}
+		})
+	}
+}
+
+func TestPaymentServiceHandleWebhookRepeated(t *testing.T) {
+	f := newPaymentFixture(t)
+	ctx := context.Background()
+	p := f.participant(t)
+
+	charge, err := f.service.CreateCharge(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+
+	if _, err := f.webhook(testWebhookSecret, charge.Reference, "PAID", charge.Amount); err != nil {
+		t.Fatalf("first HandleWebhook() error = %v", err)
+	}
+	change, err := f.webhook(testWebhookSecret, charge.Reference, "PAID", charge.Amount)
+	if err != nil {
+		t.Fatalf("repeated HandleWebhook() error = %v", err)
+	}
+	if change != nil {
+		t.Errorf("repeated HandleWebhook() change = %+v, want none", change)
+	}
+
+	// A late failure callback does not undo the payment
+	if _, err := f.webhook(testWebhookSecret, charge.Reference, "EXPIRED", charge.Amount); err != nil {
+		t.Fatalf("late HandleWebhook() error = %v", err)
+	}
+	stored, err := f.payments.FindByReferenceForUpdate(ctx, charge.Reference)
+	if err != nil {
+		t.Fatalf("failed to find payment: %v", err)
+	}
+	if stored.Status != payment.StatusPaid {
+		t.Errorf("payment status after a late failure = %s, want %s", stored.Status, payment.StatusPaid)
+	}
+
+	if queued := f.queued(t); len(queued) != 1 {
+		t.Errorf("queued emails = %v, want one confirmation", queued)
+	}
+}
+
+func TestPaymentServiceHandleWebhookUnknownReference(t *testing.T) {
+	f := newPaymentFixture(t)
+
+	_, err := f.webhook(testWebhookSecret, "TTR-UNKNOWN", "PAID", 150000)
+	if !errors.Is(err, ErrPaymentNotFound) {
+		t.Fatalf("HandleWebhook() error = %v, want %v", err, ErrPaymentNotFound)
+	}
+}
+
+func TestPaymentServiceNoSpot(t *testing.T) {
+	tests := []struct {
+		registrationStatus string
+		wantErr            error
+	}{
+		{registrationStatus: "WAITLISTED", wantErr: ErrWaitlisted},
+		{registrationStatus: "EXPIRED", wantErr: ErrOfferExpired},
+		{registrationStatus: "CANCELLED", wantErr: ErrCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.registrationStatus, func(t *testing.T) {
+			f := newPaymentFixture(t)
+			ctx := context.Background()
+			p := f.participant(t)
+
+			// The charge was created while the runner still held the spot
+			charge, err := f.service.CreateCharge(ctx, p.ID)
+			if err != nil {
+				t.Fatalf("CreateCharge() error = %v", err)
+			}
+			p.RegistrationStatus = tt.registrationStatus
+			if err := f.participants.UpdateRegistration(ctx, p); err != nil {
+				t.Fatalf("failed to update registration: %v", err)
+			}
+
+			if _, err := f.service.UpdateStatus(ctx, p.ID, "PAID"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateStatus() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// Money that arrives anyway is recorded and owed back, but gives no spot
+			if _, err := f.webhook(testWebhookSecret, charge.Reference, "PAID", 150000); err != nil {
+				t.Fatalf("HandleWebhook() error = %v", err)
+			}
+			stored, err := f.payments.FindByReferenceForUpdate(ctx, charge.Reference)
+			if err != nil || stored.Status != payment.StatusPaid {
+				t.Errorf("payment = %+v, %v, want PAID", stored, err)
+			}
+			got, err := f.participants.FindByID(ctx, p.ID)
+			if err != nil {
+				t.Fatalf("failed to find participant: %v", err)
+			}
+			if got.PaymentStatus != "REFUND_PENDING" || got.BibNumber != nil {
+				t.Errorf("participant = %s with bib %v, want REFUND_PENDING without a bib", got.PaymentStatus, got.BibNumber)
+			}
+			if got.RefundAmount == nil || *got.RefundAmount != 150000 || got.RefundCurrency == nil || *got.RefundCurrency != "IDR" {
+				t.Errorf("refund = %v %v, want the payment of 150000 IDR", got.RefundAmount, got.RefundCurrency)
+			}
+			if queued := f.queued(t); len(queued) != 0 {
+				t.Errorf("queued emails = %v, want none", queued)
+			}
+		})
+	}
+}
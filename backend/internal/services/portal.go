@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// ErrInvalidLoginLink is returned when a magic link is invalid or expired
+var ErrInvalidLoginLink = errors.New("login link is invalid or expired")
+
+// PortalService runs the participant portal, where runners see their own
+// registrations, update their contact details, cancel and transfer them to
+// another runner without a password.
+//
+// A participant asks for a magic link by entering their email address and gets
+// it by email, so only the owner of the address can log in. The link is
+// exchanged for a participant session, which covers every registration of the
+// address and is separate from admin sessions.
+type PortalService struct {
+	auth         *AuthService
+	events       repository.EventRepository
+	categories   repository.RaceCategoryRepository
+	participants repository.ParticipantRepository
+	transfers    repository.TransferRepository
+	outbox       *EmailOutbox
+	cancellation *CancellationService
+	transfer     *TransferService
+	tx           repository.Transactor
+}
+
+// NewPortalService creates a new participant portal service
+func NewPortalService(
+	auth *AuthService,
+	events repository.EventRepository,
+	categories repository.RaceCategoryRepository,
+	participants repository.ParticipantRepository,
+	transfers repository.TransferRepository,
+	outbox *EmailOutbox,
+	cancellation *CancellationService,
+	transfer *TransferService,
+	tx repository.Transactor,
+) *PortalService {
+	return &PortalService{
+		auth:         auth,
+		events:       events,
+		categories:   categories,
+		participants: participants,
+		transfers:    transfers,
+		outbox:       outbox,
+		cancellation: cancellation,
+		transfer:     transfer,
+		tx:           tx,
+	}
+}
+
+// RequestLogin emails a magic link to email if anybody registered with it.
+// Nothing tells the caller whether they did.
+func (s *PortalService) RequestLogin(ctx context.Context, email string) error {
+	registrations, err := s.participants.ListByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if len(registrations) == 0 {
+		utils.AuthLogger.Info("Portal login requested for unregistered email %s", email)
+		return nil
+	}
+
+	// One link covers every registration; it shows the latest event in the email
+	return s.outbox.Enqueue(ctx, registrations[0].ID, EmailTypePortalLogin)
+}
+
+// Login exchanges the token of a magic link for a participant session token
+func (s *PortalService) Login(ctx context.Context, loginToken string) (string, time.Time, error) {
+	email, err := s.auth.ValidateParticipantLoginToken(loginToken)
+	if err != nil {
+		return "", time.Time{}, ErrInvalidLoginLink
+	}
+
+	// The registrations may have been deleted since the link was sent
+	registrations, err := s.participants.ListByEmail(ctx, email)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if len(registrations) == 0 {
+		return "", time.Time{}, ErrInvalidLoginLink
+	}
+
+	return s.auth.GenerateParticipantToken(email)
+}
+
+// Registrations returns the registrations of email, newest first
+func (s *PortalService) Registrations(ctx context.Context, email string) ([]models.PortalRegistration, error) {
+	participants, err := s.participants.ListByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	registrations := make([]models.PortalRegistration, 0, len(participants))
+	for i := range participants {
+		registration, err := s.registration(ctx, &participants[i])
+		if err != nil {
+			return nil, err
+		}
+		registrations = append(registrations, *registration)
+	}
+
+	return registrations, nil
+}
+
+// UpdateRegistration changes the contact details of a registration of email.
+// req must already be validated.
+func (s *PortalService) UpdateRegistration(ctx context.Context, email, participantID string, req models.PortalUpdateRequest) (*models.PortalRegistration, error) {
+	var participant *models.Participant
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		participant, err = s.participants.FindByIDForUpdate(ctx, participantID)
+		if err != nil {
+			return err
+		}
+		// Other people's registrations look the same as ones that don't exist
+		if participant == nil || participant.Email != email {
+			return ErrParticipantNotFound
+		}
+
+		if req.Phone != nil {
+			participant.Phone = *req.Phone
+		}
+		if req.Address != nil {
+			participant.Address = *req.Address
+		}
+		if req.InstagramHandle != nil {
+			participant.InstagramHandle = req.InstagramHandle
+			if *req.InstagramHandle == "" {
+				participant.InstagramHandle = nil
+			}
+		}
+
+		return s.participants.UpdateContact(ctx, participant)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.registration(ctx, participant)
+}
+
+// CancelRegistration cancels a registration of email on the participant's behalf
+func (s *PortalService) CancelRegistration(ctx context.Context, email, participantID, reason string) (*models.PortalRegistration, error) {
+	participant, err := s.cancellation.CancelOwn(ctx, email, participantID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.registration(ctx, participant)
+}
+
+// TransferRegistration starts a transfer of a registration of email to
+// another runner on the participant's behalf. name and toEmail must already
+// be validated.
+func (s *PortalService) TransferRegistration(ctx context.Context, email, participantID, name, toEmail string) (*models.PortalRegistration, error) {
+	transfer, err := s.transfer.StartOwn(ctx, email, participantID, name, toEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findRegistration(ctx, transfer.ParticipantID)
+}
+
+// CancelTransfer withdraws the pending transfer of a registration of email
+func (s *PortalService) CancelTransfer(ctx context.Context, email, participantID string) (*models.PortalRegistration, error) {
+	transfer, err := s.transfer.CancelOwn(ctx, email, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findRegistration(ctx, transfer.ParticipantID)
+}
+
+// findRegistration returns what a participant sees of a registration by ID
+func (s *PortalService) findRegistration(ctx context.Context, participantID string) (*models.PortalRegistration, error) {
+	participant, err := s.participants.FindByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	return s.registration(ctx, participant)
+}
+
+// registration returns what a participant sees of their registration
+func (s *PortalService) registration(ctx context.Context, p *models.Participant) (*models.PortalRegistration, error) {
+	registration := &models.PortalRegistration{
+		ID:                 p.ID,
+		EventID:            p.EventID,
+		CategoryID:         p.CategoryID,
+		Name:               p.Name,
+		Email:              p.Email,
+		Phone:              p.Phone,
+		InstagramHandle:    p.InstagramHandle,
+		Address:            p.Address,
+		RegistrationStatus: p.RegistrationStatus,
+		PaymentStatus:      p.PaymentStatus,
+		WaitlistPosition:   p.WaitlistPosition,
+		OfferExpiresAt:     p.OfferExpiresAt,
+		BibNumber:          p.BibNumber,
+		KitCollected:       p.KitCollectedAt != nil,
+		CheckedIn:          p.CheckedInAt != nil,
+		CancelledAt:        p.CancelledAt,
+		CancellationReason: p.CancellationReason,
+		RefundAmount:       p.RefundAmount,
+		RefundCurrency:     p.RefundCurrency,
+		RefundedAt:         p.RefundedAt,
+		CreatedAt:          p.CreatedAt,
+	}
+
+	event, err := s.events.FindByID(ctx, p.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if event != nil {
+		registration.EventName = event.Name
+		registration.EventDate = event.EventDate
+		registration.EventLocation = event.Location
+	}
+
+	if p.CategoryID != nil {
+		category, err := s.categories.FindByID(ctx, *p.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		if category != nil {
+			registration.CategoryName = &category.Name
+		}
+	}
+
+	pending, err := s.transfers.FindPending(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	registration.PendingTransfer = pending
+
+	return registration, nil
+}
+
+// portalLink returns the magic link that logs in with loginToken
+func portalLink(cfg *config.Config, loginToken string) string {
+	separator := "?"
+	if strings.Contains(cfg.Portal.URL, "?") {
+		separator = "&"
+	}
+	return cfg.Portal.URL + separator + url.Values{"token": {loginToken}}.Encode()
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+const testJWTSecret = "test-jwt-secret-at-least-32-bytes-long"
+
+// portalLinkPattern finds the login token of a magic link in an email
+var portalLinkPattern = regexp.MustCompile(`https://tautaurun\.id/portal\?token=([A-Za-z0-9._-]+)`)
+
+// portalFixture is a runner registered for two events and a stranger, with the
+// participant portal on an in-memory database
+type portalFixture struct {
+	config       *config.Config
+	earlier      *models.Participant // Registration of the runner for the earlier event
+	latest       *models.Participant // Registration of the runner for the latest event
+	stranger     *models.Participant
+	participants *memory.ParticipantRepository
+	mailer       *mailer.MemoryMailer
+	outbox       *EmailOutbox
+	auth         *AuthService
+	service      *PortalService
+}
+
+func newPortalFixture(t *testing.T) *portalFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memory.NewDB()
+	events := memory.NewEventRepository(db)
+	categories := memory.NewRaceCategoryRepository(db)
+	tx := memory.NewTransactor(db)
+
+	f := &portalFixture{
+		config: &config.Config{
+			JWT:     config.JWTConfig{Secret: testJWTSecret, AccessTokenMinutes: 15},
+			SMTP:    config.SMTPConfig{FromEmail: "noreply@tautaurun.id", FromName: "Tau-Tau Run"},
+			Outbox:  config.OutboxConfig{Workers: 1, MaxAttempts: 3, RetrySeconds: 60},
+			CheckIn: config.CheckInConfig{TokenSecret: testCheckInSecret},
+			Portal:  config.PortalConfig{URL: "https://tautaurun.id/portal", LinkMinutes: 15, SessionHours: 24},
+		},
+		participants: memory.NewParticipantRepository(db),
+		mailer:       mailer.NewMemoryMailer(),
+	}
+
+	register := func(eventName, name, email string) *models.Participant {
+		event := &models.Event{Name: eventName, EventDate: "2026-12-06", Location: "Jakarta"}
+		if err := events.Create(ctx, event); err != nil {
+			t.Fatalf("failed to create event: %v", err)
+		}
+		p := &models.Participant{EventID: event.ID, Name: name, Email: email, Phone: "+6281234567890", Address: "Jalan Merdeka 1"}
+		if err := f.participants.Create(ctx, p); err != nil {
+			t.Fatalf("failed to create participant: %v", err)
+		}
+		return p
+	}
+	f.earlier = register("Trail Run", "Dewi", "dewi@example.com")
+	time.Sleep(time.Millisecond) // Registrations are listed newest first
+	f.latest = register("City Run", "Dewi", "dewi@example.com")
+	f.stranger = register("Night Run", "Budi", "budi@example.com")
+
+	f.auth = NewAuthService(f.config)
+	checkIn := NewCheckInService(f.config, memory.NewAdminRepository(db), f.participants, tx)
+	templates := NewEmailTemplateService(f.config, checkIn, f.auth, events, categories, f.participants, memory.NewEmailTemplateRepository(db), memory.NewTransferRepository(db), tx)
+	emailService := NewEmailService(f.config, f.mailer, templates, events, memory.NewEmailLogRepository(db), memory.NewEmailCampaignRepository(db), memory.NewTransferRepository(db), checkIn)
+	f.outbox = NewEmailOutbox(f.config, emailService, f.participants, memory.NewEmailOutboxRepository(db), tx)
+	f.service = NewPortalService(f.auth, events, categories, f.participants, memory.NewTransferRepository(db), f.outbox, nil, nil, tx)
+	return f
+}
+
+func TestPortalServiceLogin(t *testing.T) {
+	f := newPortalFixture(t)
+	ctx := context.Background()
+
+	if err := f.service.RequestLogin(ctx, "dewi@example.com"); err != nil {
+		t.Fatalf("RequestLogin() error = %v", err)
+	}
+	if !f.outbox.deliverNext(ctx) {
+		t.Fatal("deliverNext() = false, want the login email delivered")
+	}
+
+	sent := f.mailer.Sent()
+	if len(sent) != 1 || sent[0].Message.To[0].Address != "dewi@example.com" {
+		t.Fatalf("sent %d messages, want the login email to dewi@example.com", len(sent))
+	}
+	match := portalLinkPattern.FindStringSubmatch(sent[0].Message.Text)
+	if match == nil {
+		t.Fatalf("login email has no portal link:\n%s", sent[0].Message.Text)
+	}
+	loginToken, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatalf("failed to read login token: %v", err)
+	}
+
+	session, expiresAt, err := f.service.Login(ctx, loginToken)
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if time.Until(expiresAt) < 23*time.Hour {
+		t.Errorf("session expires at %v, want in 24 hours", expiresAt)
+	}
+	claims, err := f.auth.ValidateParticipantToken(session)
+	if err != nil || claims.Email != "dewi@example.com" {
+		t.Errorf("ValidateParticipantToken() = %+v, %v, want a session for dewi@example.com", claims, err)
+	}
+}
+
+func TestPortalServiceRequestLoginUnregistered(t *testing.T) {
+	f := newPortalFixture(t)
+	ctx := context.Background()
+
+	if err := f.service.RequestLogin(ctx, "nobody@example.com"); err != nil {
+		t.Fatalf("RequestLogin() error = %v, want no hint that the address is unknown", err)
+	}
+	if emails, total, err := f.outbox.List(ctx, OutboxStatusPending, 1, 10); err != nil || total != 0 {
+		t.Errorf("outbox = %d emails, %v, want none", len(emails), err)
+	}
+}
+
+func TestPortalServiceLoginInvalid(t *testing.T) {
+	f := newPortalFixture(t)
+
+	session, _, err := f.auth.GenerateParticipantToken("dewi@example.com")
+	if err != nil {
+		t.Fatalf("GenerateParticipantToken() error = %v", err)
+	}
+	adminToken, _, err := f.auth.GenerateToken("admin-1", "dewi@example.com", RoleOwner, "admin-session")
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	unregistered, err := f.auth.GenerateParticipantLoginToken("nobody@example.com")
+	if err != nil {
+		t.Fatalf("GenerateParticipantLoginToken() error = %v", err)
+	}
+	expired := *f.config
+	expired.Portal.LinkMinutes = -1
+	expiredLink, err := NewAuthService(&expired).GenerateParticipantLoginToken("dewi@example.com")
+	if err != nil {
+		t.Fatalf("GenerateParticipantLoginToken() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"session token":        session,
+		"admin token":          adminToken,
+		"unregistered address": unregistered,
+		"expired link":         expiredLink,
+		"garbage":              "not-a-token",
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := f.service.Login(context.Background(), token); !errors.Is(err, ErrInvalidLoginLink) {
+				t.Errorf("Login() error = %v, want ErrInvalidLoginLink", err)
+			}
+		})
+	}
+}
+
+func TestAuthServiceParticipantTokens(t *testing.T) {
+	f := newPortalFixture(t)
+
+	loginToken, err := f.auth.GenerateParticipantLoginToken("dewi@example.com")
+	if err != nil {
+		t.Fatalf("GenerateParticipantLoginToken() error = %v", err)
+	}
+	session, _, err := f.auth.GenerateParticipantToken("dewi@example.com")
+	if err != nil {
+		t.Fatalf("GenerateParticipantToken() error = %v", err)
+	}
+
+	if _, err := f.auth.ValidateParticipantToken(loginToken); err == nil {
+		t.Error("ValidateParticipantToken() accepted a login link as a session")
+	}
+	if _, err := f.auth.ValidateToken(session); err == nil {
+		t.Error("ValidateToken() accepted a participant session as an admin token")
+	}
+}
+
+func TestPortalServiceRegistrations(t *testing.T) {
+	f := newPortalFixture(t)
+
+	registrations, err := f.service.Registrations(context.Background(), "dewi@example.com")
+	if err != nil {
+		t.Fatalf("Registrations() error = %v", err)
+	}
+	if len(registrations) != 2 || registrations[0].ID != f.latest.ID || registrations[1].ID != f.earlier.ID {
+		t.Fatalf("Registrations() = %+v, want both of Dewi's, newest first", registrations)
+	}
+	if registrations[0].EventName != "City Run" || registrations[0].PaymentStatus != "UNPAID" {
+		t.Errorf("registration = %+v, want the City Run details", registrations[0])
+	}
+}
+
+func TestPortalServiceUpdateRegistration(t *testing.T) {
+	f := newPortalFixture(t)
+	ctx := context.Background()
+
+	instagram := "@dewi.runs"
+	if _, err := f.service.UpdateRegistration(ctx, "dewi@example.com", f.latest.ID, models.PortalUpdateRequest{InstagramHandle: &instagram}); err != nil {
+		t.Fatalf("UpdateRegistration() error = %v", err)
+	}
+
+	phone, removed := "+6289876543210", ""
+	updated, err := f.service.UpdateRegistration(ctx, "dewi@example.com", f.latest.ID, models.PortalUpdateRequest{Phone: &phone, InstagramHandle: &removed})
+	if err != nil {
+		t.Fatalf("UpdateRegistration() error = %v", err)
+	}
+	if updated.Phone != phone || updated.Address != "Jalan Merdeka 1" || updated.InstagramHandle != nil {
+		t.Errorf("UpdateRegistration() = phone %s, address %s, instagram %v, want the new phone, the old address and no instagram",
+			updated.Phone, updated.Address, updated.InstagramHandle)
+	}
+
+	stored, err := f.participants.FindByID(ctx, f.latest.ID)
+	if err != nil || stored.Phone != phone {
+		t.Errorf("stored phone = %v, %v, want %s", stored, err, phone)
+	}
+
+	if _, err := f.service.UpdateRegistration(ctx, "dewi@example.com", f.stranger.ID, models.PortalUpdateRequest{Phone: &phone}); !errors.Is(err, ErrParticipantNotFound) {
+		t.Errorf("UpdateRegistration() of someone else's registration error = %v, want ErrParticipantNotFound", err)
+	}
+	if stranger, _ := f.participants.FindByID(ctx, f.stranger.ID); stranger.Phone == phone {
+		t.Error("UpdateRegistration() changed someone else's registration")
+	}
+}
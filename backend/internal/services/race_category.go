@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// ErrCategoryNotFound is returned when a race category does not exist or
+// belongs to a different event
+var ErrCategoryNotFound = errors.New("race category not found")
+
+// RaceCategoryService manages the race categories of events
+type RaceCategoryService struct {
+	config     *config.Config
+	events     repository.EventRepository
+	categories repository.RaceCategoryRepository
+	waitlist   *WaitlistService
+}
+
+// NewRaceCategoryService creates a new race category service
+func NewRaceCategoryService(
+	cfg *config.Config,
+	events repository.EventRepository,
+	categories repository.RaceCategoryRepository,
+	waitlist *WaitlistService,
+) *RaceCategoryService {
+	return &RaceCategoryService{
+		config:     cfg,
+		events:     events,
+		categories: categories,
+		waitlist:   waitlist,
+	}
+}
+
+// ListByEvent returns the categories of an event, shortest distance first
+func (s *RaceCategoryService) ListByEvent(ctx context.Context, eventID string) ([]models.RaceCategory, error) {
+	if err := s.requireEvent(ctx, eventID); err != nil {
+		return nil, err
+	}
+	return s.categories.ListByEvent(ctx, eventID)
+}
+
+// Get returns a race category by ID
+func (s *RaceCategoryService) Get(ctx context.Context, id string) (*models.RaceCategory, error) {
+	category, err := s.categories.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+// Create adds a category to category.EventID. An empty currency defaults to
+// the configured payment currency.
+func (s *RaceCategoryService) Create(ctx context.Context, category *models.RaceCategory) error {
+	if err := s.requireEvent(ctx, category.EventID); err != nil {
+		return err
+	}
+	if category.Currency == "" {
+		category.Currency = s.config.Payment.Currency
+	}
+	return s.categories.Create(ctx, category)
+}
+
+// Update replaces the details of an existing category. Lowering the capacity
+// below the number of registered participants closes the category without
+// removing anyone from it; raising it offers the new spots to the waitlist.
+func (s *RaceCategoryService) Update(ctx context.Context, category *models.RaceCategory) error {
+	existing, err := s.Get(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	if category.Currency == "" {
+		category.Currency = existing.Currency
+	}
+
+	if err := s.categories.Update(ctx, category); err != nil {
+		return err
+	}
+
+	// The category is saved either way, so a failed fill is only logged
+	if err := s.waitlist.Fill(ctx, existing.EventID, &category.ID); err != nil {
+		utils.ServerLogger.Error("Failed to fill waitlist of race category %s: %v", category.ID, err)
+	}
+
+	updated, err := s.Get(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	*category = *updated
+	return nil
+}
+
+// Delete removes a category nobody has registered for
+func (s *RaceCategoryService) Delete(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	return s.categories.Delete(ctx, id)
+}
+
+// requireEvent returns ErrEventNotFound if the event does not exist
+func (s *RaceCategoryService) requireEvent(ctx context.Context, eventID string) error {
+	event, err := s.events.FindByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return ErrEventNotFound
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+)
+
+// Errors returned by RegistrationService
+var (
+	ErrCategoryRequired    = errors.New("a race category must be chosen for this event")
+	ErrDateOfBirthRequired = errors.New("date of birth is required for this race category")
+	ErrAgeNotEligible      = errors.New("participant age is outside the race category limits")
+)
+
+// RegistrationService registers participants for events, enforcing race
+// category age limits and waitlisting registrations beyond capacity
+type RegistrationService struct {
+	categories   repository.RaceCategoryRepository
+	participants repository.ParticipantRepository
+	waitlist     *WaitlistService
+	tx           repository.Transactor
+}
+
+// NewRegistrationService creates a new registration service
+func NewRegistrationService(
+	categories repository.RaceCategoryRepository,
+	participants repository.ParticipantRepository,
+	waitlist *WaitlistService,
+	tx repository.Transactor,
+) *RegistrationService {
+	return &RegistrationService{
+		categories:   categories,
+		participants: participants,
+		waitlist:     waitlist,
+		tx:           tx,
+	}
+}
+
+// Register creates a participant for event from a sanitized and validated
+// request. It returns repository.ErrDuplicateEmail if the email is already
+// registered for the event. When the event or chosen category is full, the
+// participant is created WAITLISTED with their position in the queue.
+func (s *RegistrationService) Register(ctx context.Context, event *models.Event, req *models.CreateParticipantRequest) (*models.Participant, error) {
+	participant := &models.Participant{
+		EventID:         event.ID,
+		Name:            req.Name,
+		Email:           req.Email,
+		Phone:           req.Phone,
+		InstagramHandle: req.InstagramHandle,
+		Address:         req.Address,
+		DateOfBirth:     req.DateOfBirth,
+	}
+
+	// The category or event row stays locked until the participant is inserted,
+	// so concurrent registrations cannot both take its last spot
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := s.participants.FindByEventAndEmail(ctx, event.ID, req.Email)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return repository.ErrDuplicateEmail
+		}
+
+		category, err := s.chooseCategory(ctx, event, req.CategoryID, req.DateOfBirth)
+		if err != nil {
+			return err
+		}
+		if category != nil {
+			participant.CategoryID = &category.ID
+		}
+
+		position, err := s.waitlist.placement(ctx, event.ID, participant.CategoryID)
+		if err != nil {
+			return err
+		}
+		if position != nil {
+			participant.RegistrationStatus = "WAITLISTED"
+			participant.WaitlistPosition = position
+		}
+
+		return s.participants.Create(ctx, participant)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return participant, nil
+}
+
+// chooseCategory checks that the participant may enter the chosen category.
+// It returns nil when the event has no categories.
+func (s *RegistrationService) chooseCategory(ctx context.Context, event *models.Event, categoryID string, dateOfBirth *string) (*models.RaceCategory, error) {
+	if categoryID == "" {
+		categories, err := s.categories.ListByEvent(ctx, event.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(categories) > 0 {
+			return nil, ErrCategoryRequired
+		}
+		return nil, nil
+	}
+
+	category, err := s.categories.FindByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil || category.EventID != event.ID {
+		return nil, ErrCategoryNotFound
+	}
+
+	if err := checkAge(category, dateOfBirth, event); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// checkAge checks that someone born on dateOfBirth is within the age limits
+// of a race category on the date of its event
+func checkAge(category *models.RaceCategory, dateOfBirth *string, event *models.Event) error {
+	if category.MinAge == nil && category.MaxAge == nil {
+		return nil
+	}
+
+	if dateOfBirth == nil {
+		return ErrDateOfBirthRequired
+	}
+	age, err := ageOn(*dateOfBirth, event.EventDate)
+	if err != nil {
+		return err
+	}
+	if (category.MinAge != nil && age < *category.MinAge) || (category.MaxAge != nil && age > *category.MaxAge) {
+		return ErrAgeNotEligible
+	}
+
+	return nil
+}
+
+// ageOn returns the age in whole years on date of someone born on dateOfBirth,
+// both formatted as YYYY-MM-DD
+func ageOn(dateOfBirth, date string) (int, error) {
+	born, err := time.Parse("2006-01-02", dateOfBirth)
+	if err != nil {
+		return 0, err
+	}
+	on, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return 0, err
+	}
+
+	age := on.Year() - born.Year()
+	if on.Month() < born.Month() || (on.Month() == born.Month() && on.Day() < born.Day()) {
+		age--
+	}
+	return age, nil
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// registrationFixture is a RegistrationService for one event on an
+// in-memory database
+type registrationFixture struct {
+	event        *models.Event
+	categories   *memory.RaceCategoryRepository
+	participants *memory.ParticipantRepository
+	service      *RegistrationService
+}
+
+func newRegistrationFixture(t *testing.T) *registrationFixture {
+	t.Helper()
+
+	db := memory.NewDB()
+	f := &registrationFixture{
+		event:        &models.Event{Name: "City Run", EventDate: "2026-12-06", Location: "Jakarta", RegistrationOpen: true},
+		categories:   memory.NewRaceCategoryRepository(db),
+		participants: memory.NewParticipantRepository(db),
+	}
+	events := memory.NewEventRepository(db)
+	if err := events.Create(context.Background(), f.event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+
+	cfg := &config.Config{Waitlist: config.WaitlistConfig{OfferHours: 48}}
+	tx := memory.NewTransactor(db)
+	outbox := NewEmailOutbox(cfg, NewEmailService(cfg, mailer.NewMemoryMailer(), nil, events, memory.NewEmailLogRepository(db), nil, nil, nil), f.participants, memory.NewEmailOutboxRepository(db), tx)
+	waitlist := NewWaitlistService(cfg, events, f.categories, f.participants, outbox, tx)
+	f.service = NewRegistrationService(f.categories, f.participants, waitlist, tx)
+	return f
+}
+
+// category adds a race category to eventID
+func (f *registrationFixture) category(t *testing.T, eventID, name string, capacity int, minAge, maxAge *int) *models.RaceCategory {
+	t.Helper()
+
+	c := &models.RaceCategory{EventID: eventID, Name: name, DistanceKM: 10, Price: 200000, Currency: "IDR", Capacity: capacity, MinAge: minAge, MaxAge: maxAge}
+	if err := f.categories.Create(context.Background(), c); err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+	return c
+}
+
+// request returns a valid registration request for email
+func request(email, categoryID string, dateOfBirth *string) *models.CreateParticipantRequest {
+	return &models.CreateParticipantRequest{
+		CategoryID:  categoryID,
+		Name:        "Runner",
+		Email:       email,
+		Phone:       "+6281234567890",
+		Address:     "Jalan Merdeka 1",
+		DateOfBirth: dateOfBirth,
+	}
+}
+
+func ptrInt(n int) *int {
+	return &n
+}
+
+func ptrStr(s string) *string {
+	return &s
+}
+
+func TestRegistrationServiceRegister(t *testing.T) {
+	tests := []struct {
+		name        string
+		category    string // "open", "adults", "other-event", "" for no choice, or a raw ID
+		dateOfBirth *string
+		wantErr     error
+	}{
+		{name: "category", category: "open"},
+		{name: "no category chosen", category: "", wantErr: ErrCategoryRequired},
+		{name: "category of another event", category: "other-event", wantErr: ErrCategoryNotFound},
+		{name: "unknown category", category: "00000000-0000-4000-8000-000000000000", wantErr: ErrCategoryNotFound},
+		{name: "age limit without a date of birth", category: "adults", wantErr: ErrDateOfBirthRequired},
+		{name: "turns 18 on race day", category: "adults", dateOfBirth: ptrStr("2008-12-06")},
+		{name: "turns 18 the day after", category: "adults", dateOfBirth: ptrStr("2008-12-07"), wantErr: ErrAgeNotEligible},
+		{name: "aged 60 on race day", category: "adults", dateOfBirth: ptrStr("1966-06-01")},
+		{name: "aged 61 on race day", category: "adults", dateOfBirth: ptrStr("1965-12-06"), wantErr: ErrAgeNotEligible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRegistrationFixture(t)
+			ctx := context.Background()
+
+			categoryIDs := map[string]string{
+				"open":        f.category(t, f.event.ID, "10K", 100, nil, nil).ID,
+				"adults":      f.category(t, f.event.ID, "Half Marathon", 100, ptrInt(18), ptrInt(60)).ID,
+				"other-event": f.category(t, "other-event-id", "5K", 100, nil, nil).ID,
+			}
+			categoryID, ok := categoryIDs[tt.category]
+			if !ok {
+				categoryID = tt.category
+			}
+
+			p, err := f.service.Register(ctx, f.event, request("runner@example.com", categoryID, tt.dateOfBirth))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Register() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				stored, err := f.participants.FindByEventAndEmail(ctx, f.event.ID, "runner@example.com")
+				if err != nil {
+					t.Fatalf("FindByEventAndEmail() error = %v", err)
+				}
+				if stored != nil {
+					t.Errorf("participant stored despite %v", tt.wantErr)
+				}
+				return
+			}
+
+			if p.EventID != f.event.ID || p.CategoryID == nil || *p.CategoryID != categoryID {
+				t.Errorf("Register() = event %s category %v, want event %s category %s", p.EventID, p.CategoryID, f.event.ID, categoryID)
+			}
+			category, err := f.categories.FindByID(ctx, categoryID)
+			if err != nil {
+				t.Fatalf("failed to find category: %v", err)
+			}
+			if category.Registered != 1 {
+				t.Errorf("category registered = %d, want 1", category.Registered)
+			}
+		})
+	}
+}
+
+func TestRegistrationServiceRegisterWithoutCategories(t *testing.T) {
+	f := newRegistrationFixture(t)
+
+	p, err := f.service.Register(context.Background(), f.event, request("runner@example.com", "", nil))
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if p.CategoryID != nil {
+		t.Errorf("Register() category = %s, want none", *p.CategoryID)
+	}
+}
+
+func TestRegistrationServiceRegisterWaitlist(t *testing.T) {
+	f := newRegistrationFixture(t)
+	ctx := context.Background()
+	category := f.category(t, f.event.ID, "10K", 2, nil, nil)
+
+	for i, email := range []string{"first@example.com", "second@example.com", "third@example.com", "fourth@example.com"} {
+		p, err := f.service.Register(ctx, f.event, request(email, category.ID, nil))
+		if err != nil {
+			t.Fatalf("Register(%s) error = %v", email, err)
+		}
+
+		if i < 2 {
+			if p.RegistrationStatus != "PENDING" || p.WaitlistPosition != nil {
+				t.Errorf("Register(%s) = %s at %v, want PENDING", email, p.RegistrationStatus, p.WaitlistPosition)
+			}
+			continue
+		}
+		if p.RegistrationStatus != "WAITLISTED" || p.WaitlistPosition == nil || *p.WaitlistPosition != i-1 {
+			t.Errorf("Register(%s) = %s at %v, want WAITLISTED at %d", email, p.RegistrationStatus, p.WaitlistPosition, i-1)
+		}
+	}
+
+	if _, err := f.service.Register(ctx, f.event, request("first@example.com", category.ID, nil)); !errors.Is(err, repository.ErrDuplicateEmail) {
+		t.Errorf("Register() of a registered email error = %v, want %v", err, repository.ErrDuplicateEmail)
+	}
+}
+
+func TestAgeOn(t *testing.T) {
+	tests := []struct {
+		dateOfBirth string
+		date        string
+		want        int
+	}{
+		{dateOfBirth: "2000-06-15", date: "2026-06-14", want: 25},
+		{dateOfBirth: "2000-06-15", date: "2026-06-15", want: 26},
+		{dateOfBirth: "2000-06-15", date: "2026-05-20", want: 25},
+		{dateOfBirth: "2008-02-29", date: "2026-02-28", want: 17},
+		{dateOfBirth: "2008-02-29", date: "2026-03-01", want: 18},
+	}
+
+	for _, tt := range tests {
+		got, err := ageOn(tt.dateOfBirth, tt.date)
+		if err != nil {
+			t.Fatalf("ageOn(%s, %s) error = %v", tt.dateOfBirth, tt.date, err)
+		}
+		if got != tt.want {
+			t.Errorf("ageOn(%s, %s) = %d, want %d", tt.dateOfBirth, tt.date, got, tt.want)
+		}
+	}
+
+	if _, err := ageOn("15-06-2000", "2026-06-15"); err == nil {
+		t.Error("ageOn() of a malformed date error = nil")
+	}
+}
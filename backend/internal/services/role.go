@@ -0,0 +1,63 @@
+package services
+
+// Admin roles, stored on the admins table and carried in admin tokens
+const (
+	RoleOwner   = "OWNER"
+	RoleFinance = "FINANCE"
+	RoleCheckIn = "CHECK_IN"
+	RoleViewer  = "VIEWER"
+)
+
+// Permission is a group of admin endpoints a role may be allowed to use
+type Permission string
+
+// Permissions of the admin API
+const (
+	// PermissionView covers reading events, participants, exports and emails
+	PermissionView Permission = "VIEW"
+	// PermissionManageEvents covers events, race categories, bib reservations
+	// and the email templates, schedules, campaigns and outbox of events
+	PermissionManageEvents Permission = "MANAGE_EVENTS"
+	// PermissionManageParticipants covers imports, the waitlist, bib
+	// reassignment, cancellations, transfers and resending emails
+	PermissionManageParticipants Permission = "MANAGE_PARTICIPANTS"
+	// PermissionPayments covers payment status, payment proofs and refunds
+	PermissionPayments Permission = "PAYMENTS"
+	// PermissionCheckIn covers scanning QR codes at race-kit pickup and check-in
+	PermissionCheckIn Permission = "CHECK_IN"
+	// PermissionManageAdmins covers listing admins and assigning their roles
+	PermissionManageAdmins Permission = "MANAGE_ADMINS"
+)
+
+// rolePermissions are the permissions of each role. Finance staff can view
+// participants to reconcile payments; check-in volunteers only scan codes.
+var rolePermissions = map[string][]Permission{
+	RoleOwner: {
+		PermissionView,
+		PermissionManageEvents,
+		PermissionManageParticipants,
+		PermissionPayments,
+		PermissionCheckIn,
+		PermissionManageAdmins,
+	},
+	RoleFinance: {PermissionView, PermissionPayments},
+	RoleCheckIn: {PermissionCheckIn},
+	RoleViewer:  {PermissionView},
+}
+
+// IsValidRole reports whether role is an admin role
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RoleHasPermission reports whether admins with role have permission. Unknown
+// roles have no permissions.
+func RoleHasPermission(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
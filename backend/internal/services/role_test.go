@@ -0,0 +1,43 @@
+package services
+
+import "testing"
+
+func TestRoleHasPermission(t *testing.T) {
+	all := []Permission{
+		PermissionView,
+		PermissionManageEvents,
+		PermissionManageParticipants,
+		PermissionPayments,
+		PermissionCheckIn,
+		PermissionManageAdmins,
+	}
+
+	tests := []struct {
+		role string
+		want []Permission
+	}{
+		{role: RoleOwner, want: all},
+		{role: RoleFinance, want: []Permission{PermissionView, PermissionPayments}},
+		{role: RoleCheckIn, want: []Permission{PermissionCheckIn}},
+		{role: RoleViewer, want: []Permission{PermissionView}},
+		{role: "", want: nil},
+		{role: "owner", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			allowed := map[Permission]bool{}
+			for _, p := range tt.want {
+				allowed[p] = true
+			}
+			for _, p := range all {
+				if got := RoleHasPermission(tt.role, p); got != allowed[p] {
+					t.Errorf("RoleHasPermission(%q, %s) = %t, want %t", tt.role, p, got, allowed[p])
+				}
+			}
+			if got := IsValidRole(tt.role); got != (tt.want != nil) {
+				t.Errorf("IsValidRole(%q) = %t", tt.role, got)
+			}
+		})
+	}
+}
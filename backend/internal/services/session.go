@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// Errors returned by SessionService
+var (
+	ErrInvalidToken        = errors.New("invalid or expired access token")
+	ErrTokenRevoked        = errors.New("access token was revoked")
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid, expired or revoked")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+)
+
+// SessionService manages admin sessions. Logging in starts a session with a
+// short-lived access token and a refresh token. Each refresh token can be
+// used once, to get a new pair; using one a second time means it was stolen,
+// so the whole session is revoked. Logging out revokes the session's refresh
+// tokens and the access tokens issued with them.
+type SessionService struct {
+	cfg          *config.Config
+	auth         *AuthService
+	adminService *AdminService
+	sessions     repository.SessionRepository
+	tx           repository.Transactor
+}
+
+// NewSessionService creates a new admin session service
+func NewSessionService(
+	cfg *config.Config,
+	auth *AuthService,
+	adminService *AdminService,
+	sessions repository.SessionRepository,
+	tx repository.Transactor,
+) *SessionService {
+	return &SessionService{
+		cfg:          cfg,
+		auth:         auth,
+		adminService: adminService,
+		sessions:     sessions,
+		tx:           tx,
+	}
+}
+
+// Start starts a session for an admin who has just logged in
+func (s *SessionService) Start(ctx context.Context, admin *models.Admin) (*models.LoginResponse, error) {
+	familyID, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, admin, familyID)
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token. The
+// new access token carries the admin's current role.
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string) (*models.LoginResponse, error) {
+	var session *models.LoginResponse
+	var reused, inactive bool
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		now := time.Now()
+
+		t, err := s.sessions.FindRefreshTokenForUpdate(ctx, hashToken(refreshToken))
+		if err != nil {
+			return err
+		}
+		if t == nil || t.RevokedAt != nil || !t.ExpiresAt.After(now) {
+			return ErrInvalidRefreshToken
+		}
+		// The revocations below must be committed, so their errors are
+		// returned after the transaction
+		if t.UsedAt != nil {
+			reused = true
+			utils.AuthLogger.Warning("Refresh token of admin %s was reused; revoking the session", t.AdminID)
+			return s.sessions.RevokeFamily(ctx, t.FamilyID, now)
+		}
+
+		admin, err := s.adminService.Active(ctx, t.AdminID)
+		if errors.Is(err, ErrAdminNotFound) || errors.Is(err, ErrAdminDisabled) {
+			inactive = true
+			return s.sessions.RevokeFamily(ctx, t.FamilyID, now)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.sessions.MarkRefreshTokenUsed(ctx, t.ID, now); err != nil {
+			return err
+		}
+		session, err = s.issue(ctx, admin, t.FamilyID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+	if inactive {
+		return nil, ErrInvalidRefreshToken
+	}
+	return session, nil
+}
+
+// Logout revokes the session a refresh token belongs to. Logging out with a
+// token that is unknown or already revoked changes nothing.
+func (s *SessionService) Logout(ctx context.Context, refreshToken string) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		t, err := s.sessions.FindRefreshTokenForUpdate(ctx, hashToken(refreshToken))
+		if err != nil || t == nil || t.RevokedAt != nil {
+			return err
+		}
+		return s.sessions.RevokeFamily(ctx, t.FamilyID, time.Now())
+	})
+}
+
+// LogoutAll revokes every session of an admin
+func (s *SessionService) LogoutAll(ctx context.Context, adminID string) error {
+	return s.sessions.RevokeAdmin(ctx, adminID, time.Now())
+}
+
+// Authenticate validates an admin access token and returns the admin it
+// belongs to, as stored now, so a changed role applies from the next request.
+// It returns ErrInvalidToken for tokens that don't parse or have expired,
+// ErrTokenRevoked for tokens whose session was logged out, and
+// ErrAdminDisabled or ErrAdminNotFound if the admin can no longer use the API.
+func (s *SessionService) Authenticate(ctx context.Context, tokenString string) (*models.Admin, error) {
+	claims, err := s.auth.ValidateToken(tokenString)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	// Tokens issued before sessions existed have no jti and can't be revoked
+	if claims.ID == "" {
+		return nil, ErrTokenRevoked
+	}
+	revoked, err := s.sessions.IsAccessTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	admin, err := s.adminService.Active(ctx, claims.AdminID)
+	if err != nil {
+		if errors.Is(err, ErrAdminDisabled) {
+			utils.AuthLogger.Warning("Disabled admin %s tried to use the API", claims.Email)
+		}
+		return nil, err
+	}
+
+	return admin, nil
+}
+
+// DeleteExpired removes refresh tokens and revoked access tokens that have
+// expired and are no longer needed to detect reuse or refuse a token
+func (s *SessionService) DeleteExpired(ctx context.Context) (int, error) {
+	return s.sessions.DeleteExpired(ctx, time.Now())
+}
+
+// Run deletes expired tokens every interval until ctx is cancelled
+func (s *SessionService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DeleteExpired(ctx); err != nil {
+				utils.AuthLogger.Error("Failed to delete expired admin tokens: %v", err)
+			}
+		}
+	}
+}
+
+// issue adds a new refresh token to a session, with a new access token
+func (s *SessionService) issue(ctx context.Context, admin *models.Admin, familyID string) (*models.LoginResponse, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+	accessToken, accessExpiresAt, err := s.auth.GenerateToken(admin.ID, admin.Email, admin.Role, jti)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &models.RefreshToken{
+		AdminID:         admin.ID,
+		FamilyID:        familyID,
+		TokenHash:       hashToken(refreshToken),
+		AccessJTI:       jti,
+		AccessExpiresAt: accessExpiresAt,
+		ExpiresAt:       time.Now().AddDate(0, 0, s.cfg.JWT.RefreshTokenDays),
+	}
+	if err := s.sessions.CreateRefreshToken(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{
+		Token: accessToken,
+		Admin: models.AdminInfo{
+			ID:    admin.ID,
+			Email: admin.Email,
+			Role:  admin.Role,
+		},
+		ExpiresAt:        accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: t.ExpiresAt,
+	}, nil
+}
+
+// newSecretToken generates a random refresh or password reset token
+func newSecretToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hash a refresh or password reset token is stored as.
+// The tokens are random, so a fast hash is enough.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newTokenID generates a random UUID v4 for a session or access token
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
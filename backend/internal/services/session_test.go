@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// sessionFixture is the admin session service on an in-memory database
+type sessionFixture struct {
+	auth        *AuthService
+	admins      *memory.AdminRepository
+	sessionRepo *memory.SessionRepository
+	service     *SessionService
+}
+
+func newSessionFixture(t *testing.T) *sessionFixture {
+	t.Helper()
+
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: testJWTSecret, AccessTokenMinutes: 15, RefreshTokenDays: 30}}
+	db := memory.NewDB()
+	tx := memory.NewTransactor(db)
+
+	f := &sessionFixture{
+		auth:        NewAuthService(cfg),
+		admins:      memory.NewAdminRepository(db),
+		sessionRepo: memory.NewSessionRepository(db),
+	}
+	f.service = NewSessionService(cfg, f.auth, NewAdminService(f.auth, f.admins, f.sessionRepo, tx), f.sessionRepo, tx)
+	return f
+}
+
+func TestSessionServiceRefresh(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string
+		wantErr error
+	}{
+		{
+			name: "unused token",
+			token: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				return session.RefreshToken
+			},
+		},
+		{
+			name: "unknown token",
+			token: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				return "not-a-refresh-token"
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "expired token",
+			token: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				err := f.sessionRepo.CreateRefreshToken(context.Background(), &models.RefreshToken{
+					AdminID:         admin.ID,
+					FamilyID:        "expired-family",
+					TokenHash:       hashToken("expired-token"),
+					AccessJTI:       "expired-jti",
+					AccessExpiresAt: time.Now().Add(-time.Hour),
+					ExpiresAt:       time.Now().Add(-time.Minute),
+				})
+				if err != nil {
+					t.Fatalf("failed to create refresh token: %v", err)
+				}
+				return "expired-token"
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "logged out",
+			token: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				if err := f.service.Logout(context.Background(), session.RefreshToken); err != nil {
+					t.Fatalf("Logout() error = %v", err)
+				}
+				return session.RefreshToken
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "already used",
+			token: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				if _, err := f.service.Refresh(context.Background(), session.RefreshToken); err != nil {
+					t.Fatalf("first Refresh() error = %v", err)
+				}
+				return session.RefreshToken
+			},
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "admin disabled",
+			token: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				now := time.Now()
+				if err := f.admins.SetDisabled(context.Background(), admin.ID, &now); err != nil {
+					t.Fatalf("failed to disable admin: %v", err)
+				}
+				return session.RefreshToken
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "admin deleted",
+			token: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				if err := f.admins.Delete(context.Background(), admin.ID); err != nil {
+					t.Fatalf("failed to delete admin: %v", err)
+				}
+				return session.RefreshToken
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSessionFixture(t)
+			ctx := context.Background()
+			admin := f.admins.Add(models.Admin{Email: "owner@example.com"})
+
+			session, err := f.service.Start(ctx, &admin)
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			got, err := f.service.Refresh(ctx, tt.token(t, f, admin, session))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refresh() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.RefreshToken == session.RefreshToken || got.Token == session.Token {
+				t.Error("Refresh() returned the tokens it was given")
+			}
+			if _, err := f.service.Authenticate(ctx, got.Token); err != nil {
+				t.Errorf("Authenticate() of the new access token error = %v", err)
+			}
+		})
+	}
+}
+
+func TestSessionServiceRefreshReuseRevokesSession(t *testing.T) {
+	f := newSessionFixture(t)
+	ctx := context.Background()
+	admin := f.admins.Add(models.Admin{Email: "owner@example.com"})
+
+	first, err := f.service.Start(ctx, &admin)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	second, err := f.service.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	other, err := f.service.Start(ctx, &admin)
+	if err != nil {
+		t.Fatalf("Start() of another session error = %v", err)
+	}
+
+	// Someone replays the first refresh token after it was rotated
+	if _, err := f.service.Refresh(ctx, first.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Refresh() of a used token error = %v, want %v", err, ErrRefreshTokenReused)
+	}
+
+	if _, err := f.service.Refresh(ctx, second.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Refresh() of the latest token error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+	if _, err := f.service.Authenticate(ctx, second.Token); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Authenticate() of the latest access token error = %v, want %v", err, ErrTokenRevoked)
+	}
+
+	// Other sessions of the admin are left alone
+	if _, err := f.service.Authenticate(ctx, other.Token); err != nil {
+		t.Errorf("Authenticate() of another session error = %v", err)
+	}
+	if _, err := f.service.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Errorf("Refresh() of another session error = %v", err)
+	}
+}
+
+func TestSessionServiceAuthenticate(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string
+		wantErr  error
+		wantRole string
+	}{
+		{
+			name: "valid token",
+			change: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				return session.Token
+			},
+			wantRole: RoleOwner,
+		},
+		{
+			name: "role changed after login",
+			change: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				if err := f.admins.UpdateRole(context.Background(), admin.ID, RoleViewer); err != nil {
+					t.Fatalf("failed to update role: %v", err)
+				}
+				return session.Token
+			},
+			wantRole: RoleViewer,
+		},
+		{
+			name: "malformed token",
+			change: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				return "not-a-jwt"
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "token without jti",
+			change: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				token, _, err := f.auth.GenerateToken(admin.ID, admin.Email, admin.Role, "")
+				if err != nil {
+					t.Fatalf("GenerateToken() error = %v", err)
+				}
+				return token
+			},
+			wantErr: ErrTokenRevoked,
+		},
+		{
+			name: "logged out",
+			change: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				if err := f.service.Logout(context.Background(), session.RefreshToken); err != nil {
+					t.Fatalf("Logout() error = %v", err)
+				}
+				return session.Token
+			},
+			wantErr: ErrTokenRevoked,
+		},
+		{
+			name: "admin disabled",
+			change: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				now := time.Now()
+				if err := f.admins.SetDisabled(context.Background(), admin.ID, &now); err != nil {
+					t.Fatalf("failed to disable admin: %v", err)
+				}
+				return session.Token
+			},
+			wantErr: ErrAdminDisabled,
+		},
+		{
+			name: "admin deleted",
+			change: func(t *testing.T, f *sessionFixture, admin models.Admin, session *models.LoginResponse) string {
+				if err := f.admins.Delete(context.Background(), admin.ID); err != nil {
+					t.Fatalf("failed to delete admin: %v", err)
+				}
+				return session.Token
+			},
+			wantErr: ErrAdminNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSessionFixture(t)
+			ctx := context.Background()
+			admin := f.admins.Add(models.Admin{Email: "owner@example.com"})
+
+			session, err := f.service.Start(ctx, &admin)
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			got, err := f.service.Authenticate(ctx, tt.change(t, f, admin, session))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.ID != admin.ID {
+				t.Errorf("Authenticate() admin = %s, want %s", got.ID, admin.ID)
+			}
+			if got.Role != tt.wantRole {
+				t.Errorf("Authenticate() role = %s, want %s", got.Role, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestSessionServiceLogoutAll(t *testing.T) {
+	f := newSessionFixture(t)
+	ctx := context.Background()
+	admin := f.admins.Add(models.Admin{Email: "owner@example.com"})
+	other := f.admins.Add(models.Admin{Email: "other@example.com"})
+
+	var sessions []*models.LoginResponse
+	for _, a := range []models.Admin{admin, admin, other} {
+		session, err := f.service.Start(ctx, &a)
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := f.service.LogoutAll(ctx, admin.ID); err != nil {
+		t.Fatalf("LogoutAll() error = %v", err)
+	}
+
+	for _, session := range sessions[:2] {
+		if _, err := f.service.Authenticate(ctx, session.Token); !errors.Is(err, ErrTokenRevoked) {
+			t.Errorf("Authenticate() after logging out everywhere error = %v, want %v", err, ErrTokenRevoked)
+		}
+		if _, err := f.service.Refresh(ctx, session.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("Refresh() after logging out everywhere error = %v, want %v", err, ErrInvalidRefreshToken)
+		}
+	}
+	if _, err := f.service.Authenticate(ctx, sessions[2].Token); err != nil {
+		t.Errorf("Authenticate() of another admin error = %v", err)
+	}
+}
@@ -0,0 +1,450 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// Errors returned by TransferService
+var (
+	ErrTransferClosed      = errors.New("registration can no longer be transferred")
+	ErrTransferPending     = errors.New("registration already has a pending transfer")
+	ErrNoTransferPending   = errors.New("registration has no pending transfer")
+	ErrInvalidTransferLink = errors.New("transfer link is invalid, expired or already used")
+)
+
+// TransferService lets runners give their paid registration to somebody else.
+//
+// The holder of a registration, or an admin, starts a transfer to the name and
+// email of the new runner, who gets a link by email. Accepting it with their
+// own details re-points the participant to them, keeping its payment status,
+// race category and bib number. Every transfer is kept with the details of the
+// runner before and after it, as the audit trail of who held a registration.
+//
+// Transfers can be started and accepted until the transfer deadline of the
+// event, or the day before the event if it has none.
+type TransferService struct {
+	config       *config.Config
+	auth         *AuthService
+	outbox       *EmailOutbox
+	events       repository.EventRepository
+	categories   repository.RaceCategoryRepository
+	participants repository.ParticipantRepository
+	transfers    repository.TransferRepository
+	tx           repository.Transactor
+	location     *time.Location
+}
+
+// NewTransferService creates a new registration transfer service
+func NewTransferService(
+	cfg *config.Config,
+	auth *AuthService,
+	outbox *EmailOutbox,
+	events repository.EventRepository,
+	categories repository.RaceCategoryRepository,
+	participants repository.ParticipantRepository,
+	transfers repository.TransferRepository,
+	tx repository.Transactor,
+) *TransferService {
+	return &TransferService{
+		config:       cfg,
+		auth:         auth,
+		outbox:       outbox,
+		events:       events,
+		categories:   categories,
+		participants: participants,
+		transfers:    transfers,
+		tx:           tx,
+		location:     cfg.EventLocation(),
+	}
+}
+
+// Start starts a transfer on behalf of an admin. name and email must already
+// be sanitized and validated.
+func (s *TransferService) Start(ctx context.Context, participantID, name, email, adminID string) (*models.RegistrationTransfer, error) {
+	return s.start(ctx, participantID, name, email, &adminID, nil)
+}
+
+// StartOwn starts a transfer of a registration of ownerEmail on behalf of the participant
+func (s *TransferService) StartOwn(ctx context.Context, ownerEmail, participantID, name, email string) (*models.RegistrationTransfer, error) {
+	return s.start(ctx, participantID, name, email, nil, &ownerEmail)
+}
+
+// Cancel withdraws the pending transfer of a registration on behalf of an admin
+func (s *TransferService) Cancel(ctx context.Context, participantID, adminID string) (*models.RegistrationTransfer, error) {
+	return s.cancel(ctx, participantID, &adminID, nil)
+}
+
+// CancelOwn withdraws the pending transfer of a registration of ownerEmail on
+// behalf of the participant
+func (s *TransferService) CancelOwn(ctx context.Context, ownerEmail, participantID string) (*models.RegistrationTransfer, error) {
+	return s.cancel(ctx, participantID, nil, &ownerEmail)
+}
+
+// Offer returns what the recipient of a transfer link sees before accepting it
+func (s *TransferService) Offer(ctx context.Context, token string) (*models.TransferOffer, error) {
+	transfer, err := s.pendingByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.events.FindByID(ctx, transfer.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, fmt.Errorf("event %s of transfer %s not found", transfer.EventID, transfer.ID)
+	}
+
+	offer := &models.TransferOffer{
+		EventName:     event.Name,
+		EventDate:     event.EventDate,
+		EventLocation: event.Location,
+		FromName:      transfer.FromName,
+		ToName:        transfer.ToName,
+		ToEmail:       transfer.ToEmail,
+		ExpiresAt:     transfer.ExpiresAt,
+	}
+
+	participant, err := s.participants.FindByID(ctx, transfer.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant != nil && participant.CategoryID != nil {
+		category, err := s.categories.FindByID(ctx, *participant.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		if category != nil {
+			offer.CategoryName = &category.Name
+		}
+	}
+
+	return offer, nil
+}
+
+// Accept completes the transfer of a link, re-pointing its registration to
+// the recipient with the details in req, which must already be sanitized and
+// validated. The email address is the one the transfer was sent to.
+func (s *TransferService) Accept(ctx context.Context, req models.TransferAcceptRequest) (*models.Participant, error) {
+	pending, err := s.pendingByToken(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	var participant *models.Participant
+	var transfer *models.RegistrationTransfer
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// The participant is locked before the transfer, like when starting one
+		p, err := s.participants.FindByIDForUpdate(ctx, pending.ParticipantID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return ErrInvalidTransferLink
+		}
+
+		t, err := s.transfers.FindByIDForUpdate(ctx, pending.ID)
+		if err != nil {
+			return err
+		}
+		if t == nil || t.Status != "PENDING" || !time.Now().Before(t.ExpiresAt) {
+			return ErrInvalidTransferLink
+		}
+
+		event, err := s.events.FindByID(ctx, p.EventID)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			return fmt.Errorf("event %s of participant %s not found", p.EventID, p.ID)
+		}
+
+		if err := s.check(p, event); err != nil {
+			return err
+		}
+
+		if p.CategoryID != nil {
+			category, err := s.categories.FindByID(ctx, *p.CategoryID)
+			if err != nil {
+				return err
+			}
+			if category == nil {
+				return fmt.Errorf("race category %s of participant %s not found", *p.CategoryID, p.ID)
+			}
+			if err := checkAge(category, req.DateOfBirth, event); err != nil {
+				return err
+			}
+		}
+
+		p.Name = req.Name
+		p.Email = t.ToEmail
+		p.Phone = req.Phone
+		p.Address = req.Address
+		p.InstagramHandle = req.InstagramHandle
+		p.DateOfBirth = req.DateOfBirth
+		// The previous holder's QR code must not check the new runner in
+		p.CheckInVersion++
+		if err := s.participants.UpdateOwner(ctx, p); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		t.Status = "COMPLETED"
+		t.ToName = req.Name
+		t.ToPhone = &req.Phone
+		t.CompletedAt = &now
+		if err := s.transfers.Update(ctx, t); err != nil {
+			return err
+		}
+
+		// The new runner gets their bib number and check-in code, and the
+		// previous one is told the registration is no longer theirs
+		if err := s.outbox.Enqueue(ctx, p.ID, EmailTypeTransferWelcome); err != nil {
+			return err
+		}
+		if err := s.outbox.Enqueue(ctx, p.ID, EmailTypeTransferCompleted); err != nil {
+			return err
+		}
+
+		participant = p
+		transfer = t
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.ServerLogger.Info("Transferred registration %s of event %s from %s to %s", participant.ID, participant.EventID, transfer.FromEmail, transfer.ToEmail)
+	return participant, nil
+}
+
+// ListByParticipant returns the transfers of a registration, newest first
+func (s *TransferService) ListByParticipant(ctx context.Context, participantID string) ([]models.RegistrationTransfer, error) {
+	participant, err := s.participants.FindByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	return s.transfers.ListByParticipant(ctx, participantID)
+}
+
+// ListByEvent returns the transfers of an event, optionally only those with a status, newest first
+func (s *TransferService) ListByEvent(ctx context.Context, eventID, status string) ([]models.RegistrationTransfer, error) {
+	event, err := s.events.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, ErrEventNotFound
+	}
+
+	return s.transfers.ListByEvent(ctx, eventID, status)
+}
+
+// start starts a transfer. A nil adminID means the participant started it,
+// and a non-nil ownerEmail that the registration must be theirs.
+func (s *TransferService) start(ctx context.Context, participantID, name, email string, adminID, ownerEmail *string) (*models.RegistrationTransfer, error) {
+	var transfer *models.RegistrationTransfer
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.findForUpdate(ctx, participantID, ownerEmail)
+		if err != nil {
+			return err
+		}
+
+		event, err := s.events.FindByID(ctx, p.EventID)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			return fmt.Errorf("event %s of participant %s not found", p.EventID, p.ID)
+		}
+
+		if err := s.check(p, event); err != nil {
+			return err
+		}
+
+		pending, err := s.transfers.FindPending(ctx, p.ID)
+		if err != nil {
+			return err
+		}
+		if pending != nil {
+			if time.Now().Before(pending.ExpiresAt) {
+				return ErrTransferPending
+			}
+			pending.Status = "EXPIRED"
+			if err := s.transfers.Update(ctx, pending); err != nil {
+				return err
+			}
+		}
+
+		existing, err := s.participants.FindByEventAndEmail(ctx, p.EventID, email)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return repository.ErrDuplicateEmail
+		}
+
+		transfer = &models.RegistrationTransfer{
+			ParticipantID: p.ID,
+			EventID:       p.EventID,
+			FromName:      p.Name,
+			FromEmail:     p.Email,
+			FromPhone:     p.Phone,
+			ToName:        name,
+			ToEmail:       email,
+			InitiatedBy:   adminID,
+			ExpiresAt:     time.Now().Add(time.Duration(s.config.Transfer.LinkHours) * time.Hour),
+		}
+		if err := s.transfers.Create(ctx, transfer); err != nil {
+			return err
+		}
+
+		return s.outbox.Enqueue(ctx, p.ID, EmailTypeTransferOffer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.ServerLogger.Info("Started transfer %s of registration %s from %s to %s", transfer.ID, transfer.ParticipantID, transfer.FromEmail, transfer.ToEmail)
+	return transfer, nil
+}
+
+// cancel withdraws the pending transfer of a registration. A nil adminID
+// means the participant withdrew it, and a non-nil ownerEmail that the
+// registration must be theirs.
+func (s *TransferService) cancel(ctx context.Context, participantID string, adminID, ownerEmail *string) (*models.RegistrationTransfer, error) {
+	var transfer *models.RegistrationTransfer
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.findForUpdate(ctx, participantID, ownerEmail)
+		if err != nil {
+			return err
+		}
+
+		t, err := s.transfers.FindPending(ctx, p.ID)
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			return ErrNoTransferPending
+		}
+
+		now := time.Now()
+		t.Status = "CANCELLED"
+		t.CancelledAt = &now
+		t.CancelledBy = adminID
+		if err := s.transfers.Update(ctx, t); err != nil {
+			return err
+		}
+
+		transfer = t
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.ServerLogger.Info("Cancelled transfer %s of registration %s to %s", transfer.ID, transfer.ParticipantID, transfer.ToEmail)
+	return transfer, nil
+}
+
+// findForUpdate finds and locks a participant, which must belong to
+// ownerEmail if it is not nil
+func (s *TransferService) findForUpdate(ctx context.Context, participantID string, ownerEmail *string) (*models.Participant, error) {
+	p, err := s.participants.FindByIDForUpdate(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	// Other people's registrations look the same as ones that don't exist
+	if p == nil || (ownerEmail != nil && p.Email != *ownerEmail) {
+		return nil, ErrParticipantNotFound
+	}
+	return p, nil
+}
+
+// check checks that a registration can be transferred today
+func (s *TransferService) check(p *models.Participant, event *models.Event) error {
+	switch {
+	case p.RegistrationStatus == "CANCELLED":
+		return ErrCancelled
+	case p.PaymentStatus != "PAID":
+		return ErrNotPaid
+	case p.CheckedInAt != nil:
+		return ErrAlreadyCheckedIn
+	case p.KitCollectedAt != nil:
+		return ErrKitAlreadyCollected
+	}
+
+	eventDay, err := time.ParseInLocation("2006-01-02", event.EventDate, s.location)
+	if err != nil {
+		return fmt.Errorf("invalid date %q of event %s: %w", event.EventDate, event.ID, err)
+	}
+	lastDay := eventDay.AddDate(0, 0, -1)
+	if event.TransferDeadline != nil {
+		if deadline, err := time.ParseInLocation("2006-01-02", *event.TransferDeadline, s.location); err == nil && deadline.Before(lastDay) {
+			lastDay = deadline
+		}
+	}
+
+	now := time.Now().In(s.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location)
+	if today.After(lastDay) {
+		return ErrTransferClosed
+	}
+	return nil
+}
+
+// pendingByToken returns the transfer of a link if it can still be accepted
+func (s *TransferService) pendingByToken(ctx context.Context, token string) (*models.RegistrationTransfer, error) {
+	transferID, err := s.auth.ValidateTransferToken(token)
+	if err != nil {
+		return nil, ErrInvalidTransferLink
+	}
+
+	transfer, err := s.transfers.FindByID(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer == nil || transfer.Status != "PENDING" {
+		return nil, ErrInvalidTransferLink
+	}
+
+	if !time.Now().Before(transfer.ExpiresAt) {
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			t, err := s.transfers.FindByIDForUpdate(ctx, transfer.ID)
+			if err != nil || t == nil || t.Status != "PENDING" {
+				return err
+			}
+			t.Status = "EXPIRED"
+			return s.transfers.Update(ctx, t)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidTransferLink
+	}
+
+	return transfer, nil
+}
+
+// transferLink returns the link the recipient of a transfer accepts it with
+func transferLink(cfg *config.Config, token string) string {
+	separator := "?"
+	if strings.Contains(cfg.Transfer.URL, "?") {
+		separator = "&"
+	}
+	return cfg.Transfer.URL + separator + url.Values{"token": {token}}.Encode()
+}
@@ -0,0 +1,453 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// transferFixture is an event in Jakarta a month away with a 10K and a paid
+// runner holding bib 7, with the transfer service on an in-memory database
+type transferFixture struct {
+	config       *config.Config
+	event        *models.Event
+	seller       *models.Participant
+	auth         *AuthService
+	events       *memory.EventRepository
+	participants *memory.ParticipantRepository
+	transfers    *memory.TransferRepository
+	outbox       *memory.EmailOutboxRepository
+	checkIn      *CheckInService
+	service      *TransferService
+}
+
+func newTransferFixture(t *testing.T) *transferFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	cfg := &config.Config{
+		JWT:      config.JWTConfig{Secret: testJWTSecret},
+		CheckIn:  config.CheckInConfig{TokenSecret: testCheckInSecret},
+		Events:   config.EventsConfig{Timezone: "Asia/Jakarta"},
+		Transfer: config.TransferConfig{URL: "https://tautaurun.id/transfer", LinkHours: 72},
+	}
+	db := memory.NewDB()
+	tx := memory.NewTransactor(db)
+	categories := memory.NewRaceCategoryRepository(db)
+
+	f := &transferFixture{
+		config:       cfg,
+		event:        &models.Event{Name: "City Run", EventDate: time.Now().AddDate(0, 1, 0).Format("2006-01-02"), Location: "Jakarta"},
+		auth:         NewAuthService(cfg),
+		events:       memory.NewEventRepository(db),
+		participants: memory.NewParticipantRepository(db),
+		transfers:    memory.NewTransferRepository(db),
+		outbox:       memory.NewEmailOutboxRepository(db),
+	}
+	if err := f.events.Create(ctx, f.event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	category := &models.RaceCategory{EventID: f.event.ID, Name: "10K", Capacity: 100}
+	if err := categories.Create(ctx, category); err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+
+	bib := 7
+	f.seller = &models.Participant{
+		EventID:    f.event.ID,
+		CategoryID: &category.ID,
+		Name:       "Seller Runner",
+		Email:      "seller@example.com",
+		Phone:      "+6281234567890",
+		Address:    "Jalan Thamrin 1",
+		BibNumber:  &bib,
+	}
+	if err := f.participants.Create(ctx, f.seller); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+	if err := f.participants.UpdatePaymentStatus(ctx, f.seller, "PAID"); err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+
+	outbox := NewEmailOutbox(cfg, NewEmailService(cfg, mailer.NewMemoryMailer(), nil, f.events, memory.NewEmailLogRepository(db), nil, f.transfers, nil), f.participants, f.outbox, tx)
+	f.checkIn = NewCheckInService(cfg, memory.NewAdminRepository(db), f.participants, tx)
+	f.service = NewTransferService(cfg, f.auth, outbox, f.events, categories, f.participants, f.transfers, tx)
+	return f
+}
+
+// start starts a transfer of the seller's registration and returns it with
+// the token of its link
+func (f *transferFixture) start(t *testing.T) (*models.RegistrationTransfer, string) {
+	t.Helper()
+
+	transfer, err := f.service.Start(context.Background(), f.seller.ID, "Buyer", "buyer@example.com", "admin-1")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	token, err := f.auth.GenerateTransferToken(transfer.ID, transfer.ExpiresAt)
+	if err != nil {
+		t.Fatalf("GenerateTransferToken() error = %v", err)
+	}
+	return transfer, token
+}
+
+// stored returns the seller's registration as stored
+func (f *transferFixture) stored(t *testing.T) *models.Participant {
+	t.Helper()
+
+	p, err := f.participants.FindByID(context.Background(), f.seller.ID)
+	if err != nil || p == nil {
+		t.Fatalf("failed to find participant: %v", err)
+	}
+	return p
+}
+
+// queued returns the types of the emails queued for the seller's registration
+func (f *transferFixture) queued(t *testing.T) []string {
+	t.Helper()
+
+	emails, _, err := f.outbox.List(context.Background(), OutboxStatusPending, 1, 100)
+	if err != nil {
+		t.Fatalf("failed to list outbox: %v", err)
+	}
+	types := []string{}
+	for _, e := range emails {
+		if e.ParticipantID == f.seller.ID {
+			types = append(types, e.EmailType)
+		}
+	}
+	return types
+}
+
+func TestTransferServiceStart(t *testing.T) {
+	f := newTransferFixture(t)
+	ctx := context.Background()
+
+	transfer, _ := f.start(t)
+	if transfer.Status != "PENDING" || transfer.FromEmail != "seller@example.com" || transfer.ToEmail != "buyer@example.com" {
+		t.Errorf("transfer = %s from %s to %s, want PENDING from the seller to the buyer", transfer.Status, transfer.FromEmail, transfer.ToEmail)
+	}
+	if hours := time.Until(transfer.ExpiresAt).Hours(); hours < 71 || hours > 72 {
+		t.Errorf("transfer expires in %.1f hours, want 72", hours)
+	}
+	if got := f.queued(t); !equalStrings(got, []string{EmailTypeTransferOffer}) {
+		t.Errorf("queued = %v, want the transfer offer", got)
+	}
+
+	if _, err := f.service.Start(ctx, f.seller.ID, "Other", "other@example.com", "admin-1"); !errors.Is(err, ErrTransferPending) {
+		t.Errorf("Start() with a pending transfer error = %v, want ErrTransferPending", err)
+	}
+
+	if _, err := f.service.CancelOwn(ctx, "buyer@example.com", f.seller.ID); !errors.Is(err, ErrParticipantNotFound) {
+		t.Errorf("CancelOwn() by someone else error = %v, want ErrParticipantNotFound", err)
+	}
+	cancelled, err := f.service.CancelOwn(ctx, "seller@example.com", f.seller.ID)
+	if err != nil {
+		t.Fatalf("CancelOwn() error = %v", err)
+	}
+	if cancelled.Status != "CANCELLED" || cancelled.CancelledAt == nil || cancelled.CancelledBy != nil {
+		t.Errorf("cancelled transfer = %s by %v, want CANCELLED by the participant", cancelled.Status, cancelled.CancelledBy)
+	}
+	if _, err := f.service.Cancel(ctx, f.seller.ID, "admin-1"); !errors.Is(err, ErrNoTransferPending) {
+		t.Errorf("Cancel() without a pending transfer error = %v, want ErrNoTransferPending", err)
+	}
+
+	// A new transfer can be started once the previous one is withdrawn
+	if _, err := f.service.StartOwn(ctx, "seller@example.com", f.seller.ID, "Other", "other@example.com"); err != nil {
+		t.Fatalf("StartOwn() after withdrawing error = %v", err)
+	}
+	history, err := f.service.ListByParticipant(ctx, f.seller.ID)
+	if err != nil || len(history) != 2 {
+		t.Errorf("ListByParticipant() = %d transfers, %v, want 2", len(history), err)
+	}
+}
+
+func TestTransferServiceStartExpired(t *testing.T) {
+	f := newTransferFixture(t)
+	f.config.Transfer.LinkHours = -1
+	expired, _ := f.start(t)
+
+	f.config.Transfer.LinkHours = 72
+	if _, err := f.service.Start(context.Background(), f.seller.ID, "Other", "other@example.com", "admin-1"); err != nil {
+		t.Fatalf("Start() after the previous transfer expired error = %v", err)
+	}
+
+	previous, err := f.transfers.FindByID(context.Background(), expired.ID)
+	if err != nil || previous.Status != "EXPIRED" {
+		t.Errorf("previous transfer = %+v, %v, want EXPIRED", previous, err)
+	}
+}
+
+func TestTransferServiceStartInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, f *transferFixture)
+		wantErr error
+	}{
+		{
+			name: "unpaid",
+			prepare: func(t *testing.T, f *transferFixture) {
+				if err := f.participants.UpdatePaymentStatus(context.Background(), f.stored(t), "UNPAID"); err != nil {
+					t.Fatalf("failed to update payment status: %v", err)
+				}
+			},
+			wantErr: ErrNotPaid,
+		},
+		{
+			name: "checked in",
+			prepare: func(t *testing.T, f *transferFixture) {
+				p := f.stored(t)
+				now := time.Now()
+				p.CheckedInAt = &now
+				if err := f.participants.UpdateCheckIn(context.Background(), p); err != nil {
+					t.Fatalf("failed to check in: %v", err)
+				}
+			},
+			wantErr: ErrAlreadyCheckedIn,
+		},
+		{
+			name: "recipient already registered",
+			prepare: func(t *testing.T, f *transferFixture) {
+				buyer := &models.Participant{EventID: f.event.ID, Name: "Buyer", Email: "buyer@example.com", Phone: "+6281298765432", Address: "Jalan Sudirman 2"}
+				if err := f.participants.Create(context.Background(), buyer); err != nil {
+					t.Fatalf("failed to create participant: %v", err)
+				}
+			},
+			wantErr: repository.ErrDuplicateEmail,
+		},
+		{
+			name: "day of the event",
+			prepare: func(t *testing.T, f *transferFixture) {
+				f.event.EventDate = time.Now().In(f.config.EventLocation()).Format("2006-01-02")
+				if err := f.events.Update(context.Background(), f.event); err != nil {
+					t.Fatalf("failed to update event: %v", err)
+				}
+			},
+			wantErr: ErrTransferClosed,
+		},
+		{
+			name: "transfer deadline passed",
+			prepare: func(t *testing.T, f *transferFixture) {
+				deadline := time.Now().In(f.config.EventLocation()).AddDate(0, 0, -1).Format("2006-01-02")
+				f.event.TransferDeadline = &deadline
+				if err := f.events.Update(context.Background(), f.event); err != nil {
+					t.Fatalf("failed to update event: %v", err)
+				}
+			},
+			wantErr: ErrTransferClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTransferFixture(t)
+			tt.prepare(t, f)
+
+			if _, err := f.service.Start(context.Background(), f.seller.ID, "Buyer", "buyer@example.com", "admin-1"); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Start() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := f.queued(t); len(got) != 0 {
+				t.Errorf("queued = %v, want no email", got)
+			}
+		})
+	}
+}
+
+func TestTransferServiceOffer(t *testing.T) {
+	f := newTransferFixture(t)
+	_, token := f.start(t)
+
+	offer, err := f.service.Offer(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Offer() error = %v", err)
+	}
+	if offer.EventName != "City Run" || offer.FromName != "Seller Runner" || offer.ToEmail != "buyer@example.com" {
+		t.Errorf("offer = %+v, want the event, the seller and the recipient", offer)
+	}
+	if offer.CategoryName == nil || *offer.CategoryName != "10K" {
+		t.Errorf("offer category = %v, want 10K", offer.CategoryName)
+	}
+
+	if _, err := f.service.Offer(context.Background(), "not-a-transfer-token"); !errors.Is(err, ErrInvalidTransferLink) {
+		t.Errorf("Offer() with an invalid token error = %v, want ErrInvalidTransferLink", err)
+	}
+}
+
+func TestTransferServiceAccept(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepare changes the state after the transfer was started and returns
+		// the token to accept it with
+		prepare   func(t *testing.T, f *transferFixture, transfer *models.RegistrationTransfer, token string) string
+		linkHours int // Overrides how long the transfer link is valid
+		wantErr   error
+	}{
+		{
+			name: "pending transfer",
+			prepare: func(t *testing.T, f *transferFixture, transfer *models.RegistrationTransfer, token string) string {
+				return token
+			},
+		},
+		{
+			name: "invalid token",
+			prepare: func(t *testing.T, f *transferFixture, transfer *models.RegistrationTransfer, token string) string {
+				return "not-a-transfer-token"
+			},
+			wantErr: ErrInvalidTransferLink,
+		},
+		{
+			name: "already accepted",
+			prepare: func(t *testing.T, f *transferFixture, transfer *models.RegistrationTransfer, token string) string {
+				if _, err := f.service.Accept(context.Background(), acceptRequest(token)); err != nil {
+					t.Fatalf("first Accept() error = %v", err)
+				}
+				return token
+			},
+			wantErr: ErrInvalidTransferLink,
+		},
+		{
+			name: "withdrawn",
+			prepare: func(t *testing.T, f *transferFixture, transfer *models.RegistrationTransfer, token string) string {
+				if _, err := f.service.Cancel(context.Background(), f.seller.ID, "admin-1"); err != nil {
+					t.Fatalf("Cancel() error = %v", err)
+				}
+				return token
+			},
+			wantErr: ErrInvalidTransferLink,
+		},
+		{
+			name: "expired",
+			prepare: func(t *testing.T, f *transferFixture, transfer *models.RegistrationTransfer, token string) string {
+				// The transfer itself expired even though its token has not
+				token, err := f.auth.GenerateTransferToken(transfer.ID, time.Now().Add(time.Hour))
+				if err != nil {
+					t.Fatalf("GenerateTransferToken() error = %v", err)
+				}
+				return token
+			},
+			linkHours: -1,
+			wantErr:   ErrInvalidTransferLink,
+		},
+		{
+			name: "registration cancelled",
+			prepare: func(t *testing.T, f *transferFixture, transfer *models.RegistrationTransfer, token string) string {
+				p := f.stored(t)
+				p.RegistrationStatus = "CANCELLED"
+				if err := f.participants.UpdateRegistration(context.Background(), p); err != nil {
+					t.Fatalf("failed to cancel registration: %v", err)
+				}
+				return token
+			},
+			wantErr: ErrCancelled,
+		},
+		{
+			name: "transfer deadline passed",
+			prepare: func(t *testing.T, f *transferFixture, transfer *models.RegistrationTransfer, token string) string {
+				deadline := time.Now().In(f.config.EventLocation()).AddDate(0, 0, -1).Format("2006-01-02")
+				f.event.TransferDeadline = &deadline
+				if err := f.events.Update(context.Background(), f.event); err != nil {
+					t.Fatalf("failed to update event: %v", err)
+				}
+				return token
+			},
+			wantErr: ErrTransferClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTransferFixture(t)
+			ctx := context.Background()
+			if tt.linkHours != 0 {
+				f.config.Transfer.LinkHours = tt.linkHours
+			}
+
+			transfer, token := f.start(t)
+			token = tt.prepare(t, f, transfer, token)
+			before := f.stored(t)
+
+			got, err := f.service.Accept(ctx, acceptRequest(token))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Accept() error = %v, want %v", err, tt.wantErr)
+			}
+
+			stored := f.stored(t)
+			if tt.wantErr != nil {
+				if stored.Email != before.Email || stored.Name != before.Name || stored.CheckInVersion != before.CheckInVersion {
+					t.Errorf("participant changed by a failed transfer to %s <%s>", stored.Name, stored.Email)
+				}
+				return
+			}
+
+			if got.Email != "buyer@example.com" || stored.Email != "buyer@example.com" || stored.Name != "Buyer Runner" || stored.Address != "Jalan Sudirman 2" {
+				t.Errorf("participant = %s <%s>, want Buyer Runner <buyer@example.com>", stored.Name, stored.Email)
+			}
+			if stored.PaymentStatus != "PAID" || stored.BibNumber == nil || *stored.BibNumber != 7 {
+				t.Errorf("participant = %s with bib %v, want the registration kept PAID with bib 7", stored.PaymentStatus, stored.BibNumber)
+			}
+			if got := f.queued(t); !equalStrings(got, []string{EmailTypeTransferOffer, EmailTypeTransferWelcome, EmailTypeTransferCompleted}) {
+				t.Errorf("queued = %v, want the welcome and completed emails after the offer", got)
+			}
+
+			completed, err := f.transfers.FindByID(ctx, transfer.ID)
+			if err != nil {
+				t.Fatalf("failed to find transfer: %v", err)
+			}
+			if completed.Status != "COMPLETED" || completed.CompletedAt == nil || completed.FromEmail != "seller@example.com" || completed.ToName != "Buyer Runner" {
+				t.Errorf("transfer = %s from %s to %s, want COMPLETED keeping both runners", completed.Status, completed.FromEmail, completed.ToName)
+			}
+		})
+	}
+}
+
+func TestTransferServiceAcceptRotatesCheckInToken(t *testing.T) {
+	f := newTransferFixture(t)
+	ctx := context.Background()
+
+	oldToken := f.checkIn.Token(f.stored(t))
+	if _, err := f.checkIn.Verify(ctx, oldToken, f.event.ID); err != nil {
+		t.Fatalf("Verify() of the seller's code before the transfer error = %v", err)
+	}
+
+	_, token := f.start(t)
+	accepted, err := f.service.Accept(ctx, acceptRequest(token))
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+
+	if _, err := f.checkIn.Verify(ctx, oldToken, f.event.ID); !errors.Is(err, ErrInvalidCheckInToken) {
+		t.Errorf("Verify() of the seller's code error = %v, want ErrInvalidCheckInToken", err)
+	}
+	if _, err := f.checkIn.CheckIn(ctx, oldToken, f.event.ID, "admin-1"); !errors.Is(err, ErrInvalidCheckInToken) {
+		t.Errorf("CheckIn() with the seller's code error = %v, want ErrInvalidCheckInToken", err)
+	}
+
+	newToken := f.checkIn.Token(accepted)
+	if newToken == oldToken {
+		t.Fatal("Token() after the transfer is the seller's code")
+	}
+	status, err := f.checkIn.Verify(ctx, newToken, f.event.ID)
+	if err != nil {
+		t.Fatalf("Verify() of the buyer's code error = %v", err)
+	}
+	if status.Participant.Email != "buyer@example.com" {
+		t.Errorf("Verify() participant = %s, want buyer@example.com", status.Participant.Email)
+	}
+}
+
+// acceptRequest returns the details a transfer recipient accepts token with
+func acceptRequest(token string) models.TransferAcceptRequest {
+	return models.TransferAcceptRequest{
+		Token:   token,
+		Name:    "Buyer Runner",
+		Phone:   "+6281298765432",
+		Address: "Jalan Sudirman 2",
+	}
+}
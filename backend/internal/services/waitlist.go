@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository"
+	"github.com/tau-tau-run/backend/internal/utils"
+)
+
+// ErrNotWaitlisted is returned when reordering a participant who is not on the waitlist
+var ErrNotWaitlisted = errors.New("participant is not on the waitlist")
+
+// WaitlistService queues registrations beyond capacity and offers freed spots
+// to the head of the queue.
+//
+// Capacity is tracked per scope: a race category, or an event without race
+// categories. Participants holding a spot are PENDING or CONFIRMED. A promoted
+// participant becomes PENDING with a payment deadline, and becomes EXPIRED if
+// they have not paid by then, which frees the spot for the next in line.
+type WaitlistService struct {
+	config       *config.Config
+	events       repository.EventRepository
+	categories   repository.RaceCategoryRepository
+	participants repository.ParticipantRepository
+	outbox       *EmailOutbox
+	tx           repository.Transactor
+}
+
+// NewWaitlistService creates a new waitlist service
+func NewWaitlistService(
+	cfg *config.Config,
+	events repository.EventRepository,
+	categories repository.RaceCategoryRepository,
+	participants repository.ParticipantRepository,
+	outbox *EmailOutbox,
+	tx repository.Transactor,
+) *WaitlistService {
+	return &WaitlistService{
+		config:       cfg,
+		events:       events,
+		categories:   categories,
+		participants: participants,
+		outbox:       outbox,
+		tx:           tx,
+	}
+}
+
+// List returns the waitlist of an event in queue order. A non-empty categoryID
+// restricts it to one race category.
+func (s *WaitlistService) List(ctx context.Context, eventID, categoryID string) ([]models.Participant, error) {
+	event, err := s.events.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, ErrEventNotFound
+	}
+
+	waitlist, err := s.participants.ListWaitlist(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if categoryID == "" {
+		return waitlist, nil
+	}
+
+	filtered := []models.Participant{}
+	for _, p := range waitlist {
+		if p.CategoryID != nil && *p.CategoryID == categoryID {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
+}
+
+// Reorder moves a waitlisted participant to position, shifting the others in
+// their queue. Positions past the end of the queue move them to the back.
+func (s *WaitlistService) Reorder(ctx context.Context, participantID string, position int) (*models.Participant, error) {
+	participant, err := s.participants.FindByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	var moved *models.Participant
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, _, err := s.lockSpots(ctx, participant.EventID, participant.CategoryID); err != nil {
+			return err
+		}
+
+		queue, err := s.queue(ctx, participant.EventID, participant.CategoryID)
+		if err != nil {
+			return err
+		}
+
+		from := -1
+		for i := range queue {
+			if queue[i].ID == participantID {
+				from = i
+				break
+			}
+		}
+		if from < 0 {
+			return ErrNotWaitlisted
+		}
+
+		p := queue[from]
+		queue = append(queue[:from], queue[from+1:]...)
+		to := min(position-1, len(queue))
+		queue = append(queue[:to], append([]models.Participant{p}, queue[to:]...)...)
+
+		if err := s.renumber(ctx, queue); err != nil {
+			return err
+		}
+		moved = &queue[to]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.ServerLogger.Info("Moved %s to waitlist position %d", moved.Email, *moved.WaitlistPosition)
+	return moved, nil
+}
+
+// Fill offers free spots in a scope to the head of its waitlist and queues an
+// email with the offer to each promoted participant. A nil categoryID is the
+// scope of an event without race categories.
+func (s *WaitlistService) Fill(ctx context.Context, eventID string, categoryID *string) error {
+	var offered []models.Participant
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		offered = nil
+
+		capacity, taken, err := s.lockSpots(ctx, eventID, categoryID)
+		if err != nil {
+			return err
+		}
+
+		queue, err := s.queue(ctx, eventID, categoryID)
+		if err != nil {
+			return err
+		}
+
+		free := len(queue)
+		if capacity != nil {
+			free = min(*capacity-taken, len(queue))
+		}
+		if free <= 0 {
+			return nil
+		}
+
+		expires := time.Now().Add(time.Duration(s.config.Waitlist.OfferHours) * time.Hour)
+		for i := 0; i < free; i++ {
+			p := queue[i]
+			p.RegistrationStatus = "PENDING"
+			p.WaitlistPosition = nil
+			p.OfferExpiresAt = &expires
+			if err := s.participants.UpdateRegistration(ctx, &p); err != nil {
+				return err
+			}
+			if err := s.outbox.Enqueue(ctx, p.ID, EmailTypeWaitlistOffer); err != nil {
+				return err
+			}
+			offered = append(offered, p)
+		}
+
+		return s.renumber(ctx, queue[free:])
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range offered {
+		utils.ServerLogger.Info("Offered a spot to waitlisted participant %s until %s", offered[i].Email, offered[i].OfferExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ExpireOffers marks participants who did not pay for an offered spot in time
+// as EXPIRED and offers their spots to the next in line. It returns the number
+// of offers that expired.
+func (s *WaitlistService) ExpireOffers(ctx context.Context) (int, error) {
+	overdue, err := s.participants.ListExpiredOffers(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, p := range overdue {
+		ok, err := s.expire(ctx, p.ID)
+		if err != nil {
+			return expired, err
+		}
+		if !ok {
+			continue
+		}
+		expired++
+
+		utils.ServerLogger.Info("Spot offer to %s expired", p.Email)
+
+		if err := s.Fill(ctx, p.EventID, p.CategoryID); err != nil {
+			return expired, err
+		}
+	}
+
+	return expired, nil
+}
+
+// Run expires overdue offers every interval until ctx is cancelled
+func (s *WaitlistService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ExpireOffers(ctx); err != nil {
+				utils.ServerLogger.Error("Failed to expire waitlist offers: %v", err)
+			}
+		}
+	}
+}
+
+// placement decides whether a new registration in a scope must wait. It returns
+// the waitlist position to register at, or nil if a spot is free. Registrations
+// join the queue behind anyone already waiting, even if a spot has just opened.
+// It must be called inside a transaction, which keeps the scope locked until the
+// registration is stored.
+func (s *WaitlistService) placement(ctx context.Context, eventID string, categoryID *string) (*int, error) {
+	capacity, taken, err := s.lockSpots(ctx, eventID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if capacity == nil {
+		return nil, nil
+	}
+
+	queue, err := s.queue(ctx, eventID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if taken < *capacity && len(queue) == 0 {
+		return nil, nil
+	}
+
+	position := len(queue) + 1
+	return &position, nil
+}
+
+// expire marks an overdue offer EXPIRED, unless the participant paid or the
+// offer was otherwise settled since it was listed
+func (s *WaitlistService) expire(ctx context.Context, participantID string) (bool, error) {
+	expired := false
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.participants.FindByIDForUpdate(ctx, participantID)
+		if err != nil {
+			return err
+		}
+		if p == nil || p.RegistrationStatus != "PENDING" || p.PaymentStatus != "UNPAID" ||
+			p.OfferExpiresAt == nil || p.OfferExpiresAt.After(time.Now()) {
+			return nil
+		}
+
+		p.RegistrationStatus = "EXPIRED"
+		expired = true
+		return s.participants.UpdateRegistration(ctx, p)
+	})
+	return expired, err
+}
+
+// lockSpots locks a scope against concurrent registrations and returns its
+// capacity, nil meaning unlimited, and the number of spots taken. It must be
+// called inside a transaction.
+func (s *WaitlistService) lockSpots(ctx context.Context, eventID string, categoryID *string) (*int, int, error) {
+	return lockScope(ctx, s.events, s.categories, eventID, categoryID)
+}
+
+// lockScope locks a race category, or an event when categoryID is nil, until the
+// surrounding transaction ends. It returns the capacity of the scope, nil meaning
+// unlimited, and the number of spots taken.
+func lockScope(
+	ctx context.Context,
+	events repository.EventRepository,
+	categories repository.RaceCategoryRepository,
+	eventID string,
+	categoryID *string,
+) (*int, int, error) {
+	if categoryID != nil {
+		category, err := categories.FindByIDForUpdate(ctx, *categoryID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if category == nil {
+			return nil, 0, ErrCategoryNotFound
+		}
+		return &category.Capacity, category.Registered, nil
+	}
+
+	event, err := events.FindByIDForUpdate(ctx, eventID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if event == nil {
+		return nil, 0, ErrEventNotFound
+	}
+	return event.Capacity, event.Registered, nil
+}
+
+// queue returns the waitlist of one scope in queue order
+func (s *WaitlistService) queue(ctx context.Context, eventID string, categoryID *string) ([]models.Participant, error) {
+	waitlist, err := s.participants.ListWaitlist(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := []models.Participant{}
+	for _, p := range waitlist {
+		if sameCategory(p.CategoryID, categoryID) {
+			queue = append(queue, p)
+		}
+	}
+	return queue, nil
+}
+
+// renumber saves positions 1..n for a queue, skipping participants already there
+func (s *WaitlistService) renumber(ctx context.Context, queue []models.Participant) error {
+	for i := range queue {
+		position := i + 1
+		if queue[i].WaitlistPosition != nil && *queue[i].WaitlistPosition == position {
+			continue
+		}
+		queue[i].WaitlistPosition = &position
+		if err := s.participants.UpdateRegistration(ctx, &queue[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameCategory reports whether two optional race category IDs are equal
+func sameCategory(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tau-tau-run/backend/config"
+	"github.com/tau-tau-run/backend/internal/mailer"
+	"github.com/tau-tau-run/backend/internal/models"
+	"github.com/tau-tau-run/backend/internal/repository/memory"
+)
+
+// waitlistFixture is an event for two runners without race categories, with
+// registration and waitlist services on an in-memory database
+type waitlistFixture struct {
+	event        *models.Event
+	events       *memory.EventRepository
+	participants *memory.ParticipantRepository
+	registration *RegistrationService
+	service      *WaitlistService
+}
+
+func newWaitlistFixture(t *testing.T) *waitlistFixture {
+	t.Helper()
+
+	capacity := 2
+	cfg := &config.Config{Waitlist: config.WaitlistConfig{OfferHours: 48}}
+	db := memory.NewDB()
+	tx := memory.NewTransactor(db)
+	categories := memory.NewRaceCategoryRepository(db)
+	f := &waitlistFixture{
+		event:        &models.Event{Name: "City Run", EventDate: "2026-12-06", Location: "Jakarta", RegistrationOpen: true, Capacity: &capacity},
+		events:       memory.NewEventRepository(db),
+		participants: memory.NewParticipantRepository(db),
+	}
+	if err := f.events.Create(context.Background(), f.event); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+
+	outbox := NewEmailOutbox(cfg, NewEmailService(cfg, mailer.NewMemoryMailer(), nil, f.events, memory.NewEmailLogRepository(db), nil, nil, nil), f.participants, memory.NewEmailOutboxRepository(db), tx)
+	f.service = NewWaitlistService(cfg, f.events, categories, f.participants, outbox, tx)
+	f.registration = NewRegistrationService(categories, f.participants, f.service, tx)
+	return f
+}
+
+// register registers one runner per email in order
+func (f *waitlistFixture) register(t *testing.T, emails ...string) []*models.Participant {
+	t.Helper()
+
+	registered := make([]*models.Participant, 0, len(emails))
+	for _, email := range emails {
+		p, err := f.registration.Register(context.Background(), f.event, request(email, "", nil))
+		if err != nil {
+			t.Fatalf("Register(%s) error = %v", email, err)
+		}
+		registered = append(registered, p)
+	}
+	return registered
+}
+
+// queue returns the emails on the event waitlist in queue order, checking
+// that positions run from 1 without gaps
+func (f *waitlistFixture) queue(t *testing.T) []string {
+	t.Helper()
+
+	waitlist, err := f.service.List(context.Background(), f.event.ID, "")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	emails := []string{}
+	for i, p := range waitlist {
+		if p.WaitlistPosition == nil || *p.WaitlistPosition != i+1 {
+			t.Errorf("%s is at waitlist position %v, want %d", p.Email, p.WaitlistPosition, i+1)
+		}
+		emails = append(emails, p.Email)
+	}
+	return emails
+}
+
+// status returns the stored registration status of p
+func (f *waitlistFixture) status(t *testing.T, p *models.Participant) *models.Participant {
+	t.Helper()
+
+	stored, err := f.participants.FindByID(context.Background(), p.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("failed to find participant %s: %v", p.Email, err)
+	}
+	return stored
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWaitlistServiceReorder(t *testing.T) {
+	f := newWaitlistFixture(t)
+	ctx := context.Background()
+	registered := f.register(t, "a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com")
+
+	tests := []struct {
+		participant *models.Participant
+		position    int
+		want        []string
+	}{
+		{participant: registered[4], position: 1, want: []string{"e@example.com", "c@example.com", "d@example.com"}},
+		{participant: registered[4], position: 99, want: []string{"c@example.com", "d@example.com", "e@example.com"}},
+		{participant: registered[3], position: 1, want: []string{"d@example.com", "c@example.com", "e@example.com"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := f.service.Reorder(ctx, tt.participant.ID, tt.position); err != nil {
+			t.Fatalf("Reorder(%s, %d) error = %v", tt.participant.Email, tt.position, err)
+		}
+		if got := f.queue(t); !equalStrings(got, tt.want) {
+			t.Errorf("after Reorder(%s, %d) queue = %v, want %v", tt.participant.Email, tt.position, got, tt.want)
+		}
+	}
+
+	if _, err := f.service.Reorder(ctx, registered[0].ID, 1); !errors.Is(err, ErrNotWaitlisted) {
+		t.Errorf("Reorder() of a participant holding a spot error = %v, want %v", err, ErrNotWaitlisted)
+	}
+}
+
+func TestWaitlistServiceFill(t *testing.T) {
+	f := newWaitlistFixture(t)
+	ctx := context.Background()
+	registered := f.register(t, "a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com")
+
+	capacity := 3
+	f.event.Capacity = &capacity
+	if err := f.events.Update(ctx, f.event); err != nil {
+		t.Fatalf("failed to raise capacity: %v", err)
+	}
+
+	before := time.Now()
+	if err := f.service.Fill(ctx, f.event.ID, nil); err != nil {
+		t.Fatalf("Fill() error = %v", err)
+	}
+
+	promoted := f.status(t, registered[2])
+	if promoted.RegistrationStatus != "PENDING" || promoted.WaitlistPosition != nil || promoted.OfferExpiresAt == nil {
+		t.Fatalf("promoted participant = %s at %v offered until %v, want PENDING with an offer",
+			promoted.RegistrationStatus, promoted.WaitlistPosition, promoted.OfferExpiresAt)
+	}
+	if deadline := before.Add(48 * time.Hour); promoted.OfferExpiresAt.Before(deadline) || promoted.OfferExpiresAt.After(deadline.Add(time.Minute)) {
+		t.Errorf("offer expires at %v, want 48 hours from now", promoted.OfferExpiresAt)
+	}
+	if got, want := f.queue(t), []string{"d@example.com", "e@example.com"}; !equalStrings(got, want) {
+		t.Errorf("queue = %v, want %v", got, want)
+	}
+
+	// A full event offers nothing more
+	if err := f.service.Fill(ctx, f.event.ID, nil); err != nil {
+		t.Fatalf("second Fill() error = %v", err)
+	}
+	if got := f.queue(t); len(got) != 2 {
+		t.Errorf("queue after second Fill() = %v, want 2 waiting", got)
+	}
+}
+
+func TestWaitlistServiceExpireOffers(t *testing.T) {
+	f := newWaitlistFixture(t)
+	ctx := context.Background()
+	registered := f.register(t, "a@example.com", "b@example.com", "c@example.com", "d@example.com")
+
+	// a@ lets an offer lapse and b@ pays for theirs in time
+	past := time.Now().Add(-time.Minute)
+	for _, p := range registered[:2] {
+		p.OfferExpiresAt = &past
+		if err := f.participants.UpdateRegistration(ctx, p); err != nil {
+			t.Fatalf("failed to backdate offer: %v", err)
+		}
+	}
+	if err := f.participants.UpdatePaymentStatus(ctx, registered[1], "PAID"); err != nil {
+		t.Fatalf("failed to mark participant paid: %v", err)
+	}
+
+	expired, err := f.service.ExpireOffers(ctx)
+	if err != nil {
+		t.Fatalf("ExpireOffers() error = %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("ExpireOffers() = %d, want 1", expired)
+	}
+
+	if got := f.status(t, registered[0]).RegistrationStatus; got != "EXPIRED" {
+		t.Errorf("lapsed offer status = %s, want EXPIRED", got)
+	}
+	if got := f.status(t, registered[1]).RegistrationStatus; got != "PENDING" {
+		t.Errorf("paid offer status = %s, want PENDING", got)
+	}
+	if got := f.status(t, registered[2]); got.RegistrationStatus != "PENDING" || got.OfferExpiresAt == nil {
+		t.Errorf("head of the queue = %s offered until %v, want PENDING with an offer", got.RegistrationStatus, got.OfferExpiresAt)
+	}
+	if got, want := f.queue(t), []string{"d@example.com"}; !equalStrings(got, want) {
+		t.Errorf("queue = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+	ctx := context.Background()
+	key := "payment-proofs/participant/receipt.png"
+
+	if err := store.Save(ctx, key, strings.NewReader("first"), "image/png"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Save(ctx, key, strings.NewReader("second"), "image/png"); err != nil {
+		t.Fatalf("Save() of a replacement error = %v", err)
+	}
+
+	f, err := store.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil || string(data) != "second" {
+		t.Errorf("stored file = %q, %v, want %q", data, err, "second")
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing file error = %v", err)
+	}
+	if _, err := store.Open(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() after Delete() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestLocalStorageRejectsKeysOutsideBaseDir(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+	ctx := context.Background()
+
+	for _, key := range []string{"../escape.txt", "payment-proofs/../../escape.txt", "", "."} {
+		if err := store.Save(ctx, key, strings.NewReader("data"), "text/plain"); err == nil {
+			t.Errorf("Save(%q) error = nil, want an invalid key error", key)
+		}
+		if _, err := store.Open(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Open(%q) error = %v, want an invalid key error", key, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tau-tau-run/backend/config"
+)
+
+// ErrNotFound is returned when no object exists for a key
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores uploaded files by key. Keys are slash-separated paths
+// such as "payment-proofs/<participant-id>/<file>".
+type Storage interface {
+	Save(ctx context.Context, key string, r io.Reader, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates the storage backend selected by cfg
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case "local":
+		return NewLocalStorage(cfg.Storage.LocalDir)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver %q", cfg.Storage.Driver)
+	}
+}
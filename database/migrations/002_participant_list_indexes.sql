@@ -0,0 +1,10 @@
+-- Migration: 002_participant_list_indexes
+-- Description: Indexes supporting admin participant filtering and sorting
+-- Date: 2026-10-17
+
+BEGIN;
+
+CREATE INDEX idx_participants_registration_status ON participants(registration_status);
+CREATE INDEX idx_participants_name ON participants(name);
+
+COMMIT;
@@ -0,0 +1,34 @@
+-- Migration: 003_payments
+-- Description: Online payments created through a payment gateway
+-- Date: 2026-10-17
+
+BEGIN;
+
+CREATE TABLE payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    participant_id UUID NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    reference VARCHAR(100) UNIQUE NOT NULL,
+    provider_reference VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    checkout_url TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_payment_participant FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE,
+    CONSTRAINT check_payment_status CHECK (status IN ('PENDING', 'PAID', 'FAILED', 'EXPIRED')),
+    CONSTRAINT check_payment_amount CHECK (amount > 0)
+);
+
+CREATE INDEX idx_payments_participant_id ON payments(participant_id, created_at DESC);
+
+CREATE TRIGGER update_payments_updated_at
+    BEFORE UPDATE ON payments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
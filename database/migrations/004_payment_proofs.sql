@@ -0,0 +1,28 @@
+-- Migration: 004_payment_proofs
+-- Description: Proof-of-payment uploads for manual bank transfers
+-- Date: 2026-10-17
+
+BEGIN;
+
+CREATE TABLE payment_proofs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    participant_id UUID NOT NULL,
+    storage_key VARCHAR(500) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    review_note TEXT,
+    reviewed_by UUID,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_proof_participant FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_proof_reviewer FOREIGN KEY (reviewed_by) REFERENCES admins(id) ON DELETE SET NULL,
+    CONSTRAINT check_proof_status CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED'))
+);
+
+CREATE INDEX idx_payment_proofs_status ON payment_proofs(status, created_at);
+CREATE INDEX idx_payment_proofs_participant_id ON payment_proofs(participant_id);
+
+COMMIT;
@@ -0,0 +1,48 @@
+-- Migration: 005_events
+-- Description: Events table; participants register against a specific event
+-- Date: 2026-10-17
+
+BEGIN;
+
+CREATE TABLE events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    event_date DATE NOT NULL,
+    location VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    registration_open BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_events_event_date ON events(event_date DESC);
+
+CREATE TRIGGER update_events_updated_at
+    BEFORE UPDATE ON events
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- The event previously configured through EVENT_* environment variables.
+-- Edit it through the admin API after migrating.
+INSERT INTO events (name, event_date, location, description)
+VALUES (
+    'Tau-Tau Run Fun Run 5K',
+    '2026-02-15',
+    'Gelora Bung Karno Stadium, Jakarta',
+    'Join us for an exciting 5K fun run event!'
+);
+
+-- Attach existing participants to that event
+ALTER TABLE participants ADD COLUMN event_id UUID;
+UPDATE participants SET event_id = (SELECT id FROM events ORDER BY created_at LIMIT 1);
+ALTER TABLE participants ALTER COLUMN event_id SET NOT NULL;
+ALTER TABLE participants
+    ADD CONSTRAINT fk_participant_event FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE RESTRICT;
+
+-- Email is unique per event instead of globally
+ALTER TABLE participants DROP CONSTRAINT participants_email_key;
+ALTER TABLE participants ADD CONSTRAINT unique_participant_event_email UNIQUE (event_id, email);
+
+CREATE INDEX idx_participants_event_id ON participants(event_id, created_at DESC);
+
+COMMIT;
@@ -0,0 +1,43 @@
+-- Migration: 006_race_categories
+-- Description: Race categories with capacity, pricing and age limits per event
+-- Date: 2026-10-17
+
+BEGIN;
+
+CREATE TABLE race_categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    distance_km NUMERIC(6, 2) NOT NULL,
+    price BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    capacity INTEGER NOT NULL,
+    min_age INTEGER,
+    max_age INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_category_event FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+    CONSTRAINT unique_category_event_name UNIQUE (event_id, name),
+    CONSTRAINT check_category_distance CHECK (distance_km > 0),
+    CONSTRAINT check_category_price CHECK (price > 0),
+    CONSTRAINT check_category_capacity CHECK (capacity > 0),
+    CONSTRAINT check_category_ages CHECK (min_age IS NULL OR max_age IS NULL OR min_age <= max_age)
+);
+
+CREATE INDEX idx_race_categories_event_id ON race_categories(event_id, distance_km);
+
+CREATE TRIGGER update_race_categories_updated_at
+    BEFORE UPDATE ON race_categories
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Participants registered before categories existed have none
+ALTER TABLE participants ADD COLUMN category_id UUID;
+ALTER TABLE participants
+    ADD CONSTRAINT fk_participant_category FOREIGN KEY (category_id) REFERENCES race_categories(id) ON DELETE RESTRICT;
+ALTER TABLE participants ADD COLUMN date_of_birth DATE;
+
+CREATE INDEX idx_participants_category_id ON participants(category_id);
+
+COMMIT;
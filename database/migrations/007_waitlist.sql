@@ -0,0 +1,26 @@
+-- Migration: 007_waitlist
+-- Description: Waitlist for sold-out events and race categories, with expiring spot offers
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- WAITLISTED participants queue for a spot; EXPIRED ones did not pay for an offered spot in time
+ALTER TABLE participants DROP CONSTRAINT check_registration_status;
+ALTER TABLE participants ADD CONSTRAINT check_registration_status
+    CHECK (registration_status IN ('PENDING', 'CONFIRMED', 'WAITLISTED', 'EXPIRED'));
+
+-- 1-based queue position within the event and race category, set only while WAITLISTED
+ALTER TABLE participants ADD COLUMN waitlist_position INTEGER;
+-- Payment deadline of a spot offered to a participant promoted from the waitlist
+ALTER TABLE participants ADD COLUMN offer_expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_participants_waitlist ON participants(event_id, category_id, waitlist_position)
+    WHERE registration_status = 'WAITLISTED';
+CREATE INDEX idx_participants_offer_expires_at ON participants(offer_expires_at)
+    WHERE offer_expires_at IS NOT NULL;
+
+-- Capacity of events without race categories; NULL means unlimited
+ALTER TABLE events ADD COLUMN capacity INTEGER;
+ALTER TABLE events ADD CONSTRAINT check_event_capacity CHECK (capacity IS NULL OR capacity > 0);
+
+COMMIT;
@@ -0,0 +1,32 @@
+-- Migration: 008_bib_numbers
+-- Description: Bib numbers assigned on payment, with ranges reserved for manual assignment
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- Unique within a race category, or among an event's participants without one
+ALTER TABLE participants ADD COLUMN bib_number INTEGER;
+ALTER TABLE participants ADD CONSTRAINT check_bib_number CHECK (bib_number IS NULL OR bib_number > 0);
+
+CREATE UNIQUE INDEX idx_participants_bib_number ON participants(event_id, category_id, bib_number) NULLS NOT DISTINCT
+    WHERE bib_number IS NOT NULL;
+
+-- Ranges skipped by automatic assignment, e.g. 1-50 for elites and VIPs.
+-- A reservation without a category applies to every category of the event.
+CREATE TABLE bib_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL,
+    category_id UUID,
+    range_start INTEGER NOT NULL,
+    range_end INTEGER NOT NULL,
+    label VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_bib_reservation_event FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+    CONSTRAINT fk_bib_reservation_category FOREIGN KEY (category_id) REFERENCES race_categories(id) ON DELETE CASCADE,
+    CONSTRAINT check_bib_reservation_range CHECK (range_start > 0 AND range_end >= range_start)
+);
+
+CREATE INDEX idx_bib_reservations_event_id ON bib_reservations(event_id, range_start);
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Migration: 009_check_in
+-- Description: Race-kit pickup and race-day check-in by scanning the participant's QR code
+-- Date: 2026-10-17
+
+BEGIN;
+
+ALTER TABLE participants ADD COLUMN kit_collected_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE participants ADD COLUMN kit_collected_by UUID;
+ALTER TABLE participants ADD COLUMN checked_in_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE participants ADD COLUMN checked_in_by UUID;
+
+ALTER TABLE participants ADD CONSTRAINT fk_participant_kit_collected_by
+    FOREIGN KEY (kit_collected_by) REFERENCES admins(id) ON DELETE SET NULL;
+ALTER TABLE participants ADD CONSTRAINT fk_participant_checked_in_by
+    FOREIGN KEY (checked_in_by) REFERENCES admins(id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,32 @@
+-- Migration: 010_email_outbox
+-- Description: Durable email outbox delivered by background workers with retries
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- Emails are queued in the same transaction as the change that triggers them
+-- and rendered from the participant's current data when delivered. SENDING
+-- rows whose next_attempt_at has passed were abandoned by a stopped worker and
+-- are picked up again. SKIPPED emails no longer applied when they came up, and
+-- skip_reason says why.
+CREATE TABLE email_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    participant_id UUID NOT NULL,
+    email_type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    skip_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_outbox_participant FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE,
+    CONSTRAINT check_outbox_status CHECK (status IN ('PENDING', 'SENDING', 'SENT', 'SKIPPED', 'DEAD'))
+);
+
+CREATE INDEX idx_email_outbox_due ON email_outbox(next_attempt_at) WHERE status IN ('PENDING', 'SENDING');
+CREATE INDEX idx_email_outbox_status ON email_outbox(status, created_at);
+
+COMMIT;
@@ -0,0 +1,28 @@
+-- Migration: 011_email_templates
+-- Description: Versioned, admin-editable email templates per event and email type
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- Every save adds a version; the highest version of an event and email type
+-- is the one sent. Rolling back copies an older version into a new one, so
+-- the history is never rewritten. Events without a template use the built-in
+-- default.
+CREATE TABLE email_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL,
+    email_type VARCHAR(50) NOT NULL,
+    version INTEGER NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    html_body TEXT NOT NULL,
+    text_body TEXT NOT NULL,
+    restored_from INTEGER,
+    created_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_email_templates_event FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+    CONSTRAINT fk_email_templates_created_by FOREIGN KEY (created_by) REFERENCES admins(id) ON DELETE SET NULL,
+    CONSTRAINT uq_email_templates_version UNIQUE (event_id, email_type, version)
+);
+
+COMMIT;
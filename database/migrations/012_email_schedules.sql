@@ -0,0 +1,30 @@
+-- Migration: 012_email_schedules
+-- Description: Per-event schedules of payment reminders, race-kit pickup and race-day briefing emails
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- Events without a row for an email type use the built-in schedule, which is
+-- disabled. send_time is HH:MM in the event time zone (EVENT_TIMEZONE).
+CREATE TABLE email_schedules (
+    event_id UUID NOT NULL,
+    email_type VARCHAR(50) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    days INTEGER NOT NULL DEFAULT 0,
+    send_time VARCHAR(5) NOT NULL,
+    updated_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (event_id, email_type),
+    CONSTRAINT fk_email_schedule_event FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+    CONSTRAINT fk_email_schedule_admin FOREIGN KEY (updated_by) REFERENCES admins(id) ON DELETE SET NULL,
+    CONSTRAINT check_email_schedule_days CHECK (days >= 0),
+    CONSTRAINT check_email_schedule_send_time CHECK (send_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$')
+);
+
+-- Scheduled emails are sent once per participant: the scheduler skips
+-- participants with an outbox row or a successful email log of the type
+CREATE INDEX idx_email_outbox_participant ON email_outbox(participant_id, email_type);
+CREATE INDEX idx_email_logs_participant_type ON email_logs(participant_id, email_type) WHERE status = 'SUCCESS';
+
+COMMIT;
@@ -0,0 +1,37 @@
+-- Migration: 013_email_campaigns
+-- Description: Admin email campaigns to segments of an event's participants
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- The segment columns record the filters the recipients were chosen with.
+-- Recipients are fixed when the campaign is created and queued in the outbox.
+CREATE TABLE email_campaigns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    html_body TEXT NOT NULL,
+    text_body TEXT NOT NULL,
+    payment_status VARCHAR(20),
+    registration_status VARCHAR(20),
+    category_id UUID,
+    checked_in BOOLEAN,
+    recipients INTEGER NOT NULL,
+    created_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_campaign_event FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+    CONSTRAINT fk_campaign_category FOREIGN KEY (category_id) REFERENCES race_categories(id) ON DELETE SET NULL,
+    CONSTRAINT fk_campaign_admin FOREIGN KEY (created_by) REFERENCES admins(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_email_campaigns_event ON email_campaigns(event_id, created_at DESC);
+
+-- Campaign emails are queued and logged like any other, tagged with their campaign
+ALTER TABLE email_outbox ADD COLUMN campaign_id UUID REFERENCES email_campaigns(id) ON DELETE CASCADE;
+ALTER TABLE email_logs ADD COLUMN campaign_id UUID REFERENCES email_campaigns(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_email_outbox_campaign ON email_outbox(campaign_id) WHERE campaign_id IS NOT NULL;
+CREATE INDEX idx_email_logs_campaign ON email_logs(campaign_id, sent_at) WHERE campaign_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- Migration: 014_email_log_resends
+-- Description: Link emails resent by admins to the log entry of the original
+-- Date: 2026-10-17
+
+BEGIN;
+
+ALTER TABLE email_logs ADD COLUMN resend_of UUID REFERENCES email_logs(id) ON DELETE SET NULL;
+ALTER TABLE email_logs ADD COLUMN resent_by UUID REFERENCES admins(id) ON DELETE SET NULL;
+
+-- Admins look up what was sent to a participant, newest first
+CREATE INDEX idx_email_logs_participant_sent_at ON email_logs(participant_id, sent_at DESC);
+CREATE INDEX idx_email_logs_type_sent_at ON email_logs(email_type, sent_at DESC);
+
+COMMIT;
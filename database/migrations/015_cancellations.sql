@@ -0,0 +1,49 @@
+-- Migration: 015_cancellations
+-- Description: Cancelled registrations, refunds and per-event refund policies
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- CANCELLED registrations no longer hold a spot
+ALTER TABLE participants DROP CONSTRAINT check_registration_status;
+ALTER TABLE participants ADD CONSTRAINT check_registration_status
+    CHECK (registration_status IN ('PENDING', 'CONFIRMED', 'WAITLISTED', 'EXPIRED', 'CANCELLED'));
+
+-- Registrations owed a refund, because they were cancelled after paying or a
+-- payment arrived once they held no spot, are REFUND_PENDING until an admin
+-- records it
+ALTER TABLE participants DROP CONSTRAINT check_payment_status;
+ALTER TABLE participants ADD CONSTRAINT check_payment_status
+    CHECK (payment_status IN ('UNPAID', 'PAID', 'REFUND_PENDING', 'REFUNDED'));
+
+ALTER TABLE participants ADD COLUMN cancelled_at TIMESTAMP WITH TIME ZONE;
+-- NULL when the participant cancelled through the participant portal
+ALTER TABLE participants ADD COLUMN cancelled_by UUID;
+ALTER TABLE participants ADD COLUMN cancellation_reason TEXT;
+-- Owed under the event's refund policy when cancelled, or in full for a late
+-- payment, in the smallest currency unit
+ALTER TABLE participants ADD COLUMN refund_amount BIGINT;
+ALTER TABLE participants ADD COLUMN refund_currency VARCHAR(3);
+ALTER TABLE participants ADD COLUMN refunded_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE participants ADD COLUMN refunded_by UUID;
+
+ALTER TABLE participants ADD CONSTRAINT fk_participant_cancelled_by
+    FOREIGN KEY (cancelled_by) REFERENCES admins(id) ON DELETE SET NULL;
+ALTER TABLE participants ADD CONSTRAINT fk_participant_refunded_by
+    FOREIGN KEY (refunded_by) REFERENCES admins(id) ON DELETE SET NULL;
+
+-- Admins work through the refunds still to be paid out
+CREATE INDEX idx_participants_refund_pending ON participants(cancelled_at)
+    WHERE payment_status = 'REFUND_PENDING';
+
+-- Full refund up to and including refund_full_until, refund_partial_percent
+-- after it, and none in the last refund_cutoff_days days before the event.
+-- Events without a policy give no refunds.
+ALTER TABLE events ADD COLUMN refund_full_until DATE;
+ALTER TABLE events ADD COLUMN refund_partial_percent INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN refund_cutoff_days INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE events ADD CONSTRAINT check_event_refund_partial_percent
+    CHECK (refund_partial_percent BETWEEN 0 AND 100);
+ALTER TABLE events ADD CONSTRAINT check_event_refund_cutoff_days CHECK (refund_cutoff_days >= 0);
+
+COMMIT;
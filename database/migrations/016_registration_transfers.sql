@@ -0,0 +1,52 @@
+-- Migration: 016_registration_transfers
+-- Description: Transfers of paid registrations to another runner, with their audit trail
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- A transfer is PENDING until the recipient accepts it through the emailed
+-- link, which re-points the participant to them. The from_ and to_ columns
+-- keep who held the registration before and after, since the participant row
+-- itself is overwritten.
+CREATE TABLE registration_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    participant_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    from_name VARCHAR(255) NOT NULL,
+    from_email VARCHAR(255) NOT NULL,
+    from_phone VARCHAR(50) NOT NULL,
+    to_name VARCHAR(255) NOT NULL,
+    to_email VARCHAR(255) NOT NULL,
+    to_phone VARCHAR(50),
+    -- NULL when the participant started the transfer through the participant portal
+    initiated_by UUID,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    cancelled_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_transfer_participant FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transfer_event FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transfer_initiated_by FOREIGN KEY (initiated_by) REFERENCES admins(id) ON DELETE SET NULL,
+    CONSTRAINT fk_transfer_cancelled_by FOREIGN KEY (cancelled_by) REFERENCES admins(id) ON DELETE SET NULL,
+    CONSTRAINT check_transfer_status CHECK (status IN ('PENDING', 'COMPLETED', 'CANCELLED', 'EXPIRED'))
+);
+
+-- A registration has at most one transfer waiting for its recipient
+CREATE UNIQUE INDEX idx_registration_transfers_pending ON registration_transfers(participant_id)
+    WHERE status = 'PENDING';
+CREATE INDEX idx_registration_transfers_participant ON registration_transfers(participant_id, created_at DESC);
+CREATE INDEX idx_registration_transfers_event ON registration_transfers(event_id, created_at DESC);
+
+-- Transfers can be started and accepted up to and including this date, or
+-- until the day before the event if it is not set
+ALTER TABLE events ADD COLUMN transfer_deadline DATE;
+
+-- Check-in tokens sign the participant ID together with this version, so
+-- transferring a registration makes the previous holder's QR code stop working
+ALTER TABLE participants ADD COLUMN check_in_version INTEGER NOT NULL DEFAULT 1;
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Migration: 017_admin_roles
+-- Description: Roles that limit what each admin can do
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- OWNER can do everything, including assigning roles. FINANCE handles
+-- payments and refunds, CHECK_IN scans QR codes at race-kit pickup and on race
+-- day, and VIEWER can only look. Existing admins become owners so nobody
+-- loses access.
+ALTER TABLE admins ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'OWNER';
+ALTER TABLE admins ADD CONSTRAINT check_admin_role
+    CHECK (role IN ('OWNER', 'FINANCE', 'CHECK_IN', 'VIEWER'));
+
+COMMIT;
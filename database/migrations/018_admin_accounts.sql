@@ -0,0 +1,16 @@
+-- Migration: 018_admin_accounts
+-- Description: Disabling admins and recording who invited them
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- Disabled admins can no longer log in, and tokens they already hold are
+-- refused. Clearing disabled_at enables them again.
+ALTER TABLE admins ADD COLUMN disabled_at TIMESTAMP WITH TIME ZONE;
+
+-- The admin who invited this one, NULL for admins created from the command line
+ALTER TABLE admins ADD COLUMN created_by UUID;
+ALTER TABLE admins ADD CONSTRAINT fk_admin_created_by
+    FOREIGN KEY (created_by) REFERENCES admins(id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,40 @@
+-- Migration: 019_admin_sessions
+-- Description: Refresh tokens of admin sessions and revoked access tokens
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- Each login starts a session, a family of refresh tokens. A refresh token is
+-- used once: refreshing marks it used and adds the next token to the family.
+-- Only a SHA-256 hash of each token is stored. access_jti is the access token
+-- issued with it, so revoking the family can revoke that token too.
+CREATE TABLE admin_refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    admin_id UUID NOT NULL,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    access_jti UUID NOT NULL,
+    access_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_refresh_token_admin FOREIGN KEY (admin_id) REFERENCES admins(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_admin_refresh_tokens_family ON admin_refresh_tokens(family_id);
+CREATE INDEX idx_admin_refresh_tokens_admin ON admin_refresh_tokens(admin_id);
+CREATE INDEX idx_admin_refresh_tokens_expires ON admin_refresh_tokens(expires_at);
+
+-- Access tokens refused before they expire, by their jti claim. Rows are
+-- removed once the token has expired anyway.
+CREATE TABLE revoked_access_tokens (
+    jti UUID PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_revoked_access_tokens_expires ON revoked_access_tokens(expires_at);
+
+COMMIT;
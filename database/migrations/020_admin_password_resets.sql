@@ -0,0 +1,24 @@
+-- Migration: 020_admin_password_resets
+-- Description: Single-use password reset tokens emailed to admins
+-- Date: 2026-10-17
+
+BEGIN;
+
+-- A reset token is emailed as a link when an admin forgets their password.
+-- Only a SHA-256 hash of the token is stored. used_at is set when the token
+-- resets the password, or when a newer link or a reset makes it obsolete.
+CREATE TABLE admin_password_resets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    admin_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_password_reset_admin FOREIGN KEY (admin_id) REFERENCES admins(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_admin_password_resets_admin ON admin_password_resets(admin_id);
+CREATE INDEX idx_admin_password_resets_expires ON admin_password_resets(expires_at);
+
+COMMIT;
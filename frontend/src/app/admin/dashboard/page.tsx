@@ -87,8 +87,8 @@ export default function AdminDashboardPage() {
     }
   };
 
-  const handleLogout = () => {
-    apiClient.clearToken();
+  const handleLogout = async () => {
+    await apiClient.logout();
     router.push('/admin/login');
   };
 
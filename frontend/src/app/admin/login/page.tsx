@@ -32,8 +32,8 @@ export default function AdminLoginPage() {
       });
 
       if (response.success && response.data) {
-        // Store token in API client
-        apiClient.setToken(response.data.token);
+        // Store the access and refresh tokens in API client
+        apiClient.setToken(response.data.token, response.data.refresh_token);
 
         // Redirect to dashboard
         router.push('/admin/dashboard');
@@ -39,6 +39,9 @@ export default function ParticipantList({
       <table className="min-w-full divide-y divide-gray-200">
         <thead className="bg-gray-50">
           <tr>
+            <th className="px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
+              Bib
+            </th>
             <th className="px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
               Name
             </th>
@@ -65,6 +68,11 @@ export default function ParticipantList({
         <tbody className="bg-white divide-y divide-gray-200">
           {participants.map((participant) => (
             <tr key={participant.id} className="hover:bg-gray-50">
+              <td className="px-6 py-4 whitespace-nowrap">
+                <div className="text-sm text-gray-600">
+                  {participant.bib_number ?? '-'}
+                </div>
+              </td>
               <td className="px-6 py-4 whitespace-nowrap">
                 <div className="text-sm font-medium text-gray-900">
                   {participant.name}
@@ -1,9 +1,13 @@
-import axios, { AxiosError, AxiosInstance, AxiosResponse } from 'axios';
-import type { APIResponse } from '@/types';
+import axios, { AxiosError, AxiosInstance, AxiosResponse, InternalAxiosRequestConfig } from 'axios';
+import type { APIResponse, LoginResponse } from '@/types';
 
 class APIClient {
   private client: AxiosInstance;
   private token: string | null = null;
+  private refreshToken: string | null = null;
+  // Shared by requests that fail while a refresh is in flight, since each
+  // refresh token can only be used once
+  private refreshing: Promise<boolean> | null = null;
 
   constructor() {
     this.client = axios.create({
@@ -32,11 +36,21 @@ class APIClient {
       (response: AxiosResponse<APIResponse>) => {
         return response;
       },
-      (error: AxiosError<APIResponse>) => {
+      async (error: AxiosError<APIResponse>) => {
         // Handle specific error cases
         if (error.response) {
           const { status, data } = error.response;
 
+          // Access token expired: refresh the session once and retry
+          const config = error.config as (InternalAxiosRequestConfig & { _retried?: boolean }) | undefined;
+          if (status === 403 && data?.error?.code === 'TOKEN_EXPIRED' && config && !config._retried && this.refreshToken) {
+            config._retried = true;
+            if (await this.refreshSession()) {
+              config.headers.Authorization = `Bearer ${this.token}`;
+              return this.client.request(config);
+            }
+          }
+
           if (status === 401 || status === 403) {
             // Token expired or unauthorized
             this.clearToken();
@@ -75,22 +89,68 @@ class APIClient {
     // Load token from localStorage if available
     if (typeof window !== 'undefined') {
       this.token = localStorage.getItem('auth_token');
+      this.refreshToken = localStorage.getItem('refresh_token');
     }
   }
 
-  // Set authentication token
-  setToken(token: string) {
+  // Set authentication token, and the refresh token that renews it
+  setToken(token: string, refreshToken?: string) {
     this.token = token;
+    if (refreshToken !== undefined) {
+      this.refreshToken = refreshToken;
+    }
     if (typeof window !== 'undefined') {
       localStorage.setItem('auth_token', token);
+      if (refreshToken !== undefined) {
+        localStorage.setItem('refresh_token', refreshToken);
+      }
     }
   }
 
   // Clear authentication token
   clearToken() {
     this.token = null;
+    this.refreshToken = null;
     if (typeof window !== 'undefined') {
       localStorage.removeItem('auth_token');
+      localStorage.removeItem('refresh_token');
+    }
+  }
+
+  // Exchange the refresh token for a new pair, reporting whether it worked
+  private refreshSession(): Promise<boolean> {
+    if (!this.refreshing) {
+      const refreshToken = this.refreshToken;
+      this.refreshing = axios
+        .post<APIResponse<LoginResponse>>(`${this.client.defaults.baseURL}/admin/refresh`, {
+          refresh_token: refreshToken,
+        })
+        .then((response) => {
+          const session = response.data.data;
+          if (!session) {
+            return false;
+          }
+          this.setToken(session.token, session.refresh_token);
+          return true;
+        })
+        .catch(() => false)
+        .finally(() => {
+          this.refreshing = null;
+        });
+    }
+    return this.refreshing;
+  }
+
+  // Log out of the current session on the server and forget its tokens
+  async logout() {
+    const refreshToken = this.refreshToken;
+    this.clearToken();
+    if (refreshToken) {
+      try {
+        await this.client.post('/admin/logout', { refresh_token: refreshToken });
+      } catch {
+        // The tokens are forgotten either way
+      }
     }
   }
 
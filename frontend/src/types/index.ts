@@ -7,8 +7,13 @@ export interface Participant {
   phone: string;
   instagram_handle: string | null;
   address: string;
-  registration_status: 'PENDING' | 'CONFIRMED';
+  registration_status: 'PENDING' | 'CONFIRMED' | 'WAITLISTED' | 'EXPIRED';
   payment_status: 'UNPAID' | 'PAID';
+  waitlist_position?: number | null;
+  offer_expires_at?: string | null;
+  bib_number?: number | null; // assigned when the participant pays
+  kit_collected_at?: string | null;
+  checked_in_at?: string | null;
   created_at: string;
   updated_at: string;
 }
@@ -16,20 +21,26 @@ export interface Participant {
 export interface Admin {
   id: string;
   email: string;
+  role: 'OWNER' | 'FINANCE' | 'CHECK_IN' | 'VIEWER';
 }
 
 export interface LoginResponse {
   token: string;
   admin: Admin;
   expires_at: string;
+  refresh_token: string;
+  refresh_expires_at: string;
 }
 
 export interface RegisterRequest {
+  event_id?: string; // optional while exactly one event is open
+  category_id?: string; // required once the event has race categories
   name: string;
   email: string;
   phone: string;
   instagram_handle?: string;
   address: string;
+  date_of_birth?: string; // YYYY-MM-DD, required for age-limited categories
 }
 
 export interface RegisterResponse {
@@ -37,6 +48,7 @@ export interface RegisterResponse {
   email: string;
   registration_status: string;
   payment_status: string;
+  waitlist_position?: number; // set when the race is full and the registration was waitlisted
 }
 
 export interface LoginRequest {